@@ -0,0 +1,12 @@
+package fstool
+
+import "github.com/flexigpt/llmtools-go/internal/toolutil"
+
+// Clock supplies the current time for time-dependent behavior in this package
+// ("modified within" windows).
+type Clock = toolutil.Clock
+
+// SetClock replaces the clock shared by all tool packages and returns a func
+// that restores the previous one. A nil c restores the system clock. Intended
+// for tests; it affects all callers in the process.
+func SetClock(c Clock) (restore func()) { return toolutil.SetClock(c) }
@@ -0,0 +1,61 @@
+package fstool
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+type fixedClock time.Time
+
+func (c fixedClock) Now() time.Time { return time.Time(c) }
+
+func TestSetClock_RecentlyModifiedUsesPackageClock(t *testing.T) {
+	tmpDir := t.TempDir()
+	base := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	write := func(name string, mt time.Time) string {
+		t.Helper()
+		p := filepath.Join(tmpDir, name)
+		if err := os.WriteFile(p, []byte(name), 0o600); err != nil {
+			t.Fatalf("write %s: %v", name, err)
+		}
+		if err := os.Chtimes(p, mt, mt); err != nil {
+			t.Fatalf("chtimes %s: %v", name, err)
+		}
+		return p
+	}
+	early := write("early.txt", base)
+	late := write("late.txt", base.Add(2*time.Hour))
+
+	tests := []struct {
+		name string
+		now  time.Time
+		want []string
+	}{
+		{name: "just after late file", now: base.Add(2*time.Hour + time.Minute), want: []string{late}},
+		{name: "window covers both", now: base.Add(30 * time.Minute), want: []string{late, early}},
+		{name: "long after both", now: base.Add(48 * time.Hour), want: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Cleanup(SetClock(fixedClock(tt.now)))
+			out, err := RecentlyModified(t.Context(), RecentlyModifiedArgs{Root: tmpDir, Within: time.Hour})
+			if err != nil {
+				t.Fatalf("RecentlyModified: %v", err)
+			}
+			if !out.Since.Equal(tt.now.Add(-time.Hour)) {
+				t.Fatalf("Since=%v want %v", out.Since, tt.now.Add(-time.Hour))
+			}
+			if len(out.Files) != len(tt.want) {
+				t.Fatalf("got %d files %+v, want %d", len(out.Files), out.Files, len(tt.want))
+			}
+			for i, p := range tt.want {
+				if out.Files[i].Path != p {
+					t.Fatalf("Files[%d]=%q want %q", i, out.Files[i].Path, p)
+				}
+			}
+		})
+	}
+}
@@ -88,6 +88,9 @@ func deleteFile(ctx context.Context, args DeleteFileArgs) (*DeleteFileOut, error
 	if err := ctx.Err(); err != nil {
 		return nil, err
 	}
+	if err := confinePaths(&args.Path); err != nil {
+		return nil, err
+	}
 
 	src, err := fileutil.NormalizePath(strings.TrimSpace(args.Path))
 	if err != nil {
@@ -114,7 +117,8 @@ func deleteFile(ctx context.Context, args DeleteFileArgs) (*DeleteFileOut, error
 
 	candidates := []trashCandidate{}
 	if trashDirIn == "auto" {
-		if sys, ok := detectSystemTrashDir(); ok {
+		// With a root set, keep the file inside it rather than in the system trash.
+		if sys, ok := detectSystemTrashDir(); ok && Root() == "" {
 			// "auto" should prefer system trash *when possible*; treat EXDEV as "not possible"
 			// so we can fall back to a same-filesystem .trash instead of doing a huge copy.
 			candidates = append(candidates, trashCandidate{dir: sys, allowCrossDeviceCopy: false})
@@ -125,6 +129,9 @@ func deleteFile(ctx context.Context, args DeleteFileArgs) (*DeleteFileOut, error
 			trashCandidate{dir: filepath.Join(filepath.Dir(src), ".trash"), allowCrossDeviceCopy: true},
 		)
 	} else {
+		if err := confinePaths(&trashDirIn); err != nil {
+			return nil, err
+		}
 		td, err := fileutil.NormalizePath(trashDirIn)
 		if err != nil {
 			return nil, err
@@ -0,0 +1,113 @@
+package fstool
+
+import (
+	"context"
+	"time"
+
+	"github.com/flexigpt/llmtools-go/internal/fileutil"
+	"github.com/flexigpt/llmtools-go/internal/toolutil"
+	"github.com/flexigpt/llmtools-go/spec"
+)
+
+const directorySummaryFuncID spec.FuncID = "github.com/flexigpt/llmtools-go/fstool/directorysummary.DirectorySummary"
+
+var directorySummaryTool = spec.Tool{
+	SchemaVersion: spec.SchemaVersion,
+	ID:            "01a13d76-bbcf-7132-a692-f22240937c1a",
+	Slug:          "directorysummary",
+	Version:       "v1.0.0",
+	DisplayName:   "Summarize directory",
+	Description:   "Summarize a directory: entry counts by category (text, image, binary, directory, symlink), total size, largest and newest file.",
+	Tags:          []string{"fs", "list"},
+
+	ArgSchema: spec.JSONSchema(`{
+"$schema": "http://json-schema.org/draft-07/schema#",
+"type": "object",
+"properties": {
+	"path": {
+		"type": "string",
+		"description": "Directory path to summarize.",
+		"default": "."
+	},
+	"recursive": {
+		"type": "boolean",
+		"description": "If true, include all descendants. Symlinks are counted but never followed.",
+		"default": false
+	}
+},
+"required": [],
+"additionalProperties": false
+}`),
+	GoImpl: spec.GoToolImpl{FuncID: directorySummaryFuncID},
+
+	CreatedAt:  spec.SchemaStartTime,
+	ModifiedAt: spec.SchemaStartTime,
+}
+
+func DirectorySummaryTool() spec.Tool {
+	return toolutil.CloneTool(directorySummaryTool)
+}
+
+type DirectorySummaryArgs struct {
+	Path      string `json:"path,omitempty"` // default "."
+	Recursive bool   `json:"recursive,omitempty"`
+}
+
+type DirectoryCategoryCounts struct {
+	Text      int `json:"text"`
+	Image     int `json:"image"`
+	Binary    int `json:"binary"`
+	Directory int `json:"directory"`
+	Symlink   int `json:"symlink"`
+}
+
+type DirectoryFileRef struct {
+	Path      string     `json:"path"`
+	SizeBytes int64      `json:"sizeBytes"`
+	ModTime   *time.Time `json:"modTime,omitempty"`
+}
+
+type DirectorySummaryOut struct {
+	Path           string                  `json:"path"`
+	Counts         DirectoryCategoryCounts `json:"counts"`
+	TotalFiles     int                     `json:"totalFiles"`
+	TotalSizeBytes int64                   `json:"totalSizeBytes"`
+	Largest        *DirectoryFileRef       `json:"largest,omitempty"`
+	Newest         *DirectoryFileRef       `json:"newest,omitempty"`
+}
+
+// DirectorySummary classifies the entries of Path and reports counts, total size,
+// and the largest/newest regular file. It is non-recursive unless Recursive is set.
+func DirectorySummary(ctx context.Context, args DirectorySummaryArgs) (*DirectorySummaryOut, error) {
+	return toolutil.WithRecoveryResp(func() (*DirectorySummaryOut, error) {
+		return directorySummary(ctx, args)
+	})
+}
+
+func directorySummary(ctx context.Context, args DirectorySummaryArgs) (*DirectorySummaryOut, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if err := confinePaths(&args.Path); err != nil {
+		return nil, err
+	}
+	sum, err := fileutil.SummarizeDirectory(ctx, args.Path, args.Recursive)
+	if err != nil {
+		return nil, err
+	}
+	return &DirectorySummaryOut{
+		Path:           sum.Path,
+		Counts:         DirectoryCategoryCounts(sum.Counts),
+		TotalFiles:     sum.TotalFiles,
+		TotalSizeBytes: sum.TotalSizeBytes,
+		Largest:        toDirectoryFileRef(sum.Largest),
+		Newest:         toDirectoryFileRef(sum.Newest),
+	}, nil
+}
+
+func toDirectoryFileRef(r *fileutil.FileRef) *DirectoryFileRef {
+	if r == nil {
+		return nil
+	}
+	return &DirectoryFileRef{Path: r.Path, SizeBytes: r.Size, ModTime: r.ModTime}
+}
@@ -0,0 +1,124 @@
+package fstool
+
+import (
+	"context"
+	"errors"
+	"image"
+	"image/png"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestDirectorySummary(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	write := func(name string, data []byte, mtime time.Time) string {
+		t.Helper()
+		p := filepath.Join(tmpDir, name)
+		if err := os.WriteFile(p, data, 0o600); err != nil {
+			t.Fatalf("write %s: %v", name, err)
+		}
+		if err := os.Chtimes(p, mtime, mtime); err != nil {
+			t.Fatalf("chtimes %s: %v", name, err)
+		}
+		return p
+	}
+
+	base := time.Now().Add(-time.Hour).Truncate(time.Second)
+	write("notes.txt", []byte("hello"), base)
+	write("readme.md", []byte("# title\n"), base.Add(10*time.Minute))
+	blob := write("blob.dat", append([]byte{0x00, 0xff}, make([]byte, 4096)...), base)
+
+	imgPath := filepath.Join(tmpDir, "pic.png")
+	f, err := os.Create(imgPath)
+	if err != nil {
+		t.Fatalf("create png: %v", err)
+	}
+	if err := png.Encode(f, image.NewRGBA(image.Rect(0, 0, 2, 2))); err != nil {
+		t.Fatalf("encode png: %v", err)
+	}
+	_ = f.Close()
+	newest := base.Add(30 * time.Minute)
+	if err := os.Chtimes(imgPath, newest, newest); err != nil {
+		t.Fatalf("chtimes png: %v", err)
+	}
+
+	sub := filepath.Join(tmpDir, "sub")
+	if err := os.Mkdir(sub, 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(sub, "inner.go"), []byte("package x\n"), 0o600); err != nil {
+		t.Fatalf("write inner: %v", err)
+	}
+	wantSymlinks := 1
+	if err := os.Symlink(filepath.Join(tmpDir, "notes.txt"), filepath.Join(tmpDir, "link.txt")); err != nil {
+		wantSymlinks = 0
+	}
+
+	tests := []struct {
+		name       string
+		ctx        func(t *testing.T) context.Context
+		args       DirectorySummaryArgs
+		wantErr    error
+		wantCounts DirectoryCategoryCounts
+	}{
+		{
+			name: "context_canceled",
+			ctx: func(t *testing.T) context.Context {
+				t.Helper()
+				ctx, cancel := context.WithCancel(t.Context())
+				cancel()
+				return ctx
+			},
+			args:    DirectorySummaryArgs{Path: tmpDir},
+			wantErr: context.Canceled,
+		},
+		{
+			name: "non_recursive",
+			args: DirectorySummaryArgs{Path: tmpDir},
+			wantCounts: DirectoryCategoryCounts{
+				Text: 2, Image: 1, Binary: 1, Directory: 1, Symlink: wantSymlinks,
+			},
+		},
+		{
+			name: "recursive",
+			args: DirectorySummaryArgs{Path: tmpDir, Recursive: true},
+			wantCounts: DirectoryCategoryCounts{
+				Text: 3, Image: 1, Binary: 1, Directory: 1, Symlink: wantSymlinks,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := t.Context()
+			if tt.ctx != nil {
+				ctx = tt.ctx(t)
+			}
+			out, err := DirectorySummary(ctx, tt.args)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("expected %v, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("DirectorySummary: %v", err)
+			}
+			if out.Counts != tt.wantCounts {
+				t.Fatalf("counts mismatch: got %+v want %+v", out.Counts, tt.wantCounts)
+			}
+			if out.Largest == nil || out.Largest.Path != blob {
+				t.Fatalf("largest mismatch: got %+v want %s", out.Largest, blob)
+			}
+			if !tt.args.Recursive && (out.Newest == nil || out.Newest.Path != imgPath) {
+				t.Fatalf("newest mismatch: got %+v want %s", out.Newest, imgPath)
+			}
+			if out.TotalSizeBytes <= 0 {
+				t.Fatalf("expected positive total size, got %d", out.TotalSizeBytes)
+			}
+		})
+	}
+}
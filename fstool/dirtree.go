@@ -0,0 +1,143 @@
+package fstool
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/flexigpt/llmtools-go/internal/fileutil"
+	"github.com/flexigpt/llmtools-go/internal/toolutil"
+	"github.com/flexigpt/llmtools-go/spec"
+)
+
+const dirTreeFuncID spec.FuncID = "github.com/flexigpt/llmtools-go/fstool/dirtree.DirTree"
+
+const (
+	defaultDirTreeMaxEntries = 500
+	maxDirTreeMaxEntries     = 10000
+)
+
+var dirTreeTool = spec.Tool{
+	SchemaVersion: spec.SchemaVersion,
+	ID:            "01a13dbe-2340-727e-aea3-a391ce0c0db3",
+	Slug:          "dirtree",
+	Version:       "v1.0.0",
+	DisplayName:   "Directory tree",
+	Description:   "Return the nested tree of files and directories under a directory, breadth first and bounded by depth and total entries. Directories with omitted children are marked truncated.",
+	Tags:          []string{"fs", "list"},
+
+	ArgSchema: spec.JSONSchema(`{
+"$schema": "http://json-schema.org/draft-07/schema#",
+"type": "object",
+"properties": {
+	"root": {
+		"type": "string",
+		"description": "Directory to describe.",
+		"default": "."
+	},
+	"maxDepth": {
+		"type": "integer",
+		"minimum": 0,
+		"description": "Deepest level expanded (the root's children are level 1). 0 means no depth limit.",
+		"default": 0
+	},
+	"maxEntries": {
+		"type": "integer",
+		"minimum": 0,
+		"maximum": 10000,
+		"description": "Maximum number of entries in the tree. 0 uses the default of 500.",
+		"default": 500
+	},
+	"followSymlinks": {
+		"type": "boolean",
+		"description": "Also expand symlinked directories. Each directory is expanded once, so cycles terminate.",
+		"default": false
+	}
+},
+"required": [],
+"additionalProperties": false
+}`),
+	GoImpl: spec.GoToolImpl{FuncID: dirTreeFuncID},
+
+	CreatedAt:  spec.SchemaStartTime,
+	ModifiedAt: spec.SchemaStartTime,
+}
+
+func DirTreeTool() spec.Tool {
+	return toolutil.CloneTool(dirTreeTool)
+}
+
+type DirTreeArgs struct {
+	Root       string `json:"root,omitempty"` // default "."
+	MaxDepth   int    `json:"maxDepth,omitempty"`
+	MaxEntries int    `json:"maxEntries,omitempty"` // default 500
+
+	// FollowSymlinks expands symlinked directories; not allowed with SetRoot.
+	FollowSymlinks bool `json:"followSymlinks,omitempty"`
+}
+
+type TreeNode struct {
+	Name      string     `json:"name"`
+	IsDir     bool       `json:"isDir"`
+	IsSymlink bool       `json:"isSymlink,omitempty"`
+	Truncated bool       `json:"truncated,omitempty"` // some children omitted
+	Children  []TreeNode `json:"children,omitempty"`
+}
+
+type DirTreeOut struct {
+	Root       string   `json:"root"`
+	Tree       TreeNode `json:"tree"`
+	EntryCount int      `json:"entryCount"`
+	Truncated  bool     `json:"truncated"`
+}
+
+// DirTree returns the tree under Root. Levels are filled breadth first, so when
+// MaxEntries runs out the upper levels are complete and deeper directories are
+// marked Truncated; directories beyond MaxDepth are marked the same way.
+// Symlinked directories are shown but only expanded with FollowSymlinks.
+func DirTree(ctx context.Context, args DirTreeArgs) (*DirTreeOut, error) {
+	return toolutil.WithRecoveryResp(func() (*DirTreeOut, error) {
+		return dirTree(ctx, args)
+	})
+}
+
+func dirTree(ctx context.Context, args DirTreeArgs) (*DirTreeOut, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if err := confinePaths(&args.Root); err != nil {
+		return nil, err
+	}
+	if err := checkWalkFollowSymlinks(args.FollowSymlinks); err != nil {
+		return nil, err
+	}
+	if args.MaxDepth < 0 {
+		return nil, errors.New("maxDepth must be >= 0")
+	}
+	if args.MaxEntries < 0 || args.MaxEntries > maxDirTreeMaxEntries {
+		return nil, fmt.Errorf("maxEntries must be between 0 and %d", maxDirTreeMaxEntries)
+	}
+	maxEntries := args.MaxEntries
+	if maxEntries == 0 {
+		maxEntries = defaultDirTreeMaxEntries
+	}
+
+	tree, err := fileutil.BuildDirTree(ctx, args.Root, args.MaxDepth, maxEntries, args.FollowSymlinks)
+	if err != nil {
+		return nil, err
+	}
+	return &DirTreeOut{
+		Root:       tree.Path,
+		Tree:       toTreeNode(tree.Root),
+		EntryCount: tree.NodeCount,
+		Truncated:  tree.Truncated,
+	}, nil
+}
+
+func toTreeNode(n *fileutil.DirTreeNode) TreeNode {
+	out := TreeNode{Name: n.Name, IsDir: n.IsDir, IsSymlink: n.IsSymlink, Truncated: n.Truncated}
+	for _, c := range n.Children {
+		out.Children = append(out.Children, toTreeNode(c))
+	}
+	return out
+}
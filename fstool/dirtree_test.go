@@ -0,0 +1,160 @@
+package fstool
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/flexigpt/llmtools-go/internal/toolutil"
+)
+
+// renderTree flattens a tree into "path[/][!]" lines; "/" marks directories and
+// "!" truncated ones.
+func renderTree(n TreeNode, prefix string, out *[]string) {
+	for _, c := range n.Children {
+		p := prefix + c.Name
+		line := p
+		if c.IsDir {
+			line += "/"
+		}
+		if c.Truncated {
+			line += "!"
+		}
+		*out = append(*out, line)
+		renderTree(c, p+"/", out)
+	}
+}
+
+func TestDirTree(t *testing.T) {
+	tmpDir := t.TempDir()
+	for _, d := range []string{"a/deep/deeper", "b", "empty"} {
+		if err := os.MkdirAll(filepath.Join(tmpDir, d), 0o755); err != nil {
+			t.Fatalf("mkdir %s: %v", d, err)
+		}
+	}
+	for _, f := range []string{"top.txt", "a/one.txt", "a/deep/two.txt", "a/deep/deeper/three.txt", "b/x.go"} {
+		if err := os.WriteFile(filepath.Join(tmpDir, f), []byte("x"), 0o600); err != nil {
+			t.Fatalf("write %s: %v", f, err)
+		}
+	}
+
+	tests := []struct {
+		name          string
+		args          DirTreeArgs
+		want          []string
+		wantTruncated bool
+		wantErrSub    string
+	}{
+		{
+			name: "full tree",
+			args: DirTreeArgs{Root: tmpDir},
+			want: []string{
+				"a/", "a/deep/", "a/deep/deeper/", "a/deep/deeper/three.txt", "a/deep/two.txt", "a/one.txt",
+				"b/", "b/x.go", "empty/", "top.txt",
+			},
+		},
+		{
+			name:          "max depth",
+			args:          DirTreeArgs{Root: tmpDir, MaxDepth: 2},
+			want:          []string{"a/", "a/deep/!", "a/one.txt", "b/", "b/x.go", "empty/", "top.txt"},
+			wantTruncated: true,
+		},
+		{
+			name:          "max entries keeps upper levels",
+			args:          DirTreeArgs{Root: tmpDir, MaxEntries: 6},
+			want:          []string{"a/", "a/deep/!", "a/one.txt", "b/!", "empty/", "top.txt"},
+			wantTruncated: true,
+		},
+		{
+			name:       "negative max depth",
+			args:       DirTreeArgs{Root: tmpDir, MaxDepth: -1},
+			wantErrSub: "maxDepth",
+		},
+		{
+			name:       "max entries too large",
+			args:       DirTreeArgs{Root: tmpDir, MaxEntries: maxDirTreeMaxEntries + 1},
+			wantErrSub: "maxEntries",
+		},
+		{
+			name:       "not a directory",
+			args:       DirTreeArgs{Root: filepath.Join(tmpDir, "top.txt")},
+			wantErrSub: "not a directory",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			out, err := DirTree(t.Context(), tc.args)
+			if tc.wantErrSub != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.wantErrSub) {
+					t.Fatalf("error = %v, want substring %q", err, tc.wantErrSub)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("DirTree: %v", err)
+			}
+			var got []string
+			renderTree(out.Tree, "", &got)
+			if strings.Join(got, "\n") != strings.Join(tc.want, "\n") {
+				t.Errorf("tree =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(tc.want, "\n"))
+			}
+			if out.EntryCount != len(tc.want) {
+				t.Errorf("EntryCount = %d, want %d", out.EntryCount, len(tc.want))
+			}
+			if out.Truncated != tc.wantTruncated {
+				t.Errorf("Truncated = %v, want %v", out.Truncated, tc.wantTruncated)
+			}
+			if !out.Tree.IsDir || out.Tree.Name != filepath.Base(tmpDir) {
+				t.Errorf("root node = %+v", out.Tree)
+			}
+		})
+	}
+}
+
+func TestDirTree_Symlinks(t *testing.T) {
+	if runtime.GOOS == toolutil.GOOSWindows {
+		t.Skip("symlinks require privileges on windows")
+	}
+	tmpDir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(tmpDir, "real"), 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(tmpDir, "real", "f.txt"), []byte("x"), 0o600); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	// A link back to the root forms a cycle when followed.
+	if err := os.Symlink(tmpDir, filepath.Join(tmpDir, "real", "loop")); err != nil {
+		t.Fatalf("symlink: %v", err)
+	}
+	// A self-referencing link cannot be resolved (ELOOP) and is listed as a file.
+	if err := os.Symlink("self", filepath.Join(tmpDir, "real", "self")); err != nil {
+		t.Fatalf("symlink: %v", err)
+	}
+
+	out, err := DirTree(t.Context(), DirTreeArgs{Root: tmpDir})
+	if err != nil {
+		t.Fatalf("DirTree: %v", err)
+	}
+	var got []string
+	renderTree(out.Tree, "", &got)
+	want := []string{"real/", "real/f.txt", "real/loop/", "real/self"}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("tree = %v, want %v", got, want)
+	}
+	if loop := out.Tree.Children[0].Children[1]; !loop.IsSymlink {
+		t.Errorf("loop node = %+v, want IsSymlink", loop)
+	}
+
+	out, err = DirTree(t.Context(), DirTreeArgs{Root: tmpDir, FollowSymlinks: true})
+	if err != nil {
+		t.Fatalf("DirTree follow: %v", err)
+	}
+	got = nil
+	renderTree(out.Tree, "", &got)
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("followed tree = %v, want %v (cycle expanded once)", got, want)
+	}
+}
@@ -0,0 +1,163 @@
+package fstool
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/flexigpt/llmtools-go/internal/fileutil"
+	"github.com/flexigpt/llmtools-go/internal/jsonutil"
+	"github.com/flexigpt/llmtools-go/internal/toolutil"
+	"github.com/flexigpt/llmtools-go/spec"
+)
+
+const formatJSONFileFuncID spec.FuncID = "github.com/flexigpt/llmtools-go/fstool/formatjsonfile.FormatJSONFile"
+
+const (
+	FormatJSONModePretty = "pretty"
+	FormatJSONModeMinify = "minify"
+
+	defaultFormatJSONIndent = "  "
+	maxFormatJSONIndent     = 8
+)
+
+var formatJSONFileTool = spec.Tool{
+	SchemaVersion: spec.SchemaVersion,
+	ID:            "01a13d8f-078d-711b-8b44-f36a010edcd9",
+	Slug:          "formatjsonfile",
+	Version:       "v1.0.0",
+	DisplayName:   "Format JSON file",
+	Description:   "Pretty-print or minify a local JSON file in place. Key order is preserved unless sortKeys is set; the write is atomic.",
+	Tags:          []string{"fs", "json", "write"},
+
+	ArgSchema: spec.JSONSchema(`{
+"$schema": "http://json-schema.org/draft-07/schema#",
+"type": "object",
+"properties": {
+	"path": {
+		"type": "string",
+		"description": "Absolute or relative path of the JSON file to format."
+	},
+	"mode": {
+		"type": "string",
+		"enum": ["pretty", "minify"],
+		"description": "pretty indents the document; minify removes all insignificant whitespace.",
+		"default": "pretty"
+	},
+	"indent": {
+		"type": "string",
+		"description": "Indent unit for pretty mode: spaces or tabs, at most 8 characters.",
+		"default": "  "
+	},
+	"sortKeys": {
+		"type": "boolean",
+		"description": "Sort object keys at every level instead of keeping their original order.",
+		"default": false
+	}
+},
+"required": ["path"],
+"additionalProperties": false
+}`),
+	GoImpl: spec.GoToolImpl{FuncID: formatJSONFileFuncID},
+
+	CreatedAt:  spec.SchemaStartTime,
+	ModifiedAt: spec.SchemaStartTime,
+}
+
+func FormatJSONFileTool() spec.Tool {
+	return toolutil.CloneTool(formatJSONFileTool)
+}
+
+type FormatJSONFileArgs struct {
+	Path     string `json:"path"`
+	Mode     string `json:"mode,omitempty"`   // "pretty" (default) | "minify"
+	Indent   string `json:"indent,omitempty"` // pretty only; default two spaces
+	SortKeys bool   `json:"sortKeys,omitempty"`
+}
+
+type FormatJSONFileOut struct {
+	Path        string `json:"path"`
+	Mode        string `json:"mode"`
+	BytesBefore int    `json:"bytesBefore"`
+	BytesAfter  int    `json:"bytesAfter"`
+	Changed     bool   `json:"changed"` // false => file already formatted; not rewritten
+}
+
+// FormatJSONFile reformats the JSON file at Path in place. Pretty output ends
+// with a newline; minified output does not. The file is only rewritten if its
+// bytes change.
+func FormatJSONFile(ctx context.Context, args FormatJSONFileArgs) (*FormatJSONFileOut, error) {
+	return toolutil.WithRecoveryResp(func() (*FormatJSONFileOut, error) {
+		return formatJSONFile(ctx, args)
+	})
+}
+
+func formatJSONFile(ctx context.Context, args FormatJSONFileArgs) (*FormatJSONFileOut, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if err := confinePaths(&args.Path); err != nil {
+		return nil, err
+	}
+	mode := strings.ToLower(strings.TrimSpace(args.Mode))
+	if mode == "" {
+		mode = FormatJSONModePretty
+	}
+	var indent string
+	switch mode {
+	case FormatJSONModePretty:
+		indent = args.Indent
+		if indent == "" {
+			indent = defaultFormatJSONIndent
+		}
+		if len(indent) > maxFormatJSONIndent || strings.Trim(indent, " \t") != "" {
+			return nil, fmt.Errorf("indent must be 1-%d spaces or tabs", maxFormatJSONIndent)
+		}
+	case FormatJSONModeMinify:
+		if args.Indent != "" {
+			return nil, errors.New("indent is only valid in pretty mode")
+		}
+	default:
+		return nil, fmt.Errorf(`mode must be %q or %q`, FormatJSONModePretty, FormatJSONModeMinify)
+	}
+
+	p, data, err := readJSONInput(args.Path)
+	if err != nil {
+		return nil, err
+	}
+	st, err := os.Stat(p)
+	if err != nil {
+		return nil, err
+	}
+
+	formatted, err := jsonutil.FormatJSON(data, indent, args.SortKeys)
+	if err != nil {
+		return nil, err
+	}
+	if mode == FormatJSONModePretty {
+		formatted = append(formatted, '\n')
+	}
+	if maxWrite := toolutil.GetLimits().MaxFileWrite; int64(len(formatted)) > maxWrite {
+		return nil, fmt.Errorf("content too large (%d bytes; max %d)", len(formatted), maxWrite)
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	changed := !bytes.Equal(data, formatted)
+	if changed {
+		if err := fileutil.WriteFileAtomicBytes(p, formatted, st.Mode().Perm(), true); err != nil {
+			return nil, err
+		}
+	}
+	return &FormatJSONFileOut{
+		Path:        p,
+		Mode:        mode,
+		BytesBefore: len(data),
+		BytesAfter:  len(formatted),
+		Changed:     changed,
+	}, nil
+}
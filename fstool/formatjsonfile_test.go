@@ -0,0 +1,155 @@
+package fstool
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestFormatJSONFile(t *testing.T) {
+	tmpDir := t.TempDir()
+	write := func(name, content string) string {
+		t.Helper()
+		p := filepath.Join(tmpDir, name)
+		if err := os.WriteFile(p, []byte(content), 0o640); err != nil {
+			t.Fatalf("write %s: %v", name, err)
+		}
+		return p
+	}
+
+	const compact = `{"b":1,"a":[true,null]}`
+	const pretty2 = "{\n  \"b\": 1,\n  \"a\": [\n    true,\n    null\n  ]\n}\n"
+
+	tests := []struct {
+		name          string
+		ctx           func(t *testing.T) context.Context
+		content       string
+		args          FormatJSONFileArgs
+		want          string
+		wantChanged   bool
+		wantErrIs     error
+		wantErrSubstr string
+	}{
+		{
+			name:        "pretty by default keeps key order",
+			content:     compact,
+			args:        FormatJSONFileArgs{},
+			want:        pretty2,
+			wantChanged: true,
+		},
+		{
+			name:        "minify",
+			content:     pretty2,
+			args:        FormatJSONFileArgs{Mode: FormatJSONModeMinify},
+			want:        compact,
+			wantChanged: true,
+		},
+		{
+			name:        "pretty with tab indent and sorted keys",
+			content:     compact,
+			args:        FormatJSONFileArgs{Mode: FormatJSONModePretty, Indent: "\t", SortKeys: true},
+			want:        "{\n\t\"a\": [\n\t\ttrue,\n\t\tnull\n\t],\n\t\"b\": 1\n}\n",
+			wantChanged: true,
+		},
+		{
+			name:    "already formatted is not rewritten",
+			content: pretty2,
+			args:    FormatJSONFileArgs{Mode: FormatJSONModePretty},
+			want:    pretty2,
+		},
+		{name: "invalid JSON", content: `{"a":`, wantErrSubstr: "decode JSON"},
+		{name: "bad mode", content: compact, args: FormatJSONFileArgs{Mode: "ugly"}, wantErrSubstr: "mode must be"},
+		{name: "bad indent", content: compact, args: FormatJSONFileArgs{Indent: "--"}, wantErrSubstr: "indent must be"},
+		{
+			name:          "indent with minify",
+			content:       compact,
+			args:          FormatJSONFileArgs{Mode: FormatJSONModeMinify, Indent: " "},
+			wantErrSubstr: "only valid in pretty mode",
+		},
+		{
+			name: "canceled context",
+			ctx: func(t *testing.T) context.Context {
+				t.Helper()
+				ctx, cancel := context.WithCancel(t.Context())
+				cancel()
+				return ctx
+			},
+			content:   compact,
+			wantErrIs: context.Canceled,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := t.Context()
+			if tt.ctx != nil {
+				ctx = tt.ctx(t)
+			}
+			p := write(strings.ReplaceAll(tt.name, " ", "-")+".json", tt.content)
+			args := tt.args
+			args.Path = p
+
+			out, err := FormatJSONFile(ctx, args)
+			if tt.wantErrIs != nil || tt.wantErrSubstr != "" {
+				if err == nil {
+					t.Fatalf("expected error, got %+v", out)
+				}
+				if tt.wantErrIs != nil && !errors.Is(err, tt.wantErrIs) {
+					t.Fatalf("expected errors.Is(%v), got %v", tt.wantErrIs, err)
+				}
+				if tt.wantErrSubstr != "" && !strings.Contains(err.Error(), tt.wantErrSubstr) {
+					t.Fatalf("error %q does not contain %q", err, tt.wantErrSubstr)
+				}
+				if got, _ := os.ReadFile(p); string(got) != tt.content {
+					t.Fatalf("file modified on error: %q", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			got, err := os.ReadFile(p)
+			if err != nil {
+				t.Fatalf("read back: %v", err)
+			}
+			if string(got) != tt.want {
+				t.Fatalf("content:\n%q\nwant:\n%q", got, tt.want)
+			}
+			if out.Changed != tt.wantChanged {
+				t.Fatalf("Changed=%v want %v", out.Changed, tt.wantChanged)
+			}
+			if out.BytesBefore != len(tt.content) || out.BytesAfter != len(tt.want) {
+				t.Fatalf("bytes before/after = %d/%d, want %d/%d",
+					out.BytesBefore, out.BytesAfter, len(tt.content), len(tt.want))
+			}
+			if st, err := os.Stat(p); err != nil || st.Mode().Perm() != 0o640 {
+				t.Fatalf("permissions not preserved: %v %v", st, err)
+			}
+		})
+	}
+}
+
+func TestFormatJSONFile_RoundTrip(t *testing.T) {
+	const original = `{"z":{"k":[1,2.50,"<tag>"]},"a":"é"}`
+	p := filepath.Join(t.TempDir(), "doc.json")
+	if err := os.WriteFile(p, []byte(original), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := FormatJSONFile(t.Context(), FormatJSONFileArgs{Path: p, Mode: FormatJSONModePretty}); err != nil {
+		t.Fatalf("pretty: %v", err)
+	}
+	if _, err := FormatJSONFile(t.Context(), FormatJSONFileArgs{Path: p, Mode: FormatJSONModeMinify}); err != nil {
+		t.Fatalf("minify: %v", err)
+	}
+	got, err := os.ReadFile(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != original {
+		t.Fatalf("round trip changed document:\ngot  %s\nwant %s", got, original)
+	}
+}
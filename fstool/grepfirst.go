@@ -0,0 +1,135 @@
+package fstool
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/flexigpt/llmtools-go/internal/fileutil"
+	"github.com/flexigpt/llmtools-go/internal/toolutil"
+	"github.com/flexigpt/llmtools-go/spec"
+)
+
+const grepFirstFuncID spec.FuncID = "github.com/flexigpt/llmtools-go/fstool/grepfirst.GrepFirst"
+
+var grepFirstTool = spec.Tool{
+	SchemaVersion: spec.SchemaVersion,
+	ID:            "01a13d83-aa6b-72d0-9f8a-79db536cb6d9",
+	Slug:          "grepfirst",
+	Version:       "v1.0.0",
+	DisplayName:   "Grep first match",
+	Description:   "Return the first line of a text file matching an RE2 regular expression, with its line number and capture groups. Stops reading at the first match.",
+	Tags:          []string{"fs", "search"},
+
+	ArgSchema: spec.JSONSchema(`{
+"$schema": "http://json-schema.org/draft-07/schema#",
+"type": "object",
+"properties": {
+	"path": {
+		"type": "string",
+		"description": "Absolute or relative path of the file to scan."
+	},
+	"pattern": {
+		"type": "string",
+		"description": "RE2 regular expression matched against each line (without its line ending)."
+	}
+},
+"required": ["path", "pattern"],
+"additionalProperties": false
+}`),
+	GoImpl: spec.GoToolImpl{FuncID: grepFirstFuncID},
+
+	CreatedAt:  spec.SchemaStartTime,
+	ModifiedAt: spec.SchemaStartTime,
+}
+
+func GrepFirstTool() spec.Tool {
+	return toolutil.CloneTool(grepFirstTool)
+}
+
+type GrepFirstArgs struct {
+	Path    string `json:"path"`
+	Pattern string `json:"pattern"` // RE2
+}
+
+type GrepFirstOut struct {
+	Path       string   `json:"path"`
+	Found      bool     `json:"found"`
+	LineNumber int      `json:"lineNumber,omitempty"` // 1-based
+	Line       string   `json:"line,omitempty"`
+	Groups     []string `json:"groups,omitempty"` // capture groups 1..n; unmatched groups are ""
+}
+
+// GrepFirst streams Path line by line and returns the first line matching Pattern.
+// No match is not an error: it returns Found=false.
+func GrepFirst(ctx context.Context, args GrepFirstArgs) (*GrepFirstOut, error) {
+	return toolutil.WithRecoveryResp(func() (*GrepFirstOut, error) {
+		return grepFirst(ctx, args)
+	})
+}
+
+func grepFirst(ctx context.Context, args GrepFirstArgs) (*GrepFirstOut, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if err := confinePaths(&args.Path); err != nil {
+		return nil, err
+	}
+	if args.Pattern == "" {
+		return nil, errors.New("pattern is required")
+	}
+	re, err := regexp.Compile(args.Pattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid pattern: %w", err)
+	}
+
+	path := strings.TrimSpace(args.Path)
+	if path == "" {
+		return nil, fileutil.ErrInvalidPath
+	}
+	p, err := fileutil.NormalizePath(path)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := fileutil.RequireExistingRegularFileNoSymlink(p); err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("path does not exist: %s", p)
+		}
+		return nil, err
+	}
+
+	f, err := os.Open(p)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	sc := bufio.NewScanner(f)
+	sc.Buffer(make([]byte, 0, 64*1024), int(toolutil.GetLimits().MaxTextProcessing))
+	for lineNo := 1; sc.Scan(); lineNo++ {
+		// Checking every line is cheap relative to I/O and keeps huge files cancelable.
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		line := strings.TrimSuffix(sc.Text(), "\r")
+		m := re.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		return &GrepFirstOut{
+			Path:       p,
+			Found:      true,
+			LineNumber: lineNo,
+			Line:       line,
+			Groups:     m[1:],
+		}, nil
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	return &GrepFirstOut{Path: p, Found: false}, nil
+}
@@ -0,0 +1,86 @@
+package fstool
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestGrepFirst(t *testing.T) {
+	tmpDir := t.TempDir()
+	p := filepath.Join(tmpDir, "version.txt")
+	content := "# header\r\nname = tool\r\nversion = 1.2.3\r\nversion = 9.9.9\r\n"
+	if err := os.WriteFile(p, []byte(content), 0o600); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		ctx       func(t *testing.T) context.Context
+		args      GrepFirstArgs
+		want      GrepFirstOut
+		wantErr   bool
+		wantErrIs error
+	}{
+		{
+			name: "first occurrence with groups",
+			args: GrepFirstArgs{Path: p, Pattern: `^version = (\d+)\.(\d+)\.(\d+)$`},
+			want: GrepFirstOut{Path: p, Found: true, LineNumber: 3, Line: "version = 1.2.3", Groups: []string{"1", "2", "3"}},
+		},
+		{
+			name: "no groups",
+			args: GrepFirstArgs{Path: p, Pattern: `tool`},
+			want: GrepFirstOut{Path: p, Found: true, LineNumber: 2, Line: "name = tool", Groups: []string{}},
+		},
+		{
+			name: "no match",
+			args: GrepFirstArgs{Path: p, Pattern: `^license`},
+			want: GrepFirstOut{Path: p, Found: false},
+		},
+		{name: "invalid pattern errors", args: GrepFirstArgs{Path: p, Pattern: `(`}, wantErr: true},
+		{name: "empty pattern errors", args: GrepFirstArgs{Path: p}, wantErr: true},
+		{name: "missing file errors", args: GrepFirstArgs{Path: filepath.Join(tmpDir, "nope"), Pattern: "x"}, wantErr: true},
+		{name: "directory errors", args: GrepFirstArgs{Path: tmpDir, Pattern: "x"}, wantErr: true},
+		{
+			name: "canceled context",
+			ctx: func(t *testing.T) context.Context {
+				t.Helper()
+				ctx, cancel := context.WithCancel(t.Context())
+				cancel()
+				return ctx
+			},
+			args:      GrepFirstArgs{Path: p, Pattern: "x"},
+			wantErr:   true,
+			wantErrIs: context.Canceled,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := t.Context()
+			if tt.ctx != nil {
+				ctx = tt.ctx(t)
+			}
+			out, err := GrepFirst(ctx, tt.args)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected error, got %+v", out)
+				}
+				if tt.wantErrIs != nil && !errors.Is(err, tt.wantErrIs) {
+					t.Fatalf("expected errors.Is(%v), got %v", tt.wantErrIs, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if out.Path != tt.want.Path || out.Found != tt.want.Found || out.LineNumber != tt.want.LineNumber ||
+				out.Line != tt.want.Line || !slices.Equal(out.Groups, tt.want.Groups) {
+				t.Fatalf("got %+v want %+v", *out, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,135 @@
+package fstool
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/flexigpt/llmtools-go/internal/fileutil"
+	"github.com/flexigpt/llmtools-go/internal/toolutil"
+	"github.com/flexigpt/llmtools-go/spec"
+)
+
+const headFileFuncID spec.FuncID = "github.com/flexigpt/llmtools-go/fstool/headfile.HeadFile"
+
+const (
+	defaultHeadLines = 10
+	maxHeadLines     = 10000
+)
+
+var headFileTool = spec.Tool{
+	SchemaVersion: spec.SchemaVersion,
+	ID:            "01a13dc5-5ccc-7ee6-9d63-22cd944335ab",
+	Slug:          "headfile",
+	Version:       "v1.0.0",
+	DisplayName:   "Head of file",
+	Description:   "Return the first lines of a text file, reading only as much of the file as needed. Useful for previewing large logs.",
+	Tags:          []string{"fs", "read"},
+
+	ArgSchema: spec.JSONSchema(`{
+"$schema": "http://json-schema.org/draft-07/schema#",
+"type": "object",
+"properties": {
+	"path": {
+		"type": "string",
+		"description": "Absolute or relative path of the file to read."
+	},
+	"lines": {
+		"type": "integer",
+		"minimum": 0,
+		"maximum": 10000,
+		"description": "Number of lines to return. 0 uses the default of 10.",
+		"default": 10
+	}
+},
+"required": ["path"],
+"additionalProperties": false
+}`),
+	GoImpl: spec.GoToolImpl{FuncID: headFileFuncID},
+
+	CreatedAt:  spec.SchemaStartTime,
+	ModifiedAt: spec.SchemaStartTime,
+}
+
+func HeadFileTool() spec.Tool {
+	return toolutil.CloneTool(headFileTool)
+}
+
+type HeadFileArgs struct {
+	Path  string `json:"path"`
+	Lines int    `json:"lines,omitempty"` // default 10
+}
+
+type HeadFileOut struct {
+	Path    string   `json:"path"`
+	Lines   []string `json:"lines"`   // without line endings
+	HasMore bool     `json:"hasMore"` // the file continues past the returned lines
+}
+
+// HeadFile returns the first Lines lines of Path (fewer if the file is shorter).
+// A final line without a trailing newline is returned like any other. The file
+// is streamed in small buffered reads that stop once the lines (and the next
+// one, to set HasMore) have been read.
+func HeadFile(ctx context.Context, args HeadFileArgs) (*HeadFileOut, error) {
+	return toolutil.WithRecoveryResp(func() (*HeadFileOut, error) {
+		return headFile(ctx, args)
+	})
+}
+
+func headFile(ctx context.Context, args HeadFileArgs) (*HeadFileOut, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if err := confinePaths(&args.Path); err != nil {
+		return nil, err
+	}
+	if args.Lines < 0 || args.Lines > maxHeadLines {
+		return nil, fmt.Errorf("lines must be between 0 and %d", maxHeadLines)
+	}
+	n := args.Lines
+	if n == 0 {
+		n = defaultHeadLines
+	}
+
+	path := strings.TrimSpace(args.Path)
+	if path == "" {
+		return nil, fileutil.ErrInvalidPath
+	}
+	p, err := fileutil.NormalizePath(path)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := fileutil.RequireExistingRegularFileNoSymlink(p); err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("path does not exist: %s", p)
+		}
+		return nil, err
+	}
+
+	f, err := os.Open(p)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	sc := bufio.NewScanner(f)
+	sc.Buffer(make([]byte, 0, 4096), int(toolutil.GetLimits().MaxTextProcessing))
+	out := &HeadFileOut{Path: p, Lines: []string{}}
+	for len(out.Lines) < n && sc.Scan() {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		out.Lines = append(out.Lines, sc.Text())
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	if len(out.Lines) == n {
+		// A following line, even one too long to scan, means there is more.
+		out.HasMore = sc.Scan() || sc.Err() != nil
+	}
+	return out, nil
+}
@@ -0,0 +1,112 @@
+package fstool
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestHeadFile(t *testing.T) {
+	tmpDir := t.TempDir()
+	write := func(name, content string) string {
+		t.Helper()
+		p := filepath.Join(tmpDir, name)
+		if err := os.WriteFile(p, []byte(content), 0o600); err != nil {
+			t.Fatalf("write %s: %v", name, err)
+		}
+		return p
+	}
+	var sb strings.Builder
+	for i := 1; i <= 1000; i++ {
+		fmt.Fprintf(&sb, "line %d\n", i)
+	}
+	big := write("big.log", sb.String())
+	short := write("short.txt", "one\r\ntwo\r\n")
+	noNewline := write("nonl.txt", "first\nlast")
+	empty := write("empty.txt", "")
+
+	tests := []struct {
+		name        string
+		ctx         func(t *testing.T) context.Context
+		args        HeadFileArgs
+		wantLines   []string
+		wantHasMore bool
+		wantErr     bool
+		wantErrIs   error
+	}{
+		{
+			name:        "five lines of a large file",
+			args:        HeadFileArgs{Path: big, Lines: 5},
+			wantLines:   []string{"line 1", "line 2", "line 3", "line 4", "line 5"},
+			wantHasMore: true,
+		},
+		{
+			name: "default line count",
+			args: HeadFileArgs{Path: big},
+			wantLines: []string{
+				"line 1", "line 2", "line 3", "line 4", "line 5",
+				"line 6", "line 7", "line 8", "line 9", "line 10",
+			},
+			wantHasMore: true,
+		},
+		{name: "fewer lines than requested", args: HeadFileArgs{Path: short, Lines: 5}, wantLines: []string{"one", "two"}},
+		{name: "exactly all lines", args: HeadFileArgs{Path: short, Lines: 2}, wantLines: []string{"one", "two"}},
+		{name: "no trailing newline", args: HeadFileArgs{Path: noNewline, Lines: 5}, wantLines: []string{"first", "last"}},
+		{
+			name:        "stops before unterminated last line",
+			args:        HeadFileArgs{Path: noNewline, Lines: 1},
+			wantLines:   []string{"first"},
+			wantHasMore: true,
+		},
+		{name: "empty file", args: HeadFileArgs{Path: empty, Lines: 3}, wantLines: []string{}},
+		{name: "negative lines", args: HeadFileArgs{Path: big, Lines: -1}, wantErr: true},
+		{name: "too many lines", args: HeadFileArgs{Path: big, Lines: maxHeadLines + 1}, wantErr: true},
+		{name: "missing file", args: HeadFileArgs{Path: filepath.Join(tmpDir, "missing")}, wantErr: true},
+		{name: "directory", args: HeadFileArgs{Path: tmpDir}, wantErr: true},
+		{
+			name: "canceled context",
+			ctx: func(t *testing.T) context.Context {
+				t.Helper()
+				ctx, cancel := context.WithCancel(t.Context())
+				cancel()
+				return ctx
+			},
+			args:      HeadFileArgs{Path: big},
+			wantErr:   true,
+			wantErrIs: context.Canceled,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := t.Context()
+			if tc.ctx != nil {
+				ctx = tc.ctx(t)
+			}
+			out, err := HeadFile(ctx, tc.args)
+			if tc.wantErr {
+				if err == nil {
+					t.Fatalf("expected error, got %+v", out)
+				}
+				if tc.wantErrIs != nil && !errors.Is(err, tc.wantErrIs) {
+					t.Fatalf("expected errors.Is(%v), got %v", tc.wantErrIs, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !slices.Equal(out.Lines, tc.wantLines) {
+				t.Errorf("lines=%q want %q", out.Lines, tc.wantLines)
+			}
+			if out.HasMore != tc.wantHasMore {
+				t.Errorf("HasMore=%v want %v", out.HasMore, tc.wantHasMore)
+			}
+		})
+	}
+}
@@ -0,0 +1,151 @@
+package fstool
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/flexigpt/llmtools-go/internal/fileutil"
+	"github.com/flexigpt/llmtools-go/internal/jsonutil"
+	"github.com/flexigpt/llmtools-go/internal/toolutil"
+	"github.com/flexigpt/llmtools-go/spec"
+)
+
+const jsonArrayUpdateFuncID spec.FuncID = "github.com/flexigpt/llmtools-go/fstool/jsonarrayupdate.JSONArrayUpdate"
+
+var jsonArrayUpdateTool = spec.Tool{
+	SchemaVersion: spec.SchemaVersion,
+	ID:            "01a13d81-3135-72bf-b6c3-e0152136ea0d",
+	Slug:          "jsonarrayupdate",
+	Version:       "v1.0.0",
+	DisplayName:   "Update JSON array",
+	Description:   "Append an element to, and/or remove matching elements from, an array inside a JSON file. The rest of the file's formatting is preserved and the write is atomic.",
+	Tags:          []string{"fs", "json", "write"},
+
+	ArgSchema: spec.JSONSchema(`{
+"$schema": "http://json-schema.org/draft-07/schema#",
+"type": "object",
+"properties": {
+	"path": {
+		"type": "string",
+		"description": "Absolute path of the JSON file to update."
+	},
+	"pointer": {
+		"type": "string",
+		"description": "RFC 6901 JSON Pointer to the target array, e.g. \"/plugins\". Empty string means the document root.",
+		"default": ""
+	},
+	"append": {
+		"description": "JSON value appended as one new element."
+	},
+	"removeMatching": {
+		"description": "JSON value; every element equal to it is removed (applied before append)."
+	}
+},
+"required": ["path"],
+"additionalProperties": false
+}`),
+	GoImpl: spec.GoToolImpl{FuncID: jsonArrayUpdateFuncID},
+
+	CreatedAt:  spec.SchemaStartTime,
+	ModifiedAt: spec.SchemaStartTime,
+}
+
+func JSONArrayUpdateTool() spec.Tool {
+	return toolutil.CloneTool(jsonArrayUpdateTool)
+}
+
+type JSONArrayUpdateArgs struct {
+	Path           string          `json:"path"`
+	Pointer        string          `json:"pointer,omitempty"` // "" => root
+	Append         json.RawMessage `json:"append,omitempty"`
+	RemoveMatching json.RawMessage `json:"removeMatching,omitempty"`
+}
+
+type JSONArrayUpdateOut struct {
+	Path     string `json:"path"`
+	Pointer  string `json:"pointer"`
+	Removed  int    `json:"removed"`
+	Appended int    `json:"appended"`
+	Length   int    `json:"length"` // array length after the update
+}
+
+// JSONArrayUpdate edits the array at Pointer in the JSON file at Path: elements
+// equal to RemoveMatching are removed, then Append is added as one element.
+// Only the array's text changes; newline style and indentation are preserved.
+func JSONArrayUpdate(ctx context.Context, args JSONArrayUpdateArgs) (*JSONArrayUpdateOut, error) {
+	return toolutil.WithRecoveryResp(func() (*JSONArrayUpdateOut, error) {
+		return jsonArrayUpdate(ctx, args)
+	})
+}
+
+func jsonArrayUpdate(ctx context.Context, args JSONArrayUpdateArgs) (*JSONArrayUpdateOut, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if err := confinePaths(&args.Path); err != nil {
+		return nil, err
+	}
+	hasAppend := isJSONValueSet(args.Append)
+	hasRemove := isJSONValueSet(args.RemoveMatching)
+	if !hasAppend && !hasRemove {
+		return nil, errors.New("at least one of append or removeMatching is required")
+	}
+
+	p, err := fileutil.NormalizeAbsPath(strings.TrimSpace(args.Path))
+	if err != nil {
+		return nil, err
+	}
+	tf, err := fileutil.ReadTextFileUTF8(p, toolutil.GetLimits().MaxTextProcessing)
+	if err != nil {
+		return nil, err
+	}
+
+	var appendValue, removeMatching json.RawMessage
+	if hasAppend {
+		appendValue = args.Append
+	}
+	if hasRemove {
+		removeMatching = args.RemoveMatching
+	}
+	// Operate on LF text; Render restores the file's newline style.
+	src := strings.Join(tf.Lines, "\n")
+	updated, res, err := jsonutil.UpdateArray([]byte(src), args.Pointer, appendValue, removeMatching)
+	if err != nil {
+		return nil, err
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	tf.Lines = strings.Split(string(updated), "\n")
+	data := []byte(tf.Render())
+	if maxWrite := toolutil.GetLimits().MaxFileWrite; int64(len(data)) > maxWrite {
+		return nil, fmt.Errorf("content too large (%d bytes; max %d)", len(data), maxWrite)
+	}
+	if err := fileutil.WriteFileAtomicBytes(tf.Path, data, tf.Perm, true); err != nil {
+		return nil, err
+	}
+
+	appended := 0
+	if hasAppend {
+		appended = 1
+	}
+	return &JSONArrayUpdateOut{
+		Path:     tf.Path,
+		Pointer:  args.Pointer,
+		Removed:  res.Removed,
+		Appended: appended,
+		Length:   res.Length,
+	}, nil
+}
+
+// isJSONValueSet reports whether an optional raw JSON argument was provided.
+// An explicit null counts as unset.
+func isJSONValueSet(raw json.RawMessage) bool {
+	v := bytes.TrimSpace(raw)
+	return len(v) > 0 && !bytes.Equal(v, []byte("null"))
+}
@@ -0,0 +1,153 @@
+package fstool
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestJSONArrayUpdate(t *testing.T) {
+	const cfg = "{\n  \"name\": \"app\",\n  \"plugins\": [\n    \"lint\",\n    \"fmt\"\n  ]\n}\n"
+
+	tests := []struct {
+		name          string
+		ctx           func(t *testing.T) context.Context
+		initial       string
+		args          func(p string) JSONArrayUpdateArgs
+		want          string
+		wantOut       JSONArrayUpdateOut
+		wantErr       bool
+		wantErrIs     error
+		wantErrSubstr string
+	}{
+		{
+			name:    "append element",
+			initial: cfg,
+			args: func(p string) JSONArrayUpdateArgs {
+				return JSONArrayUpdateArgs{Path: p, Pointer: "/plugins", Append: json.RawMessage(`"test"`)}
+			},
+			want:    "{\n  \"name\": \"app\",\n  \"plugins\": [\n    \"lint\",\n    \"fmt\",\n    \"test\"\n  ]\n}\n",
+			wantOut: JSONArrayUpdateOut{Pointer: "/plugins", Appended: 1, Length: 3},
+		},
+		{
+			name:    "remove matching element",
+			initial: cfg,
+			args: func(p string) JSONArrayUpdateArgs {
+				return JSONArrayUpdateArgs{Path: p, Pointer: "/plugins", RemoveMatching: json.RawMessage(`"lint"`)}
+			},
+			want:    "{\n  \"name\": \"app\",\n  \"plugins\": [\n    \"fmt\"\n  ]\n}\n",
+			wantOut: JSONArrayUpdateOut{Pointer: "/plugins", Removed: 1, Length: 1},
+		},
+		{
+			name:    "CRLF newlines preserved",
+			initial: strings.ReplaceAll(cfg, "\n", "\r\n"),
+			args: func(p string) JSONArrayUpdateArgs {
+				return JSONArrayUpdateArgs{Path: p, Pointer: "/plugins", RemoveMatching: json.RawMessage(`"fmt"`)}
+			},
+			want:    "{\r\n  \"name\": \"app\",\r\n  \"plugins\": [\r\n    \"lint\"\r\n  ]\r\n}\r\n",
+			wantOut: JSONArrayUpdateOut{Pointer: "/plugins", Removed: 1, Length: 1},
+		},
+		{
+			name:    "pointer to non-array errors",
+			initial: cfg,
+			args: func(p string) JSONArrayUpdateArgs {
+				return JSONArrayUpdateArgs{Path: p, Pointer: "/name", Append: json.RawMessage(`1`)}
+			},
+			wantErr:       true,
+			wantErrSubstr: "does not resolve to an array",
+		},
+		{
+			name:    "unresolvable pointer errors",
+			initial: cfg,
+			args: func(p string) JSONArrayUpdateArgs {
+				return JSONArrayUpdateArgs{Path: p, Pointer: "/missing", Append: json.RawMessage(`1`)}
+			},
+			wantErr:       true,
+			wantErrSubstr: "not found",
+		},
+		{
+			name:    "no operation errors",
+			initial: cfg,
+			args: func(p string) JSONArrayUpdateArgs {
+				return JSONArrayUpdateArgs{Path: p, Pointer: "/plugins"}
+			},
+			wantErr:       true,
+			wantErrSubstr: "at least one of",
+		},
+		{
+			name: "missing file errors",
+			args: func(p string) JSONArrayUpdateArgs {
+				return JSONArrayUpdateArgs{Path: p, Append: json.RawMessage(`1`)}
+			},
+			wantErr: true,
+		},
+		{
+			name: "canceled context",
+			ctx: func(t *testing.T) context.Context {
+				t.Helper()
+				ctx, cancel := context.WithCancel(t.Context())
+				cancel()
+				return ctx
+			},
+			initial: cfg,
+			args: func(p string) JSONArrayUpdateArgs {
+				return JSONArrayUpdateArgs{Path: p, Pointer: "/plugins", Append: json.RawMessage(`1`)}
+			},
+			wantErr:   true,
+			wantErrIs: context.Canceled,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := filepath.Join(t.TempDir(), "cfg.json")
+			if tt.initial != "" {
+				if err := os.WriteFile(p, []byte(tt.initial), 0o600); err != nil {
+					t.Fatalf("write: %v", err)
+				}
+			}
+			ctx := t.Context()
+			if tt.ctx != nil {
+				ctx = tt.ctx(t)
+			}
+
+			out, err := JSONArrayUpdate(ctx, tt.args(p))
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected error, got %+v", out)
+				}
+				if tt.wantErrIs != nil && !errors.Is(err, tt.wantErrIs) {
+					t.Fatalf("expected errors.Is(%v), got %v", tt.wantErrIs, err)
+				}
+				if tt.wantErrSubstr != "" && !strings.Contains(err.Error(), tt.wantErrSubstr) {
+					t.Fatalf("error %q does not contain %q", err, tt.wantErrSubstr)
+				}
+				if tt.initial != "" {
+					if got, _ := os.ReadFile(p); string(got) != tt.initial {
+						t.Fatalf("file modified on error: %q", got)
+					}
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			want := tt.wantOut
+			want.Path = p
+			if *out != want {
+				t.Fatalf("out=%+v want %+v", *out, want)
+			}
+			got, err := os.ReadFile(p)
+			if err != nil {
+				t.Fatalf("read: %v", err)
+			}
+			if string(got) != tt.want {
+				t.Fatalf("file mismatch:\ngot:  %q\nwant: %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -30,7 +30,12 @@ var listDirectoryTool = spec.Tool{
 	},
 	"pattern": {
 		"type": "string",
-		"description": "Optional glob pattern (e.g. \"*.txt\") to filter results."
+		"description": "Optional glob pattern (e.g. \"*.txt\") to filter results. Patterns with \"**\" (e.g. \"**/*.go\") match recursively."
+	},
+	"followSymlinks": {
+		"type": "boolean",
+		"description": "For recursive (\"**\") patterns, also descend into symlinked directories. Symlink cycles are visited once.",
+		"default": false
 	}
 },
 "required": [],
@@ -49,13 +54,17 @@ func ListDirectoryTool() spec.Tool {
 type ListDirectoryArgs struct {
 	Path    string `json:"path,omitempty"`    // default "."
 	Pattern string `json:"pattern,omitempty"` // Optional glob
+	// FollowSymlinks descends into symlinked directories for "**" patterns;
+	// each directory is read once, so cycles terminate. Not allowed with SetRoot.
+	FollowSymlinks bool `json:"followSymlinks,omitempty"`
 }
 type ListDirectoryOut struct {
 	Entries []string `json:"entries"`
 }
 
 // ListDirectory lists files / dirs in Path. If Pattern is supplied, the
-// results are filtered via filepath.Match.
+// results are filtered via filepath.Match; a Pattern containing "**" matches
+// recursively and returns slash-separated paths relative to Path.
 func ListDirectory(ctx context.Context, args ListDirectoryArgs) (*ListDirectoryOut, error) {
 	return toolutil.WithRecoveryResp(func() (*ListDirectoryOut, error) {
 		return listDirectory(ctx, args)
@@ -66,7 +75,13 @@ func listDirectory(ctx context.Context, args ListDirectoryArgs) (*ListDirectoryO
 	if err := ctx.Err(); err != nil {
 		return nil, err
 	}
-	entries, err := fileutil.ListDirectory(args.Path, args.Pattern)
+	if err := confinePaths(&args.Path); err != nil {
+		return nil, err
+	}
+	if err := checkWalkFollowSymlinks(args.FollowSymlinks); err != nil {
+		return nil, err
+	}
+	entries, err := fileutil.ListDirectoryCtx(ctx, args.Path, args.Pattern, args.FollowSymlinks)
 	if err != nil {
 		return nil, err
 	}
@@ -5,7 +5,11 @@ import (
 	"errors"
 	"os"
 	"path/filepath"
+	"runtime"
+	"slices"
 	"testing"
+
+	"github.com/flexigpt/llmtools-go/internal/toolutil"
 )
 
 // TestListDirectory covers happy, error, and pattern cases for ListDirectory.
@@ -124,3 +128,40 @@ func TestListDirectory(t *testing.T) {
 		})
 	}
 }
+
+func TestListDirectory_FollowSymlinks(t *testing.T) {
+	if runtime.GOOS == toolutil.GOOSWindows {
+		t.Skip("symlinks require elevated privileges on Windows")
+	}
+	tmpDir := t.TempDir()
+	outside := t.TempDir()
+	if err := os.WriteFile(filepath.Join(outside, "x.go"), []byte("package x"), 0o600); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if err := os.Symlink(outside, filepath.Join(tmpDir, "ext")); err != nil {
+		t.Fatalf("symlink: %v", err)
+	}
+	if err := os.Symlink(tmpDir, filepath.Join(outside, "back")); err != nil {
+		t.Fatalf("symlink: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		follow bool
+		want   []string
+	}{
+		{name: "symlinked dir listed but not descended", want: []string{"ext"}},
+		{name: "followed through a cycle", follow: true, want: []string{"ext", "ext/back", "ext/x.go"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, err := ListDirectory(t.Context(), ListDirectoryArgs{Path: tmpDir, Pattern: "**", FollowSymlinks: tt.follow})
+			if err != nil {
+				t.Fatalf("ListDirectory: %v", err)
+			}
+			if !slices.Equal(out.Entries, tt.want) {
+				t.Fatalf("entries=%v want %v", out.Entries, tt.want)
+			}
+		})
+	}
+}
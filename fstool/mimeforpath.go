@@ -79,6 +79,9 @@ func mimeForPath(ctx context.Context, args MIMEForPathArgs) (*MIMEForPathOut, er
 	if err := ctx.Err(); err != nil {
 		return nil, err
 	}
+	if err := confinePaths(&args.Path); err != nil {
+		return nil, err
+	}
 	path := strings.TrimSpace(args.Path)
 	if path == "" {
 		return nil, fileutil.ErrInvalidPath
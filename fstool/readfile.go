@@ -2,8 +2,11 @@ package fstool
 
 import (
 	"context"
+	"encoding/base64"
 	"errors"
 	"fmt"
+	"io"
+	"io/fs"
 	"mime"
 	"os"
 	"path/filepath"
@@ -18,6 +21,13 @@ import (
 
 const readFileFuncID spec.FuncID = "github.com/flexigpt/llmtools-go/fstool/readfile.ReadFile"
 
+// readEncodingBase64 is like fileutil.ReadEncodingBinary but never turns images
+// into image outputs.
+const readEncodingBase64 fileutil.ReadEncoding = "base64"
+
+// maxReadFileSymlinkHops bounds symlink chains followed when FollowSymlinks is set.
+const maxReadFileSymlinkHops = 8
+
 var readFileTool = spec.Tool{
 	SchemaVersion: spec.SchemaVersion,
 	ID:            "018fe0f4-b8cd-7e55-82d5-9df0bd70e4ba",
@@ -37,9 +47,19 @@ var readFileTool = spec.Tool{
 	},
 	"encoding": {
 		"type": "string",
-		"enum": ["text", "binary"],
-		"description": "Return mode: \"text\" reads file as UTF-8, \"binary\" returns base64 string.",
+		"enum": ["text", "binary", "base64"],
+		"description": "Return mode: \"text\" reads file as UTF-8, \"binary\" returns base64 string (as an image output for images), \"base64\" always returns a base64 file output.",
 		"default": "text"
+	},
+	"normalizeWhitespace": {
+		"type": "boolean",
+		"description": "Text mode only: collapse runs of spaces, join words hyphenated across line breaks, turn form feeds into blank lines, and trim trailing spaces.",
+		"default": false
+	},
+	"followSymlinks": {
+		"type": "boolean",
+		"description": "If true and path is a symlink, read its target (up to 8 links deep). Symlinked parent directories are always refused.",
+		"default": false
 	}
 },
 "required": ["path"],
@@ -57,11 +77,22 @@ func ReadFileTool() spec.Tool {
 
 type ReadFileArgs struct {
 	Path     string `json:"path"`               // required
-	Encoding string `json:"encoding,omitempty"` // "text" (default) | "binary"
+	Encoding string `json:"encoding,omitempty"` // "text" (default) | "binary" | "base64"
+
+	// NormalizeWhitespace tidies text output (see fileutil.NormalizeWhitespace).
+	// Ignored for binary reads; raw text is returned by default.
+	NormalizeWhitespace bool `json:"normalizeWhitespace,omitempty"`
+
+	// FollowSymlinks reads the target when Path itself is a symlink, following at
+	// most maxReadFileSymlinkHops links. The resolved path gets the same checks as
+	// a direct read (no symlinked parent directories, regular file, size cap).
+	// When false, a symlink Path is refused.
+	FollowSymlinks bool `json:"followSymlinks,omitempty"`
 }
 
 // ReadFile reads a file from disk and returns its contents.
-// If Encoding == "binary" the output is base64-encoded.
+// If Encoding == "binary" the output is base64-encoded, as an image output for
+// images; "base64" always returns a base64 file output, whatever the MIME type.
 func ReadFile(ctx context.Context, args ReadFileArgs) ([]spec.ToolStoreOutputUnion, error) {
 	return toolutil.WithRecoveryResp(func() ([]spec.ToolStoreOutputUnion, error) {
 		return readFile(ctx, args)
@@ -72,13 +103,12 @@ func readFile(ctx context.Context, args ReadFileArgs) ([]spec.ToolStoreOutputUni
 	if err := ctx.Err(); err != nil {
 		return nil, err
 	}
-	// Normalize and validate encoding.
-	enc := fileutil.ReadEncoding(strings.ToLower(strings.TrimSpace(args.Encoding)))
-	if enc == "" {
-		enc = fileutil.ReadEncodingText
+	if err := confinePaths(&args.Path); err != nil {
+		return nil, err
 	}
-	if enc != fileutil.ReadEncodingText && enc != fileutil.ReadEncodingBinary {
-		return nil, errors.New(`encoding must be "text" or "binary"`)
+	enc, err := readFileEncoding(args.Encoding)
+	if err != nil {
+		return nil, err
 	}
 
 	path := strings.TrimSpace(args.Path)
@@ -91,6 +121,15 @@ func readFile(ctx context.Context, args ReadFileArgs) ([]spec.ToolStoreOutputUni
 		return nil, err
 	}
 
+	if args.FollowSymlinks {
+		p, err = fileutil.ResolveFileSymlinks(p, maxReadFileSymlinkHops)
+		if err != nil {
+			return nil, err
+		}
+	} else if lst, lerr := os.Lstat(p); lerr == nil && lst.Mode()&os.ModeSymlink != 0 {
+		return nil, fmt.Errorf("refusing to read through symlink %s (set followSymlinks to read its target)", p)
+	}
+
 	// Refuse symlink traversal (file and parent dirs), and require regular file.
 	st, err := fileutil.RequireExistingRegularFileNoSymlink(p)
 	if err != nil {
@@ -99,36 +138,162 @@ func readFile(ctx context.Context, args ReadFileArgs) ([]spec.ToolStoreOutputUni
 		}
 		return nil, err
 	}
-	if st.Size() > toolutil.MaxFileReadBytes {
+	maxRead := toolutil.GetLimits().MaxFileRead
+	if st.Size() > maxRead {
 		return nil, fmt.Errorf(
 			"file %q is too large to read (%d bytes; max %d)",
-			p, st.Size(), toolutil.MaxFileReadBytes,
+			p, st.Size(), maxRead,
 		)
 	}
 
 	// Detect MIME / extension where possible.
 	mimeType, extMode, _, mimeErr := fileutil.MIMEForLocalFile(p)
+	return readFileOutputs(readSource{
+		path:     p,
+		baseName: filepath.Base(p),
+		mimeType: mimeType,
+		extMode:  extMode,
+		mimeErr:  mimeErr,
+		read: func(enc fileutil.ReadEncoding) (string, error) {
+			return fileutil.ReadFile(p, enc, maxRead)
+		},
+		pdfText: func() (string, error) {
+			// Extraction itself is limited to maxRead via LimitedReader.
+			return pdfutil.ExtractPDFTextSafe(ctx, p, int(maxRead))
+		},
+	}, enc, args.NormalizeWhitespace)
+}
+
+// ReadFileFS is ReadFile for a file in fsys, e.g. an embed.FS or an
+// fstest.MapFS, so it can be used without a real filesystem. Path is an fs.FS
+// path (slash-separated, unrooted, e.g. "docs/a.txt"). SetRoot does not apply
+// and FollowSymlinks is ignored: fsys decides what is reachable. The encoding,
+// MIME, size and UTF-8 rules match ReadFile.
+func ReadFileFS(ctx context.Context, fsys fs.FS, args ReadFileArgs) ([]spec.ToolStoreOutputUnion, error) {
+	return toolutil.WithRecoveryResp(func() ([]spec.ToolStoreOutputUnion, error) {
+		return readFileFS(ctx, fsys, args)
+	})
+}
+
+func readFileFS(ctx context.Context, fsys fs.FS, args ReadFileArgs) ([]spec.ToolStoreOutputUnion, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if fsys == nil {
+		return nil, errors.New("fsys is required")
+	}
+	enc, err := readFileEncoding(args.Encoding)
+	if err != nil {
+		return nil, err
+	}
+	p := strings.TrimSpace(args.Path)
+	if !fs.ValidPath(p) || p == "." {
+		return nil, fileutil.ErrInvalidPath
+	}
+
+	st, err := fs.Stat(fsys, p)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, fmt.Errorf("path does not exist: %s", p)
+		}
+		return nil, err
+	}
+	if !st.Mode().IsRegular() {
+		return nil, fmt.Errorf("not a regular file: %s", p)
+	}
+	maxRead := toolutil.GetLimits().MaxFileRead
+	if st.Size() > maxRead {
+		return nil, fmt.Errorf(
+			"file %q is too large to read (%d bytes; max %d)",
+			p, st.Size(), maxRead,
+		)
+	}
+
+	f, err := fsys.Open(p)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	data, err := io.ReadAll(io.LimitReader(f, maxRead+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > maxRead {
+		return nil, fmt.Errorf("file %q exceeds maximum allowed size (%d bytes)", p, maxRead)
+	}
+
+	mimeType, extMode, _ := fileutil.MIMEForContent(p, data)
+	return readFileOutputs(readSource{
+		path:     p,
+		baseName: filepath.Base(p),
+		mimeType: mimeType,
+		extMode:  extMode,
+		read: func(enc fileutil.ReadEncoding) (string, error) {
+			if enc == fileutil.ReadEncodingBinary {
+				return base64.StdEncoding.EncodeToString(data), nil
+			}
+			return string(data), nil
+		},
+		pdfText: func() (string, error) {
+			return pdfutil.ExtractPDFTextBytes(ctx, data, int(maxRead))
+		},
+	}, enc, args.NormalizeWhitespace)
+}
+
+// readFileEncoding normalizes and validates ReadFileArgs.Encoding.
+func readFileEncoding(s string) (fileutil.ReadEncoding, error) {
+	enc := fileutil.ReadEncoding(strings.ToLower(strings.TrimSpace(s)))
+	if enc == "" {
+		enc = fileutil.ReadEncodingText
+	}
+	if enc != fileutil.ReadEncodingText && enc != fileutil.ReadEncodingBinary && enc != readEncodingBase64 {
+		return "", errors.New(`encoding must be "text", "binary" or "base64"`)
+	}
+	return enc, nil
+}
+
+// readSource is a file located and size-checked by ReadFile or ReadFileFS.
+type readSource struct {
+	path     string // for messages
+	baseName string // output file/image name
+	mimeType fileutil.MIMEType
+	extMode  fileutil.ExtensionMode
+	mimeErr  error // MIME detection failure, if any
+
+	read    func(enc fileutil.ReadEncoding) (string, error) // text or base64 contents
+	pdfText func() (string, error)
+}
+
+// readFileOutputs turns src into ReadFile's outputs for the given encoding.
+func readFileOutputs(
+	src readSource,
+	enc fileutil.ReadEncoding,
+	normalizeWhitespace bool,
+) ([]spec.ToolStoreOutputUnion, error) {
+	p := src.path
 	ext := strings.ToLower(filepath.Ext(p))
 
 	isPDFByExt := ext == string(fileutil.ExtPDF)
-	isPDFByMime := mimeErr == nil && mimeType == fileutil.MIMEApplicationPDF
+	isPDFByMime := src.mimeErr == nil && src.mimeType == fileutil.MIMEApplicationPDF
 	isPDF := isPDFByExt || isPDFByMime
 
 	if enc == fileutil.ReadEncodingText {
 		// For non-PDFs, fail if MIME detection fails (conservative).
 		// For PDFs, allow text extraction even if MIME sniffing fails,
 		// as long as the extension is .pdf.
-		if !isPDF && mimeErr != nil {
-			return nil, fmt.Errorf("cannot read %q as text (MIME detection failed: %w)", p, mimeErr)
+		if !isPDF && src.mimeErr != nil {
+			return nil, fmt.Errorf("cannot read %q as text (MIME detection failed: %w)", p, src.mimeErr)
 		}
 
 		if isPDF {
 			// PDF: use the same extraction logic as attachments.
-			// Extraction itself is limited to toolutil.MaxFileReadBytes via LimitedReader.
-			text, err := pdfutil.ExtractPDFTextSafe(ctx, p, toolutil.MaxFileReadBytes)
+			text, err := src.pdfText()
 			if err != nil {
 				return nil, err
 			}
+			if normalizeWhitespace {
+				text = fileutil.NormalizeWhitespace(text)
+			}
 
 			return []spec.ToolStoreOutputUnion{
 				{
@@ -141,7 +306,7 @@ func readFile(ctx context.Context, args ReadFileArgs) ([]spec.ToolStoreOutputUni
 		}
 
 		// Non‑PDF: only allow clearly text-like files.
-		if extMode != fileutil.ExtensionModeText {
+		if src.extMode != fileutil.ExtensionModeText {
 			return nil, fmt.Errorf(
 				"cannot read non-text file %q as text; use encoding \"binary\" instead",
 				p,
@@ -149,7 +314,7 @@ func readFile(ctx context.Context, args ReadFileArgs) ([]spec.ToolStoreOutputUni
 		}
 
 		// Normal text file: read and validate UTF‑8.
-		data, err := fileutil.ReadFile(p, fileutil.ReadEncodingText, toolutil.MaxFileReadBytes)
+		data, err := src.read(fileutil.ReadEncodingText)
 		if err != nil {
 			return nil, err
 		}
@@ -159,6 +324,9 @@ func readFile(ctx context.Context, args ReadFileArgs) ([]spec.ToolStoreOutputUni
 				p,
 			)
 		}
+		if normalizeWhitespace {
+			data = fileutil.NormalizeWhitespace(data)
+		}
 
 		return []spec.ToolStoreOutputUnion{
 			{
@@ -171,20 +339,20 @@ func readFile(ctx context.Context, args ReadFileArgs) ([]spec.ToolStoreOutputUni
 	}
 
 	// Binary mode: base64-encode and return, like before.
-	data, err := fileutil.ReadFile(p, fileutil.ReadEncodingBinary, toolutil.MaxFileReadBytes)
+	data, err := src.read(fileutil.ReadEncodingBinary)
 	if err != nil {
 		return nil, err
 	}
 
-	baseName := filepath.Base(p)
+	baseName := src.baseName
 	if baseName == "" {
 		baseName = "file"
 	}
 
-	// Prefer MIMEForLocalFile result if available; otherwise fall back to extension mapping.
+	// Prefer the detected MIME type if available; otherwise fall back to extension mapping.
 	var mt string
-	if mimeErr == nil && mimeType != "" {
-		mt = string(mimeType)
+	if src.mimeErr == nil && src.mimeType != "" {
+		mt = string(src.mimeType)
 	} else {
 		if ext == "" {
 			ext = strings.ToLower(filepath.Ext(baseName))
@@ -195,7 +363,7 @@ func readFile(ctx context.Context, args ReadFileArgs) ([]spec.ToolStoreOutputUni
 		mt = "application/octet-stream"
 	}
 
-	if strings.HasPrefix(mt, "image/") {
+	if strings.HasPrefix(mt, "image/") && enc != readEncodingBase64 {
 		return []spec.ToolStoreOutputUnion{
 			{
 				Kind: spec.ToolStoreOutputKindImage,
@@ -10,6 +10,7 @@ import (
 	"runtime"
 	"strings"
 	"testing"
+	"testing/fstest"
 
 	"github.com/flexigpt/llmtools-go/internal/toolutil"
 )
@@ -97,6 +98,30 @@ func TestReadFile(t *testing.T) {
 			wantKind: "text",
 			wantText: "hello world",
 		},
+		{
+			name: "read_text_normalize_whitespace",
+			args: func(t *testing.T) ReadFileArgs {
+				t.Helper()
+				tmp := t.TempDir()
+				p := filepath.Join(tmp, "file.txt")
+				writeFile(t, p, []byte("an   exam-\nple  \nline"))
+				return ReadFileArgs{Path: p, NormalizeWhitespace: true}
+			},
+			wantKind: "text",
+			wantText: "an example\nline",
+		},
+		{
+			name: "read_text_raw_by_default_keeps_code_spacing",
+			args: func(t *testing.T) ReadFileArgs {
+				t.Helper()
+				tmp := t.TempDir()
+				p := filepath.Join(tmp, "main.go")
+				writeFile(t, p, []byte("x   := a-\nb  \n\tif y {  }\n"))
+				return ReadFileArgs{Path: p}
+			},
+			wantKind: "text",
+			wantText: "x   := a-\nb  \n\tif y {  }\n",
+		},
 		{
 			name: "read_binary_file_as_binary_returns_file_union",
 			args: func(t *testing.T) ReadFileArgs {
@@ -125,6 +150,20 @@ func TestReadFile(t *testing.T) {
 			wantMIMEPref:  "image/",
 			wantBinary:    []byte{0x11, 0x22, 0x33},
 		},
+		{
+			name: "read_png_as_base64_returns_file_union",
+			args: func(t *testing.T) ReadFileArgs {
+				t.Helper()
+				tmp := t.TempDir()
+				p := filepath.Join(tmp, "image.png")
+				writeFile(t, p, []byte{0x11, 0x22, 0x33})
+				return ReadFileArgs{Path: p, Encoding: "Base64"}
+			},
+			wantKind:     "file",
+			wantFileName: "image.png",
+			wantFileMIME: "image/png",
+			wantBinary:   []byte{0x11, 0x22, 0x33},
+		},
 		{
 			name: "invalid_encoding_errors",
 			args: func(t *testing.T) ReadFileArgs {
@@ -135,7 +174,7 @@ func TestReadFile(t *testing.T) {
 				return ReadFileArgs{Path: p, Encoding: "foo"}
 			},
 			wantErr:       true,
-			wantErrSubstr: `encoding must be "text" or "binary"`,
+			wantErrSubstr: `encoding must be "text", "binary" or "base64"`,
 		},
 		{
 			name: "read_non_text_as_text_errors",
@@ -177,7 +216,46 @@ func TestReadFile(t *testing.T) {
 				}
 				return ReadFileArgs{Path: link, Encoding: "text"}
 			},
-			wantErr: true,
+			wantErr:       true,
+			wantErrSubstr: "set followSymlinks",
+		},
+		{
+			name: "symlink_file_followed_when_enabled",
+			args: func(t *testing.T) ReadFileArgs {
+				t.Helper()
+				if runtime.GOOS == toolutil.GOOSWindows {
+					t.Skip("symlink often requires privileges on Windows")
+				}
+				tmp := t.TempDir()
+				writeFile(t, filepath.Join(tmp, "target.txt"), []byte("through the link"))
+				link := filepath.Join(tmp, "link.txt")
+				if err := os.Symlink("target.txt", link); err != nil {
+					t.Skipf("symlink not available: %v", err)
+				}
+				return ReadFileArgs{Path: link, FollowSymlinks: true}
+			},
+			wantKind: "text",
+			wantText: "through the link",
+		},
+		{
+			name: "symlink_loop_errors_when_following",
+			args: func(t *testing.T) ReadFileArgs {
+				t.Helper()
+				if runtime.GOOS == toolutil.GOOSWindows {
+					t.Skip("symlink often requires privileges on Windows")
+				}
+				tmp := t.TempDir()
+				a, b := filepath.Join(tmp, "a"), filepath.Join(tmp, "b")
+				if err := os.Symlink(b, a); err != nil {
+					t.Skipf("symlink not available: %v", err)
+				}
+				if err := os.Symlink(a, b); err != nil {
+					t.Skipf("symlink not available: %v", err)
+				}
+				return ReadFileArgs{Path: a, FollowSymlinks: true}
+			},
+			wantErr:       true,
+			wantErrSubstr: "too many levels of symlinks",
 		},
 		{
 			name: "symlink_parent_component_is_refused",
@@ -289,3 +367,121 @@ func TestReadFile(t *testing.T) {
 		})
 	}
 }
+
+// TestReadFile_LoweredReadLimit is not parallel: it changes process-wide limits.
+func TestReadFile_LoweredReadLimit(t *testing.T) {
+	p := filepath.Join(t.TempDir(), "big.txt")
+	if err := os.WriteFile(p, bytes.Repeat([]byte("a"), 2048), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ReadFile(t.Context(), ReadFileArgs{Path: p}); err != nil {
+		t.Fatalf("ReadFile with default limits: %v", err)
+	}
+
+	toolutil.SetLimits(toolutil.Limits{MaxFileRead: 1024})
+	t.Cleanup(func() { toolutil.SetLimits(toolutil.DefaultLimits()) })
+
+	_, err := ReadFile(t.Context(), ReadFileArgs{Path: p})
+	if err == nil || !strings.Contains(err.Error(), "too large to read (2048 bytes; max 1024)") {
+		t.Fatalf("ReadFile with lowered limit: got %v want too large error", err)
+	}
+}
+
+func TestReadFileFS(t *testing.T) {
+	t.Parallel()
+	png := []byte("\x89PNG\r\n\x1a\n\x00\x00")
+	fsys := fstest.MapFS{
+		"notes.txt":      {Data: []byte("hello   world\n")},
+		"docs/readme.md": {Data: []byte("# Title\n")},
+		"img/pic.png":    {Data: png},
+		"blob":           {Data: []byte{0x00, 0x01, 0x02, 0xff}},
+		"bad.txt":        {Data: []byte{0xff, 0xfe, 'a'}},
+		"dir/x.txt":      {Data: []byte("x")},
+	}
+
+	tests := []struct {
+		name          string
+		args          ReadFileArgs
+		wantText      string
+		wantImageName string
+		wantFileName  string
+		wantData      []byte
+		wantErrSubstr string
+	}{
+		{name: "text", args: ReadFileArgs{Path: "notes.txt"}, wantText: "hello   world\n"},
+		{name: "nested path", args: ReadFileArgs{Path: "docs/readme.md"}, wantText: "# Title\n"},
+		{
+			name:     "normalized text",
+			args:     ReadFileArgs{Path: "notes.txt", NormalizeWhitespace: true},
+			wantText: "hello world\n",
+		},
+		{
+			name:          "binary image",
+			args:          ReadFileArgs{Path: "img/pic.png", Encoding: "binary"},
+			wantImageName: "pic.png",
+			wantData:      png,
+		},
+		{
+			name:         "binary sniffed file",
+			args:         ReadFileArgs{Path: "blob", Encoding: "binary"},
+			wantFileName: "blob",
+			wantData:     []byte{0x00, 0x01, 0x02, 0xff},
+		},
+		{name: "binary refused as text", args: ReadFileArgs{Path: "blob"}, wantErrSubstr: "non-text file"},
+		{name: "invalid utf-8", args: ReadFileArgs{Path: "bad.txt"}, wantErrSubstr: "not valid UTF-8"},
+		{name: "missing", args: ReadFileArgs{Path: "nope.txt"}, wantErrSubstr: "does not exist"},
+		{name: "directory", args: ReadFileArgs{Path: "dir"}, wantErrSubstr: "not a regular file"},
+		{name: "rooted path invalid", args: ReadFileArgs{Path: "/notes.txt"}, wantErrSubstr: "invalid path"},
+		{name: "dot-dot path invalid", args: ReadFileArgs{Path: "docs/../notes.txt"}, wantErrSubstr: "invalid path"},
+		{
+			name:          "bad encoding",
+			args:          ReadFileArgs{Path: "notes.txt", Encoding: "utf16"},
+			wantErrSubstr: "encoding must be",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			outs, err := ReadFileFS(t.Context(), fsys, tc.args)
+			if tc.wantErrSubstr != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.wantErrSubstr) {
+					t.Fatalf("error = %v, want substring %q", err, tc.wantErrSubstr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(outs) != 1 {
+				t.Fatalf("expected 1 output, got %d", len(outs))
+			}
+			o := outs[0]
+			switch {
+			case tc.wantText != "":
+				if o.TextItem == nil || o.TextItem.Text != tc.wantText {
+					t.Fatalf("text output = %+v, want %q", o, tc.wantText)
+				}
+			case tc.wantImageName != "":
+				if o.ImageItem == nil || o.ImageItem.ImageName != tc.wantImageName ||
+					o.ImageItem.ImageMIME != "image/png" {
+					t.Fatalf("image output = %+v", o)
+				}
+				if got, _ := base64.StdEncoding.DecodeString(o.ImageItem.ImageData); !bytes.Equal(got, tc.wantData) {
+					t.Fatalf("image data = %v, want %v", got, tc.wantData)
+				}
+			default:
+				if o.FileItem == nil || o.FileItem.FileName != tc.wantFileName {
+					t.Fatalf("file output = %+v", o)
+				}
+				if got, _ := base64.StdEncoding.DecodeString(o.FileItem.FileData); !bytes.Equal(got, tc.wantData) {
+					t.Fatalf("file data = %v, want %v", got, tc.wantData)
+				}
+			}
+		})
+	}
+
+	if _, err := ReadFileFS(t.Context(), nil, ReadFileArgs{Path: "notes.txt"}); err == nil {
+		t.Fatal("expected error for nil fsys")
+	}
+}
@@ -0,0 +1,167 @@
+package fstool
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/flexigpt/llmtools-go/internal/fileutil"
+	"github.com/flexigpt/llmtools-go/internal/toolutil"
+	"github.com/flexigpt/llmtools-go/spec"
+)
+
+const recentlyModifiedFuncID spec.FuncID = "github.com/flexigpt/llmtools-go/fstool/recentlymodified.RecentlyModified"
+
+const (
+	defaultRecentlyModifiedLimit = 50
+	maxRecentlyModifiedLimit     = 1000
+)
+
+var recentlyModifiedTool = spec.Tool{
+	SchemaVersion: spec.SchemaVersion,
+	ID:            "01a13d7d-8b5b-72ea-b80c-1a71dd29072c",
+	Slug:          "recentlymodified",
+	Version:       "v1.0.0",
+	DisplayName:   "Recently modified files",
+	Description:   "List files under a directory modified within a recent time window (e.g. \"24h\"), newest first.",
+	Tags:          []string{"fs", "list"},
+
+	ArgSchema: spec.JSONSchema(`{
+"$schema": "http://json-schema.org/draft-07/schema#",
+"type": "object",
+"properties": {
+	"root": {
+		"type": "string",
+		"description": "Directory to search recursively.",
+		"default": "."
+	},
+	"within": {
+		"type": "string",
+		"description": "Time window as a Go duration, e.g. \"90m\", \"24h\", \"168h\"."
+	},
+	"limit": {
+		"type": "integer",
+		"minimum": 1,
+		"maximum": 1000,
+		"description": "Maximum number of files to return.",
+		"default": 50
+	}
+},
+"required": ["within"],
+"additionalProperties": false
+}`),
+	GoImpl: spec.GoToolImpl{FuncID: recentlyModifiedFuncID},
+
+	CreatedAt:  spec.SchemaStartTime,
+	ModifiedAt: spec.SchemaStartTime,
+}
+
+func RecentlyModifiedTool() spec.Tool {
+	return toolutil.CloneTool(recentlyModifiedTool)
+}
+
+type RecentlyModifiedArgs struct {
+	Root   string        `json:"root,omitempty"` // default "."
+	Within time.Duration `json:"within"`         // JSON: duration string ("24h") or integer nanoseconds
+	Limit  int           `json:"limit,omitempty"`
+}
+
+// UnmarshalJSON accepts "within" as a Go duration string (what the schema
+// advertises) or as integer nanoseconds (what encoding/json emits for time.Duration).
+func (a *RecentlyModifiedArgs) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		Root   string          `json:"root,omitempty"`
+		Within json.RawMessage `json:"within"`
+		Limit  int             `json:"limit,omitempty"`
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&raw); err != nil {
+		return err
+	}
+
+	var within time.Duration
+	if w := bytes.TrimSpace(raw.Within); len(w) > 0 && !bytes.Equal(w, []byte("null")) {
+		if w[0] == '"' {
+			var s string
+			if err := json.Unmarshal(w, &s); err != nil {
+				return err
+			}
+			d, err := time.ParseDuration(strings.TrimSpace(s))
+			if err != nil {
+				return fmt.Errorf("invalid within: %w", err)
+			}
+			within = d
+		} else if err := json.Unmarshal(w, &within); err != nil {
+			return fmt.Errorf("invalid within: %w", err)
+		}
+	}
+
+	*a = RecentlyModifiedArgs{Root: raw.Root, Within: within, Limit: raw.Limit}
+	return nil
+}
+
+type RecentlyModifiedOut struct {
+	Root      string             `json:"root"`
+	Since     time.Time          `json:"since"`
+	Files     []DirectoryFileRef `json:"files"`
+	Truncated bool               `json:"truncated"` // more files matched than Limit
+}
+
+// RecentlyModified returns regular files under Root whose mtime falls within the
+// last Within, sorted by mtime descending and capped at Limit.
+// Symlinks (including symlinked directories) are skipped, never followed.
+func RecentlyModified(ctx context.Context, args RecentlyModifiedArgs) (*RecentlyModifiedOut, error) {
+	return toolutil.WithRecoveryResp(func() (*RecentlyModifiedOut, error) {
+		return recentlyModified(ctx, args)
+	})
+}
+
+func recentlyModified(ctx context.Context, args RecentlyModifiedArgs) (*RecentlyModifiedOut, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if err := confinePaths(&args.Root); err != nil {
+		return nil, err
+	}
+	if args.Within <= 0 {
+		return nil, errors.New("within must be a positive duration")
+	}
+	limit := args.Limit
+	if limit == 0 {
+		limit = defaultRecentlyModifiedLimit
+	}
+	if limit < 1 || limit > maxRecentlyModifiedLimit {
+		return nil, fmt.Errorf("limit must be between 1 and %d, got %d", maxRecentlyModifiedLimit, args.Limit)
+	}
+
+	root := strings.TrimSpace(args.Root)
+	if root == "" {
+		root = "."
+	}
+	p, err := fileutil.NormalizePath(root)
+	if err != nil {
+		return nil, err
+	}
+
+	since := toolutil.Now().Add(-args.Within).UTC()
+	refs, err := fileutil.ModifiedSince(ctx, p, since)
+	if err != nil {
+		return nil, err
+	}
+
+	out := &RecentlyModifiedOut{
+		Root:      p,
+		Since:     since,
+		Files:     make([]DirectoryFileRef, 0, min(len(refs), limit)),
+		Truncated: len(refs) > limit,
+	}
+	for i := range refs[:min(len(refs), limit)] {
+		out.Files = append(out.Files, *toDirectoryFileRef(&refs[i]))
+	}
+	return out, nil
+}
@@ -0,0 +1,158 @@
+package fstool
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRecentlyModified(t *testing.T) {
+	tmpDir := t.TempDir()
+	now := time.Now().Truncate(time.Second)
+
+	write := func(rel string, age time.Duration) string {
+		t.Helper()
+		p := filepath.Join(tmpDir, rel)
+		if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+			t.Fatalf("mkdir: %v", err)
+		}
+		if err := os.WriteFile(p, []byte(rel), 0o600); err != nil {
+			t.Fatalf("write %s: %v", rel, err)
+		}
+		mt := now.Add(-age)
+		if err := os.Chtimes(p, mt, mt); err != nil {
+			t.Fatalf("chtimes %s: %v", rel, err)
+		}
+		return p
+	}
+
+	a := write("a.txt", 5*time.Minute)
+	b := write("sub/b.txt", 20*time.Minute)
+	c := write("c.txt", 50*time.Minute)
+	write("old.txt", 3*time.Hour)
+	write("sub/older.txt", 72*time.Hour)
+
+	tests := []struct {
+		name          string
+		ctx           func(t *testing.T) context.Context
+		args          RecentlyModifiedArgs
+		want          []string
+		wantTruncated bool
+		wantErr       bool
+		wantErrIs     error
+	}{
+		{
+			name: "selects window newest first",
+			args: RecentlyModifiedArgs{Root: tmpDir, Within: time.Hour},
+			want: []string{a, b, c},
+		},
+		{
+			name:          "limit truncates",
+			args:          RecentlyModifiedArgs{Root: tmpDir, Within: time.Hour, Limit: 2},
+			want:          []string{a, b},
+			wantTruncated: true,
+		},
+		{
+			name: "narrow window",
+			args: RecentlyModifiedArgs{Root: tmpDir, Within: 10 * time.Minute},
+			want: []string{a},
+		},
+		{
+			name:    "zero window errors",
+			args:    RecentlyModifiedArgs{Root: tmpDir},
+			wantErr: true,
+		},
+		{
+			name:    "limit out of range errors",
+			args:    RecentlyModifiedArgs{Root: tmpDir, Within: time.Hour, Limit: maxRecentlyModifiedLimit + 1},
+			wantErr: true,
+		},
+		{
+			name:    "missing root errors",
+			args:    RecentlyModifiedArgs{Root: filepath.Join(tmpDir, "nope"), Within: time.Hour},
+			wantErr: true,
+		},
+		{
+			name: "canceled context",
+			ctx: func(t *testing.T) context.Context {
+				t.Helper()
+				ctx, cancel := context.WithCancel(t.Context())
+				cancel()
+				return ctx
+			},
+			args:      RecentlyModifiedArgs{Root: tmpDir, Within: time.Hour},
+			wantErr:   true,
+			wantErrIs: context.Canceled,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := t.Context()
+			if tt.ctx != nil {
+				ctx = tt.ctx(t)
+			}
+			out, err := RecentlyModified(ctx, tt.args)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected error, got %+v", out)
+				}
+				if tt.wantErrIs != nil && !errors.Is(err, tt.wantErrIs) {
+					t.Fatalf("expected errors.Is(%v), got %v", tt.wantErrIs, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if out.Truncated != tt.wantTruncated {
+				t.Fatalf("Truncated=%v want %v", out.Truncated, tt.wantTruncated)
+			}
+			if len(out.Files) != len(tt.want) {
+				t.Fatalf("got %d files %+v, want %d", len(out.Files), out.Files, len(tt.want))
+			}
+			for i, p := range tt.want {
+				if out.Files[i].Path != p {
+					t.Fatalf("Files[%d]=%q want %q", i, out.Files[i].Path, p)
+				}
+			}
+		})
+	}
+}
+
+func TestRecentlyModifiedArgs_UnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		name    string
+		in      string
+		want    time.Duration
+		wantErr string
+	}{
+		{name: "duration string", in: `{"within":"90m"}`, want: 90 * time.Minute},
+		{name: "integer nanoseconds", in: `{"within":3600000000000}`, want: time.Hour},
+		{name: "bad duration", in: `{"within":"soon"}`, wantErr: "invalid within"},
+		{name: "unknown field rejected", in: `{"within":"1h","extra":1}`, wantErr: "unknown field"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got RecentlyModifiedArgs
+			err := json.Unmarshal([]byte(tt.in), &got)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got.Within != tt.want {
+				t.Fatalf("Within=%v want %v", got.Within, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,189 @@
+package fstool
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/flexigpt/llmtools-go/internal/fileutil"
+	"github.com/flexigpt/llmtools-go/internal/toolutil"
+	"github.com/flexigpt/llmtools-go/spec"
+)
+
+const renameFilesFuncID spec.FuncID = "github.com/flexigpt/llmtools-go/fstool/renamefiles.RenameFiles"
+
+// maxRenameFiles bounds how many files a single RenameFiles call may rename.
+const maxRenameFiles = 1000
+
+var renameFilesTool = spec.Tool{
+	SchemaVersion: spec.SchemaVersion,
+	ID:            "01a13d8b-6631-7418-bf84-12c33ad72513",
+	Slug:          "renamefiles",
+	Version:       "v1.0.0",
+	DisplayName:   "Rename files",
+	Description:   "Bulk-rename files directly inside a directory by regex substitution on their base names. All target names are checked for collisions before anything is renamed; use dryRun to preview.",
+	Tags:          []string{"fs", "write"},
+
+	ArgSchema: spec.JSONSchema(`{
+"$schema": "http://json-schema.org/draft-07/schema#",
+"type": "object",
+"properties": {
+	"root": {
+		"type": "string",
+		"description": "Directory whose files are renamed (not recursive).",
+		"default": "."
+	},
+	"pattern": {
+		"type": "string",
+		"description": "RE2 regular expression matched against each file's base name, e.g. \"^IMG_(\\d+)\\.jpg$\"."
+	},
+	"replacement": {
+		"type": "string",
+		"description": "Replacement for the matched text; $1 or ${1} and ${name} refer to capture groups. Must not contain path separators."
+	},
+	"dryRun": {
+		"type": "boolean",
+		"description": "If true, report the planned renames without changing anything.",
+		"default": false
+	}
+},
+"required": ["pattern", "replacement"],
+"additionalProperties": false
+}`),
+	GoImpl: spec.GoToolImpl{FuncID: renameFilesFuncID},
+
+	CreatedAt:  spec.SchemaStartTime,
+	ModifiedAt: spec.SchemaStartTime,
+}
+
+func RenameFilesTool() spec.Tool {
+	return toolutil.CloneTool(renameFilesTool)
+}
+
+type RenameFilesArgs struct {
+	Root        string `json:"root,omitempty"` // default "."
+	Pattern     string `json:"pattern"`        // RE2, matched against base names
+	Replacement string `json:"replacement"`    // regexp.Expand syntax
+	DryRun      bool   `json:"dryRun,omitempty"`
+}
+
+type RenamePair struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+type RenameFilesOut struct {
+	Root    string       `json:"root"`
+	DryRun  bool         `json:"dryRun"`
+	Renames []RenamePair `json:"renames"` // planned (dry run) or applied, sorted by From
+}
+
+// RenameFiles renames the regular files directly under Root whose base name
+// matches Pattern, substituting Replacement. Every rename is planned first; if
+// two files map to the same name, or a target name already exists, nothing is
+// renamed and the collisions are listed in the error. Each rename refuses to
+// replace an existing file. Symlinks and subdirectories are left alone.
+func RenameFiles(ctx context.Context, args RenameFilesArgs) (*RenameFilesOut, error) {
+	return toolutil.WithRecoveryResp(func() (*RenameFilesOut, error) {
+		return renameFiles(ctx, args)
+	})
+}
+
+func renameFiles(ctx context.Context, args RenameFilesArgs) (*RenameFilesOut, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if err := confinePaths(&args.Root); err != nil {
+		return nil, err
+	}
+	if args.Pattern == "" {
+		return nil, errors.New("pattern is required")
+	}
+	re, err := regexp.Compile(args.Pattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid pattern: %w", err)
+	}
+
+	root := strings.TrimSpace(args.Root)
+	if root == "" {
+		root = "."
+	}
+	dir, err := fileutil.NormalizePath(root)
+	if err != nil {
+		return nil, err
+	}
+	if err := fileutil.VerifyDirNoSymlink(dir); err != nil {
+		return nil, err
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	existing := make(map[string]bool, len(entries))
+	var pairs []RenamePair
+	for _, e := range entries {
+		existing[e.Name()] = true
+		if !e.Type().IsRegular() || !re.MatchString(e.Name()) {
+			continue
+		}
+		to := re.ReplaceAllString(e.Name(), args.Replacement)
+		if to == e.Name() {
+			continue
+		}
+		if to == "" || to == "." || to == ".." || strings.ContainsAny(to, `/\`) {
+			return nil, fmt.Errorf("invalid target name %q for %q", to, e.Name())
+		}
+		pairs = append(pairs, RenamePair{From: e.Name(), To: to})
+	}
+	if len(pairs) > maxRenameFiles {
+		return nil, fmt.Errorf("too many files to rename (%d; max %d)", len(pairs), maxRenameFiles)
+	}
+	if err := checkRenameCollisions(pairs, existing); err != nil {
+		return nil, err
+	}
+
+	out := &RenameFilesOut{Root: dir, DryRun: args.DryRun, Renames: make([]RenamePair, 0, len(pairs))}
+	for i, rp := range pairs {
+		full := RenamePair{From: filepath.Join(dir, rp.From), To: filepath.Join(dir, rp.To)}
+		if !args.DryRun {
+			if err := ctx.Err(); err != nil {
+				return nil, fmt.Errorf("%w (%d of %d renames applied)", err, i, len(pairs))
+			}
+			if err := fileutil.RenameNoReplace(full.From, full.To); err != nil {
+				return nil, fmt.Errorf("rename %s: %w (%d of %d renames applied)", rp.From, err, i, len(pairs))
+			}
+		}
+		out.Renames = append(out.Renames, full)
+	}
+	return out, nil
+}
+
+// checkRenameCollisions reports targets claimed by several files, and targets
+// that already exist in the directory (including files that are themselves being
+// renamed, since applying such chains would depend on ordering).
+func checkRenameCollisions(pairs []RenamePair, existing map[string]bool) error {
+	sources := map[string][]string{}
+	for _, rp := range pairs {
+		sources[rp.To] = append(sources[rp.To], rp.From)
+	}
+	var problems []string
+	for to, froms := range sources {
+		switch {
+		case len(froms) > 1:
+			problems = append(problems, fmt.Sprintf("%s <- %s", to, strings.Join(froms, ", ")))
+		case existing[to]:
+			problems = append(problems, fmt.Sprintf("%s <- %s (target exists)", to, froms[0]))
+		}
+	}
+	if len(problems) == 0 {
+		return nil
+	}
+	slices.Sort(problems)
+	return fmt.Errorf("rename collisions: %s", strings.Join(problems, "; "))
+}
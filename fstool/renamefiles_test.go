@@ -0,0 +1,166 @@
+package fstool
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestRenameFiles(t *testing.T) {
+	t.Parallel()
+	const photoPattern = `^IMG_(\d+)\.jpg$`
+
+	setup := func(t *testing.T, names ...string) string {
+		t.Helper()
+		dir := t.TempDir()
+		for _, n := range names {
+			if err := os.WriteFile(filepath.Join(dir, n), []byte(n), 0o600); err != nil {
+				t.Fatalf("write %s: %v", n, err)
+			}
+		}
+		return dir
+	}
+	listDir := func(t *testing.T, dir string) []string {
+		t.Helper()
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			t.Fatalf("readdir: %v", err)
+		}
+		names := make([]string, 0, len(entries))
+		for _, e := range entries {
+			names = append(names, e.Name())
+		}
+		return names
+	}
+	photos := []string{"IMG_001.jpg", "IMG_002.jpg", "notes.txt", "IMG_x.jpg"}
+
+	tests := []struct {
+		name          string
+		files         []string
+		ctx           func(t *testing.T) context.Context
+		args          func(dir string) RenameFilesArgs
+		wantRenames   []RenamePair // base names
+		wantDir       []string     // directory listing afterwards
+		wantErr       bool
+		wantErrIs     error
+		wantErrSubstr string
+	}{
+		{
+			name:  "dry run plans without renaming",
+			files: photos,
+			args: func(dir string) RenameFilesArgs {
+				return RenameFilesArgs{Root: dir, Pattern: photoPattern, Replacement: "photo_$1.jpg", DryRun: true}
+			},
+			wantRenames: []RenamePair{{"IMG_001.jpg", "photo_001.jpg"}, {"IMG_002.jpg", "photo_002.jpg"}},
+			wantDir:     []string{"IMG_001.jpg", "IMG_002.jpg", "IMG_x.jpg", "notes.txt"},
+		},
+		{
+			name:  "renames matching files",
+			files: photos,
+			args: func(dir string) RenameFilesArgs {
+				return RenameFilesArgs{Root: dir, Pattern: photoPattern, Replacement: "photo_$1.jpg"}
+			},
+			wantRenames: []RenamePair{{"IMG_001.jpg", "photo_001.jpg"}, {"IMG_002.jpg", "photo_002.jpg"}},
+			wantDir:     []string{"IMG_x.jpg", "notes.txt", "photo_001.jpg", "photo_002.jpg"},
+		},
+		{
+			name:  "targets colliding with each other",
+			files: []string{"a_1.txt", "b_1.txt", "c_2.txt"},
+			args: func(dir string) RenameFilesArgs {
+				return RenameFilesArgs{Root: dir, Pattern: `^[a-z]_(\d)\.txt$`, Replacement: "file_$1.txt"}
+			},
+			wantErr:       true,
+			wantErrSubstr: "file_1.txt <- a_1.txt, b_1.txt",
+			wantDir:       []string{"a_1.txt", "b_1.txt", "c_2.txt"},
+		},
+		{
+			name:  "target already exists",
+			files: []string{"IMG_001.jpg", "photo_001.jpg"},
+			args: func(dir string) RenameFilesArgs {
+				return RenameFilesArgs{Root: dir, Pattern: photoPattern, Replacement: "photo_$1.jpg"}
+			},
+			wantErr:       true,
+			wantErrSubstr: "photo_001.jpg <- IMG_001.jpg (target exists)",
+			wantDir:       []string{"IMG_001.jpg", "photo_001.jpg"},
+		},
+		{
+			name:  "path separator in target errors",
+			files: photos,
+			args: func(dir string) RenameFilesArgs {
+				return RenameFilesArgs{Root: dir, Pattern: photoPattern, Replacement: "../$1.jpg"}
+			},
+			wantErr:       true,
+			wantErrSubstr: "invalid target name",
+		},
+		{
+			name:  "invalid pattern errors",
+			files: photos,
+			args: func(dir string) RenameFilesArgs {
+				return RenameFilesArgs{Root: dir, Pattern: "(", Replacement: "x"}
+			},
+			wantErr:       true,
+			wantErrSubstr: "invalid pattern",
+		},
+		{
+			name:  "canceled context",
+			files: photos,
+			ctx: func(t *testing.T) context.Context {
+				t.Helper()
+				ctx, cancel := context.WithCancel(t.Context())
+				cancel()
+				return ctx
+			},
+			args: func(dir string) RenameFilesArgs {
+				return RenameFilesArgs{Root: dir, Pattern: photoPattern, Replacement: "photo_$1.jpg"}
+			},
+			wantErr:   true,
+			wantErrIs: context.Canceled,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctx := t.Context()
+			if tt.ctx != nil {
+				ctx = tt.ctx(t)
+			}
+			dir := setup(t, tt.files...)
+			out, err := RenameFiles(ctx, tt.args(dir))
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected error, got %+v", out)
+				}
+				if tt.wantErrIs != nil && !errors.Is(err, tt.wantErrIs) {
+					t.Fatalf("expected errors.Is(%v), got %v", tt.wantErrIs, err)
+				}
+				if tt.wantErrSubstr != "" && !strings.Contains(err.Error(), tt.wantErrSubstr) {
+					t.Fatalf("error %q does not contain %q", err, tt.wantErrSubstr)
+				}
+			} else {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				if out.Root != dir {
+					t.Fatalf("Root=%q want %q", out.Root, dir)
+				}
+				want := make([]RenamePair, 0, len(tt.wantRenames))
+				for _, rp := range tt.wantRenames {
+					want = append(want, RenamePair{From: filepath.Join(dir, rp.From), To: filepath.Join(dir, rp.To)})
+				}
+				if !slices.Equal(out.Renames, want) {
+					t.Fatalf("Renames=%+v want %+v", out.Renames, want)
+				}
+			}
+			if tt.wantDir != nil {
+				if got := listDir(t, dir); !slices.Equal(got, tt.wantDir) {
+					t.Fatalf("dir=%v want %v", got, tt.wantDir)
+				}
+			}
+		})
+	}
+}
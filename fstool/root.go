@@ -0,0 +1,82 @@
+package fstool
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync/atomic"
+
+	"github.com/flexigpt/llmtools-go/internal/fileutil"
+)
+
+// ErrPathEscapesRoot is returned (wrapped) for paths outside the directory set
+// with SetRoot.
+var ErrPathEscapesRoot = fileutil.ErrPathEscapesRoot
+
+var confineRoot atomic.Pointer[string]
+
+// SetRoot confines the paths accepted by all fstool tools to the existing
+// directory dir. Relative paths (and omitted optional paths) then resolve
+// against dir instead of the working directory, and paths outside it, also via
+// symlinks, fail with an error wrapping ErrPathEscapesRoot. Automatic trash
+// selection in DeleteFile no longer uses the system trash. An empty dir
+// removes the confinement.
+func SetRoot(dir string) error {
+	dir = strings.TrimSpace(dir)
+	if dir == "" {
+		confineRoot.Store(nil)
+		return nil
+	}
+	abs, err := fileutil.NormalizePathWithin(dir, ".")
+	if err != nil {
+		return fmt.Errorf("invalid root: %w", err)
+	}
+	st, err := os.Stat(abs)
+	if err != nil {
+		return fmt.Errorf("invalid root: %w", err)
+	}
+	if !st.IsDir() {
+		return fmt.Errorf("invalid root: not a directory: %s", abs)
+	}
+	confineRoot.Store(&abs)
+	return nil
+}
+
+// Root returns the directory set with SetRoot, or "" if paths are not confined.
+func Root() string {
+	if r := confineRoot.Load(); r != nil {
+		return *r
+	}
+	return ""
+}
+
+// confinePaths rewrites each path to its confined absolute form when a root is
+// set; without one the paths are left untouched.
+func confinePaths(paths ...*string) error {
+	r := Root()
+	if r == "" {
+		return nil
+	}
+	for _, p := range paths {
+		in := strings.TrimSpace(*p)
+		if in == "" {
+			in = "."
+		}
+		confined, err := fileutil.NormalizePathWithin(r, in)
+		if err != nil {
+			return err
+		}
+		*p = confined
+	}
+	return nil
+}
+
+// checkWalkFollowSymlinks refuses following symlinks during a directory walk
+// while a root is set: links below the walked path are not confined.
+func checkWalkFollowSymlinks(follow bool) error {
+	if follow && Root() != "" {
+		return errors.New("followSymlinks is not supported while paths are confined to a root")
+	}
+	return nil
+}
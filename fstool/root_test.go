@@ -0,0 +1,102 @@
+package fstool
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"testing"
+
+	"github.com/flexigpt/llmtools-go/internal/toolutil"
+)
+
+// TestSetRoot is not parallel: it changes the process-wide root.
+func TestSetRoot(t *testing.T) {
+	base := t.TempDir()
+	outside := t.TempDir()
+	if err := os.WriteFile(filepath.Join(base, "in.txt"), []byte("inside"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	secret := filepath.Join(outside, "secret.txt")
+	if err := os.WriteFile(secret, []byte("secret"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := SetRoot(filepath.Join(base, "in.txt")); err == nil {
+		t.Fatalf("SetRoot on a file: expected error")
+	}
+	if err := SetRoot(base); err != nil {
+		t.Fatalf("SetRoot: %v", err)
+	}
+	t.Cleanup(func() { _ = SetRoot("") })
+	if Root() != base {
+		t.Fatalf("Root: got %q want %q", Root(), base)
+	}
+
+	outs, err := ReadFile(t.Context(), ReadFileArgs{Path: "in.txt"})
+	if err != nil || len(outs) != 1 || outs[0].TextItem.Text != "inside" {
+		t.Fatalf("ReadFile relative to root: got (%+v, %v)", outs, err)
+	}
+	list, err := ListDirectory(t.Context(), ListDirectoryArgs{})
+	if err != nil || !slices.Contains(list.Entries, "in.txt") {
+		t.Fatalf("ListDirectory with omitted path: got (%+v, %v), want the root listing", list, err)
+	}
+
+	escapes := map[string]func() error{
+		"read absolute outside": func() error {
+			_, err := ReadFile(t.Context(), ReadFileArgs{Path: secret})
+			return err
+		},
+		"read dot-dot escape": func() error {
+			_, err := ReadFile(t.Context(), ReadFileArgs{Path: "../" + filepath.Base(outside) + "/secret.txt"})
+			return err
+		},
+		"write outside": func() error {
+			_, err := WriteFile(t.Context(), WriteFileArgs{Path: filepath.Join(outside, "new.txt"), Content: "x"})
+			return err
+		},
+		"list outside": func() error {
+			_, err := ListDirectory(t.Context(), ListDirectoryArgs{Path: outside})
+			return err
+		},
+	}
+	linked := false
+	if runtime.GOOS != toolutil.GOOSWindows {
+		if err := os.Symlink(secret, filepath.Join(base, "link")); err == nil {
+			linked = true
+			escapes["read symlink to outside"] = func() error {
+				_, err := ReadFile(t.Context(), ReadFileArgs{Path: "link", FollowSymlinks: true})
+				return err
+			}
+		}
+	}
+	for name, call := range escapes {
+		if err := call(); !errors.Is(err, ErrPathEscapesRoot) {
+			t.Errorf("%s: got %v want ErrPathEscapesRoot", name, err)
+		}
+	}
+
+	if _, err := SearchFiles(t.Context(), SearchFilesArgs{Pattern: "x", FollowSymlinks: true}); err == nil {
+		t.Errorf("SearchFiles with followSymlinks under a root: expected error")
+	}
+	if linked {
+		for _, withOffsets := range []bool{false, true} {
+			out, err := SearchFiles(t.Context(), SearchFilesArgs{Pattern: "sec.et", WithOffsets: withOffsets})
+			if err != nil || out.MatchCount != 0 {
+				t.Errorf("SearchFiles through symlink to outside (offsets=%v): got (%+v, %v), want no matches",
+					withOffsets, out, err)
+			}
+		}
+	}
+	if _, err := ListDirectory(t.Context(), ListDirectoryArgs{Pattern: "**", FollowSymlinks: true}); err == nil {
+		t.Errorf("ListDirectory with followSymlinks under a root: expected error")
+	}
+
+	if err := SetRoot(""); err != nil || Root() != "" {
+		t.Fatalf("SetRoot(\"\"): got (%v, root %q)", err, Root())
+	}
+	if _, err := ReadFile(t.Context(), ReadFileArgs{Path: secret}); err != nil {
+		t.Fatalf("ReadFile after clearing root: %v", err)
+	}
+}
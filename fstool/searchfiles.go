@@ -36,6 +36,31 @@ var searchFilesTool = spec.Tool{
 		"type": "integer",
 		"description": "Stop after this many matches (0 = unlimited).",
 		"default": 100
+	},
+	"searchCompressed": {
+		"type": "boolean",
+		"description": "Also search the decompressed content of .gz files (size-bounded).",
+		"default": false
+	},
+	"skipBinary": {
+		"type": "boolean",
+		"description": "Skip content matching for binary files (NUL bytes or mostly control characters in the first 8KB, or invalid UTF-8). Binary files can still match by path.",
+		"default": true
+	},
+	"followSymlinks": {
+		"type": "boolean",
+		"description": "Also descend into symlinked directories and search the content of symlinked files. Otherwise symlinks match by path only. Symlink cycles are visited once.",
+		"default": false
+	},
+	"withOffsets": {
+		"type": "boolean",
+		"description": "Also return, per file, the byte offsets and text of each content match (for building exact edits).",
+		"default": false
+	},
+	"failFast": {
+		"type": "boolean",
+		"description": "Abort on the first file or directory that cannot be read instead of listing it under errors.",
+		"default": false
 	}
 },
 "required": ["pattern"],
@@ -55,15 +80,64 @@ type SearchFilesArgs struct {
 	Root       string `json:"root,omitempty"` // default "."
 	Pattern    string `json:"pattern"`        // required (RE2)
 	MaxResults int    `json:"maxResults,omitempty"`
+
+	SearchCompressed bool `json:"searchCompressed,omitempty"` // also scan decompressed .gz content
+	WithOffsets      bool `json:"withOffsets,omitempty"`      // fill SearchFilesOut.Files
+	// SkipBinary defaults to true when nil; false also searches binary content.
+	SkipBinary *bool `json:"skipBinary,omitempty"`
+	// FollowSymlinks descends into symlinked directories; each directory is
+	// read once, so cycles terminate. Not allowed with SetRoot.
+	FollowSymlinks bool `json:"followSymlinks,omitempty"`
+	// FailFast aborts the search on the first unreadable file or directory;
+	// otherwise they are reported in SearchFilesOut.Errors.
+	FailFast bool `json:"failFast,omitempty"`
+
+	// OnProgress, if set, is called after each file is scanned with the count so
+	// far (total is 0: unknown while walking). Go callers only; it is not part
+	// of the tool schema.
+	OnProgress func(done, total int) `json:"-"`
 }
 type SearchFilesOut struct {
 	MatchCount        int      `json:"matchCount"`
 	ReachedMaxResults bool     `json:"reachedMaxResults"`
 	Matches           []string `json:"matches"`
+
+	// Files parallels Matches when WithOffsets is set.
+	Files []SearchFileMatch `json:"files,omitempty"`
+	// Errors lists files and directories that could not be read and were
+	// skipped. Always empty with FailFast.
+	Errors []FileError `json:"errors,omitempty"`
+}
+
+// FileError is a path that could not be read during a search.
+type FileError struct {
+	Path  string `json:"path"`
+	Error string `json:"error"`
+}
+
+// SearchFileMatch lists where the pattern matched inside one file's content.
+// Matches is empty for files that matched by path only.
+type SearchFileMatch struct {
+	Path    string               `json:"path"`
+	Matches []SearchContentMatch `json:"matches"`
+}
+
+// SearchContentMatch is a match at bytes [Start, End) of the file content
+// (decompressed content for .gz files).
+type SearchContentMatch struct {
+	Start int    `json:"start"`
+	End   int    `json:"end"`
+	Text  string `json:"text"`
 }
 
 // SearchFiles walks Root (recursively) and returns up to MaxResults files
 // whose *path* or *UTF-8 text content* match the supplied regexp.
+// With SearchCompressed, gzip-compressed (.gz) files are searched by content too.
+// Binary content is skipped unless SkipBinary is false.
+// With WithOffsets, Files also reports the byte range and text of each content
+// match (up to 1000 per file), so callers can construct exact edits.
+// Unreadable files and directories below Root are skipped and listed in Errors,
+// unless FailFast is set.
 func SearchFiles(ctx context.Context, args SearchFilesArgs) (*SearchFilesOut, error) {
 	return toolutil.WithRecoveryResp(func() (*SearchFilesOut, error) {
 		return searchFiles(ctx, args)
@@ -71,12 +145,70 @@ func SearchFiles(ctx context.Context, args SearchFilesArgs) (*SearchFilesOut, er
 }
 
 func searchFiles(ctx context.Context, args SearchFilesArgs) (*SearchFilesOut, error) {
-	matches, reachedLimit, err := fileutil.SearchFiles(ctx, args.Root, args.Pattern, args.MaxResults)
+	if err := confinePaths(&args.Root); err != nil {
+		return nil, err
+	}
+	if err := checkWalkFollowSymlinks(args.FollowSymlinks); err != nil {
+		return nil, err
+	}
+	opts := fileutil.SearchOptions{
+		Compressed:     args.SearchCompressed,
+		IncludeBinary:  args.SkipBinary != nil && !*args.SkipBinary,
+		FollowSymlinks: args.FollowSymlinks,
+		OnProgress:     args.OnProgress,
+	}
+	var fileErrs []FileError
+	opts.OnFileError = func(path string, err error) error {
+		if args.FailFast {
+			return err
+		}
+		fileErrs = append(fileErrs, FileError{Path: path, Error: err.Error()})
+		return nil
+	}
+
+	var out *SearchFilesOut
+	if args.WithOffsets {
+		o, err := searchFilesWithOffsets(ctx, args, opts)
+		if err != nil {
+			return nil, err
+		}
+		out = o
+	} else {
+		matches, reachedLimit, err := fileutil.SearchFiles(ctx, args.Root, args.Pattern, args.MaxResults, opts)
+		if err != nil {
+			return nil, err
+		}
+		out = &SearchFilesOut{
+			Matches: matches, MatchCount: len(matches),
+			ReachedMaxResults: reachedLimit,
+		}
+	}
+	out.Errors = fileErrs
+	return out, nil
+}
+
+func searchFilesWithOffsets(
+	ctx context.Context,
+	args SearchFilesArgs,
+	opts fileutil.SearchOptions,
+) (*SearchFilesOut, error) {
+	found, reachedLimit, err := fileutil.SearchFilesWithOffsets(ctx, args.Root, args.Pattern, args.MaxResults, opts)
 	if err != nil {
 		return nil, err
 	}
-	return &SearchFilesOut{
-		Matches: matches, MatchCount: len(matches),
+	out := &SearchFilesOut{
+		Matches:           make([]string, 0, len(found)),
+		MatchCount:        len(found),
 		ReachedMaxResults: reachedLimit,
-	}, nil
+		Files:             make([]SearchFileMatch, 0, len(found)),
+	}
+	for _, f := range found {
+		fm := SearchFileMatch{Path: f.Path, Matches: make([]SearchContentMatch, 0, len(f.Matches))}
+		for _, m := range f.Matches {
+			fm.Matches = append(fm.Matches, SearchContentMatch{Start: m.Start, End: m.End, Text: m.Text})
+		}
+		out.Matches = append(out.Matches, f.Path)
+		out.Files = append(out.Files, fm)
+	}
+	return out, nil
 }
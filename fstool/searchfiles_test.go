@@ -1,14 +1,151 @@
 package fstool
 
 import (
+	"bytes"
+	"compress/gzip"
 	"context"
 	"errors"
 	"os"
 	"path/filepath"
+	"runtime"
+	"slices"
 	"strings"
 	"testing"
+	"time"
+
+	"github.com/flexigpt/llmtools-go/internal/toolutil"
 )
 
+func TestSearchFiles_WithOffsets(t *testing.T) {
+	tmpDir := t.TempDir()
+	p := filepath.Join(tmpDir, "code.go")
+	content := "x := oldName()\ny := oldName() + oldName()\n"
+	if err := os.WriteFile(p, []byte(content), 0o600); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+
+	out, err := SearchFiles(t.Context(), SearchFilesArgs{Root: tmpDir, Pattern: `oldName\(\)`, WithOffsets: true})
+	if err != nil {
+		t.Fatalf("SearchFiles: %v", err)
+	}
+	if out.MatchCount != 1 || len(out.Files) != 1 || out.Files[0].Path != p {
+		t.Fatalf("unexpected out: %+v", out)
+	}
+	ms := out.Files[0].Matches
+	if len(ms) != 3 {
+		t.Fatalf("want 3 matches, got %+v", ms)
+	}
+	prev := -1
+	for i, m := range ms {
+		if m.Start <= prev || content[m.Start:m.End] != "oldName()" || m.Text != "oldName()" {
+			t.Fatalf("match %d points at %q (%+v)", i, content[m.Start:m.End], m)
+		}
+		prev = m.Start
+	}
+
+	plain, err := SearchFiles(t.Context(), SearchFilesArgs{Root: tmpDir, Pattern: "oldName"})
+	if err != nil {
+		t.Fatalf("SearchFiles: %v", err)
+	}
+	if plain.Files != nil {
+		t.Fatalf("offsets returned without WithOffsets: %+v", plain.Files)
+	}
+}
+
+func TestSearchFiles_FollowSymlinks(t *testing.T) {
+	if runtime.GOOS == toolutil.GOOSWindows {
+		t.Skip("symlinks require elevated privileges on Windows")
+	}
+	tmpDir := t.TempDir()
+	outside := t.TempDir()
+	sub := filepath.Join(tmpDir, "sub")
+	if err := os.Mkdir(sub, 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(outside, "linked.txt"), []byte("needle"), 0o600); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	// A cycle back to the search root, and a directory reachable only via a link.
+	if err := os.Symlink(tmpDir, filepath.Join(sub, "loop")); err != nil {
+		t.Fatalf("symlink: %v", err)
+	}
+	if err := os.Symlink(outside, filepath.Join(tmpDir, "ext")); err != nil {
+		t.Fatalf("symlink: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		follow bool
+		want   []string
+	}{
+		{name: "links not descended by default", want: []string{}},
+		{name: "links descended and cycle terminates", follow: true, want: []string{
+			filepath.Join(tmpDir, "ext", "linked.txt"),
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(t.Context(), 10*time.Second)
+			defer cancel()
+			out, err := SearchFiles(ctx, SearchFilesArgs{Root: tmpDir, Pattern: "needle", FollowSymlinks: tt.follow})
+			if err != nil {
+				t.Fatalf("SearchFiles: %v", err)
+			}
+			if !slices.Equal(out.Matches, tt.want) {
+				t.Fatalf("matches=%v want %v", out.Matches, tt.want)
+			}
+		})
+	}
+}
+
+func TestSearchFiles_UnreadableFile(t *testing.T) {
+	if runtime.GOOS == toolutil.GOOSWindows {
+		t.Skip("file permissions are not enforced the same way on Windows")
+	}
+	if os.Geteuid() == 0 {
+		t.Skip("root can read files without permission bits")
+	}
+	tmpDir := t.TempDir()
+	good := filepath.Join(tmpDir, "good.txt")
+	bad := filepath.Join(tmpDir, "bad.txt")
+	if err := os.WriteFile(good, []byte("needle"), 0o600); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if err := os.WriteFile(bad, []byte("needle"), 0o000); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		failFast  bool
+		wantErr   bool
+		wantMatch []string
+	}{
+		{name: "errors collected and search continues", wantMatch: []string{good}},
+		{name: "fail fast aborts", failFast: true, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, err := SearchFiles(t.Context(), SearchFilesArgs{Root: tmpDir, Pattern: "needle", FailFast: tt.failFast})
+			if tt.wantErr {
+				if !errors.Is(err, os.ErrPermission) {
+					t.Fatalf("want permission error, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("SearchFiles: %v", err)
+			}
+			if !slices.Equal(out.Matches, tt.wantMatch) {
+				t.Fatalf("matches=%v want %v", out.Matches, tt.wantMatch)
+			}
+			if len(out.Errors) != 1 || out.Errors[0].Path != bad || out.Errors[0].Error == "" {
+				t.Fatalf("unexpected errors: %+v", out.Errors)
+			}
+		})
+	}
+}
+
 // TestSearchFiles covers happy, error, and boundary cases for SearchFiles.
 func TestSearchFiles(t *testing.T) {
 	tmpDir := t.TempDir()
@@ -26,6 +163,24 @@ func TestSearchFiles(t *testing.T) {
 		t.Fatalf("write baz.txt: %v", err)
 	}
 
+	var gzBuf bytes.Buffer
+	zw := gzip.NewWriter(&gzBuf)
+	if _, err := zw.Write([]byte("rotated line with needle-42\n")); err != nil {
+		t.Fatalf("gzip write: %v", err)
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatalf("gzip close: %v", err)
+	}
+	gzPath := filepath.Join(tmpDir, "app.log.gz")
+	if err := os.WriteFile(gzPath, gzBuf.Bytes(), 0o600); err != nil {
+		t.Fatalf("write app.log.gz: %v", err)
+	}
+	binPath := filepath.Join(tmpDir, "blob.bin")
+	if err := os.WriteFile(binPath, []byte("\x00\x01\x02needle-bin\x00\xff"), 0o600); err != nil {
+		t.Fatalf("write blob.bin: %v", err)
+	}
+	no := false
+
 	tests := []struct {
 		name string
 		args SearchFilesArgs
@@ -72,6 +227,36 @@ func TestSearchFiles(t *testing.T) {
 			args: SearchFilesArgs{Root: tmpDir, Pattern: "baz"},
 			want: []string{filepath.Join(tmpDir, "sub", "baz.txt")},
 		},
+		{
+			name: "gzip content not searched by default",
+			args: SearchFilesArgs{Root: tmpDir, Pattern: "needle-42"},
+			want: []string{},
+		},
+		{
+			name: "gzip content searched with SearchCompressed",
+			args: SearchFilesArgs{Root: tmpDir, Pattern: "needle-42", SearchCompressed: true},
+			want: []string{gzPath},
+		},
+		{
+			name: "binary content skipped by default",
+			args: SearchFilesArgs{Root: tmpDir, Pattern: "needle-bin"},
+			want: []string{},
+		},
+		{
+			name: "binary content searched with SkipBinary=false",
+			args: SearchFilesArgs{Root: tmpDir, Pattern: "needle-bin", SkipBinary: &no},
+			want: []string{binPath},
+		},
+		{
+			name: "binary file still matched by path",
+			args: SearchFilesArgs{Root: tmpDir, Pattern: `blob\.bin$`},
+			want: []string{binPath},
+		},
+		{
+			name: "gzip path matched by real name",
+			args: SearchFilesArgs{Root: tmpDir, Pattern: `app\.log\.gz$`},
+			want: []string{gzPath},
+		},
 		{
 			name: "MaxResults limits output",
 			args: SearchFilesArgs{Root: tmpDir, Pattern: "txt", MaxResults: 1},
@@ -66,16 +66,13 @@ func statPath(ctx context.Context, args StatPathArgs) (*StatPathOut, error) {
 	if err := ctx.Err(); err != nil {
 		return nil, err
 	}
+	if err := confinePaths(&args.Path); err != nil {
+		return nil, err
+	}
 	pathInfo, err := fileutil.StatPath(args.Path)
 	if err != nil {
 		return nil, err
 	}
-	return &StatPathOut{
-		Path:      pathInfo.Path,
-		Name:      pathInfo.Name,
-		Exists:    pathInfo.Exists,
-		IsDir:     pathInfo.IsDir,
-		SizeBytes: pathInfo.Size,
-		ModTime:   pathInfo.ModTime,
-	}, nil
+	out := toStatPathOut(pathInfo)
+	return &out, nil
 }
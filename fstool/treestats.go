@@ -0,0 +1,111 @@
+package fstool
+
+import (
+	"context"
+	"strings"
+
+	"github.com/flexigpt/llmtools-go/internal/fileutil"
+	"github.com/flexigpt/llmtools-go/internal/toolutil"
+	"github.com/flexigpt/llmtools-go/spec"
+)
+
+const treeStatsFuncID spec.FuncID = "github.com/flexigpt/llmtools-go/fstool/treestats.TreeStats"
+
+var treeStatsTool = spec.Tool{
+	SchemaVersion: spec.SchemaVersion,
+	ID:            "01a13d88-9ae5-782b-a07c-bf1faa4763a4",
+	Slug:          "treestats",
+	Version:       "v1.0.0",
+	DisplayName:   "Tree statistics",
+	Description:   "Count files, lines, words, characters and bytes of text files under a directory, in total and per file extension. Binary files are skipped.",
+	Tags:          []string{"fs", "stats"},
+
+	ArgSchema: spec.JSONSchema(`{
+"$schema": "http://json-schema.org/draft-07/schema#",
+"type": "object",
+"properties": {
+	"root": {
+		"type": "string",
+		"description": "Directory to scan recursively.",
+		"default": "."
+	},
+	"patterns": {
+		"type": "array",
+		"items": {"type": "string"},
+		"description": "Optional glob patterns matched against file base names, e.g. [\"*.go\", \"*.md\"]. Empty means all files."
+	}
+},
+"additionalProperties": false
+}`),
+	GoImpl: spec.GoToolImpl{FuncID: treeStatsFuncID},
+
+	CreatedAt:  spec.SchemaStartTime,
+	ModifiedAt: spec.SchemaStartTime,
+}
+
+func TreeStatsTool() spec.Tool {
+	return toolutil.CloneTool(treeStatsTool)
+}
+
+type TreeStatsArgs struct {
+	Root     string   `json:"root,omitempty"` // default "."
+	Patterns []string `json:"patterns,omitempty"`
+}
+
+type TreeStatsCounts struct {
+	Files int   `json:"files"`
+	Lines int64 `json:"lines"`
+	Words int64 `json:"words"`
+	Chars int64 `json:"chars"`
+	Bytes int64 `json:"bytes"`
+}
+
+type TreeStatsOut struct {
+	Root          string                     `json:"root"`
+	Total         TreeStatsCounts            `json:"total"`
+	ByExtension   map[string]TreeStatsCounts `json:"byExtension"` // ".go", ".md", ...; "(none)" for no extension
+	SkippedBinary int                        `json:"skippedBinary"`
+}
+
+// TreeStats streams every text file under Root (optionally filtered by Patterns)
+// and reports line/word/char/byte totals overall and per lower-cased extension.
+// Files are never loaded whole; binary files and symlinks are skipped.
+func TreeStats(ctx context.Context, args TreeStatsArgs) (*TreeStatsOut, error) {
+	return toolutil.WithRecoveryResp(func() (*TreeStatsOut, error) {
+		return treeStats(ctx, args)
+	})
+}
+
+func treeStats(ctx context.Context, args TreeStatsArgs) (*TreeStatsOut, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if err := confinePaths(&args.Root); err != nil {
+		return nil, err
+	}
+	patterns := make([]string, 0, len(args.Patterns))
+	for _, p := range args.Patterns {
+		if p = strings.TrimSpace(p); p != "" {
+			patterns = append(patterns, p)
+		}
+	}
+
+	st, err := fileutil.ComputeTreeStats(ctx, strings.TrimSpace(args.Root), patterns)
+	if err != nil {
+		return nil, err
+	}
+	out := &TreeStatsOut{
+		Root:          st.Root,
+		Total:         toTreeStatsCounts(st.Total),
+		ByExtension:   make(map[string]TreeStatsCounts, len(st.ByExtension)),
+		SkippedBinary: st.SkippedBinary,
+	}
+	for ext, c := range st.ByExtension {
+		out.ByExtension[ext] = toTreeStatsCounts(c)
+	}
+	return out, nil
+}
+
+func toTreeStatsCounts(c fileutil.TextCounts) TreeStatsCounts {
+	return TreeStatsCounts{Files: c.Files, Lines: c.Lines, Words: c.Words, Chars: c.Chars, Bytes: c.Bytes}
+}
@@ -0,0 +1,111 @@
+package fstool
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestTreeStats(t *testing.T) {
+	t.Parallel()
+	tmpDir := t.TempDir()
+	write := func(rel, content string) {
+		t.Helper()
+		p := filepath.Join(tmpDir, rel)
+		if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+			t.Fatalf("mkdir: %v", err)
+		}
+		if err := os.WriteFile(p, []byte(content), 0o600); err != nil {
+			t.Fatalf("write %s: %v", rel, err)
+		}
+	}
+	write("main.go", "package main\n\nfunc main() {}\n")
+	write("internal/a/a.go", "package a\n")
+	write("README.md", "# Title\n\nSome text.\n")
+	write("docs/notes.md", "one\ntwo")
+	write("image.bin", "\x00\x01\x02")
+
+	tests := []struct {
+		name        string
+		ctx         func(t *testing.T) context.Context
+		args        TreeStatsArgs
+		wantFiles   int
+		wantLines   int64
+		wantExt     map[string]int64 // extension -> lines
+		wantSkipped int
+		wantErr     bool
+		wantErrIs   error
+	}{
+		{
+			name:        "per extension line totals",
+			args:        TreeStatsArgs{Root: tmpDir},
+			wantFiles:   4,
+			wantLines:   9,
+			wantExt:     map[string]int64{".go": 4, ".md": 5},
+			wantSkipped: 1,
+		},
+		{
+			name:      "patterns restrict files",
+			args:      TreeStatsArgs{Root: tmpDir, Patterns: []string{" *.md "}},
+			wantFiles: 2,
+			wantLines: 5,
+			wantExt:   map[string]int64{".md": 5},
+		},
+		{name: "invalid pattern errors", args: TreeStatsArgs{Root: tmpDir, Patterns: []string{"[x"}}, wantErr: true},
+		{name: "missing root errors", args: TreeStatsArgs{Root: filepath.Join(tmpDir, "nope")}, wantErr: true},
+		{
+			name: "canceled context",
+			ctx: func(t *testing.T) context.Context {
+				t.Helper()
+				ctx, cancel := context.WithCancel(t.Context())
+				cancel()
+				return ctx
+			},
+			args:      TreeStatsArgs{Root: tmpDir},
+			wantErr:   true,
+			wantErrIs: context.Canceled,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctx := t.Context()
+			if tt.ctx != nil {
+				ctx = tt.ctx(t)
+			}
+			out, err := TreeStats(ctx, tt.args)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected error, got %+v", out)
+				}
+				if tt.wantErrIs != nil && !errors.Is(err, tt.wantErrIs) {
+					t.Fatalf("expected errors.Is(%v), got %v", tt.wantErrIs, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if out.Root != tmpDir {
+				t.Fatalf("Root=%q want %q", out.Root, tmpDir)
+			}
+			if out.Total.Files != tt.wantFiles || out.Total.Lines != tt.wantLines {
+				t.Fatalf("Total=%+v want files=%d lines=%d", out.Total, tt.wantFiles, tt.wantLines)
+			}
+			if len(out.ByExtension) != len(tt.wantExt) {
+				t.Fatalf("ByExtension=%+v want lines %v", out.ByExtension, tt.wantExt)
+			}
+			for ext, lines := range tt.wantExt {
+				if out.ByExtension[ext].Lines != lines {
+					t.Fatalf("ByExtension[%q].Lines=%d want %d", ext, out.ByExtension[ext].Lines, lines)
+				}
+			}
+			if out.SkippedBinary != tt.wantSkipped {
+				t.Fatalf("SkippedBinary=%d want %d", out.SkippedBinary, tt.wantSkipped)
+			}
+		})
+	}
+}
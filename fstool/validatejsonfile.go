@@ -0,0 +1,152 @@
+package fstool
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/flexigpt/llmtools-go/internal/fileutil"
+	"github.com/flexigpt/llmtools-go/internal/jsonutil"
+	"github.com/flexigpt/llmtools-go/internal/toolutil"
+	"github.com/flexigpt/llmtools-go/spec"
+)
+
+const validateJSONFileFuncID spec.FuncID = "github.com/flexigpt/llmtools-go/fstool/validatejsonfile.ValidateJSONFile"
+
+var validateJSONFileTool = spec.Tool{
+	SchemaVersion: spec.SchemaVersion,
+	ID:            "01a13d82-7e9f-7d54-939b-061bf45e265b",
+	Slug:          "validatejsonfile",
+	Version:       "v1.0.0",
+	DisplayName:   "Validate JSON file",
+	Description:   "Validate a local JSON file against a JSON Schema (inline or from a file) and list every violation with its JSON Pointer.",
+	Tags:          []string{"fs", "json"},
+
+	ArgSchema: spec.JSONSchema(`{
+"$schema": "http://json-schema.org/draft-07/schema#",
+"type": "object",
+"properties": {
+	"path": {
+		"type": "string",
+		"description": "Absolute or relative path of the JSON file to validate."
+	},
+	"schema": {
+		"type": ["object", "boolean"],
+		"description": "Inline JSON Schema (draft-07 subset). Mutually exclusive with schemaPath."
+	},
+	"schemaPath": {
+		"type": "string",
+		"description": "Path of a JSON Schema file. Mutually exclusive with schema."
+	}
+},
+"required": ["path"],
+"additionalProperties": false
+}`),
+	GoImpl: spec.GoToolImpl{FuncID: validateJSONFileFuncID},
+
+	CreatedAt:  spec.SchemaStartTime,
+	ModifiedAt: spec.SchemaStartTime,
+}
+
+func ValidateJSONFileTool() spec.Tool {
+	return toolutil.CloneTool(validateJSONFileTool)
+}
+
+type ValidateJSONFileArgs struct {
+	Path       string          `json:"path"`
+	Schema     json.RawMessage `json:"schema,omitempty"`
+	SchemaPath string          `json:"schemaPath,omitempty"`
+}
+
+type JSONSchemaViolation struct {
+	Pointer string `json:"pointer"` // JSON Pointer into the validated document; "" is the root
+	Message string `json:"message"`
+}
+
+type ValidateJSONFileOut struct {
+	Path       string                `json:"path"`
+	Valid      bool                  `json:"valid"`
+	Violations []JSONSchemaViolation `json:"violations"`
+}
+
+// ValidateJSONFile validates the JSON document at Path against Schema or the
+// schema file at SchemaPath. A file that is not valid JSON is reported as a
+// violation; an unreadable or malformed schema is an error.
+func ValidateJSONFile(ctx context.Context, args ValidateJSONFileArgs) (*ValidateJSONFileOut, error) {
+	return toolutil.WithRecoveryResp(func() (*ValidateJSONFileOut, error) {
+		return validateJSONFile(ctx, args)
+	})
+}
+
+func validateJSONFile(ctx context.Context, args ValidateJSONFileArgs) (*ValidateJSONFileOut, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if err := confinePaths(&args.Path); err != nil {
+		return nil, err
+	}
+	hasInline := isJSONValueSet(args.Schema)
+	schemaPath := strings.TrimSpace(args.SchemaPath)
+	if hasInline == (schemaPath != "") {
+		return nil, errors.New("exactly one of schema or schemaPath is required")
+	}
+	if !hasInline {
+		if err := confinePaths(&schemaPath); err != nil {
+			return nil, err
+		}
+	}
+
+	p, doc, err := readJSONInput(args.Path)
+	if err != nil {
+		return nil, err
+	}
+	schema := []byte(args.Schema)
+	if !hasInline {
+		if _, schema, err = readJSONInput(schemaPath); err != nil {
+			return nil, fmt.Errorf("read schema: %w", err)
+		}
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	violations, err := jsonutil.ValidateJSONSchema(schema, doc)
+	if err != nil {
+		return nil, err
+	}
+	out := &ValidateJSONFileOut{
+		Path:       p,
+		Valid:      len(violations) == 0,
+		Violations: make([]JSONSchemaViolation, 0, len(violations)),
+	}
+	for _, v := range violations {
+		out.Violations = append(out.Violations, JSONSchemaViolation(v))
+	}
+	return out, nil
+}
+
+// readJSONInput reads a bounded regular (non-symlink) file for JSON processing.
+func readJSONInput(path string) (string, []byte, error) {
+	path = strings.TrimSpace(path)
+	if path == "" {
+		return "", nil, fileutil.ErrInvalidPath
+	}
+	p, err := fileutil.NormalizePath(path)
+	if err != nil {
+		return "", nil, err
+	}
+	if _, err := fileutil.RequireExistingRegularFileNoSymlink(p); err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return "", nil, fmt.Errorf("path does not exist: %s", p)
+		}
+		return "", nil, err
+	}
+	data, err := fileutil.ReadFile(p, fileutil.ReadEncodingText, toolutil.GetLimits().MaxFileRead)
+	if err != nil {
+		return "", nil, err
+	}
+	return p, []byte(data), nil
+}
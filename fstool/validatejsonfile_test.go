@@ -0,0 +1,152 @@
+package fstool
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestValidateJSONFile(t *testing.T) {
+	tmpDir := t.TempDir()
+	write := func(name, content string) string {
+		t.Helper()
+		p := filepath.Join(tmpDir, name)
+		if err := os.WriteFile(p, []byte(content), 0o600); err != nil {
+			t.Fatalf("write %s: %v", name, err)
+		}
+		return p
+	}
+
+	const schema = `{
+		"type": "object",
+		"properties": {
+			"name": {"type": "string"},
+			"replicas": {"type": "integer", "minimum": 1}
+		},
+		"required": ["name", "replicas"],
+		"additionalProperties": false
+	}`
+	schemaFile := write("schema.json", schema)
+	validDoc := write("valid.json", `{"name": "web", "replicas": 2}`)
+	invalidDoc := write("invalid.json", `{"replicas": 0, "debug": true}`)
+	notJSON := write("broken.json", `{"name": `)
+	badSchema := write("bad-schema.json", `{"type": 5}`)
+
+	tests := []struct {
+		name           string
+		ctx            func(t *testing.T) context.Context
+		args           ValidateJSONFileArgs
+		wantValid      bool
+		wantViolations []JSONSchemaViolation
+		wantErr        bool
+		wantErrIs      error
+		wantErrSubstr  string
+	}{
+		{
+			name:           "valid file with schema path",
+			args:           ValidateJSONFileArgs{Path: validDoc, SchemaPath: schemaFile},
+			wantValid:      true,
+			wantViolations: []JSONSchemaViolation{},
+		},
+		{
+			name:           "valid file with inline schema",
+			args:           ValidateJSONFileArgs{Path: validDoc, Schema: json.RawMessage(schema)},
+			wantValid:      true,
+			wantViolations: []JSONSchemaViolation{},
+		},
+		{
+			name: "invalid file reports every violation",
+			args: ValidateJSONFileArgs{Path: invalidDoc, Schema: json.RawMessage(schema)},
+			wantViolations: []JSONSchemaViolation{
+				{Pointer: "", Message: `missing required property "name"`},
+				{Pointer: "/debug", Message: `additional property "debug" is not allowed`},
+				{Pointer: "/replicas", Message: "value 0 is less than minimum 1"},
+			},
+		},
+		{
+			name: "malformed JSON file is a violation",
+			args: ValidateJSONFileArgs{Path: notJSON, SchemaPath: schemaFile},
+			wantViolations: []JSONSchemaViolation{
+				{Pointer: "", Message: "invalid JSON: unexpected EOF"},
+			},
+		},
+		{
+			name:          "malformed schema errors",
+			args:          ValidateJSONFileArgs{Path: validDoc, SchemaPath: badSchema},
+			wantErr:       true,
+			wantErrSubstr: "invalid schema",
+		},
+		{
+			name:          "inline schema not JSON errors",
+			args:          ValidateJSONFileArgs{Path: validDoc, Schema: json.RawMessage(`{"type":`)},
+			wantErr:       true,
+			wantErrSubstr: "invalid schema",
+		},
+		{
+			name:          "both schema sources errors",
+			args:          ValidateJSONFileArgs{Path: validDoc, Schema: json.RawMessage(schema), SchemaPath: schemaFile},
+			wantErr:       true,
+			wantErrSubstr: "exactly one of",
+		},
+		{
+			name:          "no schema errors",
+			args:          ValidateJSONFileArgs{Path: validDoc},
+			wantErr:       true,
+			wantErrSubstr: "exactly one of",
+		},
+		{
+			name:          "missing file errors",
+			args:          ValidateJSONFileArgs{Path: filepath.Join(tmpDir, "nope.json"), SchemaPath: schemaFile},
+			wantErr:       true,
+			wantErrSubstr: "does not exist",
+		},
+		{
+			name: "canceled context",
+			ctx: func(t *testing.T) context.Context {
+				t.Helper()
+				ctx, cancel := context.WithCancel(t.Context())
+				cancel()
+				return ctx
+			},
+			args:      ValidateJSONFileArgs{Path: validDoc, SchemaPath: schemaFile},
+			wantErr:   true,
+			wantErrIs: context.Canceled,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := t.Context()
+			if tt.ctx != nil {
+				ctx = tt.ctx(t)
+			}
+			out, err := ValidateJSONFile(ctx, tt.args)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected error, got %+v", out)
+				}
+				if tt.wantErrIs != nil && !errors.Is(err, tt.wantErrIs) {
+					t.Fatalf("expected errors.Is(%v), got %v", tt.wantErrIs, err)
+				}
+				if tt.wantErrSubstr != "" && !strings.Contains(err.Error(), tt.wantErrSubstr) {
+					t.Fatalf("error %q does not contain %q", err, tt.wantErrSubstr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if out.Valid != tt.wantValid {
+				t.Fatalf("Valid=%v want %v (violations=%+v)", out.Valid, tt.wantValid, out.Violations)
+			}
+			if !reflect.DeepEqual(out.Violations, tt.wantViolations) {
+				t.Fatalf("violations mismatch:\ngot:  %+v\nwant: %+v", out.Violations, tt.wantViolations)
+			}
+		})
+	}
+}
@@ -0,0 +1,144 @@
+package fstool
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/flexigpt/llmtools-go/internal/fileutil"
+	"github.com/flexigpt/llmtools-go/internal/toolutil"
+	"github.com/flexigpt/llmtools-go/spec"
+)
+
+const watchPathFuncID spec.FuncID = "github.com/flexigpt/llmtools-go/fstool/watchpath.WatchPath"
+
+const (
+	defaultWatchTimeout = 30 * time.Second
+	maxWatchTimeout     = 10 * time.Minute
+	// watchPollInterval bounds how often the path is stat'ed while waiting.
+	watchPollInterval = 200 * time.Millisecond
+)
+
+var watchPathTool = spec.Tool{
+	SchemaVersion: spec.SchemaVersion,
+	ID:            "01a13dcc-d5dd-7062-9ff0-1811b7981f55",
+	Slug:          "watchpath",
+	Version:       "v1.0.0",
+	DisplayName:   "Watch path for changes",
+	Description:   "Wait until a file's modification time or size changes (or it is created or removed), or a timeout elapses. Useful for waiting on build artifacts.",
+	Tags:          []string{"fs", "stat"},
+
+	ArgSchema: spec.JSONSchema(`{
+"$schema": "http://json-schema.org/draft-07/schema#",
+"type": "object",
+"properties": {
+	"path": {
+		"type": "string",
+		"description": "Absolute or relative path to watch. It need not exist yet."
+	},
+	"timeoutMS": {
+		"type": "integer",
+		"minimum": 0,
+		"default": 30000,
+		"description": "How long to wait for a change in milliseconds. 0 uses the default; values are capped at 10 minutes."
+	}
+},
+"required": ["path"],
+"additionalProperties": false
+}`),
+	GoImpl: spec.GoToolImpl{FuncID: watchPathFuncID},
+
+	CreatedAt:  spec.SchemaStartTime,
+	ModifiedAt: spec.SchemaStartTime,
+}
+
+func WatchPathTool() spec.Tool {
+	return toolutil.CloneTool(watchPathTool)
+}
+
+type WatchPathArgs struct {
+	Path      string `json:"path"`
+	TimeoutMS int    `json:"timeoutMS,omitempty"` // default 30000; capped at 10 minutes
+}
+
+type WatchPathOut struct {
+	// Changed is false if the timeout elapsed without a change.
+	Changed bool `json:"changed"`
+	// Stat is the path's metadata when the call returned.
+	Stat StatPathOut `json:"stat"`
+}
+
+// WatchPath polls Path until its existence, size or modification time differs
+// from when the call started, or TimeoutMS elapses. Polling (at most every
+// 200ms) avoids a platform notification dependency, so changes that are undone
+// between two polls are missed. A registry call timeout shorter than TimeoutMS
+// cancels the wait with an error.
+func WatchPath(ctx context.Context, args WatchPathArgs) (*WatchPathOut, error) {
+	return toolutil.WithRecoveryResp(func() (*WatchPathOut, error) {
+		return watchPath(ctx, args)
+	})
+}
+
+func watchPath(ctx context.Context, args WatchPathArgs) (*WatchPathOut, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if err := confinePaths(&args.Path); err != nil {
+		return nil, err
+	}
+	if args.TimeoutMS < 0 {
+		return nil, errors.New("timeoutMS must be >= 0")
+	}
+	timeout := defaultWatchTimeout
+	if args.TimeoutMS > 0 {
+		timeout = min(time.Duration(args.TimeoutMS)*time.Millisecond, maxWatchTimeout)
+	}
+
+	initial, err := fileutil.StatPath(args.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	deadline := time.NewTimer(timeout)
+	defer deadline.Stop()
+	ticker := time.NewTicker(min(watchPollInterval, timeout))
+	defer ticker.Stop()
+
+	current := initial
+	for {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-deadline.C:
+			return &WatchPathOut{Changed: false, Stat: toStatPathOut(current)}, nil
+		case <-ticker.C:
+		}
+		if current, err = fileutil.StatPath(args.Path); err != nil {
+			return nil, err
+		}
+		if pathInfoChanged(initial, current) {
+			return &WatchPathOut{Changed: true, Stat: toStatPathOut(current)}, nil
+		}
+	}
+}
+
+func pathInfoChanged(a, b *fileutil.PathInfo) bool {
+	if a.Exists != b.Exists || a.IsDir != b.IsDir || a.Size != b.Size {
+		return true
+	}
+	if a.ModTime == nil || b.ModTime == nil {
+		return a.ModTime != b.ModTime
+	}
+	return !a.ModTime.Equal(*b.ModTime)
+}
+
+func toStatPathOut(p *fileutil.PathInfo) StatPathOut {
+	return StatPathOut{
+		Path:      p.Path,
+		Name:      p.Name,
+		Exists:    p.Exists,
+		IsDir:     p.IsDir,
+		SizeBytes: p.Size,
+		ModTime:   p.ModTime,
+	}
+}
@@ -0,0 +1,133 @@
+package fstool
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestWatchPath(t *testing.T) {
+	tests := []struct {
+		name string
+		// setup prepares path and returns the change to apply while watching
+		// (nil for none).
+		setup       func(t *testing.T, path string) func() error
+		timeoutMS   int
+		wantChanged bool
+		wantExists  bool
+		wantSize    int64
+	}{
+		{
+			name: "append_detected",
+			setup: func(t *testing.T, path string) func() error {
+				t.Helper()
+				writeWatchFile(t, path, "v1")
+				return func() error {
+					f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0)
+					if err != nil {
+						return err
+					}
+					if _, err := f.WriteString(" v2"); err != nil {
+						_ = f.Close()
+						return err
+					}
+					return f.Close()
+				}
+			},
+			timeoutMS:   10000,
+			wantChanged: true,
+			wantExists:  true,
+			wantSize:    5,
+		},
+		{
+			name: "creation_detected",
+			setup: func(t *testing.T, path string) func() error {
+				t.Helper()
+				return func() error { return os.WriteFile(path, []byte("artifact"), 0o600) }
+			},
+			timeoutMS:   10000,
+			wantChanged: true,
+			wantExists:  true,
+			wantSize:    8,
+		},
+		{
+			name: "removal_detected",
+			setup: func(t *testing.T, path string) func() error {
+				t.Helper()
+				writeWatchFile(t, path, "v1")
+				return func() error { return os.Remove(path) }
+			},
+			timeoutMS:   10000,
+			wantChanged: true,
+		},
+		{
+			name: "timeout_without_change",
+			setup: func(t *testing.T, path string) func() error {
+				t.Helper()
+				writeWatchFile(t, path, "v1")
+				return nil
+			},
+			timeoutMS:  50,
+			wantExists: true,
+			wantSize:   2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "out.bin")
+			change := tt.setup(t, path)
+
+			changeErr := make(chan error, 1)
+			if change != nil {
+				go func() {
+					// Give WatchPath time to record the initial stat.
+					time.Sleep(50 * time.Millisecond)
+					changeErr <- change()
+				}()
+			}
+
+			out, err := WatchPath(t.Context(), WatchPathArgs{Path: path, TimeoutMS: tt.timeoutMS})
+			if err != nil {
+				t.Fatalf("WatchPath: %v", err)
+			}
+			if change != nil {
+				if err := <-changeErr; err != nil {
+					t.Fatalf("change: %v", err)
+				}
+			}
+			if out.Changed != tt.wantChanged || out.Stat.Exists != tt.wantExists || out.Stat.SizeBytes != tt.wantSize {
+				t.Fatalf("unexpected out: %+v", out)
+			}
+		})
+	}
+}
+
+func TestWatchPath_Errors(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "f.txt")
+	writeWatchFile(t, path, "x")
+
+	t.Run("negative_timeout", func(t *testing.T) {
+		if _, err := WatchPath(t.Context(), WatchPathArgs{Path: path, TimeoutMS: -1}); err == nil {
+			t.Fatal("expected error")
+		}
+	})
+	t.Run("context_canceled_while_waiting", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(t.Context(), 50*time.Millisecond)
+		defer cancel()
+		_, err := WatchPath(ctx, WatchPathArgs{Path: path, TimeoutMS: 10000})
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Fatalf("err=%v, want context.DeadlineExceeded", err)
+		}
+	})
+}
+
+func writeWatchFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+}
@@ -2,12 +2,15 @@ package fstool
 
 import (
 	"context"
+	"crypto/sha256"
 	"encoding/base64"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 	"unicode/utf8"
 
 	"github.com/flexigpt/llmtools-go/internal/fileutil"
@@ -53,6 +56,20 @@ var writeFileTool = spec.Tool{
 		"type": "boolean",
 		"description": "If true, create missing parent directories. Max new directories created is 8.",
 		"default": false
+	},
+	"expectedModTime": {
+		"type": "string",
+		"format": "date-time",
+		"description": "Optional RFC 3339 modification time observed when the file was read. If the existing file is newer, the write is refused."
+	},
+	"expectedSHA256": {
+		"type": "string",
+		"description": "Optional hex SHA-256 of the content observed when the file was read. If the current content differs (or the file no longer exists), the write is refused."
+	},
+	"dryRun": {
+		"type": "boolean",
+		"description": "If true, run all checks and report what would happen without touching the disk.",
+		"default": false
 	}
 },
 "required": ["path", "content"],
@@ -75,11 +92,36 @@ type WriteFileArgs struct {
 	Content       string `json:"content"`
 	Overwrite     bool   `json:"overwrite,omitempty"`
 	CreateParents bool   `json:"createParents,omitempty"`
+
+	// ExpectedModTime enables optimistic concurrency: if the existing destination
+	// has a newer mtime, the write is refused. Nil skips the check.
+	ExpectedModTime *time.Time `json:"expectedModTime,omitempty"`
+	// ExpectedSHA256 is a content precondition that is robust against mtime jitter.
+	// When set, the destination must exist and hash to this value.
+	ExpectedSHA256 string `json:"expectedSHA256,omitempty"`
+
+	// DryRun performs every check a write would (path, parent directories,
+	// destination type, overwrite and preconditions, size cap) and reports the
+	// outcome without creating directories, temp files or the file itself.
+	DryRun bool `json:"dryRun,omitempty"`
 }
 
+// WriteFileAction describes what a write did, or would do in a dry run.
+type WriteFileAction string
+
+const (
+	WriteFileActionCreate    WriteFileAction = "create"
+	WriteFileActionOverwrite WriteFileAction = "overwrite"
+)
+
 type WriteFileOut struct {
-	Path         string `json:"path"`
-	BytesWritten int64  `json:"bytesWritten"`
+	Path string `json:"path"`
+	// BytesWritten is the content size; in a dry run, the size that would be written.
+	BytesWritten int64           `json:"bytesWritten"`
+	Action       WriteFileAction `json:"action"`
+	// CreatedDirs counts parent directories created (or, in a dry run, to create).
+	CreatedDirs int  `json:"createdDirs,omitempty"`
+	DryRun      bool `json:"dryRun,omitempty"`
 }
 
 func WriteFile(ctx context.Context, args WriteFileArgs) (*WriteFileOut, error) {
@@ -92,6 +134,9 @@ func writeFile(ctx context.Context, args WriteFileArgs) (*WriteFileOut, error) {
 	if err := ctx.Err(); err != nil {
 		return nil, err
 	}
+	if err := confinePaths(&args.Path); err != nil {
+		return nil, err
+	}
 
 	p, err := fileutil.NormalizeAbsPath(strings.TrimSpace(args.Path))
 	if err != nil {
@@ -119,8 +164,8 @@ func writeFile(ctx context.Context, args WriteFileArgs) (*WriteFileOut, error) {
 	case fileutil.ReadEncodingBinary:
 		b64 := strings.TrimSpace(args.Content)
 		// Pre-check decoded size to avoid huge allocations.
-		if int64(base64.StdEncoding.DecodedLen(len(b64))) > toolutil.MaxFileWriteBytes {
-			return nil, fmt.Errorf("content too large (decoded > %d bytes)", toolutil.MaxFileWriteBytes)
+		if maxWrite := toolutil.GetLimits().MaxFileWrite; int64(base64.StdEncoding.DecodedLen(len(b64))) > maxWrite {
+			return nil, fmt.Errorf("content too large (decoded > %d bytes)", maxWrite)
 		}
 		decoded, derr := base64.StdEncoding.DecodeString(b64)
 		if derr != nil {
@@ -129,8 +174,15 @@ func writeFile(ctx context.Context, args WriteFileArgs) (*WriteFileOut, error) {
 		data = decoded
 	}
 
-	if int64(len(data)) > toolutil.MaxFileWriteBytes {
-		return nil, fmt.Errorf("content too large (%d bytes; max %d)", len(data), toolutil.MaxFileWriteBytes)
+	if maxWrite := toolutil.GetLimits().MaxFileWrite; int64(len(data)) > maxWrite {
+		return nil, fmt.Errorf("content too large (%d bytes; max %d)", len(data), maxWrite)
+	}
+
+	expectedSHA := strings.ToLower(strings.TrimSpace(args.ExpectedSHA256))
+	if expectedSHA != "" {
+		if b, err := hex.DecodeString(expectedSHA); err != nil || len(b) != sha256.Size {
+			return nil, errors.New("expectedSHA256 must be a 64 character hex string")
+		}
 	}
 
 	parent := filepath.Dir(p)
@@ -139,8 +191,13 @@ func writeFile(ctx context.Context, args WriteFileArgs) (*WriteFileOut, error) {
 		return nil, fileutil.ErrInvalidPath
 	}
 
+	createdDirs := 0
 	if args.CreateParents {
-		_, err := fileutil.EnsureDirNoSymlink(parent, 8 /*max new dirs*/)
+		ensure := fileutil.EnsureDirNoSymlink
+		if args.DryRun {
+			ensure = fileutil.PlanDirNoSymlink
+		}
+		createdDirs, err = ensure(parent, 8 /*max new dirs*/)
 		if err != nil {
 			return nil, err
 		}
@@ -152,6 +209,7 @@ func writeFile(ctx context.Context, args WriteFileArgs) (*WriteFileOut, error) {
 	}
 
 	// Validate existing destination if present.
+	action := WriteFileActionCreate
 	if st, err := os.Lstat(p); err == nil {
 		if st.IsDir() {
 			return nil, fmt.Errorf("path is a directory, not a file: %s", p)
@@ -163,10 +221,35 @@ func writeFile(ctx context.Context, args WriteFileArgs) (*WriteFileOut, error) {
 		if !args.Overwrite {
 			return nil, fmt.Errorf("file already exists and overwrite=false: %s", p)
 		}
+		action = WriteFileActionOverwrite
+		if args.ExpectedModTime != nil && st.ModTime().After(*args.ExpectedModTime) {
+			return nil, fmt.Errorf("file modified since read (concurrent change): %s", p)
+		}
+		if expectedSHA != "" {
+			got, err := fileutil.SHA256HexFile(p, toolutil.GetLimits().MaxFileRead)
+			if err != nil {
+				return nil, err
+			}
+			if got != expectedSHA {
+				return nil, fmt.Errorf("file content changed since read: %s", p)
+			}
+		}
 	} else if !errors.Is(err, os.ErrNotExist) {
 		return nil, err
+	} else if expectedSHA != "" {
+		return nil, fmt.Errorf("file content changed since read (file no longer exists): %s", p)
 	}
 
+	out := &WriteFileOut{
+		Path:         p,
+		BytesWritten: int64(len(data)),
+		Action:       action,
+		CreatedDirs:  createdDirs,
+		DryRun:       args.DryRun,
+	}
+	if args.DryRun {
+		return out, nil
+	}
 	if err := fileutil.WriteFileAtomicBytes(p, data, 0o600, args.Overwrite); err != nil {
 		// Provide stable tool error message for the most common case.
 		if !args.Overwrite && errors.Is(err, os.ErrExist) {
@@ -174,8 +257,5 @@ func writeFile(ctx context.Context, args WriteFileArgs) (*WriteFileOut, error) {
 		}
 		return nil, err
 	}
-	return &WriteFileOut{
-		Path:         p,
-		BytesWritten: int64(len(data)),
-	}, nil
+	return out, nil
 }
@@ -3,13 +3,19 @@ package fstool
 import (
 	"bytes"
 	"context"
+	"crypto/sha256"
 	"encoding/base64"
+	"encoding/hex"
 	"errors"
+	"fmt"
+	"io/fs"
 	"os"
 	"path/filepath"
 	"runtime"
+	"slices"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/flexigpt/llmtools-go/internal/toolutil"
 )
@@ -241,6 +247,179 @@ func TestWriteFile(t *testing.T) {
 				}
 			},
 		},
+		{
+			name: "expectedModTime_matching_writes",
+			run: func(t *testing.T) {
+				t.Helper()
+				tmp := t.TempDir()
+				p := filepath.Join(tmp, "mt.txt")
+				if err := os.WriteFile(p, []byte("a"), 0o600); err != nil {
+					t.Fatalf("seed: %v", err)
+				}
+				st, err := os.Stat(p)
+				if err != nil {
+					t.Fatalf("Stat: %v", err)
+				}
+				mt := st.ModTime()
+				_, err = WriteFile(t.Context(), WriteFileArgs{
+					Path: p, Content: "b", Overwrite: true, ExpectedModTime: &mt,
+				})
+				if err != nil {
+					t.Fatalf("WriteFile: %v", err)
+				}
+				b, _ := os.ReadFile(p)
+				if string(b) != "b" {
+					t.Fatalf("content mismatch: %q", string(b))
+				}
+			},
+		},
+		{
+			name: "expectedModTime_newer_on_disk_refuses",
+			run: func(t *testing.T) {
+				t.Helper()
+				tmp := t.TempDir()
+				p := filepath.Join(tmp, "mt.txt")
+				if err := os.WriteFile(p, []byte("a"), 0o600); err != nil {
+					t.Fatalf("seed: %v", err)
+				}
+				seen := time.Now().Add(-time.Hour)
+				if err := os.Chtimes(p, seen, seen.Add(time.Minute)); err != nil {
+					t.Fatalf("Chtimes: %v", err)
+				}
+				_, err := WriteFile(t.Context(), WriteFileArgs{
+					Path: p, Content: "b", Overwrite: true, ExpectedModTime: &seen,
+				})
+				if err == nil || !strings.Contains(err.Error(), "file modified since read (concurrent change)") {
+					t.Fatalf("expected concurrent change error, got %v", err)
+				}
+				b, _ := os.ReadFile(p)
+				if string(b) != "a" {
+					t.Fatalf("expected original content preserved, got %q", string(b))
+				}
+			},
+		},
+		{
+			name: "expectedModTime_nil_skips_check",
+			run: func(t *testing.T) {
+				t.Helper()
+				tmp := t.TempDir()
+				p := filepath.Join(tmp, "mt.txt")
+				if err := os.WriteFile(p, []byte("a"), 0o600); err != nil {
+					t.Fatalf("seed: %v", err)
+				}
+				future := time.Now().Add(time.Hour)
+				if err := os.Chtimes(p, future, future); err != nil {
+					t.Fatalf("Chtimes: %v", err)
+				}
+				if _, err := WriteFile(t.Context(), WriteFileArgs{Path: p, Content: "b", Overwrite: true}); err != nil {
+					t.Fatalf("WriteFile: %v", err)
+				}
+			},
+		},
+		{
+			name: "expectedSHA256_matching_writes",
+			run: func(t *testing.T) {
+				t.Helper()
+				tmp := t.TempDir()
+				p := filepath.Join(tmp, "sha.txt")
+				if err := os.WriteFile(p, []byte("abc"), 0o600); err != nil {
+					t.Fatalf("seed: %v", err)
+				}
+				sum := sha256.Sum256([]byte("abc"))
+				_, err := WriteFile(t.Context(), WriteFileArgs{
+					Path: p, Content: "new", Overwrite: true,
+					ExpectedSHA256: strings.ToUpper(hex.EncodeToString(sum[:])),
+				})
+				if err != nil {
+					t.Fatalf("WriteFile: %v", err)
+				}
+				b, _ := os.ReadFile(p)
+				if string(b) != "new" {
+					t.Fatalf("content mismatch: %q", string(b))
+				}
+			},
+		},
+		{
+			name: "expectedSHA256_mismatch_refuses",
+			run: func(t *testing.T) {
+				t.Helper()
+				tmp := t.TempDir()
+				p := filepath.Join(tmp, "sha.txt")
+				if err := os.WriteFile(p, []byte("changed"), 0o600); err != nil {
+					t.Fatalf("seed: %v", err)
+				}
+				sum := sha256.Sum256([]byte("abc"))
+				_, err := WriteFile(t.Context(), WriteFileArgs{
+					Path: p, Content: "new", Overwrite: true,
+					ExpectedSHA256: hex.EncodeToString(sum[:]),
+				})
+				if err == nil || !strings.Contains(err.Error(), "file content changed since read") {
+					t.Fatalf("expected content changed error, got %v", err)
+				}
+				b, _ := os.ReadFile(p)
+				if string(b) != "changed" {
+					t.Fatalf("expected original content preserved, got %q", string(b))
+				}
+			},
+		},
+		{
+			name: "expectedSHA256_missing_file_refuses",
+			run: func(t *testing.T) {
+				t.Helper()
+				tmp := t.TempDir()
+				p := filepath.Join(tmp, "gone.txt")
+				sum := sha256.Sum256([]byte("abc"))
+				_, err := WriteFile(t.Context(), WriteFileArgs{
+					Path: p, Content: "new", Overwrite: true,
+					ExpectedSHA256: hex.EncodeToString(sum[:]),
+				})
+				if err == nil || !strings.Contains(err.Error(), "file content changed since read") {
+					t.Fatalf("expected content changed error, got %v", err)
+				}
+				if _, statErr := os.Stat(p); statErr == nil {
+					t.Fatalf("did not expect file to be created")
+				}
+			},
+		},
+		{
+			name: "expectedSHA256_symlink_refuses",
+			run: func(t *testing.T) {
+				t.Helper()
+				tmp := t.TempDir()
+				target := filepath.Join(tmp, "target.txt")
+				if err := os.WriteFile(target, []byte("abc"), 0o600); err != nil {
+					t.Fatalf("seed: %v", err)
+				}
+				p := filepath.Join(tmp, "link.txt")
+				if err := os.Symlink(target, p); err != nil {
+					t.Skipf("symlink not supported: %v", err)
+				}
+				sum := sha256.Sum256([]byte("abc"))
+				_, err := WriteFile(t.Context(), WriteFileArgs{
+					Path: p, Content: "new", Overwrite: true,
+					ExpectedSHA256: hex.EncodeToString(sum[:]),
+				})
+				if err == nil || !strings.Contains(err.Error(), "symlink") {
+					t.Fatalf("expected symlink error, got %v", err)
+				}
+				b, _ := os.ReadFile(target)
+				if string(b) != "abc" {
+					t.Fatalf("expected target preserved, got %q", string(b))
+				}
+			},
+		},
+		{
+			name: "expectedSHA256_invalid_format_errors",
+			run: func(t *testing.T) {
+				t.Helper()
+				tmp := t.TempDir()
+				p := filepath.Join(tmp, "bad.txt")
+				_, err := WriteFile(t.Context(), WriteFileArgs{Path: p, Content: "x", ExpectedSHA256: "xyz"})
+				if err == nil {
+					t.Fatalf("expected error")
+				}
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -250,3 +429,152 @@ func TestWriteFile(t *testing.T) {
 		})
 	}
 }
+
+func TestWriteFile_DryRun(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name       string
+		setup      func(t *testing.T, dir string) WriteFileArgs
+		wantErrSub string
+		wantAction WriteFileAction
+		wantDirs   int
+		skipWin    bool
+	}{
+		{
+			name: "new_file_would_be_created",
+			setup: func(t *testing.T, dir string) WriteFileArgs {
+				t.Helper()
+				return WriteFileArgs{Path: filepath.Join(dir, "new.txt"), Content: "hello"}
+			},
+			wantAction: WriteFileActionCreate,
+		},
+		{
+			name: "existing_file_would_be_overwritten",
+			setup: func(t *testing.T, dir string) WriteFileArgs {
+				t.Helper()
+				p := filepath.Join(dir, "old.txt")
+				if err := os.WriteFile(p, []byte("original"), 0o600); err != nil {
+					t.Fatalf("WriteFile: %v", err)
+				}
+				return WriteFileArgs{Path: p, Content: "hello", Overwrite: true}
+			},
+			wantAction: WriteFileActionOverwrite,
+		},
+		{
+			name: "overwrite_false_collision_errors",
+			setup: func(t *testing.T, dir string) WriteFileArgs {
+				t.Helper()
+				p := filepath.Join(dir, "old.txt")
+				if err := os.WriteFile(p, []byte("original"), 0o600); err != nil {
+					t.Fatalf("WriteFile: %v", err)
+				}
+				return WriteFileArgs{Path: p, Content: "hello"}
+			},
+			wantErrSub: "overwrite=false",
+		},
+		{
+			name: "missing_parents_counted_not_created",
+			setup: func(t *testing.T, dir string) WriteFileArgs {
+				t.Helper()
+				return WriteFileArgs{Path: filepath.Join(dir, "a", "b", "c.txt"), Content: "x", CreateParents: true}
+			},
+			wantAction: WriteFileActionCreate,
+			wantDirs:   2,
+		},
+		{
+			name: "missing_parent_without_createParents_errors",
+			setup: func(t *testing.T, dir string) WriteFileArgs {
+				t.Helper()
+				return WriteFileArgs{Path: filepath.Join(dir, "a", "c.txt"), Content: "x"}
+			},
+			wantErrSub: "no such file",
+		},
+		{
+			name: "parent_depth_limit_errors",
+			setup: func(t *testing.T, dir string) WriteFileArgs {
+				t.Helper()
+				deep := filepath.Join(dir, "1", "2", "3", "4", "5", "6", "7", "8", "9", "f.txt")
+				return WriteFileArgs{Path: deep, Content: "x", CreateParents: true}
+			},
+			wantErrSub: "too many parent directories",
+		},
+		{
+			name: "directory_destination_errors",
+			setup: func(t *testing.T, dir string) WriteFileArgs {
+				t.Helper()
+				if err := os.Mkdir(filepath.Join(dir, "d"), 0o755); err != nil {
+					t.Fatalf("Mkdir: %v", err)
+				}
+				return WriteFileArgs{Path: filepath.Join(dir, "d"), Content: "x", Overwrite: true}
+			},
+			wantErrSub: "is a directory",
+		},
+		{
+			name: "symlink_parent_errors",
+			setup: func(t *testing.T, dir string) WriteFileArgs {
+				t.Helper()
+				if err := os.Mkdir(filepath.Join(dir, "real"), 0o755); err != nil {
+					t.Fatalf("Mkdir: %v", err)
+				}
+				if err := os.Symlink(filepath.Join(dir, "real"), filepath.Join(dir, "link")); err != nil {
+					t.Fatalf("Symlink: %v", err)
+				}
+				return WriteFileArgs{Path: filepath.Join(dir, "link", "f.txt"), Content: "x"}
+			},
+			wantErrSub: "symlink",
+			skipWin:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if tt.skipWin && runtime.GOOS == toolutil.GOOSWindows {
+				t.Skip("symlinks require elevated privileges on Windows")
+			}
+			dir := t.TempDir()
+			args := tt.setup(t, dir)
+			args.DryRun = true
+			before := snapshotTree(t, dir)
+
+			out, err := WriteFile(t.Context(), args)
+			if after := snapshotTree(t, dir); !slices.Equal(before, after) {
+				t.Fatalf("dry run changed the disk: before=%v after=%v", before, after)
+			}
+			if tt.wantErrSub != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErrSub) {
+					t.Fatalf("error=%v; want substring %q", err, tt.wantErrSub)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("WriteFile: %v", err)
+			}
+			if !out.DryRun || out.Action != tt.wantAction || out.CreatedDirs != tt.wantDirs ||
+				out.BytesWritten != int64(len(args.Content)) {
+				t.Fatalf("unexpected out: %+v", out)
+			}
+		})
+	}
+}
+
+// snapshotTree lists every path under dir with its size, for change detection.
+func snapshotTree(t *testing.T, dir string) []string {
+	t.Helper()
+	var out []string
+	err := filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		out = append(out, fmt.Sprintf("%s:%d", p, info.Size()))
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("WalkDir: %v", err)
+	}
+	return out
+}
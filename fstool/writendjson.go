@@ -0,0 +1,127 @@
+package fstool
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/flexigpt/llmtools-go/internal/fileutil"
+	"github.com/flexigpt/llmtools-go/internal/jsonutil"
+	"github.com/flexigpt/llmtools-go/internal/toolutil"
+	"github.com/flexigpt/llmtools-go/spec"
+)
+
+const writeNDJSONFuncID spec.FuncID = "github.com/flexigpt/llmtools-go/fstool/writendjson.WriteNDJSON"
+
+var writeNDJSONTool = spec.Tool{
+	SchemaVersion: spec.SchemaVersion,
+	ID:            "01a13d78-21d5-7464-a9de-fda7a0fc80d1",
+	Slug:          "writendjson",
+	Version:       "v1.0.0",
+	DisplayName:   "Write NDJSON file",
+	Description:   "Write a JSON array to disk as newline-delimited JSON (one compact element per line). The file is written atomically.",
+	Tags:          []string{"fs", "json"},
+
+	ArgSchema: spec.JSONSchema(`{
+"$schema": "http://json-schema.org/draft-07/schema#",
+"type": "object",
+"properties": {
+	"path": {
+		"type": "string",
+		"description": "Absolute path of the NDJSON file to write."
+	},
+	"items": {
+		"type": "array",
+		"description": "JSON array; each element becomes one line."
+	},
+	"overwrite": {
+		"type": "boolean",
+		"description": "If false and the file exists, return an error.",
+		"default": false
+	}
+},
+"required": ["path", "items"],
+"additionalProperties": false
+}`),
+	GoImpl: spec.GoToolImpl{FuncID: writeNDJSONFuncID},
+
+	CreatedAt:  spec.SchemaStartTime,
+	ModifiedAt: spec.SchemaStartTime,
+}
+
+func WriteNDJSONTool() spec.Tool {
+	return toolutil.CloneTool(writeNDJSONTool)
+}
+
+type WriteNDJSONArgs struct {
+	Path      string          `json:"path"`
+	Items     json.RawMessage `json:"items"` // must be a JSON array
+	Overwrite bool            `json:"overwrite,omitempty"`
+}
+
+type WriteNDJSONOut struct {
+	Path         string `json:"path"`
+	LinesWritten int    `json:"linesWritten"`
+	BytesWritten int64  `json:"bytesWritten"`
+}
+
+// WriteNDJSON writes each element of the Items array as one line of Path,
+// committing via temp file + rename so readers never see a partial file.
+func WriteNDJSON(ctx context.Context, args WriteNDJSONArgs) (*WriteNDJSONOut, error) {
+	return toolutil.WithRecoveryResp(func() (*WriteNDJSONOut, error) {
+		return writeNDJSON(ctx, args)
+	})
+}
+
+func writeNDJSON(ctx context.Context, args WriteNDJSONArgs) (*WriteNDJSONOut, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if err := confinePaths(&args.Path); err != nil {
+		return nil, err
+	}
+
+	p, err := fileutil.NormalizeAbsPath(strings.TrimSpace(args.Path))
+	if err != nil {
+		return nil, err
+	}
+
+	raw := strings.TrimSpace(string(args.Items))
+	if !strings.HasPrefix(raw, "[") {
+		return nil, errors.New("items must be a JSON array")
+	}
+	var items []json.RawMessage
+	if err := json.Unmarshal([]byte(raw), &items); err != nil {
+		return nil, fmt.Errorf("invalid items: %w", err)
+	}
+
+	data, err := jsonutil.EncodeNDJSON(items)
+	if err != nil {
+		return nil, err
+	}
+	if maxWrite := toolutil.GetLimits().MaxFileWrite; int64(len(data)) > maxWrite {
+		return nil, fmt.Errorf("content too large (%d bytes; max %d)", len(data), maxWrite)
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	if err := fileutil.VerifyDirNoSymlink(filepath.Dir(p)); err != nil {
+		return nil, err
+	}
+	if err := fileutil.WriteFileAtomicBytes(p, data, 0o600, args.Overwrite); err != nil {
+		if !args.Overwrite && errors.Is(err, os.ErrExist) {
+			return nil, fmt.Errorf("file already exists and overwrite=false: %s", p)
+		}
+		return nil, err
+	}
+	return &WriteNDJSONOut{
+		Path:         p,
+		LinesWritten: len(items),
+		BytesWritten: int64(len(data)),
+	}, nil
+}
@@ -0,0 +1,104 @@
+package fstool
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestWriteNDJSON(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		items     string
+		seed      string
+		overwrite bool
+		wantErr   string
+		wantLines []any
+	}{
+		{
+			name:      "array_round_trip",
+			items:     `[{"a": 1, "b": [1, 2]}, "two\nlines", 3, null]`,
+			wantLines: []any{map[string]any{"a": 1.0, "b": []any{1.0, 2.0}}, "two\nlines", 3.0, nil},
+		},
+		{
+			name:      "empty_array_writes_empty_file",
+			items:     `[]`,
+			wantLines: nil,
+		},
+		{
+			name:    "object_rejected",
+			items:   `{"a": 1}`,
+			wantErr: "must be a JSON array",
+		},
+		{
+			name:    "existing_without_overwrite",
+			items:   `[1]`,
+			seed:    "old",
+			wantErr: "overwrite=false",
+		},
+		{
+			name:      "existing_with_overwrite",
+			items:     `[1]`,
+			seed:      "old",
+			overwrite: true,
+			wantLines: []any{1.0},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			p := filepath.Join(t.TempDir(), "out.ndjson")
+			if tt.seed != "" {
+				if err := os.WriteFile(p, []byte(tt.seed), 0o600); err != nil {
+					t.Fatalf("seed: %v", err)
+				}
+			}
+
+			out, err := WriteNDJSON(t.Context(), WriteNDJSONArgs{
+				Path:      p,
+				Items:     json.RawMessage(tt.items),
+				Overwrite: tt.overwrite,
+			})
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("WriteNDJSON: %v", err)
+			}
+			if out.LinesWritten != len(tt.wantLines) {
+				t.Fatalf("LinesWritten: got %d want %d", out.LinesWritten, len(tt.wantLines))
+			}
+
+			f, err := os.Open(p)
+			if err != nil {
+				t.Fatalf("open: %v", err)
+			}
+			defer f.Close()
+			var got []any
+			sc := bufio.NewScanner(f)
+			for sc.Scan() {
+				var v any
+				if err := json.Unmarshal(sc.Bytes(), &v); err != nil {
+					t.Fatalf("line %q is not JSON: %v", sc.Text(), err)
+				}
+				got = append(got, v)
+			}
+			if err := sc.Err(); err != nil {
+				t.Fatalf("scan: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.wantLines) {
+				t.Fatalf("round trip mismatch: got %#v want %#v", got, tt.wantLines)
+			}
+		})
+	}
+}
@@ -0,0 +1,134 @@
+package llmtools
+
+import (
+	"container/list"
+	"sync"
+	"time"
+
+	"github.com/flexigpt/llmtools-go/internal/toolutil"
+	"github.com/flexigpt/llmtools-go/spec"
+)
+
+const (
+	defaultIdempotencyTTL        = 10 * time.Minute
+	defaultIdempotencyMaxEntries = 256
+)
+
+type idempotencyKey struct {
+	funcID spec.FuncID
+	key    string
+}
+
+// idempotencyEntry is a call result, or a call still in flight (ready not yet closed).
+type idempotencyEntry struct {
+	k       idempotencyKey
+	ready   chan struct{}
+	outputs []spec.ToolStoreOutputUnion
+	err     error
+	expires time.Time // zero while in flight
+}
+
+// idempotencyCache remembers successful call outputs by (funcID, key) for a
+// TTL, bounded by LRU eviction. Failed calls are not cached.
+type idempotencyCache struct {
+	mu  sync.Mutex
+	ttl time.Duration // <=0 disables caching
+	max int           // <=0 means unbounded
+
+	lru *list.List // front=most recently used; Value=*idempotencyEntry
+	m   map[idempotencyKey]*list.Element
+}
+
+func newIdempotencyCache(ttl time.Duration, maxEntries int) *idempotencyCache {
+	return &idempotencyCache{
+		ttl: ttl,
+		max: maxEntries,
+		lru: list.New(),
+		m:   map[idempotencyKey]*list.Element{},
+	}
+}
+
+func (c *idempotencyCache) enabled() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.ttl > 0
+}
+
+// acquire returns the live entry for k, or registers a new in-flight entry
+// and reports leader=true; the leader must call finish.
+func (c *idempotencyCache) acquire(k idempotencyKey) (e *idempotencyEntry, leader bool) {
+	now := toolutil.Now()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.evictExpiredLocked(now)
+
+	if el, ok := c.m[k]; ok {
+		c.lru.MoveToFront(el)
+		e, _ = el.Value.(*idempotencyEntry)
+		return e, false
+	}
+	e = &idempotencyEntry{k: k, ready: make(chan struct{})}
+	c.m[k] = c.lru.PushFront(e)
+	c.evictOverLimitLocked()
+	return e, true
+}
+
+// finish publishes the leader's result. Successful outputs are kept for the
+// TTL; failures are dropped so a retry executes again.
+func (c *idempotencyCache) finish(e *idempotencyEntry, outputs []spec.ToolStoreOutputUnion, err error) {
+	now := toolutil.Now()
+	c.mu.Lock()
+	e.outputs = cloneOutputs(outputs)
+	e.err = err
+	e.expires = now.Add(c.ttl)
+	if el, ok := c.m[e.k]; ok && el.Value == e && err != nil {
+		c.deleteElemLocked(el)
+	}
+	c.mu.Unlock()
+	close(e.ready)
+}
+
+func (c *idempotencyCache) evictExpiredLocked(now time.Time) {
+	// Entries are not ordered by expiry, so scan; the cache is small and bounded.
+	for el := c.lru.Back(); el != nil; {
+		prev := el.Prev()
+		if e, _ := el.Value.(*idempotencyEntry); e == nil || (!e.expires.IsZero() && !now.Before(e.expires)) {
+			c.deleteElemLocked(el)
+		}
+		el = prev
+	}
+}
+
+func (c *idempotencyCache) evictOverLimitLocked() {
+	if c.max <= 0 {
+		return
+	}
+	for c.lru.Len() > c.max {
+		c.deleteElemLocked(c.lru.Back())
+	}
+}
+
+func (c *idempotencyCache) deleteElemLocked(el *list.Element) {
+	if e, _ := el.Value.(*idempotencyEntry); e != nil {
+		delete(c.m, e.k)
+	}
+	c.lru.Remove(el)
+}
+
+func (c *idempotencyCache) sizeForTest() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.m)
+}
+
+// cloneOutputs deep-copies outputs so cached results cannot be mutated by callers.
+func cloneOutputs(in []spec.ToolStoreOutputUnion) []spec.ToolStoreOutputUnion {
+	if in == nil {
+		return nil
+	}
+	out := make([]spec.ToolStoreOutputUnion, len(in))
+	for i, o := range in {
+		out[i] = toolutil.CloneToolOutput(o)
+	}
+	return out
+}
@@ -0,0 +1,253 @@
+package llmtools
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/flexigpt/llmtools-go/spec"
+)
+
+type fakeClock struct {
+	mu sync.Mutex
+	t  time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.t
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	c.t = c.t.Add(d)
+	c.mu.Unlock()
+}
+
+// countingTool registers a tool whose outputs are "run-<n>" for the n-th execution.
+func countingTool(t *testing.T, r *Registry, funcID string, failFirst bool) (spec.FuncID, *atomic.Int32) {
+	t.Helper()
+	var runs atomic.Int32
+	tool := mkTool(funcID, "counting_"+funcID[strings.LastIndex(funcID, ".")+1:]) // slugs are unique per registry
+	fn := func(context.Context, json.RawMessage) ([]spec.ToolStoreOutputUnion, error) {
+		n := runs.Add(1)
+		if failFirst && n == 1 {
+			return nil, errors.New("transient failure")
+		}
+		return textOut(fmt.Sprintf("run-%d", n)), nil
+	}
+	if err := r.RegisterTool(tool, fn); err != nil {
+		t.Fatalf("RegisterTool error: %v", err)
+	}
+	return tool.GoImpl.FuncID, &runs
+}
+
+func TestRegistry_Call_IdempotencyKey(t *testing.T) {
+	type call struct {
+		key       string
+		advance   time.Duration
+		want      string
+		wantErr   bool
+		otherTool bool
+	}
+	tests := []struct {
+		name      string
+		opts      []RegistryOption
+		failFirst bool
+		calls     []call
+		wantRuns  int32
+	}{
+		{
+			name:     "retry with same key returns cached result",
+			calls:    []call{{key: "k1", want: "run-1"}, {key: "k1", want: "run-1"}},
+			wantRuns: 1,
+		},
+		{
+			name:     "different keys execute separately",
+			calls:    []call{{key: "k1", want: "run-1"}, {key: "k2", want: "run-2"}, {key: "k1", want: "run-1"}},
+			wantRuns: 2,
+		},
+		{
+			name:     "no key always executes",
+			calls:    []call{{want: "run-1"}, {want: "run-2"}},
+			wantRuns: 2,
+		},
+		{
+			name:     "same key on another tool is independent",
+			calls:    []call{{key: "k1", want: "run-1"}, {key: "k1", otherTool: true, want: "run-1"}},
+			wantRuns: 1,
+		},
+		{
+			name:      "failed call is not cached",
+			failFirst: true,
+			calls:     []call{{key: "k1", wantErr: true}, {key: "k1", want: "run-2"}, {key: "k1", want: "run-2"}},
+			wantRuns:  2,
+		},
+		{
+			name: "entry expires after ttl",
+			opts: []RegistryOption{WithIdempotencyCache(time.Minute, 10)},
+			calls: []call{
+				{key: "k1", want: "run-1"},
+				{key: "k1", advance: 59 * time.Second, want: "run-1"},
+				{key: "k1", advance: time.Second, want: "run-2"},
+			},
+			wantRuns: 2,
+		},
+		{
+			name:     "max entries evicts least recently used",
+			opts:     []RegistryOption{WithIdempotencyCache(time.Minute, 1)},
+			calls:    []call{{key: "k1", want: "run-1"}, {key: "k2", want: "run-2"}, {key: "k1", want: "run-3"}},
+			wantRuns: 3,
+		},
+		{
+			name:     "zero ttl disables cache",
+			opts:     []RegistryOption{WithIdempotencyCache(0, 10)},
+			calls:    []call{{key: "k1", want: "run-1"}, {key: "k1", want: "run-2"}},
+			wantRuns: 2,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			fc := &fakeClock{t: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)}
+			t.Cleanup(SetClock(fc))
+
+			r, err := NewRegistry(tc.opts...)
+			if err != nil {
+				t.Fatalf("NewRegistry error: %v", err)
+			}
+			funcID, runs := countingTool(t, r, "github.com/acme/tools.Counting", tc.failFirst)
+			otherID, otherRuns := countingTool(t, r, "github.com/acme/tools.Other", false)
+
+			for i, c := range tc.calls {
+				fc.Advance(c.advance)
+				id := funcID
+				if c.otherTool {
+					id = otherID
+				}
+				var opts []CallOption
+				if c.key != "" {
+					opts = append(opts, WithIdempotencyKey(c.key))
+				}
+				out, err := r.Call(t.Context(), id, json.RawMessage(`{}`), opts...)
+				if c.wantErr {
+					if err == nil {
+						t.Fatalf("call %d: expected error", i)
+					}
+					continue
+				}
+				if err != nil {
+					t.Fatalf("call %d: unexpected error: %v", i, err)
+				}
+				if len(out) != 1 || out[0].TextItem == nil || out[0].TextItem.Text != c.want {
+					t.Fatalf("call %d: got %#v want text %q", i, out, c.want)
+				}
+			}
+			if got := runs.Load(); got != tc.wantRuns {
+				t.Fatalf("tool ran %d times, want %d", got, tc.wantRuns)
+			}
+			if otherRuns.Load() > 1 {
+				t.Fatalf("other tool ran %d times", otherRuns.Load())
+			}
+		})
+	}
+}
+
+func TestRegistry_Call_IdempotencyKey_CachedOutputsAreCopies(t *testing.T) {
+	r, err := NewRegistry()
+	if err != nil {
+		t.Fatalf("NewRegistry error: %v", err)
+	}
+	funcID, _ := countingTool(t, r, "github.com/acme/tools.Counting", false)
+
+	first, err := r.Call(t.Context(), funcID, json.RawMessage(`{}`), WithIdempotencyKey("k"))
+	if err != nil {
+		t.Fatalf("Call error: %v", err)
+	}
+	first[0].TextItem.Text = "mutated"
+
+	second, err := r.Call(t.Context(), funcID, json.RawMessage(`{}`), WithIdempotencyKey("k"))
+	if err != nil {
+		t.Fatalf("Call error: %v", err)
+	}
+	second[0].TextItem.Text += "!"
+
+	third, err := r.Call(t.Context(), funcID, json.RawMessage(`{}`), WithIdempotencyKey("k"))
+	if err != nil {
+		t.Fatalf("Call error: %v", err)
+	}
+	if third[0].TextItem.Text != "run-1" {
+		t.Fatalf("cached output was mutated: %q", third[0].TextItem.Text)
+	}
+}
+
+func TestRegistry_Call_IdempotencyKey_InFlightCallIsShared(t *testing.T) {
+	r, err := NewRegistry()
+	if err != nil {
+		t.Fatalf("NewRegistry error: %v", err)
+	}
+	var runs atomic.Int32
+	started := make(chan struct{})
+	release := make(chan struct{})
+	tool := mkTool("github.com/acme/tools.Slow", "slow")
+	fn := func(context.Context, json.RawMessage) ([]spec.ToolStoreOutputUnion, error) {
+		runs.Add(1)
+		close(started)
+		<-release
+		return textOut("done"), nil
+	}
+	if err := r.RegisterTool(tool, fn); err != nil {
+		t.Fatalf("RegisterTool error: %v", err)
+	}
+
+	type result struct {
+		out []spec.ToolStoreOutputUnion
+		err error
+	}
+	results := make(chan result, 2)
+	call := func() {
+		out, err := r.Call(t.Context(), tool.GoImpl.FuncID, json.RawMessage(`{}`), WithIdempotencyKey("k"))
+		results <- result{out, err}
+	}
+	go call()
+	<-started
+	go call()
+
+	// The retry must not start a second execution while the first is in flight.
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+	for range 2 {
+		res := <-results
+		if res.err != nil || len(res.out) != 1 || res.out[0].TextItem.Text != "done" {
+			t.Fatalf("unexpected result: %#v, %v", res.out, res.err)
+		}
+	}
+	if got := runs.Load(); got != 1 {
+		t.Fatalf("tool ran %d times, want 1", got)
+	}
+
+	t.Run("waiter honors its own context", func(t *testing.T) {
+		c := newIdempotencyCache(time.Minute, 10)
+		k := idempotencyKey{funcID: "f", key: "k"}
+		if _, leader := c.acquire(k); !leader {
+			t.Fatal("expected first acquire to lead")
+		}
+		r2 := &Registry{idempotency: c}
+		ctx, cancel := context.WithCancel(t.Context())
+		cancel()
+		_, err := r2.callIdempotent(ctx, k, nil, nil)
+		if !errors.Is(err, context.Canceled) {
+			t.Fatalf("expected context.Canceled, got %v", err)
+		}
+		if c.sizeForTest() != 1 {
+			t.Fatalf("in-flight entry should remain, size=%d", c.sizeForTest())
+		}
+	})
+}
@@ -0,0 +1,143 @@
+package imagetool
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+
+	"github.com/flexigpt/llmtools-go/internal/fileutil"
+	"github.com/flexigpt/llmtools-go/internal/toolutil"
+	"github.com/flexigpt/llmtools-go/spec"
+)
+
+const convertImageFuncID spec.FuncID = "github.com/flexigpt/llmtools-go/imagetool/convertimage.ConvertImage"
+
+var convertImageTool = spec.Tool{
+	SchemaVersion: spec.SchemaVersion,
+	ID:            "01a13d73-dd2c-7cc9-9f4c-82a53e040fb5",
+	Slug:          "convertimage",
+	Version:       "v1.0.0",
+	DisplayName:   "Convert image",
+	Description:   "Convert a local image to png, jpeg or gif and return the base64-encoded result. Animated GIFs are reduced to their first frame.",
+	Tags:          []string{"image", "file"},
+
+	ArgSchema: spec.JSONSchema(`{
+"$schema": "http://json-schema.org/draft-07/schema#",
+"type": "object",
+"properties": {
+	"path": {
+		"type": "string",
+		"description": "Absolute or relative path of the image to convert."
+	},
+	"format": {
+		"type": "string",
+		"enum": ["png", "jpeg", "gif"],
+		"description": "Target image format."
+	},
+	"quality": {
+		"type": "integer",
+		"minimum": 1,
+		"maximum": 100,
+		"description": "JPEG quality (1-100). Ignored for other formats. Default is 75."
+	}
+},
+"required": ["path", "format"],
+"additionalProperties": false
+}`),
+	GoImpl: spec.GoToolImpl{FuncID: convertImageFuncID},
+
+	CreatedAt:  spec.SchemaStartTime,
+	ModifiedAt: spec.SchemaStartTime,
+}
+
+func ConvertImageTool() spec.Tool {
+	return toolutil.CloneTool(convertImageTool)
+}
+
+type ConvertImageArgs struct {
+	Path    string `json:"path"`
+	Format  string `json:"format"`            // "png" | "jpeg" | "gif"
+	Quality int    `json:"quality,omitempty"` // JPEG only
+}
+
+type ConvertImageOut struct {
+	Path         string `json:"path"`
+	SourceFormat string `json:"sourceFormat"`
+
+	Format    string `json:"format"`
+	MIMEType  string `json:"mimeType"`
+	Width     int    `json:"width"`
+	Height    int    `json:"height"`
+	SizeBytes int64  `json:"sizeBytes"`
+
+	// FirstFrameOnly is set when the source had multiple frames (animated GIF)
+	// and only the first one was converted.
+	FirstFrameOnly   bool `json:"firstFrameOnly,omitempty"`
+	SourceFrameCount int  `json:"sourceFrameCount,omitempty"`
+
+	Base64Data string `json:"base64Data"`
+}
+
+// ConvertImage decodes a local image and re-encodes it in the requested format.
+// Semantics:
+//   - empty/missing path => error
+//   - unsupported target format => error
+//   - animated GIF source => first frame only, FirstFrameOnly=true.
+func ConvertImage(ctx context.Context, args ConvertImageArgs) (*ConvertImageOut, error) {
+	return toolutil.WithRecoveryResp(func() (*ConvertImageOut, error) {
+		return convertImage(ctx, args)
+	})
+}
+
+func convertImage(ctx context.Context, args ConvertImageArgs) (*ConvertImageOut, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	format := fileutil.NormalizeImageFormat(args.Format)
+	if format == "" {
+		return nil, errors.New("format is required")
+	}
+	if format != "jpeg" && args.Quality != 0 {
+		return nil, errors.New("quality is only supported for jpeg")
+	}
+
+	maxRead := toolutil.GetLimits().MaxFileRead
+	src, err := fileutil.DecodeImageFile(args.Path, maxRead)
+	if err != nil {
+		return nil, err
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	data, mt, err := fileutil.EncodeImage(src.Image, format, args.Quality)
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > maxRead {
+		return nil, fmt.Errorf(
+			"converted image too large (%d bytes; max %d)",
+			len(data),
+			maxRead,
+		)
+	}
+
+	b := src.Image.Bounds()
+	out := &ConvertImageOut{
+		Path:         src.Path,
+		SourceFormat: src.Format,
+		Format:       format,
+		MIMEType:     string(mt),
+		Width:        b.Dx(),
+		Height:       b.Dy(),
+		SizeBytes:    int64(len(data)),
+		Base64Data:   base64.StdEncoding.EncodeToString(data),
+	}
+	if src.FrameCount > 1 {
+		out.FirstFrameOnly = true
+		out.SourceFrameCount = src.FrameCount
+	}
+	return out, nil
+}
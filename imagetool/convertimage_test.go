@@ -0,0 +1,155 @@
+package imagetool
+
+import (
+	"bytes"
+	"encoding/base64"
+	"image"
+	"image/color"
+	"image/gif"
+	"image/jpeg"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestConvertImage(t *testing.T) {
+	tmpDir := t.TempDir()
+	pngPath := filepath.Join(tmpDir, "img.png")
+	writePNG(t, pngPath, 40, 30)
+
+	gifPath := filepath.Join(tmpDir, "anim.gif")
+	writeAnimatedGIF(t, gifPath, 12, 10, 3)
+
+	type tc struct {
+		name    string
+		args    ConvertImageArgs
+		wantErr bool
+		check   func(t *testing.T, out *ConvertImageOut)
+	}
+	tests := []tc{
+		{
+			name: "png to jpeg keeps dimensions",
+			args: ConvertImageArgs{Path: pngPath, Format: "jpeg", Quality: 90},
+			check: func(t *testing.T, out *ConvertImageOut) {
+				t.Helper()
+				if out.Format != "jpeg" || out.MIMEType != "image/jpeg" {
+					t.Fatalf("unexpected format/mime: %q / %q", out.Format, out.MIMEType)
+				}
+				if out.SourceFormat != "png" {
+					t.Fatalf("SourceFormat mismatch: got %q want %q", out.SourceFormat, "png")
+				}
+				raw, err := base64.StdEncoding.DecodeString(out.Base64Data)
+				if err != nil {
+					t.Fatalf("base64 decode: %v", err)
+				}
+				if out.SizeBytes != int64(len(raw)) {
+					t.Fatalf("SizeBytes mismatch: got %d want %d", out.SizeBytes, len(raw))
+				}
+				img, err := jpeg.Decode(bytes.NewReader(raw))
+				if err != nil {
+					t.Fatalf("decode converted jpeg: %v", err)
+				}
+				if b := img.Bounds(); b.Dx() != 40 || b.Dy() != 30 {
+					t.Fatalf("unexpected dimensions: %dx%d", b.Dx(), b.Dy())
+				}
+				if out.FirstFrameOnly {
+					t.Fatalf("did not expect FirstFrameOnly for a still image")
+				}
+			},
+		},
+		{
+			name: "jpg alias accepted",
+			args: ConvertImageArgs{Path: pngPath, Format: "JPG"},
+			check: func(t *testing.T, out *ConvertImageOut) {
+				t.Helper()
+				if out.Format != "jpeg" {
+					t.Fatalf("Format mismatch: got %q want %q", out.Format, "jpeg")
+				}
+			},
+		},
+		{
+			name: "animated gif to png takes first frame",
+			args: ConvertImageArgs{Path: gifPath, Format: "png"},
+			check: func(t *testing.T, out *ConvertImageOut) {
+				t.Helper()
+				if !out.FirstFrameOnly || out.SourceFrameCount != 3 {
+					t.Fatalf("expected FirstFrameOnly with 3 frames, got %+v", out)
+				}
+				if out.Width != 12 || out.Height != 10 {
+					t.Fatalf("unexpected dimensions: %dx%d", out.Width, out.Height)
+				}
+				raw, err := base64.StdEncoding.DecodeString(out.Base64Data)
+				if err != nil {
+					t.Fatalf("base64 decode: %v", err)
+				}
+				if _, format, err := image.Decode(bytes.NewReader(raw)); err != nil || format != "png" {
+					t.Fatalf("expected decodable png, got format=%q err=%v", format, err)
+				}
+			},
+		},
+		{
+			name:    "unsupported target format",
+			args:    ConvertImageArgs{Path: pngPath, Format: "webp"},
+			wantErr: true,
+		},
+		{
+			name:    "quality out of range",
+			args:    ConvertImageArgs{Path: pngPath, Format: "jpeg", Quality: 101},
+			wantErr: true,
+		},
+		{
+			name:    "quality with non-jpeg target",
+			args:    ConvertImageArgs{Path: pngPath, Format: "png", Quality: 50},
+			wantErr: true,
+		},
+		{
+			name:    "missing file errors",
+			args:    ConvertImageArgs{Path: filepath.Join(tmpDir, "missing.png"), Format: "png"},
+			wantErr: true,
+		},
+		{
+			name:    "missing format errors",
+			args:    ConvertImageArgs{Path: pngPath},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, err := ConvertImage(t.Context(), tt.args)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected error, got nil (out=%+v)", out)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if tt.check != nil {
+				tt.check(t, out)
+			}
+		})
+	}
+}
+
+func writeAnimatedGIF(t *testing.T, path string, w, h, frames int) {
+	t.Helper()
+
+	pal := color.Palette{color.Black, color.White}
+	g := &gif.GIF{}
+	for i := range frames {
+		fr := image.NewPaletted(image.Rect(0, 0, w, h), pal)
+		fr.SetColorIndex(i%w, 0, 1)
+		g.Image = append(g.Image, fr)
+		g.Delay = append(g.Delay, 10)
+	}
+
+	var buf bytes.Buffer
+	if err := gif.EncodeAll(&buf, g); err != nil {
+		t.Fatalf("encode gif: %v", err)
+	}
+	if err := os.WriteFile(path, buf.Bytes(), 0o600); err != nil {
+		t.Fatalf("write gif: %v", err)
+	}
+}
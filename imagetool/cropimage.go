@@ -0,0 +1,174 @@
+package imagetool
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"image"
+	"image/draw"
+
+	"github.com/flexigpt/llmtools-go/internal/fileutil"
+	"github.com/flexigpt/llmtools-go/internal/toolutil"
+	"github.com/flexigpt/llmtools-go/spec"
+)
+
+const cropImageFuncID spec.FuncID = "github.com/flexigpt/llmtools-go/imagetool/cropimage.CropImage"
+
+var cropImageTool = spec.Tool{
+	SchemaVersion: spec.SchemaVersion,
+	ID:            "01a13d75-f6e3-7299-b46b-05072b0a4454",
+	Slug:          "cropimage",
+	Version:       "v1.0.0",
+	DisplayName:   "Crop image",
+	Description:   "Crop a rectangle (in pixels, origin at top-left) out of a local image and return the base64-encoded result in the source format.",
+	Tags:          []string{"image", "file"},
+
+	ArgSchema: spec.JSONSchema(`{
+"$schema": "http://json-schema.org/draft-07/schema#",
+"type": "object",
+"properties": {
+	"path": {
+		"type": "string",
+		"description": "Absolute or relative path of the image to crop."
+	},
+	"x": {
+		"type": "integer",
+		"description": "Left edge of the crop rectangle.",
+		"default": 0
+	},
+	"y": {
+		"type": "integer",
+		"description": "Top edge of the crop rectangle.",
+		"default": 0
+	},
+	"width": {
+		"type": "integer",
+		"minimum": 1,
+		"description": "Width of the crop rectangle."
+	},
+	"height": {
+		"type": "integer",
+		"minimum": 1,
+		"description": "Height of the crop rectangle."
+	},
+	"strict": {
+		"type": "boolean",
+		"description": "If true, a rectangle extending outside the image is an error. If false, it is clamped to the image bounds.",
+		"default": false
+	}
+},
+"required": ["path", "width", "height"],
+"additionalProperties": false
+}`),
+	GoImpl: spec.GoToolImpl{FuncID: cropImageFuncID},
+
+	CreatedAt:  spec.SchemaStartTime,
+	ModifiedAt: spec.SchemaStartTime,
+}
+
+func CropImageTool() spec.Tool {
+	return toolutil.CloneTool(cropImageTool)
+}
+
+type CropImageArgs struct {
+	Path   string `json:"path"`
+	X      int    `json:"x,omitempty"`
+	Y      int    `json:"y,omitempty"`
+	Width  int    `json:"width"`
+	Height int    `json:"height"`
+	Strict bool   `json:"strict,omitempty"`
+}
+
+type CropImageOut struct {
+	Path     string `json:"path"`
+	Format   string `json:"format"`
+	MIMEType string `json:"mimeType"`
+
+	// Effective crop rectangle after clamping.
+	X      int `json:"x"`
+	Y      int `json:"y"`
+	Width  int `json:"width"`
+	Height int `json:"height"`
+	// Clamped is true if the requested rectangle was reduced to fit the image.
+	Clamped bool `json:"clamped,omitempty"`
+
+	SizeBytes  int64  `json:"sizeBytes"`
+	Base64Data string `json:"base64Data"`
+}
+
+// CropImage crops a rectangle out of a local image.
+// Semantics:
+//   - non-positive width/height => error
+//   - rectangle partially outside the image => clamped (Strict=false) or error (Strict=true)
+//   - rectangle fully outside the image => error
+//   - animated GIF source => first frame only.
+func CropImage(ctx context.Context, args CropImageArgs) (*CropImageOut, error) {
+	return toolutil.WithRecoveryResp(func() (*CropImageOut, error) {
+		return cropImage(ctx, args)
+	})
+}
+
+func cropImage(ctx context.Context, args CropImageArgs) (*CropImageOut, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if args.Width <= 0 || args.Height <= 0 {
+		return nil, errors.New("width and height must be positive")
+	}
+
+	src, err := fileutil.DecodeImageFile(args.Path, toolutil.GetLimits().MaxFileRead)
+	if err != nil {
+		return nil, err
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	bounds := src.Image.Bounds()
+	want := image.Rect(args.X, args.Y, args.X+args.Width, args.Y+args.Height).Add(bounds.Min)
+	rect := want.Intersect(bounds)
+	if rect.Empty() {
+		return nil, fmt.Errorf(
+			"crop rectangle (%d,%d %dx%d) does not overlap image bounds %dx%d",
+			args.X, args.Y, args.Width, args.Height, bounds.Dx(), bounds.Dy(),
+		)
+	}
+	clamped := rect != want
+	if clamped && args.Strict {
+		return nil, fmt.Errorf(
+			"crop rectangle (%d,%d %dx%d) exceeds image bounds %dx%d",
+			args.X, args.Y, args.Width, args.Height, bounds.Dx(), bounds.Dy(),
+		)
+	}
+
+	data, mt, err := fileutil.EncodeImage(subImage(src.Image, rect), src.Format, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	return &CropImageOut{
+		Path:       src.Path,
+		Format:     src.Format,
+		MIMEType:   string(mt),
+		X:          rect.Min.X - bounds.Min.X,
+		Y:          rect.Min.Y - bounds.Min.Y,
+		Width:      rect.Dx(),
+		Height:     rect.Dy(),
+		Clamped:    clamped,
+		SizeBytes:  int64(len(data)),
+		Base64Data: base64.StdEncoding.EncodeToString(data),
+	}, nil
+}
+
+// subImage returns the r portion of img, sharing pixels when the concrete type supports it.
+func subImage(img image.Image, r image.Rectangle) image.Image {
+	if si, ok := img.(interface {
+		SubImage(r image.Rectangle) image.Image
+	}); ok {
+		return si.SubImage(r)
+	}
+	dst := image.NewRGBA(image.Rect(0, 0, r.Dx(), r.Dy()))
+	draw.Draw(dst, dst.Bounds(), img, r.Min, draw.Src)
+	return dst
+}
@@ -0,0 +1,117 @@
+package imagetool
+
+import (
+	"bytes"
+	"encoding/base64"
+	"image"
+	"image/color"
+	"image/png"
+	"path/filepath"
+	"testing"
+)
+
+func TestCropImage(t *testing.T) {
+	tmpDir := t.TempDir()
+	imgPath := filepath.Join(tmpDir, "fixture.png")
+
+	// 200x200 fixture: top-left quadrant is green, rest is red.
+	fixture := image.NewRGBA(image.Rect(0, 0, 200, 200))
+	for y := range 200 {
+		for x := range 200 {
+			c := color.RGBA{R: 255, A: 255}
+			if x < 100 && y < 100 {
+				c = color.RGBA{G: 255, A: 255}
+			}
+			fixture.SetRGBA(x, y, c)
+		}
+	}
+	writeImageFile(t, imgPath, fixture, "png")
+
+	type tc struct {
+		name    string
+		args    CropImageArgs
+		wantErr bool
+		check   func(t *testing.T, out *CropImageOut, img image.Image)
+	}
+	tests := []tc{
+		{
+			name: "crop 50x50 region",
+			args: CropImageArgs{Path: imgPath, X: 25, Y: 25, Width: 50, Height: 50},
+			check: func(t *testing.T, out *CropImageOut, img image.Image) {
+				t.Helper()
+				if out.Width != 50 || out.Height != 50 || out.Clamped {
+					t.Fatalf("unexpected out: %+v", out)
+				}
+				if b := img.Bounds(); b.Dx() != 50 || b.Dy() != 50 {
+					t.Fatalf("unexpected decoded dimensions: %dx%d", b.Dx(), b.Dy())
+				}
+				if out.Format != "png" || out.MIMEType != "image/png" {
+					t.Fatalf("unexpected format/mime: %q / %q", out.Format, out.MIMEType)
+				}
+				r, g, _, _ := img.At(img.Bounds().Min.X, img.Bounds().Min.Y).RGBA()
+				if r != 0 || g == 0 {
+					t.Fatalf("expected green pixel from top-left quadrant")
+				}
+			},
+		},
+		{
+			name: "out of bounds is clamped",
+			args: CropImageArgs{Path: imgPath, X: 180, Y: 190, Width: 50, Height: 50},
+			check: func(t *testing.T, out *CropImageOut, img image.Image) {
+				t.Helper()
+				if !out.Clamped || out.Width != 20 || out.Height != 10 || out.X != 180 || out.Y != 190 {
+					t.Fatalf("unexpected out: %+v", out)
+				}
+				if b := img.Bounds(); b.Dx() != 20 || b.Dy() != 10 {
+					t.Fatalf("unexpected decoded dimensions: %dx%d", b.Dx(), b.Dy())
+				}
+			},
+		},
+		{
+			name:    "out of bounds errors when strict",
+			args:    CropImageArgs{Path: imgPath, X: 180, Y: 190, Width: 50, Height: 50, Strict: true},
+			wantErr: true,
+		},
+		{
+			name:    "no overlap errors",
+			args:    CropImageArgs{Path: imgPath, X: 300, Y: 300, Width: 10, Height: 10},
+			wantErr: true,
+		},
+		{
+			name:    "zero size errors",
+			args:    CropImageArgs{Path: imgPath, Width: 0, Height: 10},
+			wantErr: true,
+		},
+		{
+			name:    "missing file errors",
+			args:    CropImageArgs{Path: filepath.Join(tmpDir, "missing.png"), Width: 1, Height: 1},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, err := CropImage(t.Context(), tt.args)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected error, got nil (out=%+v)", out)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			raw, err := base64.StdEncoding.DecodeString(out.Base64Data)
+			if err != nil {
+				t.Fatalf("base64 decode: %v", err)
+			}
+			img, err := png.Decode(bytes.NewReader(raw))
+			if err != nil {
+				t.Fatalf("decode cropped png: %v", err)
+			}
+			if tt.check != nil {
+				tt.check(t, out, img)
+			}
+		})
+	}
+}
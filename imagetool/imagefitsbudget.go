@@ -0,0 +1,140 @@
+package imagetool
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/flexigpt/llmtools-go/internal/fileutil"
+	"github.com/flexigpt/llmtools-go/internal/toolutil"
+	"github.com/flexigpt/llmtools-go/spec"
+)
+
+const imageFitsBudgetFuncID spec.FuncID = "github.com/flexigpt/llmtools-go/imagetool/imagefitsbudget.ImageFitsBudget"
+
+var imageFitsBudgetTool = spec.Tool{
+	SchemaVersion: spec.SchemaVersion,
+	ID:            "01a13db7-f7a3-7a99-a855-4d55560c02a9",
+	Slug:          "imagefitsbudget",
+	Version:       "v1.0.0",
+	DisplayName:   "Image fits budget",
+	Description:   "Check whether a local image is within a pixel count and/or file size budget (e.g. before sending it to a vision model). If not, suggest a downscaled size that keeps the aspect ratio.",
+	Tags:          []string{"image", "vision"},
+
+	ArgSchema: spec.JSONSchema(`{
+"$schema": "http://json-schema.org/draft-07/schema#",
+"type": "object",
+"properties": {
+	"path": {
+		"type": "string",
+		"description": "Absolute or relative path of the image to check."
+	},
+	"maxPixels": {
+		"type": "integer",
+		"minimum": 0,
+		"description": "Maximum width*height. 0 means no pixel budget."
+	},
+	"maxBytes": {
+		"type": "integer",
+		"minimum": 0,
+		"description": "Maximum file size in bytes. 0 means no byte budget."
+	}
+},
+"required": ["path"],
+"additionalProperties": false
+}`),
+	GoImpl: spec.GoToolImpl{FuncID: imageFitsBudgetFuncID},
+
+	CreatedAt:  spec.SchemaStartTime,
+	ModifiedAt: spec.SchemaStartTime,
+}
+
+func ImageFitsBudgetTool() spec.Tool {
+	return toolutil.CloneTool(imageFitsBudgetTool)
+}
+
+type ImageFitsBudgetArgs struct {
+	Path      string `json:"path"`
+	MaxPixels int64  `json:"maxPixels,omitempty"`
+	MaxBytes  int64  `json:"maxBytes,omitempty"`
+}
+
+type ImageFitsBudgetOut struct {
+	Path      string `json:"path"`
+	Width     int    `json:"width"`
+	Height    int    `json:"height"`
+	Pixels    int64  `json:"pixels"`
+	SizeBytes int64  `json:"sizeBytes"`
+
+	Fits       bool `json:"fits"`
+	FitsPixels bool `json:"fitsPixels"`
+	FitsBytes  bool `json:"fitsBytes"`
+
+	// Suggested downscaled size; set only when Fits is false.
+	SuggestedWidth  int `json:"suggestedWidth,omitempty"`
+	SuggestedHeight int `json:"suggestedHeight,omitempty"`
+}
+
+// ImageFitsBudget reports whether an image is within MaxPixels (width*height)
+// and MaxBytes (file size); a zero budget is not checked, but at least one must
+// be set. Dimensions follow the EXIF display orientation, as a viewer would
+// show them. When over budget, the suggested size keeps the aspect ratio and
+// meets the pixel budget exactly; for the byte budget it assumes the encoded
+// size scales with the pixel count, so it is an estimate.
+func ImageFitsBudget(ctx context.Context, args ImageFitsBudgetArgs) (*ImageFitsBudgetOut, error) {
+	return toolutil.WithRecoveryResp(func() (*ImageFitsBudgetOut, error) {
+		return imageFitsBudget(ctx, args)
+	})
+}
+
+func imageFitsBudget(ctx context.Context, args ImageFitsBudgetArgs) (*ImageFitsBudgetOut, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if args.MaxPixels < 0 || args.MaxBytes < 0 {
+		return nil, errors.New("maxPixels and maxBytes must be >= 0")
+	}
+	if args.MaxPixels == 0 && args.MaxBytes == 0 {
+		return nil, errors.New("maxPixels or maxBytes is required")
+	}
+
+	// Only headers are decoded, so the file size itself is not capped here.
+	info, err := fileutil.ReadImageCtx(ctx, args.Path, false, 0)
+	if err != nil {
+		return nil, err
+	}
+	if !info.Exists {
+		return nil, fmt.Errorf("path does not exist: %s", info.Path)
+	}
+
+	w, h := info.Width, info.Height
+	if fileutil.OrientationSwapsAxes(info.Orientation) {
+		w, h = h, w
+	}
+	out := &ImageFitsBudgetOut{
+		Path:      info.Path,
+		Width:     w,
+		Height:    h,
+		Pixels:    int64(w) * int64(h),
+		SizeBytes: info.Size,
+	}
+	out.FitsPixels = args.MaxPixels == 0 || out.Pixels <= args.MaxPixels
+	out.FitsBytes = args.MaxBytes == 0 || out.SizeBytes <= args.MaxBytes
+	out.Fits = out.FitsPixels && out.FitsBytes
+	if out.Fits || out.Pixels == 0 {
+		return out, nil
+	}
+
+	// Linear scale factor; pixel count (and, by assumption, bytes) scale with its square.
+	scale := 1.0
+	if !out.FitsPixels {
+		scale = min(scale, math.Sqrt(float64(args.MaxPixels)/float64(out.Pixels)))
+	}
+	if !out.FitsBytes {
+		scale = min(scale, math.Sqrt(float64(args.MaxBytes)/float64(out.SizeBytes)))
+	}
+	out.SuggestedWidth = max(1, int(math.Floor(float64(w)*scale)))
+	out.SuggestedHeight = max(1, int(math.Floor(float64(h)*scale)))
+	return out, nil
+}
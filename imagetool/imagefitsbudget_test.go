@@ -0,0 +1,104 @@
+package imagetool
+
+import (
+	"image"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestImageFitsBudget(t *testing.T) {
+	tmpDir := t.TempDir()
+	imgPath := filepath.Join(tmpDir, "wide.png")
+	writeImageFile(t, imgPath, image.NewRGBA(image.Rect(0, 0, 40, 20)), "png")
+	fi, err := os.Stat(imgPath)
+	if err != nil {
+		t.Fatalf("stat: %v", err)
+	}
+	size := fi.Size()
+
+	tests := []struct {
+		name       string
+		args       ImageFitsBudgetArgs
+		wantErr    bool
+		wantFits   bool
+		wantPixels bool
+		wantBytes  bool
+		wantW      int
+		wantH      int
+		// wantAtMost checks the suggestion against wantW/wantH as upper bounds,
+		// for estimates that depend on the encoded size.
+		wantAtMost bool
+	}{
+		{
+			name:       "under both budgets",
+			args:       ImageFitsBudgetArgs{Path: imgPath, MaxPixels: 800, MaxBytes: size},
+			wantFits:   true,
+			wantPixels: true,
+			wantBytes:  true,
+		},
+		{
+			name:      "over pixel budget suggests aspect preserving size",
+			args:      ImageFitsBudgetArgs{Path: imgPath, MaxPixels: 200},
+			wantBytes: true,
+			wantW:     20,
+			wantH:     10,
+		},
+		{
+			name:       "over byte budget suggests a smaller size",
+			args:       ImageFitsBudgetArgs{Path: imgPath, MaxBytes: size / 4},
+			wantPixels: true,
+			wantW:      20,
+			wantH:      10,
+			wantAtMost: true,
+		},
+		{
+			name:    "no budget",
+			args:    ImageFitsBudgetArgs{Path: imgPath},
+			wantErr: true,
+		},
+		{
+			name:    "negative budget",
+			args:    ImageFitsBudgetArgs{Path: imgPath, MaxPixels: -1},
+			wantErr: true,
+		},
+		{
+			name:    "missing file",
+			args:    ImageFitsBudgetArgs{Path: filepath.Join(tmpDir, "missing.png"), MaxPixels: 1},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, err := ImageFitsBudget(t.Context(), tt.args)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected error, got nil (out=%+v)", out)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if out.Width != 40 || out.Height != 20 || out.Pixels != 800 || out.SizeBytes != size {
+				t.Fatalf("unexpected image info: %+v", out)
+			}
+			if out.Fits != tt.wantFits || out.FitsPixels != tt.wantPixels || out.FitsBytes != tt.wantBytes {
+				t.Fatalf("fits=%v/%v/%v want %v/%v/%v",
+					out.Fits, out.FitsPixels, out.FitsBytes, tt.wantFits, tt.wantPixels, tt.wantBytes)
+			}
+			if tt.wantAtMost {
+				if out.SuggestedWidth < 1 || out.SuggestedWidth > tt.wantW ||
+					out.SuggestedHeight < 1 || out.SuggestedHeight > tt.wantH {
+					t.Fatalf("suggested=%dx%d want at most %dx%d",
+						out.SuggestedWidth, out.SuggestedHeight, tt.wantW, tt.wantH)
+				}
+				return
+			}
+			if out.SuggestedWidth != tt.wantW || out.SuggestedHeight != tt.wantH {
+				t.Fatalf("suggested=%dx%d want %dx%d", out.SuggestedWidth, out.SuggestedHeight, tt.wantW, tt.wantH)
+			}
+		})
+	}
+}
@@ -0,0 +1,182 @@
+package imagetool
+
+import (
+	"context"
+	"fmt"
+	"image"
+	"math/bits"
+	"strconv"
+	"strings"
+
+	"github.com/flexigpt/llmtools-go/internal/fileutil"
+	"github.com/flexigpt/llmtools-go/internal/toolutil"
+	"github.com/flexigpt/llmtools-go/spec"
+)
+
+const imageHashFuncID spec.FuncID = "github.com/flexigpt/llmtools-go/imagetool/imagehash.ImageHash"
+
+// imageHashAlgorithm is the only algorithm currently produced by ImageHash.
+const imageHashAlgorithm = "ahash"
+
+// hashSide is the side of the grayscale thumbnail; hashSide*hashSide must be 64.
+const hashSide = 8
+
+var imageHashTool = spec.Tool{
+	SchemaVersion: spec.SchemaVersion,
+	ID:            "01a13d74-f442-7f1f-8d67-7d5d10bc82f5",
+	Slug:          "imagehash",
+	Version:       "v1.0.0",
+	DisplayName:   "Image perceptual hash",
+	Description:   "Compute a 64-bit perceptual (average) hash of a local image as a hex string. Near-duplicate images have hashes with a small Hamming distance.",
+	Tags:          []string{"image", "hash"},
+
+	ArgSchema: spec.JSONSchema(`{
+"$schema": "http://json-schema.org/draft-07/schema#",
+"type": "object",
+"properties": {
+	"path": {
+		"type": "string",
+		"description": "Absolute or relative path of the image to hash."
+	}
+},
+"required": ["path"],
+"additionalProperties": false
+}`),
+	GoImpl: spec.GoToolImpl{FuncID: imageHashFuncID},
+
+	CreatedAt:  spec.SchemaStartTime,
+	ModifiedAt: spec.SchemaStartTime,
+}
+
+func ImageHashTool() spec.Tool {
+	return toolutil.CloneTool(imageHashTool)
+}
+
+type ImageHashArgs struct {
+	Path string `json:"path"`
+}
+
+type ImageHashOut struct {
+	Path      string `json:"path"`
+	Hash      string `json:"hash"`      // 16 hex chars
+	Algorithm string `json:"algorithm"` // "ahash"
+	Width     int    `json:"width"`
+	Height    int    `json:"height"`
+}
+
+// ImageHash computes a 64-bit average hash: the image is downscaled to 8x8
+// grayscale and each bit records whether a cell is brighter than the mean.
+func ImageHash(ctx context.Context, args ImageHashArgs) (*ImageHashOut, error) {
+	return toolutil.WithRecoveryResp(func() (*ImageHashOut, error) {
+		return imageHash(ctx, args)
+	})
+}
+
+// ImageDistance returns the Hamming distance between two hashes produced by ImageHash.
+// 0 means identical; small values (roughly <= 5) indicate near-duplicates.
+func ImageDistance(a, b string) (int, error) {
+	ha, err := parseImageHash(a)
+	if err != nil {
+		return 0, err
+	}
+	hb, err := parseImageHash(b)
+	if err != nil {
+		return 0, err
+	}
+	return bits.OnesCount64(ha ^ hb), nil
+}
+
+func imageHash(ctx context.Context, args ImageHashArgs) (*ImageHashOut, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	src, err := fileutil.DecodeImageFile(args.Path, toolutil.GetLimits().MaxFileRead)
+	if err != nil {
+		return nil, err
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	b := src.Image.Bounds()
+	return &ImageHashOut{
+		Path:      src.Path,
+		Hash:      fmt.Sprintf("%016x", averageHash(src.Image)),
+		Algorithm: imageHashAlgorithm,
+		Width:     b.Dx(),
+		Height:    b.Dy(),
+	}, nil
+}
+
+func averageHash(img image.Image) uint64 {
+	cells := grayThumbnail(img, hashSide, hashSide)
+
+	var sum float64
+	for _, v := range cells {
+		sum += v
+	}
+	mean := sum / float64(len(cells))
+
+	var h uint64
+	for i, v := range cells {
+		if v > mean {
+			h |= 1 << (uint(len(cells) - 1 - i))
+		}
+	}
+	return h
+}
+
+// grayThumbnail box-filters img down to w x h luminance cells (row-major).
+// Every source pixel contributes to exactly one cell, so it also works when
+// the source is smaller than the thumbnail.
+func grayThumbnail(img image.Image, w, h int) []float64 {
+	b := img.Bounds()
+	sums := make([]float64, w*h)
+	counts := make([]int, w*h)
+
+	dx, dy := b.Dx(), b.Dy()
+	if dx == 0 || dy == 0 {
+		return sums
+	}
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		cy := (y - b.Min.Y) * h / dy
+		for x := b.Min.X; x < b.Max.X; x++ {
+			cx := (x - b.Min.X) * w / dx
+			r, g, bl, _ := img.At(x, y).RGBA()
+			// ITU-R BT.601 luma on 16-bit channels.
+			lum := 0.299*float64(r) + 0.587*float64(g) + 0.114*float64(bl)
+			sums[cy*w+cx] += lum
+			counts[cy*w+cx]++
+		}
+	}
+
+	// Cells can be empty only if the source is smaller than the thumbnail;
+	// borrow the nearest source pixel in that case.
+	for cy := range h {
+		for cx := range w {
+			i := cy*w + cx
+			if counts[i] > 0 {
+				sums[i] /= float64(counts[i])
+				continue
+			}
+			sx := b.Min.X + cx*dx/w
+			sy := b.Min.Y + cy*dy/h
+			r, g, bl, _ := img.At(sx, sy).RGBA()
+			sums[i] = 0.299*float64(r) + 0.587*float64(g) + 0.114*float64(bl)
+		}
+	}
+	return sums
+}
+
+func parseImageHash(s string) (uint64, error) {
+	t := strings.TrimSpace(s)
+	if len(t) != 16 {
+		return 0, fmt.Errorf("invalid image hash %q: expected 16 hex characters", s)
+	}
+	v, err := strconv.ParseUint(t, 16, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid image hash %q: %w", s, err)
+	}
+	return v, nil
+}
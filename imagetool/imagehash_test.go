@@ -0,0 +1,145 @@
+package imagetool
+
+import (
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestImageHash(t *testing.T) {
+	tmpDir := t.TempDir()
+	img := patternImage(64, 48)
+
+	pngA := filepath.Join(tmpDir, "a.png")
+	pngB := filepath.Join(tmpDir, "b.png")
+	jpgLow := filepath.Join(tmpDir, "low.jpg")
+	writeImageFile(t, pngA, img, "png")
+	writeImageFile(t, pngB, img, "png")
+	writeImageFile(t, jpgLow, img, "jpeg")
+
+	inverted := filepath.Join(tmpDir, "inverted.png")
+	writeImageFile(t, inverted, invertImage(img), "png")
+
+	hash := func(p string) string {
+		t.Helper()
+		out, err := ImageHash(t.Context(), ImageHashArgs{Path: p})
+		if err != nil {
+			t.Fatalf("ImageHash(%s): %v", p, err)
+		}
+		if len(out.Hash) != 16 || out.Algorithm != "ahash" {
+			t.Fatalf("unexpected out: %+v", out)
+		}
+		return out.Hash
+	}
+
+	ha, hb := hash(pngA), hash(pngB)
+	if ha != hb {
+		t.Fatalf("identical images hashed differently: %s vs %s", ha, hb)
+	}
+
+	tests := []struct {
+		name    string
+		other   string
+		maxDist int
+		minDist int
+	}{
+		{name: "identical", other: pngB, maxDist: 0},
+		{name: "recompressed jpeg stays close", other: jpgLow, maxDist: 5},
+		{name: "inverted image is far", other: inverted, minDist: 32, maxDist: 64},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d, err := ImageDistance(ha, hash(tt.other))
+			if err != nil {
+				t.Fatalf("ImageDistance: %v", err)
+			}
+			if d > tt.maxDist {
+				t.Fatalf("distance %d exceeds %d", d, tt.maxDist)
+			}
+			if d < tt.minDist {
+				t.Fatalf("distance %d below %d", d, tt.minDist)
+			}
+		})
+	}
+
+	if _, err := ImageHash(t.Context(), ImageHashArgs{Path: filepath.Join(tmpDir, "missing.png")}); err == nil {
+		t.Fatalf("expected error for missing file")
+	}
+}
+
+func TestImageDistance_InvalidInput(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b string
+	}{
+		{name: "too short", a: "abc", b: "0000000000000000"},
+		{name: "non-hex", a: "zzzzzzzzzzzzzzzz", b: "0000000000000000"},
+		{name: "second invalid", a: "0000000000000000", b: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ImageDistance(tt.a, tt.b); err == nil {
+				t.Fatalf("expected error")
+			}
+		})
+	}
+
+	d, err := ImageDistance("ffffffffffffffff", "0000000000000000")
+	if err != nil || d != 64 {
+		t.Fatalf("expected distance 64, got %d (err=%v)", d, err)
+	}
+}
+
+// patternImage draws a horizontal gradient with a bright square so the hash
+// has structure in both dimensions.
+func patternImage(w, h int) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := range h {
+		for x := range w {
+			v := uint8(x * 255 / w)
+			if x > w/4 && x < w/2 && y > h/4 && y < 3*h/4 {
+				v = 255
+			}
+			img.Set(x, y, color.RGBA{R: v, G: v / 2, B: 255 - v, A: 255})
+		}
+	}
+	return img
+}
+
+func invertImage(src *image.RGBA) *image.RGBA {
+	b := src.Bounds()
+	out := image.NewRGBA(b)
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			c := src.RGBAAt(x, y)
+			out.SetRGBA(x, y, color.RGBA{R: 255 - c.R, G: 255 - c.G, B: 255 - c.B, A: 255})
+		}
+	}
+	return out
+}
+
+func writeImageFile(t *testing.T, path string, img image.Image, format string) {
+	t.Helper()
+
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatalf("create image: %v", err)
+	}
+	switch format {
+	case "jpeg":
+		err = jpeg.Encode(f, img, &jpeg.Options{Quality: 40})
+	default:
+		err = png.Encode(f, img)
+	}
+	if err != nil {
+		_ = f.Close()
+		t.Fatalf("encode %s: %v", format, err)
+	}
+	if err := f.Close(); err != nil {
+		t.Fatalf("close image file: %v", err)
+	}
+}
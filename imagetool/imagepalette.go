@@ -0,0 +1,221 @@
+package imagetool
+
+import (
+	"context"
+	"fmt"
+	"image"
+	"slices"
+
+	"github.com/flexigpt/llmtools-go/internal/fileutil"
+	"github.com/flexigpt/llmtools-go/internal/toolutil"
+	"github.com/flexigpt/llmtools-go/spec"
+)
+
+const imagePaletteFuncID spec.FuncID = "github.com/flexigpt/llmtools-go/imagetool/imagepalette.ImagePalette"
+
+const (
+	defaultPaletteColors = 5
+	maxPaletteColors     = 16
+	// paletteSampleSide bounds the sampling grid so large images stay fast.
+	paletteSampleSide = 128
+)
+
+var imagePaletteTool = spec.Tool{
+	SchemaVersion: spec.SchemaVersion,
+	ID:            "01a13d77-71a8-75fc-8a9d-e09b49d54030",
+	Slug:          "imagepalette",
+	Version:       "v1.0.0",
+	DisplayName:   "Image color palette",
+	Description:   "Extract the dominant colors of a local image as hex strings with their approximate coverage fractions.",
+	Tags:          []string{"image", "color"},
+
+	ArgSchema: spec.JSONSchema(`{
+"$schema": "http://json-schema.org/draft-07/schema#",
+"type": "object",
+"properties": {
+	"path": {
+		"type": "string",
+		"description": "Absolute or relative path of the image to analyze."
+	},
+	"colors": {
+		"type": "integer",
+		"minimum": 1,
+		"maximum": 16,
+		"description": "Maximum number of dominant colors to return.",
+		"default": 5
+	}
+},
+"required": ["path"],
+"additionalProperties": false
+}`),
+	GoImpl: spec.GoToolImpl{FuncID: imagePaletteFuncID},
+
+	CreatedAt:  spec.SchemaStartTime,
+	ModifiedAt: spec.SchemaStartTime,
+}
+
+func ImagePaletteTool() spec.Tool {
+	return toolutil.CloneTool(imagePaletteTool)
+}
+
+type ImagePaletteArgs struct {
+	Path   string `json:"path"`
+	Colors int    `json:"colors,omitempty"` // default 5, max 16
+}
+
+type PaletteColor struct {
+	Hex      string  `json:"hex"`      // "#rrggbb"
+	Coverage float64 `json:"coverage"` // fraction of sampled opaque pixels, 0..1
+}
+
+type ImagePaletteOut struct {
+	Path   string         `json:"path"`
+	Width  int            `json:"width"`
+	Height int            `json:"height"`
+	Colors []PaletteColor `json:"colors"`
+}
+
+// ImagePalette returns up to Colors dominant colors, sorted by coverage (descending).
+// Colors are found with median-cut over a bounded sample grid; fully transparent
+// pixels are ignored and coverage fractions sum to ~1.0.
+func ImagePalette(ctx context.Context, args ImagePaletteArgs) (*ImagePaletteOut, error) {
+	return toolutil.WithRecoveryResp(func() (*ImagePaletteOut, error) {
+		return imagePalette(ctx, args)
+	})
+}
+
+func imagePalette(ctx context.Context, args ImagePaletteArgs) (*ImagePaletteOut, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	n := args.Colors
+	if n == 0 {
+		n = defaultPaletteColors
+	}
+	if n < 1 || n > maxPaletteColors {
+		return nil, fmt.Errorf("colors must be between 1 and %d, got %d", maxPaletteColors, args.Colors)
+	}
+
+	src, err := fileutil.DecodeImageFile(args.Path, toolutil.GetLimits().MaxFileRead)
+	if err != nil {
+		return nil, err
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	b := src.Image.Bounds()
+	return &ImagePaletteOut{
+		Path:   src.Path,
+		Width:  b.Dx(),
+		Height: b.Dy(),
+		Colors: medianCutPalette(samplePixels(src.Image, paletteSampleSide), n),
+	}, nil
+}
+
+type rgb [3]uint8
+
+// samplePixels picks at most side*side opaque pixels on an evenly spaced grid.
+func samplePixels(img image.Image, side int) []rgb {
+	b := img.Bounds()
+	stepX := max(1, b.Dx()/side)
+	stepY := max(1, b.Dy()/side)
+
+	out := make([]rgb, 0, min(b.Dx(), side)*min(b.Dy(), side))
+	for y := b.Min.Y; y < b.Max.Y; y += stepY {
+		for x := b.Min.X; x < b.Max.X; x += stepX {
+			r, g, bl, a := img.At(x, y).RGBA()
+			if a == 0 {
+				continue
+			}
+			// Un-premultiply so semi-transparent pixels keep their hue.
+			out = append(out, rgb{
+				uint8(r * 0xff / a),
+				uint8(g * 0xff / a),
+				uint8(bl * 0xff / a),
+			})
+		}
+	}
+	return out
+}
+
+func medianCutPalette(px []rgb, n int) []PaletteColor {
+	if len(px) == 0 {
+		return []PaletteColor{}
+	}
+
+	buckets := [][]rgb{px}
+	for len(buckets) < n {
+		// Split the bucket with the widest channel range.
+		bi, ch, span := -1, 0, 0
+		for i, bk := range buckets {
+			if len(bk) < 2 {
+				continue
+			}
+			c, s := widestChannel(bk)
+			if s > span {
+				bi, ch, span = i, c, s
+			}
+		}
+		if bi < 0 {
+			// Every bucket is a single color.
+			break
+		}
+		bk := buckets[bi]
+		slices.SortFunc(bk, func(a, b rgb) int { return int(a[ch]) - int(b[ch]) })
+		mid := len(bk) / 2
+		buckets[bi] = bk[:mid]
+		buckets = append(buckets, bk[mid:])
+	}
+
+	// Average each bucket and merge buckets that round to the same hex color.
+	total := float64(len(px))
+	byHex := map[string]float64{}
+	for _, bk := range buckets {
+		var sr, sg, sb int
+		for _, p := range bk {
+			sr += int(p[0])
+			sg += int(p[1])
+			sb += int(p[2])
+		}
+		k := len(bk)
+		hex := fmt.Sprintf("#%02x%02x%02x", (sr+k/2)/k, (sg+k/2)/k, (sb+k/2)/k)
+		byHex[hex] += float64(k) / total
+	}
+
+	out := make([]PaletteColor, 0, len(byHex))
+	for h, c := range byHex {
+		out = append(out, PaletteColor{Hex: h, Coverage: c})
+	}
+	slices.SortFunc(out, func(a, b PaletteColor) int {
+		switch {
+		case a.Coverage > b.Coverage:
+			return -1
+		case a.Coverage < b.Coverage:
+			return 1
+		}
+		// Stable output for equal coverage.
+		if a.Hex < b.Hex {
+			return -1
+		}
+		return 1
+	})
+	return out
+}
+
+func widestChannel(px []rgb) (channel, span int) {
+	lo := rgb{255, 255, 255}
+	hi := rgb{}
+	for _, p := range px {
+		for c := range 3 {
+			lo[c] = min(lo[c], p[c])
+			hi[c] = max(hi[c], p[c])
+		}
+	}
+	for c := range 3 {
+		if s := int(hi[c]) - int(lo[c]); s > span {
+			channel, span = c, s
+		}
+	}
+	return channel, span
+}
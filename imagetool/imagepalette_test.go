@@ -0,0 +1,96 @@
+package imagetool
+
+import (
+	"image"
+	"image/color"
+	"math"
+	"path/filepath"
+	"testing"
+)
+
+func TestImagePalette(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	solidPath := filepath.Join(tmpDir, "solid.png")
+	solid := image.NewRGBA(image.Rect(0, 0, 300, 200))
+	for y := range 200 {
+		for x := range 300 {
+			solid.SetRGBA(x, y, color.RGBA{R: 0x12, G: 0x34, B: 0x56, A: 255})
+		}
+	}
+	writeImageFile(t, solidPath, solid, "png")
+
+	// 3/4 blue, 1/4 yellow.
+	splitPath := filepath.Join(tmpDir, "split.png")
+	split := image.NewRGBA(image.Rect(0, 0, 80, 40))
+	for y := range 40 {
+		for x := range 80 {
+			c := color.RGBA{B: 255, A: 255}
+			if x >= 60 {
+				c = color.RGBA{R: 255, G: 255, A: 255}
+			}
+			split.SetRGBA(x, y, c)
+		}
+	}
+	writeImageFile(t, splitPath, split, "png")
+
+	type tc struct {
+		name    string
+		args    ImagePaletteArgs
+		wantErr bool
+		want    []PaletteColor
+	}
+	tests := []tc{
+		{
+			name: "solid color yields one dominant color",
+			args: ImagePaletteArgs{Path: solidPath, Colors: 4},
+			want: []PaletteColor{{Hex: "#123456", Coverage: 1}},
+		},
+		{
+			name: "two colors sorted by coverage",
+			args: ImagePaletteArgs{Path: splitPath},
+			want: []PaletteColor{
+				{Hex: "#0000ff", Coverage: 0.75},
+				{Hex: "#ffff00", Coverage: 0.25},
+			},
+		},
+		{
+			name:    "colors out of range",
+			args:    ImagePaletteArgs{Path: solidPath, Colors: 17},
+			wantErr: true,
+		},
+		{
+			name:    "missing file",
+			args:    ImagePaletteArgs{Path: filepath.Join(tmpDir, "missing.png")},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, err := ImagePalette(t.Context(), tt.args)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected error, got nil (out=%+v)", out)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(out.Colors) != len(tt.want) {
+				t.Fatalf("colors mismatch: got %+v want %+v", out.Colors, tt.want)
+			}
+			var sum float64
+			for i, c := range out.Colors {
+				sum += c.Coverage
+				if c.Hex != tt.want[i].Hex || math.Abs(c.Coverage-tt.want[i].Coverage) > 0.02 {
+					t.Fatalf("color %d mismatch: got %+v want %+v", i, c, tt.want[i])
+				}
+			}
+			if math.Abs(sum-1) > 1e-9 {
+				t.Fatalf("coverage should sum to 1, got %f", sum)
+			}
+		})
+	}
+}
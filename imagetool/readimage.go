@@ -2,6 +2,7 @@ package imagetool
 
 import (
 	"context"
+	"errors"
 	"time"
 
 	"github.com/flexigpt/llmtools-go/internal/fileutil"
@@ -32,6 +33,21 @@ var readImageTool = spec.Tool{
 		"type": "boolean",
 		"description": "If true, include the base64-encoded file contents in the output.",
 		"default": false
+	},
+	"applyOrientation": {
+		"type": "boolean",
+		"description": "If true, width/height reflect the EXIF display orientation (rotated JPEGs report swapped dimensions). Raw dimensions are always reported separately.",
+		"default": true
+	},
+	"maxBytes": {
+		"type": "integer",
+		"minimum": 0,
+		"description": "Refuse files larger than this many bytes. 0 uses the tool read limit, which is also the upper bound."
+	},
+	"followSymlinks": {
+		"type": "boolean",
+		"description": "If true and path is a symlink, read its target, which must be inside the symlink's directory. Otherwise symlinks are refused.",
+		"default": false
 	}
 },
 "required": ["path"],
@@ -50,6 +66,15 @@ func ReadImageTool() spec.Tool {
 type ReadImageArgs struct {
 	Path              string `json:"path"`
 	IncludeBase64Data bool   `json:"includeBase64Data"`
+	// ApplyOrientation defaults to true when nil.
+	ApplyOrientation *bool `json:"applyOrientation,omitempty"`
+	// MaxBytes lowers the file size cap for this call; 0 (or a larger value)
+	// uses the configured file read limit.
+	MaxBytes int64 `json:"maxBytes,omitempty"`
+	// FollowSymlinks reads the target when Path is a symlink, provided the fully
+	// resolved target stays inside the symlink's directory (else the error wraps
+	// fileutil.ErrPathEscapesRoot). When false, a symlink Path is refused.
+	FollowSymlinks bool `json:"followSymlinks,omitempty"`
 }
 
 type ReadImageOut struct {
@@ -64,6 +89,11 @@ type ReadImageOut struct {
 	Format   string `json:"format,omitempty"`   // "png", "jpeg", ...
 	MIMEType string `json:"mimeType,omitempty"` // "image/png", ...
 
+	// Stored pixel dimensions, before applying EXIF orientation.
+	RawWidth    int `json:"rawWidth,omitempty"`
+	RawHeight   int `json:"rawHeight,omitempty"`
+	Orientation int `json:"orientation,omitempty"` // EXIF orientation 1-8, 0 if absent
+
 	// Optional content.
 	Base64Data string `json:"base64Data,omitempty"`
 }
@@ -73,7 +103,9 @@ type ReadImageOut struct {
 //   - empty path => error
 //   - directory path => error
 //   - non-image/unsupported image => error
-//   - non-existent path => (Exists=false, err=nil).
+//   - non-existent path => (Exists=false, err=nil)
+//   - symlink path => error, unless FollowSymlinks is set
+//   - file larger than the size cap => error wrapping fileutil.ErrFileExceedsMaxSize.
 func ReadImage(ctx context.Context, args ReadImageArgs) (*ReadImageOut, error) {
 	return toolutil.WithRecoveryResp(func() (*ReadImageOut, error) {
 		return readImage(ctx, args)
@@ -85,7 +117,22 @@ func readImage(ctx context.Context, args ReadImageArgs) (*ReadImageOut, error) {
 		return nil, err
 	}
 
-	info, err := fileutil.ReadImage(args.Path, args.IncludeBase64Data, toolutil.MaxFileReadBytes)
+	if args.MaxBytes < 0 {
+		return nil, errors.New("maxBytes must be >= 0")
+	}
+	maxBytes := toolutil.GetLimits().MaxFileRead
+	if args.MaxBytes > 0 {
+		maxBytes = min(maxBytes, args.MaxBytes)
+	}
+
+	path := args.Path
+	if args.FollowSymlinks {
+		var err error
+		if path, err = fileutil.ResolveSymlinkInDir(path); err != nil {
+			return nil, err
+		}
+	}
+	info, err := fileutil.ReadImageCtx(ctx, path, args.IncludeBase64Data, maxBytes)
 	if err != nil {
 		return nil, err
 	}
@@ -102,7 +149,15 @@ func readImage(ctx context.Context, args ReadImageArgs) (*ReadImageOut, error) {
 		Format:   info.Format,
 		MIMEType: string(info.MIMEType),
 
+		RawWidth:    info.Width,
+		RawHeight:   info.Height,
+		Orientation: info.Orientation,
+
 		Base64Data: info.Base64Data,
 	}
+	applyOrientation := args.ApplyOrientation == nil || *args.ApplyOrientation
+	if applyOrientation && fileutil.OrientationSwapsAxes(info.Orientation) {
+		out.Width, out.Height = info.Height, info.Width
+	}
 	return out, nil
 }
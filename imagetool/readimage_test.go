@@ -7,11 +7,17 @@ import (
 	"errors"
 	"image"
 	"image/color"
+	"image/jpeg"
 	"image/png"
 	"os"
 	"path/filepath"
+	"runtime"
+	"strings"
 	"testing"
 	"time"
+
+	"github.com/flexigpt/llmtools-go/internal/fileutil"
+	"github.com/flexigpt/llmtools-go/internal/toolutil"
 )
 
 func TestReadImage(t *testing.T) {
@@ -198,6 +204,30 @@ func TestReadImage(t *testing.T) {
 			args:    ReadImageArgs{},
 			wantErr: true,
 		},
+		{
+			name:      "maxBytes below file size errors",
+			ctx:       t.Context(),
+			args:      ReadImageArgs{Path: imgPath, MaxBytes: 16},
+			wantErr:   true,
+			wantErrIs: fileutil.ErrFileExceedsMaxSize,
+		},
+		{
+			name:    "negative maxBytes errors",
+			ctx:     t.Context(),
+			args:    ReadImageArgs{Path: imgPath, MaxBytes: -1},
+			wantErr: true,
+		},
+		{
+			name: "maxBytes at file size succeeds",
+			ctx:  t.Context(),
+			args: ReadImageArgs{Path: imgPath, MaxBytes: fi.Size()},
+			check: func(t *testing.T, out *ReadImageOut) {
+				t.Helper()
+				if out.Width != 8 || out.Height != 6 {
+					t.Fatalf("unexpected dimensions: %dx%d", out.Width, out.Height)
+				}
+			},
+		},
 		{
 			name:      "context canceled returns context error (even with valid path)",
 			ctx:       canceledCtx,
@@ -279,3 +309,124 @@ func writePNG(t *testing.T, path string, w, h int) []byte {
 	}
 	return raw
 }
+
+func TestReadImage_FollowSymlinks(t *testing.T) {
+	if runtime.GOOS == toolutil.GOOSWindows {
+		t.Skip("symlinks require elevated privileges on Windows")
+	}
+	tmpDir := t.TempDir()
+	imgDir := filepath.Join(tmpDir, "images")
+	if err := os.Mkdir(imgDir, 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	imgPath := filepath.Join(imgDir, "img.png")
+	writePNG(t, imgPath, 8, 6)
+	outsidePath := filepath.Join(tmpDir, "outside.png")
+	writePNG(t, outsidePath, 4, 4)
+
+	link := filepath.Join(imgDir, "link.png")
+	if err := os.Symlink("img.png", link); err != nil {
+		t.Fatalf("symlink: %v", err)
+	}
+	escapeLink := filepath.Join(imgDir, "escape.png")
+	if err := os.Symlink(outsidePath, escapeLink); err != nil {
+		t.Fatalf("symlink: %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		args       ReadImageArgs
+		wantErrSub string
+		wantErrIs  error
+	}{
+		{
+			name:       "symlink refused by default",
+			args:       ReadImageArgs{Path: link},
+			wantErrSub: "symlink",
+		},
+		{
+			name: "symlink followed when enabled",
+			args: ReadImageArgs{Path: link, FollowSymlinks: true},
+		},
+		{
+			name:      "target outside the symlink directory refused",
+			args:      ReadImageArgs{Path: escapeLink, FollowSymlinks: true},
+			wantErrIs: fileutil.ErrPathEscapesRoot,
+		},
+		{
+			name: "regular file unaffected by option",
+			args: ReadImageArgs{Path: imgPath, FollowSymlinks: true},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, err := ReadImage(t.Context(), tt.args)
+			if tt.wantErrSub != "" || tt.wantErrIs != nil {
+				if err == nil {
+					t.Fatalf("expected error, got nil (out=%+v)", out)
+				}
+				if tt.wantErrSub != "" && !strings.Contains(err.Error(), tt.wantErrSub) {
+					t.Fatalf("error %q does not contain %q", err, tt.wantErrSub)
+				}
+				if tt.wantErrIs != nil && !errors.Is(err, tt.wantErrIs) {
+					t.Fatalf("error=%v; want errors.Is(_, %v)=true", err, tt.wantErrIs)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ReadImage: %v", err)
+			}
+			if out.Width != 8 || out.Height != 6 || out.Format != "png" {
+				t.Fatalf("unexpected image info: %+v", out)
+			}
+		})
+	}
+}
+
+func TestReadImage_EXIFOrientation(t *testing.T) {
+	tmpDir := t.TempDir()
+	p := filepath.Join(tmpDir, "rotated.jpg")
+
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, image.NewRGBA(image.Rect(0, 0, 40, 20)), nil); err != nil {
+		t.Fatalf("jpeg encode: %v", err)
+	}
+	// APP1/Exif segment (big-endian TIFF) with IFD0 orientation=6 (rotate 90 CW).
+	app1 := []byte{
+		0xFF, 0xE1, 0x00, 0x22,
+		'E', 'x', 'i', 'f', 0x00, 0x00,
+		'M', 'M', 0x00, 0x2A, 0x00, 0x00, 0x00, 0x08,
+		0x00, 0x01,
+		0x01, 0x12, 0x00, 0x03, 0x00, 0x00, 0x00, 0x01, 0x00, 0x06, 0x00, 0x00,
+		0x00, 0x00, 0x00, 0x00,
+	}
+	raw := buf.Bytes()
+	data := append(append(append([]byte{}, raw[:2]...), app1...), raw[2:]...)
+	if err := os.WriteFile(p, data, 0o600); err != nil {
+		t.Fatalf("write jpeg: %v", err)
+	}
+
+	no := false
+	tests := []struct {
+		name         string
+		apply        *bool
+		wantW, wantH int
+	}{
+		{name: "default applies orientation", apply: nil, wantW: 20, wantH: 40},
+		{name: "orientation disabled reports raw", apply: &no, wantW: 40, wantH: 20},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, err := ReadImage(t.Context(), ReadImageArgs{Path: p, ApplyOrientation: tt.apply})
+			if err != nil {
+				t.Fatalf("ReadImage: %v", err)
+			}
+			if out.Width != tt.wantW || out.Height != tt.wantH {
+				t.Fatalf("dimensions: got %dx%d want %dx%d", out.Width, out.Height, tt.wantW, tt.wantH)
+			}
+			if out.RawWidth != 40 || out.RawHeight != 20 || out.Orientation != 6 {
+				t.Fatalf("unexpected raw info: %+v", out)
+			}
+		})
+	}
+}
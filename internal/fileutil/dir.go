@@ -1,14 +1,92 @@
 package fileutil
 
 import (
+	"context"
+	"errors"
+	"io/fs"
 	"os"
+	"path"
 	"path/filepath"
 	"sort"
+	"strings"
+	"time"
 )
 
+// DirEntryInfo is a directory entry with the metadata gathered while listing.
+type DirEntryInfo struct {
+	Name    string      `json:"name"`
+	IsDir   bool        `json:"isDir"`
+	Size    int64       `json:"size"`
+	Mode    fs.FileMode `json:"mode"`
+	ModTime time.Time   `json:"modTime"`
+}
+
 // ListDirectory lists files/dirs in path (default "."), pattern is an optional
 // glob filter (filepath.Match).
-func ListDirectory(path, pattern string) ([]string, error) {
+//
+// A pattern containing "**" is matched recursively instead: entries anywhere
+// under path are matched by their slash-separated relative path, and a "**"
+// segment matches zero or more directories (e.g. "**/*.go"). Such results are
+// returned as relative slash paths. Symlinked directories are listed but only
+// descended if followSymlinks is set (see WalkDirFollow).
+func ListDirectory(path, pattern string, followSymlinks bool) ([]string, error) {
+	return ListDirectoryCtx(context.Background(), path, pattern, followSymlinks)
+}
+
+// ListDirectoryCtx is ListDirectory that stops with ctx.Err() once ctx is done,
+// checking before reading the directory and at every entry of a recursive walk.
+func ListDirectoryCtx(ctx context.Context, path, pattern string, followSymlinks bool) ([]string, error) {
+	entries, err := listDirEntries(ctx, path, pattern, followSymlinks)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]string, 0, len(entries))
+	for _, e := range entries {
+		out = append(out, e.name)
+	}
+	return out, nil
+}
+
+// ListDirectoryDetailed is ListDirectory with per-entry metadata, taken from
+// the entries read while listing rather than a separate stat per name.
+// Symlinks are reported as links, except those followed by a recursive
+// listing with followSymlinks, which report their target. Entries removed
+// while listing are skipped.
+func ListDirectoryDetailed(path, pattern string, followSymlinks bool) ([]DirEntryInfo, error) {
+	entries, err := listDirEntries(context.Background(), path, pattern, followSymlinks)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]DirEntryInfo, 0, len(entries))
+	for _, e := range entries {
+		info, err := e.d.Info()
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				continue
+			}
+			return nil, err
+		}
+		out = append(out, DirEntryInfo{
+			Name:    e.name,
+			IsDir:   info.IsDir(),
+			Size:    info.Size(),
+			Mode:    info.Mode(),
+			ModTime: info.ModTime().UTC(),
+		})
+	}
+	return out, nil
+}
+
+type dirMatch struct {
+	name string
+	d    fs.DirEntry
+}
+
+// listDirEntries returns the entries matching pattern, sorted by name.
+func listDirEntries(ctx context.Context, path, pattern string, followSymlinks bool) ([]dirMatch, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
 	dir := path
 	if dir == "" {
 		dir = "."
@@ -18,12 +96,15 @@ func ListDirectory(path, pattern string) ([]string, error) {
 	if err != nil {
 		return nil, err
 	}
+	if strings.Contains(pattern, "**") {
+		return listDirectoryRecursive(ctx, dir, pattern, followSymlinks)
+	}
 	entries, err := os.ReadDir(dir)
 	if err != nil {
 		return nil, err
 	}
 
-	out := make([]string, 0, len(entries))
+	out := make([]dirMatch, 0, len(entries))
 	for _, e := range entries {
 		name := e.Name()
 		if pattern != "" {
@@ -35,9 +116,80 @@ func ListDirectory(path, pattern string) ([]string, error) {
 				continue
 			}
 		}
-		out = append(out, name)
+		out = append(out, dirMatch{name: name, d: e})
+	}
+	sortDirMatches(out)
+
+	return out, nil
+}
+
+func listDirectoryRecursive(ctx context.Context, dir, pattern string, followSymlinks bool) ([]dirMatch, error) {
+	segs := strings.Split(filepath.ToSlash(pattern), "/")
+	for _, s := range segs {
+		if s == "**" {
+			continue
+		}
+		if _, err := path.Match(s, ""); err != nil {
+			return nil, filepath.ErrBadPattern
+		}
+	}
+
+	walk := filepath.WalkDir
+	if followSymlinks {
+		walk = WalkDirFollow
+	}
+	out := []dirMatch{}
+	err := walk(dir, func(p string, d fs.DirEntry, walkErr error) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if walkErr != nil {
+			return walkErr
+		}
+		if p == dir {
+			return nil
+		}
+		rel, err := filepath.Rel(dir, p)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		if matchGlobSegments(segs, strings.Split(rel, "/")) {
+			out = append(out, dirMatch{name: rel, d: d})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
-	sort.Strings(out)
+	sortDirMatches(out)
 
 	return out, nil
 }
+
+func sortDirMatches(m []dirMatch) {
+	sort.Slice(m, func(i, j int) bool { return m[i].name < m[j].name })
+}
+
+// matchGlobSegments reports whether name matches pat segment by segment; a
+// "**" segment matches zero or more name segments. Segments are pre-validated.
+func matchGlobSegments(pat, name []string) bool {
+	for len(pat) > 0 {
+		if pat[0] == "**" {
+			for i := 0; i <= len(name); i++ {
+				if matchGlobSegments(pat[1:], name[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+		if len(name) == 0 {
+			return false
+		}
+		if ok, _ := path.Match(pat[0], name[0]); !ok {
+			return false
+		}
+		pat, name = pat[1:], name[1:]
+	}
+	return len(name) == 0
+}
@@ -0,0 +1,151 @@
+package fileutil
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+type FileCategory string
+
+const (
+	FileCategoryText      FileCategory = "text"
+	FileCategoryImage     FileCategory = "image"
+	FileCategoryBinary    FileCategory = "binary"
+	FileCategoryDirectory FileCategory = "directory"
+	FileCategorySymlink   FileCategory = "symlink"
+)
+
+// CategoryCounts holds per-category entry counts for a directory summary.
+type CategoryCounts struct {
+	Text      int `json:"text"`
+	Image     int `json:"image"`
+	Binary    int `json:"binary"`
+	Directory int `json:"directory"`
+	Symlink   int `json:"symlink"`
+}
+
+// FileRef identifies a single file in a summary.
+type FileRef struct {
+	Path    string     `json:"path"`
+	Size    int64      `json:"size"`
+	ModTime *time.Time `json:"modTime,omitempty"`
+}
+
+type DirectorySummary struct {
+	Path           string         `json:"path"`
+	Counts         CategoryCounts `json:"counts"`
+	TotalFiles     int            `json:"totalFiles"`
+	TotalSizeBytes int64          `json:"totalSizeBytes"`
+	Largest        *FileRef       `json:"largest,omitempty"`
+	Newest         *FileRef       `json:"newest,omitempty"`
+}
+
+// ClassifyEntry returns the summary category for a directory entry.
+// Regular files are classified via MIMEForLocalFile; if detection fails, or the
+// entry is a special file, it is treated as binary.
+func ClassifyEntry(path string, d fs.DirEntry) FileCategory {
+	switch {
+	case d.Type()&fs.ModeSymlink != 0:
+		return FileCategorySymlink
+	case d.IsDir():
+		return FileCategoryDirectory
+	case !d.Type().IsRegular():
+		// Devices, pipes, sockets: never open them for sniffing.
+		return FileCategoryBinary
+	}
+	_, mode, _, err := MIMEForLocalFile(path)
+	if err != nil {
+		return FileCategoryBinary
+	}
+	switch mode {
+	case ExtensionModeText:
+		return FileCategoryText
+	case ExtensionModeImage:
+		return FileCategoryImage
+	default:
+		return FileCategoryBinary
+	}
+}
+
+// SummarizeDirectory counts entries of dir (default ".") by category and
+// reports total size, largest and newest regular file.
+// If recursive is false only direct children are inspected.
+// Symlinks are counted but never followed.
+func SummarizeDirectory(ctx context.Context, dir string, recursive bool) (*DirectorySummary, error) {
+	if dir == "" {
+		dir = "."
+	}
+	root, err := NormalizePath(dir)
+	if err != nil {
+		return nil, err
+	}
+	st, err := os.Stat(root)
+	if err != nil {
+		return nil, err
+	}
+	if !st.IsDir() {
+		return nil, fmt.Errorf("path is not a directory: %s", root)
+	}
+
+	out := &DirectorySummary{Path: root}
+	walkFn := func(path string, d fs.DirEntry, walkErr error) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if walkErr != nil {
+			return walkErr
+		}
+		if path == root {
+			return nil
+		}
+
+		switch ClassifyEntry(path, d) {
+		case FileCategorySymlink:
+			out.Counts.Symlink++
+			return nil
+		case FileCategoryDirectory:
+			out.Counts.Directory++
+			if !recursive {
+				return fs.SkipDir
+			}
+			return nil
+		case FileCategoryText:
+			out.Counts.Text++
+		case FileCategoryImage:
+			out.Counts.Image++
+		default:
+			out.Counts.Binary++
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				// Removed while walking.
+				return nil
+			}
+			return err
+		}
+		out.TotalFiles++
+		out.TotalSizeBytes += info.Size()
+
+		mt := info.ModTime().UTC()
+		ref := &FileRef{Path: path, Size: info.Size(), ModTime: &mt}
+		if out.Largest == nil || ref.Size > out.Largest.Size {
+			out.Largest = ref
+		}
+		if out.Newest == nil || mt.After(*out.Newest.ModTime) {
+			out.Newest = ref
+		}
+		return nil
+	}
+
+	if err := filepath.WalkDir(root, walkFn); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
@@ -0,0 +1,75 @@
+package fileutil
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestSummarizeDirectory(t *testing.T) {
+	root := t.TempDir()
+	small := mustWriteFile(t, root, "a.txt", 3)
+	big := mustWriteFile(t, root, "b.bin", 64)
+	if err := os.WriteFile(big, append([]byte{0, 1, 2}, make([]byte, 61)...), 0o600); err != nil {
+		t.Fatalf("write binary: %v", err)
+	}
+	sub := filepath.Join(root, "sub")
+	if err := os.Mkdir(sub, 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	nested := mustWriteFile(t, sub, "n.md", 10)
+
+	old := time.Now().Add(-2 * time.Hour)
+	for _, p := range []string{small, big} {
+		if err := os.Chtimes(p, old, old); err != nil {
+			t.Fatalf("chtimes: %v", err)
+		}
+	}
+
+	tests := []struct {
+		name       string
+		recursive  bool
+		wantCounts CategoryCounts
+		wantFiles  int
+		wantNewest string
+	}{
+		{
+			name:       "non-recursive",
+			wantCounts: CategoryCounts{Text: 1, Binary: 1, Directory: 1},
+			wantFiles:  2,
+			wantNewest: small,
+		},
+		{
+			name:       "recursive",
+			recursive:  true,
+			wantCounts: CategoryCounts{Text: 2, Binary: 1, Directory: 1},
+			wantFiles:  3,
+			wantNewest: nested,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := SummarizeDirectory(t.Context(), root, tt.recursive)
+			if err != nil {
+				t.Fatalf("SummarizeDirectory: %v", err)
+			}
+			if got.Counts != tt.wantCounts {
+				t.Fatalf("counts mismatch: got %+v want %+v", got.Counts, tt.wantCounts)
+			}
+			if got.TotalFiles != tt.wantFiles {
+				t.Fatalf("TotalFiles mismatch: got %d want %d", got.TotalFiles, tt.wantFiles)
+			}
+			if got.Largest == nil || got.Largest.Path != big {
+				t.Fatalf("Largest mismatch: got %+v want %s", got.Largest, big)
+			}
+			if tt.recursive && (got.Newest == nil || got.Newest.Path != tt.wantNewest) {
+				t.Fatalf("Newest mismatch: got %+v want %s", got.Newest, tt.wantNewest)
+			}
+		})
+	}
+
+	if _, err := SummarizeDirectory(t.Context(), small, false); err == nil {
+		t.Fatalf("expected error for non-directory path")
+	}
+}
@@ -2,6 +2,7 @@ package fileutil
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"os"
 	"path/filepath"
@@ -76,7 +77,7 @@ func TestListDirectory(t *testing.T) {
 			if tc.setup != nil {
 				tc.setup(t)
 			}
-			got, err := ListDirectory(tc.dir, tc.pattern)
+			got, err := ListDirectory(tc.dir, tc.pattern, false)
 
 			if tc.wantErrIs != nil || tc.wantIsNotExist {
 				if err == nil {
@@ -100,6 +101,151 @@ func TestListDirectory(t *testing.T) {
 	}
 }
 
+func TestListDirectory_Recursive(t *testing.T) {
+	root := t.TempDir()
+	for _, d := range []string{"sub", filepath.Join("sub", "deep"), "other"} {
+		if err := os.MkdirAll(filepath.Join(root, d), 0o755); err != nil {
+			t.Fatalf("mkdir %s: %v", d, err)
+		}
+	}
+	mustWriteFile(t, root, "top.txt", 1)
+	mustWriteFile(t, root, "top.log", 1)
+	mustWriteFile(t, root, filepath.Join("sub", "b.txt"), 1)
+	mustWriteFile(t, root, filepath.Join("sub", "deep", "a.txt"), 1)
+	mustWriteFile(t, root, filepath.Join("other", "c.go"), 1)
+
+	tests := []struct {
+		name      string
+		pattern   string
+		want      []string
+		wantErrIs error
+	}{
+		{
+			name:    "single level pattern is not recursive",
+			pattern: "*.txt",
+			want:    []string{"top.txt"},
+		},
+		{
+			name:    "double star matches at any depth",
+			pattern: "**/*.txt",
+			want:    []string{"sub/b.txt", "sub/deep/a.txt", "top.txt"},
+		},
+		{
+			name:    "double star under a fixed prefix",
+			pattern: "sub/**/*.txt",
+			want:    []string{"sub/b.txt", "sub/deep/a.txt"},
+		},
+		{
+			name:    "trailing double star matches the directory and its subtree",
+			pattern: "sub/**",
+			want:    []string{"sub", "sub/b.txt", "sub/deep", "sub/deep/a.txt"},
+		},
+		{
+			name:    "no matches returns empty slice",
+			pattern: "**/*.md",
+			want:    []string{},
+		},
+		{
+			name:      "invalid segment returns filepath.ErrBadPattern",
+			pattern:   "**/[",
+			wantErrIs: filepath.ErrBadPattern,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := ListDirectory(root, tc.pattern, false)
+			if tc.wantErrIs != nil {
+				if !errors.Is(err, tc.wantErrIs) {
+					t.Fatalf("error=%v; want errors.Is(_, %v)=true", err, tc.wantErrIs)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Fatalf("got=%#v want=%#v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestListDirectoryDetailed(t *testing.T) {
+	root := t.TempDir()
+	mustWriteFile(t, root, "b.log", 7)
+	mustWriteFile(t, root, "a.txt", 3)
+	if err := os.Mkdir(filepath.Join(root, "subdir"), 0o755); err != nil {
+		t.Fatalf("failed to mkdir subdir: %v", err)
+	}
+	mustWriteFile(t, root, filepath.Join("subdir", "c.txt"), 5)
+
+	tests := []struct {
+		name      string
+		pattern   string
+		wantNames []string
+		wantDirs  []bool
+		wantSizes map[string]int64
+		wantErrIs error
+	}{
+		{
+			name:      "mixed directory",
+			wantNames: []string{"a.txt", "b.log", "subdir"},
+			wantDirs:  []bool{false, false, true},
+			wantSizes: map[string]int64{"a.txt": 3, "b.log": 7},
+		},
+		{
+			name:      "pattern filters entries",
+			pattern:   "*.log",
+			wantNames: []string{"b.log"},
+			wantDirs:  []bool{false},
+			wantSizes: map[string]int64{"b.log": 7},
+		},
+		{
+			name:      "recursive pattern",
+			pattern:   "**/*.txt",
+			wantNames: []string{"a.txt", "subdir/c.txt"},
+			wantDirs:  []bool{false, false},
+			wantSizes: map[string]int64{"a.txt": 3, "subdir/c.txt": 5},
+		},
+		{
+			name:      "invalid glob returns filepath.ErrBadPattern",
+			pattern:   "[",
+			wantErrIs: filepath.ErrBadPattern,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := ListDirectoryDetailed(root, tc.pattern, false)
+			if tc.wantErrIs != nil {
+				if !errors.Is(err, tc.wantErrIs) {
+					t.Fatalf("error=%v; want errors.Is(_, %v)=true", err, tc.wantErrIs)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(got) != len(tc.wantNames) {
+				t.Fatalf("got %d entries (%#v), want %v", len(got), got, tc.wantNames)
+			}
+			for i, e := range got {
+				if e.Name != tc.wantNames[i] {
+					t.Errorf("entry %d name=%q want %q", i, e.Name, tc.wantNames[i])
+				}
+				if e.IsDir != tc.wantDirs[i] || e.Mode.IsDir() != tc.wantDirs[i] {
+					t.Errorf("%s: IsDir=%v Mode=%v want dir=%v", e.Name, e.IsDir, e.Mode, tc.wantDirs[i])
+				}
+				if want, ok := tc.wantSizes[e.Name]; ok && e.Size != want {
+					t.Errorf("%s: Size=%d want %d", e.Name, e.Size, want)
+				}
+				if e.ModTime.IsZero() {
+					t.Errorf("%s: zero ModTime", e.Name)
+				}
+			}
+		})
+	}
+}
+
 func TestListDirectory_DefaultPathDot(t *testing.T) {
 	// Not parallel: this test changes the working directory globally.
 	tmp := t.TempDir()
@@ -111,7 +257,7 @@ func TestListDirectory_DefaultPathDot(t *testing.T) {
 		t.Fatalf("failed to mkdir: %v", err)
 	}
 
-	got, err := ListDirectory("", "")
+	got, err := ListDirectory("", "", false)
 	if err != nil {
 		t.Fatalf("expected nil error, got %v", err)
 	}
@@ -179,7 +325,7 @@ func TestListDirectory_Additional(t *testing.T) {
 
 	for _, tc := range tests {
 		t.Run(tc.name, func(t *testing.T) {
-			got, err := ListDirectory(tc.dir, tc.pattern)
+			got, err := ListDirectory(tc.dir, tc.pattern, false)
 
 			if tc.wantErr {
 				if err == nil {
@@ -221,3 +367,25 @@ func mustWriteFile(t *testing.T, dir, name string, size int) string {
 	}
 	return full
 }
+
+func TestListDirectoryCtx_Canceled(t *testing.T) {
+	root := t.TempDir()
+	mustWriteFile(t, root, "a.txt", 1)
+	if err := os.Mkdir(filepath.Join(root, "sub"), 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	mustWriteFile(t, filepath.Join(root, "sub"), "b.txt", 1)
+
+	ctx, cancel := context.WithCancel(t.Context())
+	cancel()
+	for _, pattern := range []string{"", "*.txt", "**/*.txt"} {
+		if _, err := ListDirectoryCtx(ctx, root, pattern, false); !errors.Is(err, context.Canceled) {
+			t.Errorf("pattern %q: err=%v want context.Canceled", pattern, err)
+		}
+	}
+
+	got, err := ListDirectoryCtx(t.Context(), root, "**/*.txt", false)
+	if err != nil || !slices.Equal(got, []string{"a.txt", "sub/b.txt"}) {
+		t.Fatalf("live context: got %v, %v", got, err)
+	}
+}
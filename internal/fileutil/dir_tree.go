@@ -0,0 +1,121 @@
+package fileutil
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// DirTreeNode is one entry of a directory tree built by BuildDirTree.
+type DirTreeNode struct {
+	Name      string         `json:"name"`
+	IsDir     bool           `json:"isDir"`
+	IsSymlink bool           `json:"isSymlink,omitempty"`
+	Truncated bool           `json:"truncated,omitempty"` // some children omitted
+	Children  []*DirTreeNode `json:"children,omitempty"`
+}
+
+// DirTree is the result of BuildDirTree.
+type DirTree struct {
+	Path      string       `json:"path"`
+	Root      *DirTreeNode `json:"root"`
+	NodeCount int          `json:"nodeCount"` // excluding Root
+	Truncated bool         `json:"truncated"`
+}
+
+// BuildDirTree returns the tree under dir (default "."), breadth first so that
+// upper levels are kept when the budget runs out. At most maxEntries nodes
+// (excluding the root) are included, and directories deeper than maxDepth
+// (root children are depth 1) are not expanded; 0 means no limit for either.
+// Directories whose children were cut are marked Truncated. Symlinked
+// directories are only expanded with followSymlinks, and then each directory is
+// expanded once (by resolved path) so cycles terminate. Entries are sorted by
+// name. Subdirectories that cannot be read are kept, marked Truncated.
+func BuildDirTree(ctx context.Context, dir string, maxDepth, maxEntries int, followSymlinks bool) (*DirTree, error) {
+	if dir == "" {
+		dir = "."
+	}
+	root, err := NormalizePath(dir)
+	if err != nil {
+		return nil, err
+	}
+	st, err := os.Stat(root)
+	if err != nil {
+		return nil, err
+	}
+	if !st.IsDir() {
+		return nil, fmt.Errorf("path is not a directory: %s", root)
+	}
+
+	type pending struct {
+		node  *DirTreeNode
+		path  string
+		depth int
+	}
+	out := &DirTree{Path: root, Root: &DirTreeNode{Name: filepath.Base(root), IsDir: true}}
+	visited := map[string]bool{}
+	queue := []pending{{node: out.Root, path: root}}
+	for len(queue) > 0 {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		cur := queue[0]
+		queue = queue[1:]
+
+		if followSymlinks {
+			realPath, err := filepath.EvalSymlinks(cur.path)
+			if err != nil {
+				return nil, err
+			}
+			if visited[realPath] {
+				continue
+			}
+			visited[realPath] = true
+		}
+		entries, err := os.ReadDir(cur.path)
+		if err != nil {
+			if cur.node == out.Root || !(errors.Is(err, fs.ErrPermission) || errors.Is(err, fs.ErrNotExist)) {
+				return nil, err
+			}
+			// Unreadable or removed while walking: keep the node, without children.
+			cur.node.Truncated = true
+			out.Truncated = true
+			continue
+		}
+		if len(entries) == 0 {
+			continue
+		}
+		budgetLeft := maxEntries <= 0 || out.NodeCount < maxEntries
+		if (maxDepth > 0 && cur.depth >= maxDepth) || !budgetLeft {
+			cur.node.Truncated = true
+			out.Truncated = true
+			continue
+		}
+
+		for _, e := range entries {
+			if maxEntries > 0 && out.NodeCount >= maxEntries {
+				cur.node.Truncated = true
+				out.Truncated = true
+				break
+			}
+			p := filepath.Join(cur.path, e.Name())
+			n := &DirTreeNode{Name: e.Name(), IsDir: e.IsDir()}
+			if e.Type()&fs.ModeSymlink != 0 {
+				n.IsSymlink = true
+				// Broken, looping or unreadable links are reported as non-directories.
+				if st, err := os.Stat(p); err == nil {
+					n.IsDir = st.IsDir()
+				}
+			}
+			cur.node.Children = append(cur.node.Children, n)
+			out.NodeCount++
+			if n.IsDir && (!n.IsSymlink || followSymlinks) {
+				queue = append(queue, pending{node: n, path: p, depth: cur.depth + 1})
+			}
+		}
+	}
+	return out, nil
+}
@@ -132,6 +132,9 @@ const (
 	MIMEApplicationOpenXMLXLS MIMEType = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
 	MIMEApplicationODT        MIMEType = "application/vnd.oasis.opendocument.text"
 	MIMEApplicationODS        MIMEType = "application/vnd.oasis.opendocument.spreadsheet"
+
+	MIMEApplicationZIP  MIMEType = "application/zip"
+	MIMEApplicationGzip MIMEType = "application/gzip"
 )
 
 // ExtensionToMIMEType is an internal registry of common/explicitly-supported extensions.
@@ -291,6 +294,19 @@ func MIMEForLocalFile(
 	return mt, m, MIMEDetectMethodSniff, nil
 }
 
+// MIMEForContent is MIMEForLocalFile for content already in memory: name (a
+// file name or path) supplies the extension and data is sniffed if needed.
+func MIMEForContent(name string, data []byte) (mimeType MIMEType, mode ExtensionMode, method MIMEDetectMethod) {
+	if ext := filepath.Ext(name); ext != "" {
+		mt, e := MIMEFromExtensionString(ext)
+		if e == nil && mt != MIMEEmpty && GetBaseMIME(mt) != string(MIMEApplicationOctetStream) {
+			return mt, GetModeForMIME(mt), MIMEDetectMethodExtension
+		}
+	}
+	mt := MIMEFromContent(data)
+	return mt, GetModeForMIME(mt), MIMEDetectMethodSniff
+}
+
 // MIMEFromExtensionString returns a best-known MIME for the given extension string.
 // Accepts "png" as well as ".png" (useful because image.DecodeConfig returns "png").
 //
@@ -331,36 +347,68 @@ func SniffFileMIME(path string) (mimeType MIMEType, mode ExtensionMode, err erro
 	}
 	defer f.Close()
 
-	buf := make([]byte, 4096)
+	buf := make([]byte, mimeSniffBytes)
 	n, err := f.Read(buf)
 	if err != nil && !errors.Is(err, io.EOF) {
 		return MIMEEmpty, ExtensionModeDefault, err
 	}
 
-	sample := buf[:n]
+	mt := MIMEFromContent(buf[:n])
+	return mt, GetModeForMIME(mt), nil
+}
+
+// mimeSniffBytes bounds the prefix inspected by content sniffing.
+const mimeSniffBytes = 4096
+
+// contentMagic lists leading byte signatures checked before falling back to
+// http.DetectContentType.
+var contentMagic = []struct {
+	prefix string
+	mime   MIMEType
+}{
+	{"%PDF-", MIMEApplicationPDF},
+	{"\x89PNG\r\n\x1a\n", MIMEImagePNG},
+	{"\xff\xd8\xff", MIMEImageJPEG},
+	{"GIF87a", MIMEImageGIF},
+	{"GIF89a", MIMEImageGIF},
+	{"PK\x03\x04", MIMEApplicationZIP},
+	{"PK\x05\x06", MIMEApplicationZIP}, // Empty archive.
+	{"\x1f\x8b", MIMEApplicationGzip},
+}
+
+// MIMEFromContent returns a best-effort MIME type for data, looking at no more
+// than its first mimeSniffBytes bytes. Common formats are matched by magic
+// bytes; anything else goes through http.DetectContentType, with a text
+// heuristic deciding between text/plain and application/octet-stream when
+// that is inconclusive. Empty data is treated as text.
+func MIMEFromContent(data []byte) MIMEType {
+	sample := data[:min(len(data), mimeSniffBytes)]
 	if len(sample) == 0 {
-		// Empty file: treat as text.
-		return MIMETextPlain, ExtensionModeText, nil
+		return MIMETextPlain
+	}
+	for _, m := range contentMagic {
+		if strings.HasPrefix(string(sample[:min(len(sample), len(m.prefix))]), m.prefix) {
+			return m.mime
+		}
 	}
 
 	mt := MIMEType(http.DetectContentType(sample))
-	m := GetModeForMIME(mt)
 
 	// If DetectContentType is generic, try to classify text via heuristic.
 	if GetBaseMIME(mt) == string(MIMEApplicationOctetStream) || mt == MIMEEmpty {
 		if isProbablyTextSample(sample) {
-			return MIMETextPlain, ExtensionModeText, nil
+			return MIMETextPlain
 		}
-		return MIMEApplicationOctetStream, ExtensionModeDefault, nil
+		return MIMEApplicationOctetStream
 	}
 
 	// If DetectContentType says "text/plain" but the sample is clearly binary,
-	// downgrade to default/octet-stream.
-	if m == ExtensionModeText && !isProbablyTextSample(sample) {
-		return MIMEApplicationOctetStream, ExtensionModeDefault, nil
+	// downgrade to octet-stream.
+	if GetModeForMIME(mt) == ExtensionModeText && !isProbablyTextSample(sample) {
+		return MIMEApplicationOctetStream
 	}
 
-	return mt, m, nil
+	return mt
 }
 
 func GetModeForMIME(mt MIMEType) ExtensionMode {
@@ -1,6 +1,7 @@
 package fileutil
 
 import (
+	"bytes"
 	"errors"
 	"mime"
 	"os"
@@ -345,6 +346,81 @@ func TestSniffFileMIME(t *testing.T) {
 	}
 }
 
+func TestMIMEFromContent(t *testing.T) {
+	tests := []struct {
+		name string
+		data []byte
+		want MIMEType
+	}{
+		{name: "empty is text", data: nil, want: MIMETextPlain},
+		{name: "PDF header", data: []byte("%PDF-1.7\n%\xe2\xe3\xcf\xd3\n"), want: MIMEApplicationPDF},
+		{
+			name: "PNG signature",
+			data: []byte{0x89, 'P', 'N', 'G', '\r', '\n', 0x1a, '\n', 0, 0, 0, 13},
+			want: MIMEImagePNG,
+		},
+		{name: "JPEG SOI", data: []byte{0xff, 0xd8, 0xff, 0xe0, 0, 0x10}, want: MIMEImageJPEG},
+		{name: "GIF89a", data: []byte("GIF89a\x01\x00"), want: MIMEImageGIF},
+		{name: "ZIP local header", data: []byte("PK\x03\x04\x14\x00"), want: MIMEApplicationZIP},
+		{name: "gzip", data: []byte{0x1f, 0x8b, 0x08, 0}, want: MIMEApplicationGzip},
+		{name: "plain text", data: []byte("just some words\nand another line\n"), want: MIMETextPlain},
+		{name: "truncated magic is not matched", data: []byte("%PD"), want: MIMETextPlain},
+		{name: "binary without magic", data: []byte{0x00, 0x01, 0x02, 0x03, 0xfe}, want: MIMEApplicationOctetStream},
+		{
+			name: "only a bounded prefix is inspected",
+			data: append(bytes.Repeat([]byte("a"), mimeSniffBytes), 0x00),
+			want: MIMETextPlain,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := MIMEFromContent(tc.data); got != tc.want {
+				t.Fatalf("MIMEFromContent=%q want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestMIMEForContent(t *testing.T) {
+	tests := []struct {
+		name       string
+		file       string
+		data       []byte
+		wantMIME   MIMEType
+		wantMethod MIMEDetectMethod
+	}{
+		{
+			name:       "extension wins",
+			file:       "docs/a.json",
+			data:       []byte{0x00},
+			wantMIME:   MIMEApplicationJSON,
+			wantMethod: MIMEDetectMethodExtension,
+		},
+		{
+			name:       "no extension sniffs",
+			file:       "blob",
+			data:       []byte("%PDF-1.4"),
+			wantMIME:   MIMEApplicationPDF,
+			wantMethod: MIMEDetectMethodSniff,
+		},
+		{
+			name:       "unknown extension sniffs",
+			file:       "x.zzz9",
+			data:       []byte("hi"),
+			wantMIME:   MIMETextPlain,
+			wantMethod: MIMEDetectMethodSniff,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mt, mode, method := MIMEForContent(tc.file, tc.data)
+			if mt != tc.wantMIME || method != tc.wantMethod || mode != GetModeForMIME(tc.wantMIME) {
+				t.Fatalf("MIMEForContent=(%q, %q, %q) want (%q, %q)", mt, mode, method, tc.wantMIME, tc.wantMethod)
+			}
+		})
+	}
+}
+
 func TestIsProbablyTextSample(t *testing.T) {
 	tests := []struct {
 		name string
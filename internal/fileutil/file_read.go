@@ -1,7 +1,9 @@
 package fileutil
 
 import (
+	"crypto/sha256"
 	"encoding/base64"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"io"
@@ -51,3 +53,47 @@ func ReadFile(path string, encoding ReadEncoding, maxBytes int64) (string, error
 	}
 	return base64.StdEncoding.EncodeToString(data), nil
 }
+
+// SHA256HexFile streams the regular file at path through SHA-256 and returns the
+// lowercase hex digest. Symlinks (including in parent components) and non-regular
+// files such as FIFOs are refused. If maxBytes > 0, larger files are an error.
+func SHA256HexFile(path string, maxBytes int64) (string, error) {
+	if path == "" {
+		return "", ErrInvalidPath
+	}
+	st, err := RequireExistingRegularFileNoSymlink(path)
+	if err != nil {
+		return "", err
+	}
+	if maxBytes > 0 && st.Size() > maxBytes {
+		return "", fmt.Errorf("file %q exceeds maximum allowed size (%d bytes)", path, maxBytes)
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	// Guard against the path being replaced between the checks and the open.
+	fst, err := f.Stat()
+	if err != nil {
+		return "", err
+	}
+	if !os.SameFile(st, fst) {
+		return "", fmt.Errorf("file changed while opening: %s", path)
+	}
+
+	r := io.Reader(f)
+	if maxBytes > 0 {
+		r = io.LimitReader(f, maxBytes+1)
+	}
+	h := sha256.New()
+	n, err := io.Copy(h, r)
+	if err != nil {
+		return "", err
+	}
+	if maxBytes > 0 && n > maxBytes {
+		return "", fmt.Errorf("file %q exceeds maximum allowed size (%d bytes)", path, maxBytes)
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
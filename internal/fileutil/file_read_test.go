@@ -167,3 +167,59 @@ func TestReadFile_MaxBytes(t *testing.T) {
 		})
 	}
 }
+
+func TestSHA256HexFile(t *testing.T) {
+	dir := t.TempDir()
+	p := filepath.Join(dir, "hash.txt")
+	writeFile(t, p, "abc")
+	link := filepath.Join(dir, "link.txt")
+	if err := os.Symlink(p, link); err != nil {
+		link = ""
+	}
+
+	tests := []struct {
+		name      string
+		path      string
+		maxBytes  int64
+		needsLink bool
+		want      string
+		wantErr   string
+	}{
+		{
+			name: "known digest",
+			path: p,
+			want: "ba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad",
+		},
+		{
+			name:     "at size limit",
+			path:     p,
+			maxBytes: 3,
+			want:     "ba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad",
+		},
+		{name: "over size limit", path: p, maxBytes: 2, wantErr: "exceeds maximum allowed size"},
+		{name: "symlink refused", path: link, needsLink: true, wantErr: "symlink"},
+		{name: "directory refused", path: dir, wantErr: "directory"},
+		{name: "empty path", path: "", wantErr: "invalid path"},
+		{name: "missing file", path: filepath.Join(dir, "missing.txt"), wantErr: "missing.txt"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.needsLink && link == "" {
+				t.Skip("symlink not supported")
+			}
+			got, err := SHA256HexFile(tt.path, tt.maxBytes)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("err=%v, want substring %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Fatalf("digest mismatch: got %s want %s", got, tt.want)
+			}
+		})
+	}
+}
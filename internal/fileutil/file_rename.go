@@ -0,0 +1,38 @@
+package fileutil
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"runtime"
+
+	"github.com/flexigpt/llmtools-go/internal/toolutil"
+)
+
+// RenameNoReplace renames src to dst, failing with an os.ErrExist error if dst
+// already exists. On Unix the destination is created with a hard link (which never
+// overwrites) before src is removed, so an existing dst is never clobbered even if
+// it appears concurrently. Where hard links are unsupported, and on Windows, dst is
+// checked first and the remaining race window is accepted.
+func RenameNoReplace(src, dst string) error {
+	if runtime.GOOS != toolutil.GOOSWindows {
+		err := os.Link(src, dst)
+		if err == nil {
+			if rmErr := os.Remove(src); rmErr != nil {
+				_ = os.Remove(dst)
+				return rmErr
+			}
+			return nil
+		}
+		if errors.Is(err, os.ErrExist) {
+			return fmt.Errorf("destination already exists: %s: %w", dst, os.ErrExist)
+		}
+		// Fall through: e.g. the filesystem does not support hard links.
+	}
+	if _, err := os.Lstat(dst); err == nil {
+		return fmt.Errorf("destination already exists: %s: %w", dst, os.ErrExist)
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return os.Rename(src, dst)
+}
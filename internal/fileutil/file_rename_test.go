@@ -0,0 +1,53 @@
+package fileutil
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRenameNoReplace(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name      string
+		dstExists bool
+		wantErrIs error
+	}{
+		{name: "renames to a free name"},
+		{name: "refuses existing destination", dstExists: true, wantErrIs: os.ErrExist},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			dir := t.TempDir()
+			src, dst := filepath.Join(dir, "src.txt"), filepath.Join(dir, "dst.txt")
+			mustWriteBytes(t, src, []byte("src"))
+			if tt.dstExists {
+				mustWriteBytes(t, dst, []byte("dst"))
+			}
+
+			err := RenameNoReplace(src, dst)
+			if tt.wantErrIs != nil {
+				if !errors.Is(err, tt.wantErrIs) {
+					t.Fatalf("expected errors.Is(%v), got %v", tt.wantErrIs, err)
+				}
+				for p, want := range map[string]string{src: "src", dst: "dst"} {
+					if got, _ := os.ReadFile(p); string(got) != want {
+						t.Fatalf("%s = %q, want %q (must be untouched)", p, got, want)
+					}
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if _, err := os.Lstat(src); !errors.Is(err, os.ErrNotExist) {
+				t.Fatalf("source still exists: %v", err)
+			}
+			if got, _ := os.ReadFile(dst); string(got) != "src" {
+				t.Fatalf("dst = %q, want %q", got, "src")
+			}
+		})
+	}
+}
@@ -2,6 +2,7 @@ package fileutil
 
 import (
 	"bytes"
+	"context"
 	"encoding/base64"
 	"errors"
 	"fmt"
@@ -21,6 +22,10 @@ type ImageInfo struct {
 	Height   int      `json:"height,omitempty"`
 	Format   string   `json:"format,omitempty"`   // e.g. "jpeg", "png"
 	MIMEType MIMEType `json:"mimeType,omitempty"` // e.g. "image/jpeg"
+
+	// Orientation is the EXIF orientation (1-8) for JPEGs that carry one; 0 otherwise.
+	// Width/Height are always the raw (stored) dimensions.
+	Orientation int `json:"orientation,omitempty"`
 }
 
 // ImageData holds metadata (and optionally content) for an image file.
@@ -34,11 +39,27 @@ type ImageData struct {
 // If includeBase64 is true, Base64Data will contain the base64-encoded file
 // contents. If the file does not exist, Exists == false and err == nil.
 // Returns an error if the path is empty, a directory, or not a supported image.
+// Files larger than maxBytes (if > 0) are refused with ErrFileExceedsMaxSize,
+// whether or not contents are requested.
 func ReadImage(
 	path string,
 	includeBase64Data bool,
 	maxBytes int64,
 ) (*ImageData, error) {
+	return ReadImageCtx(context.Background(), path, includeBase64Data, maxBytes)
+}
+
+// ReadImageCtx is ReadImage that returns ctx.Err() if ctx is done before the
+// file is read or its header decoded.
+func ReadImageCtx(
+	ctx context.Context,
+	path string,
+	includeBase64Data bool,
+	maxBytes int64,
+) (*ImageData, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
 	if strings.TrimSpace(path) == "" {
 		return nil, ErrInvalidPath
 	}
@@ -53,44 +74,44 @@ func ReadImage(
 	st, err := os.Lstat(p)
 	if err != nil {
 		if errors.Is(err, os.ErrNotExist) {
-			out.Exists = false
+			// Not an error: just report non-existence.
 			return out, nil
 		}
 		return nil, err
 	}
-	out.Exists = true
-	out.Name = st.Name()
-	out.IsDir = st.IsDir()
-	out.Size = st.Size()
-	mt := st.ModTime().UTC()
-	out.ModTime = &mt
-
+	// Reject before reporting any metadata, which would describe the link.
 	if (st.Mode() & os.ModeSymlink) != 0 {
 		return nil, fmt.Errorf("refusing to operate on symlink file: %s", p)
 	}
-	if !out.Exists {
-		// Not an error: just report non-existence.
-		return out, nil
-	}
-	if out.IsDir {
+	if st.IsDir() {
 		return nil, errors.New("path points to a directory, expected file")
 	}
 	if !st.Mode().IsRegular() {
 		return nil, fmt.Errorf("expected regular file: %s", p)
 	}
+	out.Exists = true
+	out.Name = st.Name()
+	out.Size = st.Size()
+	mt := st.ModTime().UTC()
+	out.ModTime = &mt
+
+	// The cap applies even when only headers are decoded, so an oversized file
+	// is refused up front rather than surfacing as a truncated decode.
+	if maxBytes > 0 && out.Size > maxBytes {
+		return nil, fmt.Errorf(
+			"file %q exceeds maximum allowed size (%d bytes): %w",
+			out.Path,
+			maxBytes,
+			ErrFileExceedsMaxSize,
+		)
+	}
 
 	// We need to decode the image config; if includeBase64 is true, we can
 	// read the whole file once and reuse that data for both config and base64.
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
 	if includeBase64Data {
-		if maxBytes > 0 && out.Size > maxBytes {
-			return nil, fmt.Errorf(
-				"file %q exceeds maximum allowed size (%d bytes): %w",
-				out.Path,
-				maxBytes,
-				ErrFileExceedsMaxSize,
-			)
-		}
-
 		f, err := os.Open(out.Path)
 		if err != nil {
 			return nil, err
@@ -114,6 +135,9 @@ func ReadImage(
 			)
 		}
 
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
 		reader := bytes.NewReader(data)
 		err = decodeImageConfig(out, reader)
 		if err != nil {
@@ -143,10 +167,16 @@ func ReadImage(
 }
 
 func decodeImageConfig(info *ImageData, reader io.Reader) error {
-	cfg, fmtName, err := image.DecodeConfig(reader)
+	// Keep the bytes consumed by DecodeConfig: for JPEG the APP1/Exif segment
+	// precedes the SOF marker, so it is always within what the decoder read.
+	var head bytes.Buffer
+	cfg, fmtName, err := image.DecodeConfig(io.TeeReader(reader, &head))
 	if err != nil {
 		return err
 	}
+	if fmtName == "jpeg" {
+		info.Orientation = jpegEXIFOrientation(head.Bytes())
+	}
 
 	info.Width = cfg.Width
 	info.Height = cfg.Height
@@ -0,0 +1,163 @@
+package fileutil
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"os"
+	"strings"
+)
+
+// maxDecodePixels caps width*height before a full pixel decode, so a tiny file
+// with huge declared dimensions cannot exhaust memory.
+const maxDecodePixels = 64 * 1024 * 1024
+
+// ErrUnsupportedImageFormat is returned when an encode target is not supported.
+var ErrUnsupportedImageFormat = errors.New("unsupported image format")
+
+// DecodedImage is a fully decoded image plus the metadata of its source file.
+type DecodedImage struct {
+	ImageInfo
+
+	Image image.Image `json:"-"`
+	// FrameCount is the number of frames in the source; > 1 only for animated GIFs.
+	// Image always holds the first frame.
+	FrameCount int `json:"frameCount"`
+}
+
+// DecodeImageFile reads and fully decodes a local image file using the registered decoders.
+// It applies the same safety rules as ReadImage (no symlinks, regular file, maxBytes cap)
+// but, unlike ReadImage, a non-existent path is an error.
+func DecodeImageFile(path string, maxBytes int64) (*DecodedImage, error) {
+	p, err := NormalizePath(path)
+	if err != nil {
+		return nil, err
+	}
+	st, err := RequireExistingRegularFileNoSymlink(p)
+	if err != nil {
+		return nil, err
+	}
+	if maxBytes > 0 && st.Size() > maxBytes {
+		return nil, fmt.Errorf(
+			"file %q exceeds maximum allowed size (%d bytes): %w",
+			p,
+			maxBytes,
+			ErrFileExceedsMaxSize,
+		)
+	}
+
+	f, err := os.Open(p)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	r := io.Reader(f)
+	if maxBytes > 0 {
+		r = io.LimitReader(f, maxBytes+1)
+	}
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	if maxBytes > 0 && int64(len(data)) > maxBytes {
+		return nil, fmt.Errorf(
+			"file %q exceeds maximum allowed size (%d bytes): %w",
+			p,
+			maxBytes,
+			ErrFileExceedsMaxSize,
+		)
+	}
+
+	info := ImageData{}
+	info.Path = p
+	info.Name = st.Name()
+	info.Exists = true
+	info.Size = st.Size()
+	mt := st.ModTime().UTC()
+	info.ModTime = &mt
+	if err := decodeImageConfig(&info, bytes.NewReader(data)); err != nil {
+		return nil, err
+	}
+	if int64(info.Width)*int64(info.Height) > maxDecodePixels {
+		return nil, fmt.Errorf(
+			"image dimensions %dx%d exceed decode limit (%d pixels)",
+			info.Width,
+			info.Height,
+			maxDecodePixels,
+		)
+	}
+
+	out := &DecodedImage{ImageInfo: info.ImageInfo, FrameCount: 1}
+	if info.Format == "gif" {
+		g, err := gif.DecodeAll(bytes.NewReader(data))
+		if err != nil {
+			return nil, err
+		}
+		if len(g.Image) == 0 {
+			return nil, errors.New("gif contains no frames")
+		}
+		out.Image = g.Image[0]
+		out.FrameCount = len(g.Image)
+		return out, nil
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	out.Image = img
+	return out, nil
+}
+
+// NormalizeImageFormat lowercases a format name and maps aliases ("jpg") to the
+// names used by the image package ("jpeg").
+func NormalizeImageFormat(format string) string {
+	f := strings.ToLower(strings.TrimSpace(format))
+	f = strings.TrimPrefix(f, ".")
+	if f == "jpg" {
+		return "jpeg"
+	}
+	return f
+}
+
+// EncodeImage encodes img into format ("png", "jpeg" or "gif").
+// jpegQuality is only used for JPEG; 0 selects jpeg.DefaultQuality.
+func EncodeImage(img image.Image, format string, jpegQuality int) ([]byte, MIMEType, error) {
+	if img == nil {
+		return nil, MIMEEmpty, errors.New("nil image")
+	}
+
+	var buf bytes.Buffer
+	switch f := NormalizeImageFormat(format); f {
+	case "png":
+		if err := png.Encode(&buf, img); err != nil {
+			return nil, MIMEEmpty, err
+		}
+		return buf.Bytes(), MIMEImagePNG, nil
+	case "jpeg":
+		q := jpegQuality
+		if q == 0 {
+			q = jpeg.DefaultQuality
+		}
+		if q < 1 || q > 100 {
+			return nil, MIMEEmpty, fmt.Errorf("jpeg quality must be between 1 and 100, got %d", q)
+		}
+		if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: q}); err != nil {
+			return nil, MIMEEmpty, err
+		}
+		return buf.Bytes(), MIMEImageJPEG, nil
+	case "gif":
+		if err := gif.Encode(&buf, img, nil); err != nil {
+			return nil, MIMEEmpty, err
+		}
+		return buf.Bytes(), MIMEImageGIF, nil
+	default:
+		return nil, MIMEEmpty, fmt.Errorf("%w: %q", ErrUnsupportedImageFormat, format)
+	}
+}
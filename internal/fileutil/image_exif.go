@@ -0,0 +1,109 @@
+package fileutil
+
+import (
+	"bytes"
+	"encoding/binary"
+)
+
+const (
+	jpegMarkerSOI  = 0xD8
+	jpegMarkerSOS  = 0xDA
+	jpegMarkerAPP1 = 0xE1
+
+	exifTagOrientation = 0x0112
+	tiffTypeShort      = 3
+)
+
+// OrientationSwapsAxes reports whether an EXIF orientation (1-8) rotates the
+// image by 90 or 270 degrees, i.e. display width/height are swapped.
+func OrientationSwapsAxes(orientation int) bool {
+	return orientation >= 5 && orientation <= 8
+}
+
+// jpegEXIFOrientation scans JPEG header bytes for an APP1/Exif segment and returns
+// the IFD0 orientation tag (1-8). It returns 0 when no valid orientation is found.
+// Parsing is best effort: truncated or malformed data yields 0, never an error.
+func jpegEXIFOrientation(head []byte) int {
+	if len(head) < 4 || head[0] != 0xFF || head[1] != jpegMarkerSOI {
+		return 0
+	}
+
+	i := 2
+	for i+4 <= len(head) {
+		if head[i] != 0xFF {
+			return 0
+		}
+		marker := head[i+1]
+		if marker == 0xFF {
+			// Fill byte.
+			i++
+			continue
+		}
+		if marker == jpegMarkerSOS {
+			return 0
+		}
+		segLen := int(binary.BigEndian.Uint16(head[i+2 : i+4]))
+		if segLen < 2 {
+			return 0
+		}
+		start, end := i+4, i+2+segLen
+		if end > len(head) {
+			return 0
+		}
+		if marker == jpegMarkerAPP1 {
+			if o := exifOrientation(head[start:end]); o != 0 {
+				return o
+			}
+		}
+		i = end
+	}
+	return 0
+}
+
+func exifOrientation(seg []byte) int {
+	const exifHeader = "Exif\x00\x00"
+	if !bytes.HasPrefix(seg, []byte(exifHeader)) {
+		return 0
+	}
+	tiff := seg[len(exifHeader):]
+	if len(tiff) < 8 {
+		return 0
+	}
+
+	var bo binary.ByteOrder
+	switch string(tiff[:2]) {
+	case "II":
+		bo = binary.LittleEndian
+	case "MM":
+		bo = binary.BigEndian
+	default:
+		return 0
+	}
+	if bo.Uint16(tiff[2:4]) != 42 {
+		return 0
+	}
+
+	ifd := int(bo.Uint32(tiff[4:8]))
+	if ifd < 8 || ifd+2 > len(tiff) {
+		return 0
+	}
+	n := int(bo.Uint16(tiff[ifd : ifd+2]))
+	for e := range n {
+		off := ifd + 2 + e*12
+		if off+12 > len(tiff) {
+			return 0
+		}
+		if bo.Uint16(tiff[off:off+2]) != exifTagOrientation {
+			continue
+		}
+		if bo.Uint16(tiff[off+2:off+4]) != tiffTypeShort {
+			return 0
+		}
+		v := int(bo.Uint16(tiff[off+8 : off+10]))
+		if v < 1 || v > 8 {
+			return 0
+		}
+		return v
+	}
+	return 0
+}
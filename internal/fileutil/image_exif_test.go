@@ -0,0 +1,94 @@
+package fileutil
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"image/jpeg"
+	"path/filepath"
+	"testing"
+)
+
+func TestJPEGEXIFOrientation(t *testing.T) {
+	plain := encodeTestJPEG(t, 4, 2)
+
+	tests := []struct {
+		name string
+		data []byte
+		want int
+	}{
+		{name: "no exif", data: plain, want: 0},
+		{name: "little endian orientation 6", data: withEXIFOrientation(plain, binary.LittleEndian, 6), want: 6},
+		{name: "big endian orientation 8", data: withEXIFOrientation(plain, binary.BigEndian, 8), want: 8},
+		{name: "out of range value ignored", data: withEXIFOrientation(plain, binary.LittleEndian, 9), want: 0},
+		{name: "not a jpeg", data: []byte("not a jpeg"), want: 0},
+		{name: "truncated segment", data: withEXIFOrientation(plain, binary.LittleEndian, 6)[:12], want: 0},
+		{name: "empty", data: nil, want: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := jpegEXIFOrientation(tt.data); got != tt.want {
+				t.Fatalf("orientation: got %d want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReadImage_EXIFOrientation(t *testing.T) {
+	dir := t.TempDir()
+	p := filepath.Join(dir, "rotated.jpg")
+	mustWriteBytes(t, p, withEXIFOrientation(encodeTestJPEG(t, 40, 20), binary.BigEndian, 6))
+
+	for _, includeB64 := range []bool{false, true} {
+		info, err := ReadImage(p, includeB64, 0)
+		if err != nil {
+			t.Fatalf("ReadImage(includeB64=%v): %v", includeB64, err)
+		}
+		if info.Orientation != 6 || info.Width != 40 || info.Height != 20 {
+			t.Fatalf("unexpected info (includeB64=%v): %+v", includeB64, info.ImageInfo)
+		}
+	}
+	if !OrientationSwapsAxes(6) || OrientationSwapsAxes(3) || OrientationSwapsAxes(0) {
+		t.Fatalf("OrientationSwapsAxes mismatch")
+	}
+}
+
+func encodeTestJPEG(t *testing.T, w, h int) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, image.NewRGBA(image.Rect(0, 0, w, h)), nil); err != nil {
+		t.Fatalf("jpeg encode: %v", err)
+	}
+	return buf.Bytes()
+}
+
+// withEXIFOrientation inserts an APP1/Exif segment with a single IFD0
+// orientation entry right after the SOI marker.
+func withEXIFOrientation(jpg []byte, bo binary.AppendByteOrder, orientation uint16) []byte {
+	tiff := make([]byte, 0, 26)
+	if bo == binary.AppendByteOrder(binary.LittleEndian) {
+		tiff = append(tiff, 'I', 'I')
+	} else {
+		tiff = append(tiff, 'M', 'M')
+	}
+	tiff = bo.AppendUint16(tiff, 42)
+	tiff = bo.AppendUint32(tiff, 8) // IFD0 offset
+	tiff = bo.AppendUint16(tiff, 1) // entry count
+	tiff = bo.AppendUint16(tiff, exifTagOrientation)
+	tiff = bo.AppendUint16(tiff, tiffTypeShort)
+	tiff = bo.AppendUint32(tiff, 1)
+	tiff = bo.AppendUint16(tiff, orientation)
+	tiff = bo.AppendUint16(tiff, 0) // value padding
+	tiff = bo.AppendUint32(tiff, 0) // next IFD
+
+	payload := append([]byte("Exif\x00\x00"), tiff...)
+	seg := []byte{0xFF, jpegMarkerAPP1}
+	seg = binary.BigEndian.AppendUint16(seg, uint16(len(payload)+2))
+	seg = append(seg, payload...)
+
+	out := make([]byte, 0, len(jpg)+len(seg))
+	out = append(out, jpg[:2]...)
+	out = append(out, seg...)
+	out = append(out, jpg[2:]...)
+	return out
+}
@@ -2,6 +2,7 @@ package fileutil
 
 import (
 	"bytes"
+	"context"
 	"encoding/base64"
 	"errors"
 	"image"
@@ -108,6 +109,15 @@ func TestReadImage(t *testing.T) {
 			wantErrIs:   ErrFileExceedsMaxSize,
 			errContains: "exceeds maximum allowed size",
 		},
+		{
+			name:        "includeBase64=false enforces maxBytes too",
+			path:        imgPath,
+			includeB64:  false,
+			maxBytes:    8,
+			wantErr:     true,
+			wantErrIs:   ErrFileExceedsMaxSize,
+			errContains: "exceeds maximum allowed size",
+		},
 		{
 			name:       "includeBase64=true accepts exact maxBytes",
 			path:       imgPath,
@@ -144,6 +154,20 @@ func TestReadImage(t *testing.T) {
 			errContains: "refusing to operate on symlink file",
 			SkipWin:     true,
 		},
+		{
+			name:        "dangling symlink rejected rather than reported missing",
+			path:        filepath.Join(dir, "dangling.png"),
+			wantErr:     true,
+			errContains: "refusing to operate on symlink file",
+			SkipWin:     true,
+		},
+		{
+			name:        "symlink to directory rejected as symlink",
+			path:        filepath.Join(dir, "dirlink"),
+			wantErr:     true,
+			errContains: "refusing to operate on symlink file",
+			SkipWin:     true,
+		},
 	}
 
 	// Prepare corrupt files and symlink target (setup is outside the table to keep it table-driven).
@@ -151,6 +175,8 @@ func TestReadImage(t *testing.T) {
 	mustWriteBytes(t, filepath.Join(dir, "corrupt2.bin"), []byte("still not an image"))
 	if runtime.GOOS != toolutil.GOOSWindows {
 		mustSymlinkOrSkip(t, imgPath, filepath.Join(dir, "link.png"))
+		mustSymlinkOrSkip(t, filepath.Join(dir, "gone.png"), filepath.Join(dir, "dangling.png"))
+		mustSymlinkOrSkip(t, dir, filepath.Join(dir, "dirlink"))
 	}
 
 	for _, tc := range tests {
@@ -181,6 +207,9 @@ func TestReadImage(t *testing.T) {
 				t.Fatalf("IsDir=%v want=%v", out.IsDir, tc.wantIsDir)
 			}
 			if !tc.wantExists {
+				if out.Name != "" || out.Size != 0 || out.ModTime != nil {
+					t.Fatalf("missing path reported metadata: %+v", out.ImageInfo)
+				}
 				return
 			}
 
@@ -206,3 +235,27 @@ func TestReadImage(t *testing.T) {
 
 	_ = os.Remove(imgPath)
 }
+
+func TestReadImageCtx_Canceled(t *testing.T) {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, 2, 3))); err != nil {
+		t.Fatalf("encode png: %v", err)
+	}
+	p := filepath.Join(t.TempDir(), "pic.png")
+	if err := os.WriteFile(p, buf.Bytes(), 0o600); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(t.Context())
+	cancel()
+	for _, withData := range []bool{false, true} {
+		if out, err := ReadImageCtx(ctx, p, withData, 0); !errors.Is(err, context.Canceled) {
+			t.Errorf("includeBase64Data=%v: got %+v, %v; want context.Canceled", withData, out, err)
+		}
+	}
+
+	out, err := ReadImageCtx(t.Context(), p, true, 0)
+	if err != nil || out.Width != 2 || out.Height != 3 || out.Base64Data == "" {
+		t.Fatalf("live context: got %+v, %v", out, err)
+	}
+}
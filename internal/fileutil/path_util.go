@@ -16,21 +16,32 @@ import (
 
 var errPathMustBeAbsolute = errors.New("path must be absolute")
 
+// ErrPathEscapesRoot is returned by NormalizePathWithin for paths outside the root.
+var ErrPathEscapesRoot = errors.New("path escapes root")
+
 // EnsureDirNoSymlink creates missing directories one component at a time,
 // refusing to traverse symlink components.
 // "maxNewDirs: 0 => unlimited"; otherwise limits how many missing dirs it will create.
 func EnsureDirNoSymlink(dir string, maxNewDirs int) (created int, err error) {
-	return walkDirNoSymlink(dir, true, maxNewDirs)
+	return walkDirNoSymlink(dir, true, false, maxNewDirs)
+}
+
+// PlanDirNoSymlink runs the checks of EnsureDirNoSymlink without creating
+// anything and returns how many directories it would create.
+func PlanDirNoSymlink(dir string, maxNewDirs int) (missing int, err error) {
+	return walkDirNoSymlink(dir, true, true, maxNewDirs)
 }
 
 // VerifyDirNoSymlink ensures dir exists and is a directory, and none of its
 // components are symlinks.
 func VerifyDirNoSymlink(dir string) error {
-	_, err := walkDirNoSymlink(dir, false, 0)
+	_, err := walkDirNoSymlink(dir, false, false, 0)
 	return err
 }
 
-func walkDirNoSymlink(dir string, createMissing bool, maxNewDirs int) (created int, err error) {
+// walkDirNoSymlink checks dir component by component. With createMissing,
+// missing components are created, or only counted if dryRun is set.
+func walkDirNoSymlink(dir string, createMissing, dryRun bool, maxNewDirs int) (created int, err error) {
 	d, err := NormalizePath(dir)
 	if err != nil {
 		return 0, err
@@ -67,7 +78,7 @@ func walkDirNoSymlink(dir string, createMissing bool, maxNewDirs int) (created i
 	}
 
 	created = 0
-	for _, part := range parts {
+	for i, part := range parts {
 		if cur == "" {
 			cur = part
 		} else {
@@ -99,6 +110,14 @@ func walkDirNoSymlink(dir string, createMissing bool, maxNewDirs int) (created i
 		if !createMissing {
 			return created, err
 		}
+		if dryRun {
+			// Nothing below a missing directory can exist yet.
+			missing := len(parts) - i
+			if maxNewDirs > 0 && missing > maxNewDirs {
+				return created, fmt.Errorf("too many parent directories to create (max %d)", maxNewDirs)
+			}
+			return missing, nil
+		}
 
 		if maxNewDirs > 0 && created >= maxNewDirs {
 			return created, fmt.Errorf("too many parent directories to create (max %d)", maxNewDirs)
@@ -112,6 +131,71 @@ func walkDirNoSymlink(dir string, createMissing bool, maxNewDirs int) (created i
 	return created, nil
 }
 
+// ResolveFileSymlinks follows path while it is a symlink, for at most maxHops
+// links, and returns the final (non-symlink) path. Relative link targets are
+// resolved against the link's directory. Only the final component may be a
+// symlink: the parent directories of path and of every target must be
+// symlink-free (see VerifyDirNoSymlink). The returned path is not checked to exist.
+func ResolveFileSymlinks(path string, maxHops int) (string, error) {
+	p, err := NormalizePath(path)
+	if err != nil {
+		return "", err
+	}
+	for hops := 0; ; hops++ {
+		if err := VerifyDirNoSymlink(filepath.Dir(p)); err != nil {
+			return "", err
+		}
+		st, err := os.Lstat(p)
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				return p, nil
+			}
+			return "", err
+		}
+		if st.Mode()&os.ModeSymlink == 0 {
+			return p, nil
+		}
+		if hops >= maxHops {
+			return "", fmt.Errorf("too many levels of symlinks (max %d): %s", maxHops, path)
+		}
+		target, err := os.Readlink(p)
+		if err != nil {
+			return "", err
+		}
+		if !filepath.IsAbs(target) {
+			target = filepath.Join(filepath.Dir(p), target)
+		}
+		p = filepath.Clean(target)
+	}
+}
+
+// ResolveSymlinkInDir returns the fully resolved target of path when path is a
+// symlink. The target must lie inside the (resolved) directory containing the
+// link, else the error wraps ErrPathEscapesRoot. Paths that are not symlinks,
+// or do not exist, are returned normalized but otherwise unchanged.
+func ResolveSymlinkInDir(path string) (string, error) {
+	p, err := NormalizePath(path)
+	if err != nil {
+		return "", err
+	}
+	if st, lerr := os.Lstat(p); lerr != nil || st.Mode()&os.ModeSymlink == 0 {
+		// Not a symlink; missing or unreadable paths surface in the caller's stat.
+		return p, nil
+	}
+	realDir, err := filepath.EvalSymlinks(filepath.Dir(p))
+	if err != nil {
+		return "", err
+	}
+	target, err := filepath.EvalSymlinks(p)
+	if err != nil {
+		return "", err
+	}
+	if !isWithinDir(realDir, target) {
+		return "", fmt.Errorf("%w: symlink %s resolves to %s", ErrPathEscapesRoot, p, target)
+	}
+	return target, nil
+}
+
 func UniquePathInDir(dir, base string) (string, error) {
 	// First try the plain name.
 	p := filepath.Join(dir, base)
@@ -170,6 +254,93 @@ func NormalizePath(p string) (string, error) {
 	return filepath.Clean(p), nil
 }
 
+// NormalizePathWithin normalizes p like NormalizePath and confines it to the
+// existing directory base: relative paths are resolved against base, and the
+// result must lie within base both lexically and after evaluating symlinks in
+// its existing prefix, else the error wraps ErrPathEscapesRoot. The returned
+// path is absolute and clean but not symlink-resolved, so callers keep their
+// own symlink policy.
+func NormalizePathWithin(base, p string) (string, error) {
+	b, err := NormalizePath(base)
+	if err != nil {
+		return "", err
+	}
+	if b, err = filepath.Abs(b); err != nil {
+		return "", err
+	}
+	realBase, err := filepath.EvalSymlinks(b)
+	if err != nil {
+		return "", fmt.Errorf("invalid root %q: %w", base, err)
+	}
+
+	np, err := NormalizePath(p)
+	if err != nil {
+		return "", err
+	}
+	if !filepath.IsAbs(np) {
+		np = filepath.Join(b, np)
+	}
+	if !isWithinDir(b, np) && !isWithinDir(realBase, np) {
+		return "", fmt.Errorf("%w: %s", ErrPathEscapesRoot, np)
+	}
+	resolved, err := evalExistingPrefix(np, maxRootSymlinkHops)
+	if err != nil {
+		return "", err
+	}
+	if !isWithinDir(realBase, resolved) {
+		return "", fmt.Errorf("%w: %s resolves to %s", ErrPathEscapesRoot, np, resolved)
+	}
+	return np, nil
+}
+
+// maxRootSymlinkHops bounds dangling symlinks followed by evalExistingPrefix.
+const maxRootSymlinkHops = 40
+
+// evalExistingPrefix evaluates symlinks in the longest existing prefix of the
+// absolute path p and appends the remaining, not yet existing, components. A
+// dangling symlink in p is followed to its target, so it cannot hide where a
+// later create would land.
+func evalExistingPrefix(p string, hops int) (string, error) {
+	rest := ""
+	for cur := p; ; {
+		resolved, err := filepath.EvalSymlinks(cur)
+		if err == nil {
+			return filepath.Join(resolved, rest), nil
+		}
+		if !errors.Is(err, os.ErrNotExist) {
+			return "", err
+		}
+		if st, lerr := os.Lstat(cur); lerr == nil && st.Mode()&os.ModeSymlink != 0 {
+			if hops <= 0 {
+				return "", fmt.Errorf("too many levels of symlinks: %s", p)
+			}
+			target, err := os.Readlink(cur)
+			if err != nil {
+				return "", err
+			}
+			if !filepath.IsAbs(target) {
+				target = filepath.Join(filepath.Dir(cur), target)
+			}
+			return evalExistingPrefix(filepath.Join(target, rest), hops-1)
+		}
+		parent := filepath.Dir(cur)
+		if parent == cur {
+			return p, nil
+		}
+		rest = filepath.Join(filepath.Base(cur), rest)
+		cur = parent
+	}
+}
+
+// isWithinDir reports whether the clean absolute path p is dir or below it.
+func isWithinDir(dir, p string) bool {
+	rel, err := filepath.Rel(dir, p)
+	if err != nil {
+		return false
+	}
+	return rel == "." || (rel != ".." && !strings.HasPrefix(rel, ".."+string(os.PathSeparator)))
+}
+
 func randomHex(nBytes int) (string, error) {
 	b := make([]byte, nBytes)
 	if _, err := rand.Read(b); err != nil {
@@ -348,3 +348,123 @@ func mustSymlinkOrSkip(t *testing.T, oldname, newname string) {
 		t.Skipf("symlink not supported/allowed: %v", err)
 	}
 }
+
+func TestResolveFileSymlinks(t *testing.T) {
+	if runtime.GOOS == toolutil.GOOSWindows {
+		t.Skip("symlink tests skipped on Windows")
+	}
+	root := t.TempDir()
+	target := filepath.Join(root, "target.txt")
+	mustWriteBytes(t, target, []byte("x"))
+
+	direct := filepath.Join(root, "direct")
+	mustSymlinkOrSkip(t, target, direct)
+	relative := filepath.Join(root, "relative")
+	mustSymlinkOrSkip(t, "target.txt", relative)
+	chained := filepath.Join(root, "chained")
+	mustSymlinkOrSkip(t, direct, chained)
+	loopA, loopB := filepath.Join(root, "loopA"), filepath.Join(root, "loopB")
+	mustSymlinkOrSkip(t, loopB, loopA)
+	mustSymlinkOrSkip(t, loopA, loopB)
+
+	realDir := filepath.Join(root, "real")
+	if err := os.Mkdir(realDir, 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	mustWriteBytes(t, filepath.Join(realDir, "inner.txt"), []byte("x"))
+	linkDir := filepath.Join(root, "linkdir")
+	mustSymlinkOrSkip(t, realDir, linkDir)
+	viaDirTarget := filepath.Join(root, "viadir")
+	mustSymlinkOrSkip(t, filepath.Join(linkDir, "inner.txt"), viaDirTarget)
+
+	tests := []struct {
+		name        string
+		path        string
+		maxHops     int
+		want        string
+		errContains string
+	}{
+		{name: "regular file unchanged", path: target, maxHops: 0, want: target},
+		{name: "direct link", path: direct, maxHops: 1, want: target},
+		{name: "relative link", path: relative, maxHops: 1, want: target},
+		{name: "chain within limit", path: chained, maxHops: 2, want: target},
+		{name: "chain over limit", path: chained, maxHops: 1, errContains: "too many levels of symlinks"},
+		{name: "zero hops refuses link", path: direct, maxHops: 0, errContains: "too many levels of symlinks"},
+		{name: "loop errors", path: loopA, maxHops: 8, errContains: "too many levels of symlinks"},
+		{name: "symlinked parent refused", path: filepath.Join(linkDir, "inner.txt"), maxHops: 8, errContains: "symlink path component"},
+		{name: "target through symlinked dir refused", path: viaDirTarget, maxHops: 8, errContains: "symlink path component"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := ResolveFileSymlinks(tc.path, tc.maxHops)
+			if tc.errContains != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.errContains) {
+					t.Fatalf("expected error containing %q, got %v (path %q)", tc.errContains, err, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tc.want {
+				t.Fatalf("got %q want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestNormalizePathWithin(t *testing.T) {
+	if runtime.GOOS == toolutil.GOOSWindows {
+		t.Skip("symlink tests skipped on Windows")
+	}
+	base := t.TempDir()
+	outside := t.TempDir()
+	mustWriteBytes(t, filepath.Join(base, "in.txt"), []byte("x"))
+	mustWriteBytes(t, filepath.Join(outside, "secret.txt"), []byte("x"))
+	mustSymlinkOrSkip(t, filepath.Join(outside, "secret.txt"), filepath.Join(base, "escape"))
+	mustSymlinkOrSkip(t, outside, filepath.Join(base, "escapedir"))
+	mustSymlinkOrSkip(t, filepath.Join(outside, "new.txt"), filepath.Join(base, "dangling"))
+	mustSymlinkOrSkip(t, "in.txt", filepath.Join(base, "inlink"))
+
+	tests := []struct {
+		name       string
+		path       string
+		want       string
+		wantEscape bool
+	}{
+		{name: "relative in root", path: "in.txt", want: filepath.Join(base, "in.txt")},
+		{name: "absolute in root", path: filepath.Join(base, "in.txt"), want: filepath.Join(base, "in.txt")},
+		{name: "root itself", path: ".", want: base},
+		{name: "not yet existing", path: "sub/new.txt", want: filepath.Join(base, "sub", "new.txt")},
+		{name: "dot-dot inside root", path: "sub/../in.txt", want: filepath.Join(base, "in.txt")},
+		{name: "symlink inside root", path: "inlink", want: filepath.Join(base, "inlink")},
+		{name: "dot-dot escape", path: "../" + filepath.Base(outside) + "/secret.txt", wantEscape: true},
+		{name: "absolute outside", path: filepath.Join(outside, "secret.txt"), wantEscape: true},
+		{name: "symlink to outside file", path: "escape", wantEscape: true},
+		{name: "through symlinked dir", path: "escapedir/secret.txt", wantEscape: true},
+		{name: "new file under symlinked dir", path: "escapedir/new.txt", wantEscape: true},
+		{name: "dangling symlink to outside", path: "dangling", wantEscape: true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := NormalizePathWithin(base, tc.path)
+			if tc.wantEscape {
+				if !errors.Is(err, ErrPathEscapesRoot) {
+					t.Fatalf("NormalizePathWithin(%q): got (%q, %v), want ErrPathEscapesRoot", tc.path, got, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("NormalizePathWithin(%q): unexpected error: %v", tc.path, err)
+			}
+			if got != tc.want {
+				t.Fatalf("NormalizePathWithin(%q)=%q want=%q", tc.path, got, tc.want)
+			}
+		})
+	}
+
+	if _, err := NormalizePathWithin(filepath.Join(base, "missing"), "x"); err == nil {
+		t.Fatalf("expected error for missing base")
+	}
+}
@@ -0,0 +1,76 @@
+package fileutil
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+)
+
+// ModifiedSince walks root (default ".") recursively and returns the regular
+// files whose mtime is not before since, newest first (ties broken by path).
+// Symlinks, including symlinked directories, are skipped and never followed.
+func ModifiedSince(ctx context.Context, root string, since time.Time) ([]FileRef, error) {
+	if root == "" {
+		root = "."
+	}
+	base, err := NormalizePath(root)
+	if err != nil {
+		return nil, err
+	}
+	st, err := os.Stat(base)
+	if err != nil {
+		return nil, err
+	}
+	if !st.IsDir() {
+		return nil, fmt.Errorf("path is not a directory: %s", base)
+	}
+
+	var out []FileRef
+	walkFn := func(path string, d fs.DirEntry, walkErr error) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if walkErr != nil {
+			return walkErr
+		}
+		if d.Type()&fs.ModeSymlink != 0 || !d.Type().IsRegular() {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				// Removed while walking.
+				return nil
+			}
+			return err
+		}
+		mt := info.ModTime().UTC()
+		if mt.Before(since) {
+			return nil
+		}
+		out = append(out, FileRef{Path: path, Size: info.Size(), ModTime: &mt})
+		return nil
+	}
+	if err := filepath.WalkDir(base, walkFn); err != nil {
+		return nil, err
+	}
+
+	slices.SortFunc(out, func(a, b FileRef) int {
+		if c := b.ModTime.Compare(*a.ModTime); c != 0 {
+			return c
+		}
+		if a.Path < b.Path {
+			return -1
+		}
+		if a.Path > b.Path {
+			return 1
+		}
+		return 0
+	})
+	return out, nil
+}
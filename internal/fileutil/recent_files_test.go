@@ -0,0 +1,65 @@
+package fileutil
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestModifiedSince(t *testing.T) {
+	root := t.TempDir()
+	now := time.Now().Truncate(time.Second)
+
+	touch := func(p string, age time.Duration) {
+		t.Helper()
+		mt := now.Add(-age)
+		if err := os.Chtimes(p, mt, mt); err != nil {
+			t.Fatalf("chtimes: %v", err)
+		}
+	}
+	fresh := mustWriteFile(t, root, "fresh.txt", 1)
+	touch(fresh, time.Minute)
+	older := mustWriteFile(t, root, "older.txt", 1)
+	touch(older, 30*time.Minute)
+	stale := mustWriteFile(t, root, "stale.txt", 1)
+	touch(stale, 48*time.Hour)
+
+	sub := filepath.Join(root, "sub")
+	if err := os.Mkdir(sub, 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	nested := mustWriteFile(t, sub, "nested.txt", 1)
+	touch(nested, 10*time.Minute)
+
+	// A symlinked directory must not be followed.
+	outside := t.TempDir()
+	touch(mustWriteFile(t, outside, "outside.txt", 1), 0)
+	_ = os.Symlink(outside, filepath.Join(root, "linked"))
+
+	got, err := ModifiedSince(t.Context(), root, now.Add(-time.Hour))
+	if err != nil {
+		t.Fatalf("ModifiedSince: %v", err)
+	}
+	want := []string{fresh, nested, older}
+	if len(got) != len(want) {
+		t.Fatalf("got %d files (%+v), want %d", len(got), got, len(want))
+	}
+	for i, p := range want {
+		if got[i].Path != p {
+			t.Fatalf("got[%d]=%q want %q", i, got[i].Path, p)
+		}
+	}
+
+	ctx, cancel := context.WithCancel(t.Context())
+	cancel()
+	if _, err := ModifiedSince(ctx, root, now); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+
+	if _, err := ModifiedSince(t.Context(), fresh, now); err == nil {
+		t.Fatalf("expected error for non-directory root")
+	}
+}
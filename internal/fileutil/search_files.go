@@ -1,25 +1,110 @@
 package fileutil
 
 import (
+	"compress/gzip"
 	"context"
 	"errors"
+	"io"
 	"io/fs"
 	"os"
 	"path/filepath"
 	"regexp"
+	"strings"
 	"unicode/utf8"
 )
 
+// maxSearchContentBytes bounds how much content (decompressed, for .gz files)
+// is loaded per file when matching contents.
+const maxSearchContentBytes = 1 * 1024 * 1024
+
+// maxContentMatchesPerFile bounds the offsets reported for a single file.
+const maxContentMatchesPerFile = 1000
+
+// binarySampleBytes is how much of a file's content is sampled to detect binary data.
+const binarySampleBytes = 8 * 1024
+
 var errSearchLimitReached = errors.New("search limit reached")
 
+// SearchOptions tunes SearchFiles content matching. The zero value searches
+// plain (not compressed) text files only.
+type SearchOptions struct {
+	// Compressed decompresses ".gz" files (bounded) and searches their content;
+	// paths are always matched against the real file name.
+	Compressed bool
+	// IncludeBinary also searches content that looks binary (NUL bytes or many
+	// control characters in the first binarySampleBytes, or invalid UTF-8).
+	// Binary files can always match by path.
+	IncludeBinary bool
+	// FollowSymlinks descends into symlinked directories and searches the
+	// content of symlinked files (see WalkDirFollow); otherwise symlinks are
+	// matched by path only.
+	FollowSymlinks bool
+	// OnProgress, if set, is called on the walking goroutine after each file is
+	// scanned, with the number scanned so far. total is always 0, as the file
+	// count is not known until the walk ends. It should return quickly.
+	OnProgress func(done, total int)
+	// OnFileError, if set, is called for each file or directory below root that
+	// cannot be read. Returning nil skips it and continues the walk; returning an
+	// error aborts the search with that error. If nil, unreadable directories
+	// abort the search and unreadable files are skipped silently.
+	OnFileError func(path string, err error) error
+}
+
+// ContentMatch is one regexp match in a file's content. Start and End are byte
+// offsets (End exclusive) into the content, decompressed for .gz files.
+type ContentMatch struct {
+	Start int    `json:"start"`
+	End   int    `json:"end"`
+	Text  string `json:"text"`
+}
+
+// FileMatch is a file found by SearchFilesWithOffsets. Matches lists content
+// matches in order (at most maxContentMatchesPerFile); it is empty for files
+// that matched by path only.
+type FileMatch struct {
+	Path    string         `json:"path"`
+	Matches []ContentMatch `json:"matches"`
+}
+
 // SearchFiles walks root (default ".") recursively and returns up to maxResults files
 // whose *path* or UTF-8 text content* match the regexp pattern.
 // If maxResults <= 0, it is treated as "no limit".
+// opts selects compressed and binary content searching.
 func SearchFiles(
 	ctx context.Context,
 	root, pattern string,
 	maxResults int,
+	opts SearchOptions,
 ) (matchedFiles []string, reachedLimit bool, err error) {
+	found, reachedLimit, err := searchFiles(ctx, root, pattern, maxResults, opts, false)
+	if err != nil {
+		return nil, reachedLimit, err
+	}
+	var matches []string
+	for _, f := range found {
+		matches = append(matches, f.Path)
+	}
+	return matches, reachedLimit, nil
+}
+
+// SearchFilesWithOffsets is SearchFiles that also reports where the pattern
+// matches inside each file's content, for building exact edits.
+func SearchFilesWithOffsets(
+	ctx context.Context,
+	root, pattern string,
+	maxResults int,
+	opts SearchOptions,
+) (matchedFiles []FileMatch, reachedLimit bool, err error) {
+	return searchFiles(ctx, root, pattern, maxResults, opts, true)
+}
+
+func searchFiles(
+	ctx context.Context,
+	root, pattern string,
+	maxResults int,
+	opts SearchOptions,
+	withOffsets bool,
+) (matchedFiles []FileMatch, reachedLimit bool, err error) {
 	reachedLimit = false
 
 	if pattern == "" {
@@ -42,14 +127,27 @@ func SearchFiles(
 		limit = int(^uint(0) >> 1) // effectively “infinite”
 	}
 
-	var matches []string
+	var matches []FileMatch
+	scanned := 0
+
+	// checkLimit aborts the walk once the limit is reached.
+	checkLimit := func() error {
+		if len(matches) >= limit {
+			reachedLimit = true
+			return errSearchLimitReached
+		}
+		return nil
+	}
 
 	walkFn := func(path string, d fs.DirEntry, walkErr error) error {
 		if err := ctx.Err(); err != nil {
 			return err
 		}
 		if walkErr != nil {
-			return walkErr
+			if d == nil || path == root || opts.OnFileError == nil {
+				return walkErr
+			}
+			return opts.OnFileError(path, walkErr)
 		}
 
 		// If we've already hit the limit, abort the walk entirely.
@@ -64,34 +162,51 @@ func SearchFiles(
 			return nil
 		}
 
-		// Path match first.
-		if re.MatchString(path) {
-			matches = append(matches, path)
-		} else {
+		// Path match first; content is read if that fails, or for offsets.
+		// Only regular files are read: a symlink not followed by the walk may
+		// point anywhere, including outside root.
+		pathMatched := re.MatchString(path)
+		contentMatched := false
+		var cm []ContentMatch
+		if (!pathMatched || withOffsets) && d.Type().IsRegular() {
 			// Check file content only for reasonably small files.
-			if info, _ := d.Info(); info != nil && info.Size() < 1*1024*1024 { // 1 MB guard
-				if data, rerr := os.ReadFile(path); rerr == nil {
-					sample := data[:min(len(data), 4096)]
-					if !isProbablyTextSample(sample) || !utf8.Valid(data) {
-						return nil
+			if info, _ := d.Info(); info != nil && info.Size() < maxSearchContentBytes {
+				gz := opts.Compressed && strings.EqualFold(filepath.Ext(path), ".gz")
+				data, ok, rerr := readSearchContent(path, gz, opts.IncludeBinary)
+				if rerr != nil && opts.OnFileError != nil {
+					if err := opts.OnFileError(path, rerr); err != nil {
+						return err
 					}
-					if re.Match(data) {
-						matches = append(matches, path)
+				}
+				if ok {
+					if withOffsets {
+						cm = contentMatches(re, data)
+						contentMatched = len(cm) > 0
+					} else {
+						contentMatched = re.Match(data)
 					}
 				}
 			}
 		}
-
-		// If we just reached or exceeded the limit, abort the walk.
-		if len(matches) >= limit {
-			reachedLimit = true
-			return errSearchLimitReached
+		if pathMatched || contentMatched {
+			if withOffsets && cm == nil {
+				cm = []ContentMatch{}
+			}
+			matches = append(matches, FileMatch{Path: path, Matches: cm})
+		}
+		scanned++
+		if opts.OnProgress != nil {
+			opts.OnProgress(scanned, 0)
 		}
 
-		return nil
+		return checkLimit()
 	}
 
-	err = filepath.WalkDir(root, walkFn)
+	walk := filepath.WalkDir
+	if opts.FollowSymlinks {
+		walk = WalkDirFollow
+	}
+	err = walk(root, walkFn)
 	if err != nil && !errors.Is(err, errSearchLimitReached) {
 		return nil, reachedLimit, err
 	}
@@ -103,3 +218,51 @@ func SearchFiles(
 
 	return matches, reachedLimit, nil
 }
+
+// contentMatches returns the byte ranges of re's matches in data.
+func contentMatches(re *regexp.Regexp, data []byte) []ContentMatch {
+	locs := re.FindAllIndex(data, maxContentMatchesPerFile)
+	out := make([]ContentMatch, 0, len(locs))
+	for _, loc := range locs {
+		out = append(out, ContentMatch{Start: loc[0], End: loc[1], Text: string(data[loc[0]:loc[1]])})
+	}
+	return out
+}
+
+// readSearchContent loads a file's content for matching, decompressing gzip
+// files if gz is set. It reports false for unreadable, oversized or corrupt
+// content, and for binary content unless includeBinary is set. Only failures
+// to open or read the file are returned as errors.
+func readSearchContent(path string, gz, includeBinary bool) ([]byte, bool, error) {
+	var data []byte
+	if gz {
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, false, err
+		}
+		defer f.Close()
+		zr, err := gzip.NewReader(f)
+		if err != nil {
+			return nil, false, nil
+		}
+		defer zr.Close()
+		data, err = io.ReadAll(io.LimitReader(zr, maxSearchContentBytes+1))
+		if err != nil || len(data) > maxSearchContentBytes {
+			return nil, false, nil
+		}
+	} else {
+		var err error
+		if data, err = os.ReadFile(path); err != nil {
+			return nil, false, err
+		}
+	}
+
+	if includeBinary {
+		return data, true, nil
+	}
+	sample := data[:min(len(data), binarySampleBytes)]
+	if !isProbablyTextSample(sample) || !utf8.Valid(data) {
+		return nil, false, nil
+	}
+	return data, true, nil
+}
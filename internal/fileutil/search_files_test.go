@@ -1,6 +1,8 @@
 package fileutil
 
 import (
+	"bytes"
+	"compress/gzip"
 	"context"
 	"fmt"
 	"os"
@@ -136,7 +138,7 @@ func TestSearchFilesBasic(t *testing.T) {
 
 	for _, tc := range tests {
 		t.Run(tc.name, func(t *testing.T) {
-			got, reachedLimit, err := SearchFiles(t.Context(), tc.root, tc.pattern, tc.maxResults)
+			got, reachedLimit, err := SearchFiles(t.Context(), tc.root, tc.pattern, tc.maxResults, SearchOptions{})
 
 			if tc.wantErr {
 				if err == nil {
@@ -205,7 +207,7 @@ func TestSearchFilesRootDefaultUsesCWD(t *testing.T) {
 
 	for _, tc := range tests {
 		t.Run(tc.name, func(t *testing.T) {
-			got, _, err := SearchFiles(t.Context(), "", tc.pattern, tc.maxResults)
+			got, _, err := SearchFiles(t.Context(), "", tc.pattern, tc.maxResults, SearchOptions{})
 			if err != nil {
 				t.Fatalf("unexpected error: %v", err)
 			}
@@ -247,7 +249,7 @@ func TestSearchFilesConcurrency(t *testing.T) {
 				go func(id int) {
 					defer wg.Done()
 					for j := 0; j < tc.iterations; j++ {
-						got, _, err := SearchFiles(t.Context(), tc.searchRoot, tc.searchPat, 0)
+						got, _, err := SearchFiles(t.Context(), tc.searchRoot, tc.searchPat, 0, SearchOptions{})
 						if err != nil {
 							errCh <- fmt.Errorf("goroutine %d: unexpected error: %w", id, err)
 							return
@@ -358,7 +360,7 @@ func TestSearchFiles_EdgeCases(t *testing.T) {
 	for _, tc := range tests {
 		t.Run(tc.name, func(t *testing.T) {
 			ctx, root, pattern, want := tc.setup(t)
-			got, _, err := SearchFiles(ctx, root, pattern, 0)
+			got, _, err := SearchFiles(ctx, root, pattern, 0, SearchOptions{})
 			if tc.wantErr {
 				if err == nil {
 					t.Fatalf("expected error, got nil")
@@ -375,6 +377,118 @@ func TestSearchFiles_EdgeCases(t *testing.T) {
 	}
 }
 
+func TestSearchFilesCompressed(t *testing.T) {
+	root := t.TempDir()
+	writeGZ := func(name string, content []byte) string {
+		t.Helper()
+		var buf bytes.Buffer
+		zw := gzip.NewWriter(&buf)
+		if _, err := zw.Write(content); err != nil {
+			t.Fatalf("gzip write: %v", err)
+		}
+		if err := zw.Close(); err != nil {
+			t.Fatalf("gzip close: %v", err)
+		}
+		p := filepath.Join(root, name)
+		mustWriteBytes(t, p, buf.Bytes())
+		return p
+	}
+	logGZ := writeGZ("app.log.1.gz", []byte("INFO start\nERROR needle here\n"))
+	writeGZ("binary.gz", []byte{0x00, 'n', 'e', 'e', 'd', 'l', 'e'})
+	// Decompresses past the content bound: skipped.
+	writeGZ("huge.gz", append(bytes.Repeat([]byte("a"), maxSearchContentBytes), []byte("needle")...))
+	// Not actually gzip: plain text by default, unreadable when decompressing.
+	corrupt := filepath.Join(root, "corrupt.gz")
+	mustWriteBytes(t, corrupt, []byte("needle, but not gzip"))
+
+	tests := []struct {
+		name       string
+		compressed bool
+		want       []string
+	}{
+		{name: "flag unset ignores gzip content", want: []string{corrupt}},
+		{name: "flag set finds gzip text content", compressed: true, want: []string{logGZ}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, _, err := SearchFiles(t.Context(), root, "needle", 0, SearchOptions{Compressed: tc.compressed})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !equalStringSets(got, tc.want) {
+				t.Fatalf("got=%#v want=%#v (order-independent)", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestSearchFilesWithOffsets(t *testing.T) {
+	root := t.TempDir()
+	multi := filepath.Join(root, "multi.txt")
+	writeFile(t, multi, "foo bar foo\nbaz foo")
+	utf := filepath.Join(root, "utf.txt")
+	writeFile(t, utf, "héllo foo")
+	named := filepath.Join(root, "foo_name.txt")
+	writeFile(t, named, "nothing relevant")
+	writeFile(t, filepath.Join(root, "other.txt"), "no match here")
+
+	tests := []struct {
+		name    string
+		pattern string
+		want    map[string][][2]int
+	}{
+		{
+			name:    "every match reported with byte offsets",
+			pattern: "foo",
+			want: map[string][][2]int{
+				multi: {{0, 3}, {8, 11}, {16, 19}},
+				utf:   {{7, 10}},
+				named: {},
+			},
+		},
+		{
+			name:    "multi-line pattern spans lines",
+			pattern: `foo\nbaz`,
+			want:    map[string][][2]int{multi: {{8, 15}}},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, reached, err := SearchFilesWithOffsets(t.Context(), root, tc.pattern, 0, SearchOptions{})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if reached {
+				t.Fatalf("unexpected reachedLimit")
+			}
+			if len(got) != len(tc.want) {
+				t.Fatalf("got %d files (%+v), want %d", len(got), got, len(tc.want))
+			}
+			for _, f := range got {
+				wantLocs, ok := tc.want[f.Path]
+				if !ok {
+					t.Fatalf("unexpected file %q", f.Path)
+				}
+				if f.Matches == nil || len(f.Matches) != len(wantLocs) {
+					t.Fatalf("%s: matches=%+v want %v", f.Path, f.Matches, wantLocs)
+				}
+				data, err := os.ReadFile(f.Path)
+				if err != nil {
+					t.Fatalf("read: %v", err)
+				}
+				for i, m := range f.Matches {
+					if m.Start != wantLocs[i][0] || m.End != wantLocs[i][1] {
+						t.Fatalf("%s match %d: [%d,%d) want %v", f.Path, i, m.Start, m.End, wantLocs[i])
+					}
+					if string(data[m.Start:m.End]) != m.Text {
+						t.Fatalf("%s match %d: bytes %q != text %q", f.Path, i, data[m.Start:m.End], m.Text)
+					}
+				}
+			}
+		})
+	}
+}
+
 // Build a deterministic directory tree for SearchFiles tests.
 func createSearchTestTree(t *testing.T) searchTestTree {
 	t.Helper()
@@ -426,6 +540,42 @@ func createSearchTestTree(t *testing.T) searchTestTree {
 }
 
 // Helper to write text files in tests.
+func TestSearchFilesOnProgress(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.Mkdir(filepath.Join(dir, "sub"), 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	writeFile(t, filepath.Join(dir, "a.txt"), "needle")
+	writeFile(t, filepath.Join(dir, "b.txt"), "hay")
+	writeFile(t, filepath.Join(dir, "sub", "c.txt"), "needle")
+
+	tests := []struct {
+		name       string
+		maxResults int
+		wantDone   []int
+	}{
+		{name: "every file reported", wantDone: []int{1, 2, 3}},
+		{name: "stops with the walk at the limit", maxResults: 1, wantDone: []int{1}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var done []int
+			opts := SearchOptions{OnProgress: func(d, total int) {
+				if total != 0 {
+					t.Errorf("total=%d, want 0 (unknown)", total)
+				}
+				done = append(done, d)
+			}}
+			if _, _, err := SearchFiles(t.Context(), dir, "needle", tc.maxResults, opts); err != nil {
+				t.Fatalf("SearchFiles: %v", err)
+			}
+			if !slices.Equal(done, tc.wantDone) {
+				t.Fatalf("progress done=%v want %v", done, tc.wantDone)
+			}
+		})
+	}
+}
+
 func writeFile(t *testing.T, path, content string) {
 	t.Helper()
 	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
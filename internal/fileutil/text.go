@@ -2,9 +2,19 @@ package fileutil
 
 import (
 	"fmt"
+	"regexp"
 	"strings"
 )
 
+var (
+	// hyphenBreakRe matches a word broken across lines with a hyphen ("exam-\nple").
+	// The continuation must start lowercase so list markers and "A-\nB" codes survive.
+	hyphenBreakRe = regexp.MustCompile(`(\p{L})-[ \t]*\n[ \t]*(\p{Ll})`)
+	spaceRunRe    = regexp.MustCompile(`[ \t]{2,}`)
+	// formFeedRe matches a form feed (page break) with the line breaks around it.
+	formFeedRe = regexp.MustCompile(`[ \t]*\n?\f+\n?`)
+)
+
 // NormalizeLineBlockInput makes tool line-block arguments more forgiving.
 //
 // Behavior:
@@ -169,3 +179,31 @@ func IsBlockEqualsAt(haystack, needle []string, start int) bool {
 	}
 	return true
 }
+
+// NormalizeWhitespace tidies extracted text for LLM consumption.
+//
+// Behavior:
+//   - Converts CRLF/CR line endings to LF.
+//   - Converts form feeds (page breaks) to a blank line.
+//   - Joins words hyphenated across a line break ("exam-\nple" -> "example").
+//   - Collapses interior runs of spaces/tabs to a single space (leading indentation is kept).
+//   - Trims trailing spaces/tabs from every line.
+func NormalizeWhitespace(s string) string {
+	s = strings.ReplaceAll(s, "\r\n", "\n")
+	s = strings.ReplaceAll(s, "\r", "\n")
+	s = formFeedRe.ReplaceAllString(s, "\n\n")
+	s = hyphenBreakRe.ReplaceAllString(s, "$1$2")
+
+	lines := strings.Split(s, "\n")
+	for i, line := range lines {
+		body := strings.TrimLeft(line, " \t")
+		indent := line[:len(line)-len(body)]
+		body = strings.TrimRight(spaceRunRe.ReplaceAllString(body, " "), " \t")
+		if body == "" {
+			lines[i] = ""
+			continue
+		}
+		lines[i] = indent + body
+	}
+	return strings.Join(lines, "\n")
+}
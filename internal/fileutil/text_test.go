@@ -248,3 +248,32 @@ func equalIntSlices(a, b []int) bool {
 	}
 	return true
 }
+
+func TestNormalizeWhitespace(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{name: "empty", in: "", want: ""},
+		{name: "collapses interior spaces and tabs", in: "a   b\t\tc", want: "a b c"},
+		{name: "trims trailing spaces", in: "a  \nb\t", want: "a\nb"},
+		{name: "keeps leading indentation", in: "    x   = 1", want: "    x = 1"},
+		{name: "joins hyphenated line break", in: "an exam-\nple here", want: "an example here"},
+		{name: "joins hyphenation with surrounding spaces", in: "exam- \n  ple", want: "example"},
+		{name: "normalizes CRLF before joining", in: "exam-\r\nple", want: "example"},
+		{name: "keeps hyphen before uppercase", in: "ISO-\n8601 and A-\nB", want: "ISO-\n8601 and A-\nB"},
+		{name: "keeps list markers", in: "items:\n- one\n- two", want: "items:\n- one\n- two"},
+		{name: "whitespace-only line becomes empty", in: "a\n   \nb", want: "a\n\nb"},
+		{name: "form feed becomes blank line", in: "page one\fpage two", want: "page one\n\npage two"},
+		{name: "form feed absorbs adjacent newlines", in: "one  \n\f\ntwo", want: "one\n\ntwo"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := NormalizeWhitespace(tc.in); got != tc.want {
+				t.Fatalf("got=%q want=%q", got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,229 @@
+package fileutil
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+)
+
+// NoExtensionKey is the TreeStats.ByExtension key for files without an extension.
+const NoExtensionKey = "(none)"
+
+const (
+	maxTreeStatsWorkers = 8
+	treeStatsChunkSize  = 32 * 1024
+	// treeStatsSampleSize is how much of a file's head is sniffed for binary content.
+	treeStatsSampleSize = 4096
+)
+
+// TextCounts are streamed line/word/char/byte totals for one or more text files.
+type TextCounts struct {
+	Files int   `json:"files"`
+	Lines int64 `json:"lines"`
+	Words int64 `json:"words"`
+	Chars int64 `json:"chars"` // UTF-8 code points
+	Bytes int64 `json:"bytes"`
+}
+
+func (c *TextCounts) add(o TextCounts) {
+	c.Files += o.Files
+	c.Lines += o.Lines
+	c.Words += o.Words
+	c.Chars += o.Chars
+	c.Bytes += o.Bytes
+}
+
+type TreeStats struct {
+	Root          string                `json:"root"`
+	Total         TextCounts            `json:"total"`
+	ByExtension   map[string]TextCounts `json:"byExtension"` // lower-cased ".ext" or NoExtensionKey
+	SkippedBinary int                   `json:"skippedBinary"`
+}
+
+// ComputeTreeStats walks root (default ".") recursively and counts lines, words,
+// chars and bytes of every regular text file whose base name matches one of
+// patterns (filepath.Match syntax; empty means all files).
+// Files are read in fixed-size chunks by a bounded pool of workers, so memory use
+// does not depend on file size. Files whose head looks binary are skipped and
+// counted in SkippedBinary. Symlinks are skipped and never followed.
+func ComputeTreeStats(ctx context.Context, root string, patterns []string) (*TreeStats, error) {
+	if root == "" {
+		root = "."
+	}
+	base, err := NormalizePath(root)
+	if err != nil {
+		return nil, err
+	}
+	st, err := os.Stat(base)
+	if err != nil {
+		return nil, err
+	}
+	if !st.IsDir() {
+		return nil, fmt.Errorf("path is not a directory: %s", base)
+	}
+	for _, pat := range patterns {
+		if _, err := filepath.Match(pat, ""); err != nil {
+			return nil, fmt.Errorf("invalid pattern %q: %w", pat, err)
+		}
+	}
+
+	ctx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+
+	type result struct {
+		ext    string
+		counts TextCounts
+		binary bool
+	}
+	paths := make(chan string)
+	results := make(chan result)
+
+	var wg sync.WaitGroup
+	for range min(runtime.GOMAXPROCS(0), maxTreeStatsWorkers) {
+		wg.Go(func() {
+			for p := range paths {
+				counts, binary, err := countTextFile(ctx, p)
+				if err != nil {
+					if errors.Is(err, fs.ErrNotExist) {
+						// Removed while walking.
+						continue
+					}
+					cancel(err)
+					continue
+				}
+				select {
+				case results <- result{ext: extensionKey(p), counts: counts, binary: binary}:
+				case <-ctx.Done():
+				}
+			}
+		})
+	}
+
+	out := &TreeStats{Root: base, ByExtension: map[string]TextCounts{}}
+	collected := make(chan struct{})
+	go func() {
+		defer close(collected)
+		for r := range results {
+			if r.binary {
+				out.SkippedBinary++
+				continue
+			}
+			out.Total.add(r.counts)
+			c := out.ByExtension[r.ext]
+			c.add(r.counts)
+			out.ByExtension[r.ext] = c
+		}
+	}()
+
+	walkErr := filepath.WalkDir(base, func(path string, d fs.DirEntry, walkErr error) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if walkErr != nil {
+			return walkErr
+		}
+		if d.Type()&fs.ModeSymlink != 0 || !d.Type().IsRegular() || !matchesAny(patterns, d.Name()) {
+			return nil
+		}
+		select {
+		case paths <- path:
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	})
+	close(paths)
+	wg.Wait()
+	close(results)
+	<-collected
+
+	if cause := context.Cause(ctx); cause != nil {
+		return nil, cause
+	}
+	if walkErr != nil {
+		return nil, walkErr
+	}
+	return out, nil
+}
+
+// countTextFile streams path in chunks. binary is true (with zero counts) if the
+// first chunk does not look like text.
+func countTextFile(ctx context.Context, path string) (counts TextCounts, binary bool, err error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return TextCounts{}, false, err
+	}
+	defer f.Close()
+
+	buf := make([]byte, treeStatsChunkSize)
+	inWord := false
+	var last byte
+	for first := true; ; first = false {
+		if err := ctx.Err(); err != nil {
+			return TextCounts{}, false, err
+		}
+		n, rerr := io.ReadFull(f, buf)
+		chunk := buf[:n]
+		if first && !isProbablyTextSample(chunk[:min(n, treeStatsSampleSize)]) {
+			return TextCounts{}, true, nil
+		}
+		for _, b := range chunk {
+			if b == '\n' {
+				counts.Lines++
+			}
+			// UTF-8 continuation bytes are never whitespace, so byte-wise
+			// word splitting is safe for multi-byte text.
+			space := b == ' ' || b == '\t' || b == '\n' || b == '\r' || b == '\v' || b == '\f'
+			if !space && !inWord {
+				counts.Words++
+			}
+			inWord = !space
+			if b&0xC0 != 0x80 {
+				counts.Chars++
+			}
+		}
+		counts.Bytes += int64(n)
+		if n > 0 {
+			last = chunk[n-1]
+		}
+		if errors.Is(rerr, io.EOF) || errors.Is(rerr, io.ErrUnexpectedEOF) {
+			break
+		}
+		if rerr != nil {
+			return TextCounts{}, false, rerr
+		}
+	}
+	if counts.Bytes > 0 && last != '\n' {
+		// Unterminated final line.
+		counts.Lines++
+	}
+	counts.Files = 1
+	return counts, false, nil
+}
+
+func extensionKey(path string) string {
+	ext := strings.ToLower(filepath.Ext(path))
+	if ext == "" {
+		return NoExtensionKey
+	}
+	return ext
+}
+
+func matchesAny(patterns []string, name string) bool {
+	if len(patterns) == 0 {
+		return true
+	}
+	for _, pat := range patterns {
+		if ok, _ := filepath.Match(pat, name); ok {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,115 @@
+package fileutil
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestComputeTreeStats(t *testing.T) {
+	t.Parallel()
+	root := t.TempDir()
+	write := func(rel string, data []byte) {
+		t.Helper()
+		p := filepath.Join(root, rel)
+		if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+			t.Fatalf("mkdir: %v", err)
+		}
+		if err := os.WriteFile(p, data, 0o600); err != nil {
+			t.Fatalf("write %s: %v", rel, err)
+		}
+	}
+	write("main.go", []byte("package main\n\nfunc main() {}\n"))    // 3 lines, 5 words
+	write("pkg/util.go", []byte("package pkg\nvar x = 1"))          // 2 lines (unterminated), 6 words
+	write("README.md", []byte("# Título\n"))                        // 1 line, 2 words, 8 chars + \n
+	write("docs/GUIDE.MD", []byte(strings.Repeat("word\n", 10000))) // spans several chunks
+	write("Makefile", []byte("all:\n"))                             // no extension
+	write("blob.bin", []byte{0x00, 0x01, 0x02, 'a', '\n'})          // binary, skipped
+	write("empty.txt", nil)                                         // 0 lines
+
+	// Symlinked directories are not followed.
+	outside := t.TempDir()
+	if err := os.WriteFile(filepath.Join(outside, "x.go"), []byte("a\nb\n"), 0o600); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	_ = os.Symlink(outside, filepath.Join(root, "linked"))
+
+	canceled, cancel := context.WithCancel(t.Context())
+	cancel()
+
+	tests := []struct {
+		name        string
+		ctx         context.Context
+		root        string
+		patterns    []string
+		wantTotal   TextCounts
+		wantByExt   map[string]TextCounts
+		wantSkipped int
+		wantErrIs   error
+		wantErr     bool
+	}{
+		{
+			name:      "all files",
+			root:      root,
+			wantTotal: TextCounts{Files: 6, Lines: 3 + 2 + 1 + 10000 + 1, Words: 5 + 6 + 2 + 10000 + 1, Chars: 29 + 21 + 9 + 50000 + 5, Bytes: 29 + 21 + 10 + 50000 + 5},
+			wantByExt: map[string]TextCounts{
+				".go":          {Files: 2, Lines: 5, Words: 11, Chars: 50, Bytes: 50},
+				".md":          {Files: 2, Lines: 10001, Words: 10002, Chars: 50009, Bytes: 50010},
+				NoExtensionKey: {Files: 1, Lines: 1, Words: 1, Chars: 5, Bytes: 5},
+				".txt":         {Files: 1},
+			},
+			wantSkipped: 1,
+		},
+		{
+			name:      "patterns filter by base name",
+			root:      root,
+			patterns:  []string{"*.go"},
+			wantTotal: TextCounts{Files: 2, Lines: 5, Words: 11, Chars: 50, Bytes: 50},
+			wantByExt: map[string]TextCounts{".go": {Files: 2, Lines: 5, Words: 11, Chars: 50, Bytes: 50}},
+		},
+		{name: "invalid pattern errors", root: root, patterns: []string{"["}, wantErr: true},
+		{name: "file root errors", root: filepath.Join(root, "main.go"), wantErr: true},
+		{name: "missing root errors", root: filepath.Join(root, "missing"), wantErr: true},
+		{name: "canceled context", ctx: canceled, root: root, wantErr: true, wantErrIs: context.Canceled},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctx := tt.ctx
+			if ctx == nil {
+				ctx = t.Context()
+			}
+			got, err := ComputeTreeStats(ctx, tt.root, tt.patterns)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected error, got %+v", got)
+				}
+				if tt.wantErrIs != nil && !errors.Is(err, tt.wantErrIs) {
+					t.Fatalf("expected errors.Is(%v), got %v", tt.wantErrIs, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got.Total != tt.wantTotal {
+				t.Fatalf("Total=%+v want %+v", got.Total, tt.wantTotal)
+			}
+			if len(got.ByExtension) != len(tt.wantByExt) {
+				t.Fatalf("ByExtension=%+v want %+v", got.ByExtension, tt.wantByExt)
+			}
+			for ext, want := range tt.wantByExt {
+				if got.ByExtension[ext] != want {
+					t.Fatalf("ByExtension[%q]=%+v want %+v", ext, got.ByExtension[ext], want)
+				}
+			}
+			if got.SkippedBinary != tt.wantSkipped {
+				t.Fatalf("SkippedBinary=%d want %d", got.SkippedBinary, tt.wantSkipped)
+			}
+		})
+	}
+}
@@ -0,0 +1,72 @@
+package fileutil
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// WalkDirFollow is filepath.WalkDir that also descends into symlinked
+// directories. Every directory is read once, keyed by its resolved path, so
+// symlink cycles (and links back to an ancestor) terminate. Paths passed to fn
+// are as reached through the links. A symlink whose target exists is passed
+// with a DirEntry describing the target; broken links are passed as links.
+// fn returning fs.SkipDir or fs.SkipAll behaves as with filepath.WalkDir.
+func WalkDirFollow(root string, fn fs.WalkDirFunc) error {
+	info, err := os.Stat(root)
+	if err != nil {
+		err = fn(root, nil, err)
+	} else {
+		err = walkFollow(root, fs.FileInfoToDirEntry(info), fn, map[string]bool{})
+	}
+	if errors.Is(err, fs.SkipDir) || errors.Is(err, fs.SkipAll) {
+		return nil
+	}
+	return err
+}
+
+func walkFollow(path string, d fs.DirEntry, fn fs.WalkDirFunc, visited map[string]bool) error {
+	if err := fn(path, d, nil); err != nil || !d.IsDir() {
+		if errors.Is(err, fs.SkipDir) && d.IsDir() {
+			return nil
+		}
+		return err
+	}
+
+	realPath, err := filepath.EvalSymlinks(path)
+	if err != nil {
+		return fn(path, d, err)
+	}
+	if visited[realPath] {
+		return nil
+	}
+	visited[realPath] = true
+
+	entries, err := os.ReadDir(path) // Sorted by name.
+	if err != nil {
+		// Second call for the directory, as filepath.WalkDir does.
+		if err = fn(path, d, err); err != nil {
+			if errors.Is(err, fs.SkipDir) {
+				return nil
+			}
+			return err
+		}
+	}
+
+	for _, e := range entries {
+		p := filepath.Join(path, e.Name())
+		if e.Type()&fs.ModeSymlink != 0 {
+			if info, serr := os.Stat(p); serr == nil {
+				e = fs.FileInfoToDirEntry(info)
+			}
+		}
+		if err := walkFollow(p, e, fn, visited); err != nil {
+			if errors.Is(err, fs.SkipDir) {
+				break
+			}
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,89 @@
+package fileutil
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"testing"
+
+	"github.com/flexigpt/llmtools-go/internal/toolutil"
+)
+
+func TestWalkDirFollow(t *testing.T) {
+	if runtime.GOOS == toolutil.GOOSWindows {
+		t.Skip("symlinks require elevated privileges on Windows")
+	}
+	root := t.TempDir()
+	outside := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(root, "a", "b"), 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	mustWriteBytes(t, filepath.Join(root, "a", "f.txt"), []byte("f"))
+	mustWriteBytes(t, filepath.Join(outside, "g.txt"), []byte("g"))
+	// Cycles: back to an ancestor and to the root itself.
+	mustSymlinkOrSkip(t, filepath.Join(root, "a"), filepath.Join(root, "a", "b", "loop"))
+	mustSymlinkOrSkip(t, root, filepath.Join(root, "self"))
+	// A directory only reachable through a link.
+	mustSymlinkOrSkip(t, outside, filepath.Join(root, "ext"))
+
+	tests := []struct {
+		name string
+		walk func(string, fs.WalkDirFunc) error
+		skip string
+		want []string
+	}{
+		{
+			name: "without following links are not descended",
+			walk: filepath.WalkDir,
+			want: []string{".", "a", "a/b", "a/b/loop", "a/f.txt", "ext", "self"},
+		},
+		{
+			name: "following descends links and terminates on cycles",
+			walk: WalkDirFollow,
+			want: []string{".", "a", "a/b", "a/b/loop", "a/f.txt", "ext", "ext/g.txt", "self"},
+		},
+		{
+			name: "SkipDir on a followed link",
+			walk: WalkDirFollow,
+			skip: "ext",
+			want: []string{".", "a", "a/b", "a/b/loop", "a/f.txt", "ext", "self"},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var got []string
+			err := tc.walk(root, func(p string, d fs.DirEntry, err error) error {
+				if err != nil {
+					return err
+				}
+				rel, rerr := filepath.Rel(root, p)
+				if rerr != nil {
+					return rerr
+				}
+				rel = filepath.ToSlash(rel)
+				got = append(got, rel)
+				if rel == tc.skip {
+					return fs.SkipDir
+				}
+				return nil
+			})
+			if err != nil {
+				t.Fatalf("walk: %v", err)
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Fatalf("got=%v want=%v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestWalkDirFollow_MissingRoot(t *testing.T) {
+	missing := filepath.Join(t.TempDir(), "nope")
+	err := WalkDirFollow(missing, func(_ string, _ fs.DirEntry, err error) error { return err })
+	if !errors.Is(err, fs.ErrNotExist) {
+		t.Fatalf("error=%v; want not-exist", err)
+	}
+}
@@ -0,0 +1,177 @@
+package jsonutil
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// ArrayUpdateResult reports what UpdateArray changed.
+type ArrayUpdateResult struct {
+	Removed int // elements removed because they matched
+	Length  int // array length after the update
+}
+
+// UpdateArray edits the array at pointer inside data: it first removes every
+// element semantically equal to removeMatching (if set), then appends
+// appendValue (if set) as a single element. Everything outside the array, and
+// the original text of kept elements, is left byte-for-byte intact; new
+// elements are indented to match their siblings.
+func UpdateArray(data []byte, pointer string, appendValue, removeMatching json.RawMessage) ([]byte, ArrayUpdateResult, error) {
+	var res ArrayUpdateResult
+	start, end, err := LocateValue(data, pointer)
+	if err != nil {
+		return nil, res, err
+	}
+	span := data[start:end]
+	if len(span) == 0 || span[0] != '[' {
+		return nil, res, fmt.Errorf("pointer %q does not resolve to an array", pointer)
+	}
+
+	var elems []json.RawMessage
+	if err := json.Unmarshal(span, &elems); err != nil {
+		return nil, res, fmt.Errorf("decode JSON: %w", err)
+	}
+
+	if len(bytes.TrimSpace(removeMatching)) > 0 {
+		var want any
+		if err := json.Unmarshal(removeMatching, &want); err != nil {
+			return nil, res, fmt.Errorf("invalid removeMatching: %w", err)
+		}
+		kept := elems[:0]
+		for _, e := range elems {
+			var got any
+			if err := json.Unmarshal(e, &got); err != nil {
+				return nil, res, fmt.Errorf("decode JSON: %w", err)
+			}
+			if reflect.DeepEqual(got, want) {
+				res.Removed++
+				continue
+			}
+			kept = append(kept, e)
+		}
+		elems = kept
+	}
+
+	layout := detectArrayLayout(data, start, span)
+	if len(bytes.TrimSpace(appendValue)) > 0 {
+		if !json.Valid(appendValue) {
+			return nil, res, errors.New("invalid append: not valid JSON")
+		}
+		var buf bytes.Buffer
+		if layout.multiline {
+			err = json.Indent(&buf, bytes.TrimSpace(appendValue), layout.elemIndent, layout.unit)
+		} else {
+			err = json.Compact(&buf, appendValue)
+		}
+		if err != nil {
+			return nil, res, fmt.Errorf("invalid append: %w", err)
+		}
+		elems = append(elems, buf.Bytes())
+	}
+	res.Length = len(elems)
+
+	var out bytes.Buffer
+	out.Grow(len(data) + 64)
+	out.Write(data[:start])
+	out.WriteByte('[')
+	if len(elems) > 0 {
+		for i, e := range elems {
+			if i > 0 {
+				out.WriteString(layout.sep)
+			}
+			if layout.multiline {
+				out.WriteString("\n" + layout.elemIndent)
+			}
+			out.Write(e)
+		}
+		if layout.multiline {
+			out.WriteString("\n" + layout.closeIndent)
+		}
+	}
+	out.WriteByte(']')
+	out.Write(data[end:])
+	return out.Bytes(), res, nil
+}
+
+type arrayLayout struct {
+	multiline   bool
+	sep         string // between elements: "," for multi-line, "," or ", " inline
+	elemIndent  string // leading whitespace of each element line
+	closeIndent string // leading whitespace of the closing bracket line
+	unit        string // one indentation level, for json.Indent of new elements
+}
+
+// detectArrayLayout infers how the array at data[start:] (text span) is formatted.
+// Empty arrays take their style from the rest of the document.
+func detectArrayLayout(data []byte, start int, span []byte) arrayLayout {
+	lineIndent := leadingIndent(data, start)
+	unit := documentIndentUnit(data)
+	inner := bytes.TrimSpace(span[1 : len(span)-1])
+
+	if len(inner) == 0 {
+		if unit == "" {
+			return arrayLayout{sep: ","}
+		}
+		return arrayLayout{
+			multiline:   true,
+			sep:         ",",
+			elemIndent:  lineIndent + unit,
+			closeIndent: lineIndent,
+			unit:        unit,
+		}
+	}
+
+	if !bytes.ContainsAny(span, "\n") {
+		sep := ","
+		if bytes.Contains(span, []byte(", ")) {
+			sep = ", "
+		}
+		return arrayLayout{sep: sep}
+	}
+
+	// First element's indentation: whitespace after the first newline following '['.
+	rest := span[1:]
+	nl := bytes.IndexByte(rest, '\n')
+	firstLine := rest[nl+1:]
+	elemIndent := string(firstLine[:len(firstLine)-len(bytes.TrimLeft(firstLine, " \t"))])
+	closeIndent := leadingIndent(span, len(span)-1)
+	if strings.HasPrefix(elemIndent, closeIndent) && len(elemIndent) > len(closeIndent) {
+		unit = elemIndent[len(closeIndent):]
+	}
+	if unit == "" {
+		unit = "  "
+	}
+	return arrayLayout{
+		multiline:   true,
+		sep:         ",",
+		elemIndent:  elemIndent,
+		closeIndent: closeIndent,
+		unit:        unit,
+	}
+}
+
+// leadingIndent returns the whitespace at the start of the line containing data[i].
+func leadingIndent(data []byte, i int) string {
+	lineStart := bytes.LastIndexByte(data[:i], '\n') + 1
+	line := data[lineStart:]
+	return string(line[:len(line)-len(bytes.TrimLeft(line, " \t"))])
+}
+
+// documentIndentUnit returns the indentation of the first indented line, or ""
+// if the document is compact (single line).
+func documentIndentUnit(data []byte) string {
+	for _, line := range bytes.Split(data, []byte("\n"))[1:] {
+		trimmed := bytes.TrimLeft(line, " \t")
+		if len(trimmed) > 0 && len(trimmed) < len(line) {
+			return string(line[:len(line)-len(trimmed)])
+		}
+	}
+	if bytes.ContainsAny(bytes.TrimSpace(data), "\n") {
+		return "  "
+	}
+	return ""
+}
@@ -0,0 +1,110 @@
+package jsonutil
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestUpdateArray(t *testing.T) {
+	t.Parallel()
+
+	indented := "{\n  \"name\": \"cfg\",\n  \"items\": [\n    \"a\",\n    {\"id\": 1}\n  ]\n}\n"
+	tests := []struct {
+		name        string
+		doc         string
+		pointer     string
+		appendValue string
+		remove      string
+		want        string
+		wantResult  ArrayUpdateResult
+		errSubstr   string
+	}{
+		{
+			name:        "append keeps indentation",
+			doc:         indented,
+			pointer:     "/items",
+			appendValue: `{"id":2,"tags":["x"]}`,
+			want: "{\n  \"name\": \"cfg\",\n  \"items\": [\n    \"a\",\n    {\"id\": 1},\n" +
+				"    {\n      \"id\": 2,\n      \"tags\": [\n        \"x\"\n      ]\n    }\n  ]\n}\n",
+			wantResult: ArrayUpdateResult{Length: 3},
+		},
+		{
+			name:       "remove matching is semantic",
+			doc:        indented,
+			pointer:    "/items",
+			remove:     `{ "id" : 1 }`,
+			want:       "{\n  \"name\": \"cfg\",\n  \"items\": [\n    \"a\"\n  ]\n}\n",
+			wantResult: ArrayUpdateResult{Removed: 1, Length: 1},
+		},
+		{
+			name:        "remove then append",
+			doc:         `{"xs": [1, 2, 1]}`,
+			pointer:     "/xs",
+			remove:      `1`,
+			appendValue: `3`,
+			want:        `{"xs": [2, 3]}`,
+			wantResult:  ArrayUpdateResult{Removed: 2, Length: 2},
+		},
+		{
+			name:        "compact inline array",
+			doc:         `{"xs":[1,2]}`,
+			pointer:     "/xs",
+			appendValue: `{ "a" : 1 }`,
+			want:        `{"xs":[1,2,{"a":1}]}`,
+			wantResult:  ArrayUpdateResult{Length: 3},
+		},
+		{
+			name:        "empty array in indented document",
+			doc:         "{\n\t\"xs\": []\n}",
+			pointer:     "/xs",
+			appendValue: `"v"`,
+			want:        "{\n\t\"xs\": [\n\t\t\"v\"\n\t]\n}",
+			wantResult:  ArrayUpdateResult{Length: 1},
+		},
+		{
+			name:        "root array",
+			doc:         `[]`,
+			pointer:     "",
+			appendValue: `true`,
+			want:        `[true]`,
+			wantResult:  ArrayUpdateResult{Length: 1},
+		},
+		{
+			name:       "no match removes nothing",
+			doc:        `{"xs": [1]}`,
+			pointer:    "/xs",
+			remove:     `2`,
+			want:       `{"xs": [1]}`,
+			wantResult: ArrayUpdateResult{Length: 1},
+		},
+		{name: "not an array", doc: `{"xs": {}}`, pointer: "/xs", appendValue: `1`, errSubstr: "does not resolve to an array"},
+		{name: "missing pointer", doc: `{}`, pointer: "/xs", appendValue: `1`, errSubstr: "not found"},
+		{name: "invalid append", doc: `{"xs": []}`, pointer: "/xs", appendValue: `{`, errSubstr: "invalid append"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, res, err := UpdateArray([]byte(tt.doc), tt.pointer, json.RawMessage(tt.appendValue), json.RawMessage(tt.remove))
+			if tt.errSubstr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.errSubstr) {
+					t.Fatalf("expected error containing %q, got %v", tt.errSubstr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if string(got) != tt.want {
+				t.Fatalf("output mismatch:\ngot:  %q\nwant: %q", got, tt.want)
+			}
+			if res != tt.wantResult {
+				t.Fatalf("result=%+v want %+v", res, tt.wantResult)
+			}
+			if !json.Valid(got) {
+				t.Fatalf("output is not valid JSON: %s", got)
+			}
+		})
+	}
+}
@@ -0,0 +1,176 @@
+package jsonutil
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// EncodeCanonicalJSON encodes value in the canonical form of RFC 8785 (JCS), so
+// semantically equal values produce byte-identical output suitable for hashing:
+// object keys are sorted (by UTF-16 code units) at every level, there is no
+// insignificant whitespace, numbers use the shortest ECMAScript form and
+// strings escape only what JSON requires.
+// The value is first marshaled with encoding/json, so struct tags and
+// json.Marshaler implementations apply; numbers are then treated as IEEE 754
+// doubles, as the RFC requires.
+func EncodeCanonicalJSON(value any) (json.RawMessage, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, fmt.Errorf("encode JSON: %w", err)
+	}
+	dec := newDecoder(bytes.NewReader(data), false, 0)
+	dec.UseNumber()
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		return nil, fmt.Errorf("decode JSON: %w", err)
+	}
+
+	var buf bytes.Buffer
+	buf.Grow(len(data))
+	if err := writeCanonical(&buf, v); err != nil {
+		return nil, fmt.Errorf("encode canonical JSON: %w", err)
+	}
+	return json.RawMessage(buf.Bytes()), nil
+}
+
+// HashValue returns the hex SHA-256 digest of the canonical JSON encoding of value
+// (see EncodeCanonicalJSON), so structurally equal values, e.g. maps built in a
+// different order, hash identically. It suits cache keys for tool arguments.
+func HashValue(value any) (string, error) {
+	data, err := EncodeCanonicalJSON(value)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+func writeCanonical(buf *bytes.Buffer, v any) error {
+	switch x := v.(type) {
+	case nil:
+		buf.WriteString("null")
+	case bool:
+		buf.WriteString(strconv.FormatBool(x))
+	case json.Number:
+		s, err := canonicalNumber(x)
+		if err != nil {
+			return err
+		}
+		buf.WriteString(s)
+	case string:
+		writeCanonicalString(buf, x)
+	case []any:
+		buf.WriteByte('[')
+		for i, e := range x {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			if err := writeCanonical(buf, e); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte(']')
+	case map[string]any:
+		keys := make([]string, 0, len(x))
+		for k := range x {
+			keys = append(keys, k)
+		}
+		slices.SortFunc(keys, compareUTF16)
+		buf.WriteByte('{')
+		for i, k := range keys {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			writeCanonicalString(buf, k)
+			buf.WriteByte(':')
+			if err := writeCanonical(buf, x[k]); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte('}')
+	default:
+		return fmt.Errorf("unexpected JSON value of type %T", v)
+	}
+	return nil
+}
+
+// canonicalNumber formats n as ECMAScript's Number.prototype.toString would,
+// which is also how encoding/json formats float64 values.
+func canonicalNumber(n json.Number) (string, error) {
+	f, err := strconv.ParseFloat(string(n), 64)
+	if err != nil {
+		return "", fmt.Errorf("number %s: %w", n, err)
+	}
+	if math.IsInf(f, 0) || math.IsNaN(f) {
+		return "", errors.New("number " + string(n) + " is out of range")
+	}
+	if f == 0 {
+		return "0", nil // also normalizes -0
+	}
+	data, err := json.Marshal(f)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// writeCanonicalString writes s as a JSON string using the RFC 8785 escapes:
+// the two-character forms for \b \t \n \f \r " and \, \u00xx for other control
+// characters, and everything else verbatim. Invalid UTF-8 becomes U+FFFD.
+func writeCanonicalString(buf *bytes.Buffer, s string) {
+	const hex = "0123456789abcdef"
+	buf.WriteByte('"')
+	for i := 0; i < len(s); {
+		c := s[i]
+		if c >= utf8.RuneSelf {
+			r, size := utf8.DecodeRuneInString(s[i:])
+			if r == utf8.RuneError && size == 1 {
+				buf.WriteRune(utf8.RuneError)
+			} else {
+				buf.WriteString(s[i : i+size])
+			}
+			i += size
+			continue
+		}
+		switch c {
+		case '"', '\\':
+			buf.WriteByte('\\')
+			buf.WriteByte(c)
+		case '\b':
+			buf.WriteString(`\b`)
+		case '\f':
+			buf.WriteString(`\f`)
+		case '\n':
+			buf.WriteString(`\n`)
+		case '\r':
+			buf.WriteString(`\r`)
+		case '\t':
+			buf.WriteString(`\t`)
+		default:
+			if c < 0x20 {
+				buf.WriteString(`\u00`)
+				buf.WriteByte(hex[c>>4])
+				buf.WriteByte(hex[c&0xf])
+			} else {
+				buf.WriteByte(c)
+			}
+		}
+		i++
+	}
+	buf.WriteByte('"')
+}
+
+// compareUTF16 orders strings by their UTF-16 code units, as RFC 8785 requires
+// for object keys.
+func compareUTF16(a, b string) int {
+	return slices.Compare(utf16.Encode([]rune(a)), utf16.Encode([]rune(b)))
+}
@@ -0,0 +1,179 @@
+package jsonutil
+
+import (
+	"encoding/json"
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestEncodeCanonicalJSON(t *testing.T) {
+	t.Parallel()
+
+	type inner struct {
+		Z int    `json:"z"`
+		A string `json:"a"`
+	}
+	type outer struct {
+		List  []any  `json:"list"`
+		Inner inner  `json:"inner"`
+		Skip  string `json:"skip,omitempty"`
+		B     bool   `json:"b"`
+	}
+
+	tests := []struct {
+		name       string
+		in         any
+		want       string
+		wantErrSub string
+	}{
+		{name: "null", in: nil, want: `null`},
+		{name: "scalars", in: []any{true, false, "s", 1}, want: `[true,false,"s",1]`},
+		{
+			name: "nested maps are sorted",
+			in:   map[string]any{"b": map[string]any{"y": 1, "x": []any{map[string]any{"d": 1, "c": 2}}}, "a": nil},
+			want: `{"a":null,"b":{"x":[{"c":2,"d":1}],"y":1}}`,
+		},
+		{
+			name: "struct fields are sorted by JSON name",
+			in:   outer{List: []any{}, Inner: inner{Z: 1, A: "x"}, B: true},
+			want: `{"b":true,"inner":{"a":"x","z":1},"list":[]}`,
+		},
+		{
+			name: "raw message is re-encoded",
+			in:   json.RawMessage("{ \"b\" : [ 1.50 , 2e-3 ],\n \"a\" : {} }"),
+			want: `{"a":{},"b":[1.5,0.002]}`,
+		},
+		{
+			name: "numbers use ECMAScript form",
+			in: json.RawMessage(
+				`[1E30, 4.50, 0.000001, 1e-7, -0, 1e21, 333333333.33333329, 9007199254740993, -1.5e-10, 100]`,
+			),
+			want: `[1e+30,4.5,0.000001,1e-7,0,1e+21,333333333.3333333,9007199254740992,-1.5e-10,100]`,
+		},
+		{
+			name: "strings escape only what is required",
+			in:   "<a&b> \u2028 \u00e9 \U0001F600 \"q\" \\ \b\f\n\r\t \x1f \x7f",
+			want: "\"<a&b> \u2028 \u00e9 \U0001F600 \\\"q\\\" \\\\ \\b\\f\\n\\r\\t \\u001f \x7f\"",
+		},
+		{
+			name: "keys sort by UTF-16 code units",
+			in: map[string]any{
+				"\u20ac":     "Euro Sign",
+				"\r":         "Carriage Return",
+				"\ufb33":     "Hebrew Letter Dalet With Dagesh",
+				"1":          "One",
+				"\U0001F600": "Emoji: Grinning Face",
+				"\u0080":     "Control",
+				"\u00f6":     "Latin Small Letter O With Diaeresis",
+			},
+			want: "{\"\\r\":\"Carriage Return\",\"1\":\"One\",\"\u0080\":\"Control\"," +
+				"\"\u00f6\":\"Latin Small Letter O With Diaeresis\",\"\u20ac\":\"Euro Sign\"," +
+				"\"\U0001F600\":\"Emoji: Grinning Face\",\"\ufb33\":\"Hebrew Letter Dalet With Dagesh\"}",
+		},
+		{name: "NaN", in: math.NaN(), wantErrSub: "encode JSON"},
+		{name: "unsupported type", in: make(chan int), wantErrSub: "encode JSON"},
+		{name: "number out of float range", in: json.Number("1e400"), wantErrSub: "out of range"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := EncodeCanonicalJSON(tt.in)
+			if tt.wantErrSub != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErrSub) {
+					t.Fatalf("expected error containing %q, got %v (out=%s)", tt.wantErrSub, err, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if string(got) != tt.want {
+				t.Fatalf("unexpected output.\nwant: %s\ngot:  %s", tt.want, got)
+			}
+			if !json.Valid(got) {
+				t.Fatalf("output is not valid JSON: %s", got)
+			}
+		})
+	}
+}
+
+func TestEncodeCanonicalJSON_EqualValuesAreByteIdentical(t *testing.T) {
+	t.Parallel()
+
+	a := map[string]any{"path": "/tmp/x", "opts": map[string]any{"depth": 2, "follow": true}, "n": 1.0}
+	b := json.RawMessage(`{ "n": 1.000, "opts": { "follow": true, "depth": 2e0 }, "path": "/tmp/x" }`)
+	c := struct {
+		Opts struct {
+			Follow bool `json:"follow"`
+			Depth  int  `json:"depth"`
+		} `json:"opts"`
+		Path string  `json:"path"`
+		N    float64 `json:"n"`
+	}{Path: "/tmp/x", N: 1}
+	c.Opts.Follow, c.Opts.Depth = true, 2
+
+	want := `{"n":1,"opts":{"depth":2,"follow":true},"path":"/tmp/x"}`
+	for i, v := range []any{a, b, c} {
+		got, err := EncodeCanonicalJSON(v)
+		if err != nil {
+			t.Fatalf("value %d: unexpected error: %v", i, err)
+		}
+		if string(got) != want {
+			t.Fatalf("value %d: got %s want %s", i, got, want)
+		}
+	}
+}
+
+func TestHashValue(t *testing.T) {
+	t.Parallel()
+
+	type args struct {
+		Path    string         `json:"path"`
+		Options map[string]any `json:"options"`
+	}
+	a := map[string]any{"b": []any{1, 2.0}, "a": map[string]any{"y": true, "x": "s"}}
+	b := map[string]any{}
+	b["a"] = map[string]any{"x": "s", "y": true}
+	b["b"] = []any{1.0, 2}
+
+	tests := []struct {
+		name      string
+		x, y      any
+		wantEqual bool
+	}{
+		{name: "map order and number form", x: a, y: b, wantEqual: true},
+		{
+			name:      "struct and equivalent raw JSON",
+			x:         args{Path: "p", Options: map[string]any{"n": 1, "m": 2}},
+			y:         json.RawMessage(` { "options": {"m": 2e0, "n": 1}, "path": "p" } `),
+			wantEqual: true,
+		},
+		{name: "different values", x: map[string]int{"a": 1}, y: map[string]int{"a": 2}},
+		{name: "array order matters", x: []int{1, 2}, y: []int{2, 1}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			hx, err := HashValue(tc.x)
+			if err != nil {
+				t.Fatalf("HashValue(x): %v", err)
+			}
+			hy, err := HashValue(tc.y)
+			if err != nil {
+				t.Fatalf("HashValue(y): %v", err)
+			}
+			if len(hx) != 64 {
+				t.Fatalf("digest %q is not hex SHA-256", hx)
+			}
+			if (hx == hy) != tc.wantEqual {
+				t.Fatalf("hashes %s vs %s, wantEqual=%v", hx, hy, tc.wantEqual)
+			}
+		})
+	}
+
+	if _, err := HashValue(math.NaN()); err == nil {
+		t.Fatal("expected error for NaN")
+	}
+}
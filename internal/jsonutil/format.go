@@ -0,0 +1,89 @@
+package jsonutil
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// FormatJSON reformats a single JSON document. An empty indent produces compact
+// output; otherwise each nesting level is indented by indent.
+// Key order, number literals and string escapes are preserved as written unless
+// sortKeys is set, in which case object keys are sorted at every level.
+// Input beyond the default depth/size limits fails with ErrJSONTooDeep/ErrJSONTooLarge.
+func FormatJSON(data []byte, indent string, sortKeys bool) ([]byte, error) {
+	if err := checkJSONLimits(data, DefaultMaxJSONDepth, DefaultMaxJSONBytes); err != nil {
+		return nil, fmt.Errorf("decode JSON: %w", err)
+	}
+	if !json.Valid(data) {
+		// Re-decode for a positioned error message.
+		var v any
+		if err := decodeBytes(data, &v, false, true, DefaultMaxJSONDepth, DefaultMaxJSONBytes); err != nil {
+			return nil, err
+		}
+		return nil, errors.New("decode JSON: invalid document")
+	}
+
+	src := data
+	if sortKeys {
+		sorted, err := sortedJSON(data)
+		if err != nil {
+			return nil, err
+		}
+		src = sorted
+	}
+
+	var buf bytes.Buffer
+	var err error
+	if indent == "" {
+		err = json.Compact(&buf, src)
+	} else {
+		err = json.Indent(&buf, bytes.TrimSpace(src), "", indent)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("format JSON: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// PrettyJSON indents a serialized JSON value for display, one member or element
+// per line, indented by indent per level ("" uses two spaces).
+func PrettyJSON(raw json.RawMessage, indent string) (json.RawMessage, error) {
+	if indent == "" {
+		indent = "  "
+	}
+	out, err := FormatJSON(raw, indent, false)
+	if err != nil {
+		return nil, err
+	}
+	return json.RawMessage(out), nil
+}
+
+// MinifyJSON removes insignificant whitespace from a serialized JSON value.
+func MinifyJSON(raw json.RawMessage) (json.RawMessage, error) {
+	out, err := FormatJSON(raw, "", false)
+	if err != nil {
+		return nil, err
+	}
+	return json.RawMessage(out), nil
+}
+
+// sortedJSON re-encodes data with object keys sorted. Numbers keep their
+// literal form and HTML characters are not escaped.
+func sortedJSON(data []byte) ([]byte, error) {
+	dec := newDecoder(bytes.NewReader(data), false, DefaultMaxJSONBytes)
+	dec.UseNumber()
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		return nil, fmt.Errorf("decode JSON: %w", err)
+	}
+
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(v); err != nil {
+		return nil, fmt.Errorf("encode JSON: %w", err)
+	}
+	return buf.Bytes(), nil
+}
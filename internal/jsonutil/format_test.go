@@ -0,0 +1,144 @@
+package jsonutil
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestFormatJSON(t *testing.T) {
+	t.Parallel()
+
+	const src = "{\"b\": 1.50, \"a\": [1, {\"z\": \"<x>\", \"y\": null}], \"c\": {}}\n"
+
+	tests := []struct {
+		name       string
+		in         string
+		indent     string
+		sortKeys   bool
+		want       string
+		wantErrSub string
+	}{
+		{
+			name: "minify preserves key order and literals",
+			in:   src,
+			want: `{"b":1.50,"a":[1,{"z":"<x>","y":null}],"c":{}}`,
+		},
+		{
+			name:   "pretty preserves key order",
+			in:     src,
+			indent: "  ",
+			want:   "{\n  \"b\": 1.50,\n  \"a\": [\n    1,\n    {\n      \"z\": \"<x>\",\n      \"y\": null\n    }\n  ],\n  \"c\": {}\n}",
+		},
+		{
+			name:     "minify with sorted keys",
+			in:       src,
+			sortKeys: true,
+			want:     `{"a":[1,{"y":null,"z":"<x>"}],"b":1.50,"c":{}}`,
+		},
+		{
+			name:     "pretty with tab indent and sorted keys",
+			in:       `{"b":true,"a":"é"}`,
+			indent:   "\t",
+			sortKeys: true,
+			want:     "{\n\t\"a\": \"é\",\n\t\"b\": true\n}",
+		},
+		{
+			name:   "scalar document",
+			in:     " 42 ",
+			indent: "  ",
+			want:   "42",
+		},
+		{name: "invalid JSON", in: `{"a":}`, wantErrSub: "decode JSON"},
+		{name: "trailing data", in: `{} {}`, wantErrSub: "trailing data"},
+		{name: "empty input", in: "", wantErrSub: "decode JSON"},
+		{name: "too deep", in: strings.Repeat("[", 10000) + strings.Repeat("]", 10000), wantErrSub: "nesting too deep"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := FormatJSON([]byte(tt.in), tt.indent, tt.sortKeys)
+			if tt.wantErrSub != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErrSub) {
+					t.Fatalf("expected error containing %q, got %v", tt.wantErrSub, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if string(got) != tt.want {
+				t.Fatalf("got:\n%s\nwant:\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatJSON_RoundTrip(t *testing.T) {
+	t.Parallel()
+
+	const compact = `{"name":"x","tags":["a","b"],"n":{"k":-1e3}}`
+	pretty, err := FormatJSON([]byte(compact), "    ", false)
+	if err != nil {
+		t.Fatalf("pretty: %v", err)
+	}
+	back, err := FormatJSON(pretty, "", false)
+	if err != nil {
+		t.Fatalf("minify: %v", err)
+	}
+	if string(back) != compact {
+		t.Fatalf("round trip changed document:\ngot  %s\nwant %s", back, compact)
+	}
+}
+
+func TestPrettyAndMinifyJSON(t *testing.T) {
+	t.Parallel()
+
+	const in = ` {"b":[1, 2.50,{"c":"<x> \u00e9"}],"a":{},"z":[],"n":null} `
+	const wantPretty = "{\n  \"b\": [\n    1,\n    2.50,\n    {\n      \"c\": \"<x> \\u00e9\"\n    }\n  ],\n" +
+		"  \"a\": {},\n  \"z\": [],\n  \"n\": null\n}"
+	const wantMin = `{"b":[1,2.50,{"c":"<x> \u00e9"}],"a":{},"z":[],"n":null}`
+
+	pretty, err := PrettyJSON(json.RawMessage(in), "")
+	if err != nil {
+		t.Fatalf("PrettyJSON: %v", err)
+	}
+	if string(pretty) != wantPretty {
+		t.Fatalf("pretty:\nwant: %s\ngot:  %s", wantPretty, pretty)
+	}
+	minified, err := MinifyJSON(pretty)
+	if err != nil {
+		t.Fatalf("MinifyJSON: %v", err)
+	}
+	if string(minified) != wantMin {
+		t.Fatalf("minify:\nwant: %s\ngot:  %s", wantMin, minified)
+	}
+	again, err := PrettyJSON(minified, "")
+	if err != nil {
+		t.Fatalf("PrettyJSON: %v", err)
+	}
+	if string(again) != string(pretty) {
+		t.Fatalf("round trip not stable:\nfirst:  %s\nsecond: %s", pretty, again)
+	}
+
+	tabbed, err := PrettyJSON(json.RawMessage(`[1,{"a":2}]`), "\t")
+	if err != nil {
+		t.Fatalf("PrettyJSON tab: %v", err)
+	}
+	if want := "[\n\t1,\n\t{\n\t\t\"a\": 2\n\t}\n]"; string(tabbed) != want {
+		t.Fatalf("tab indent: want %q got %q", want, tabbed)
+	}
+
+	for _, bad := range []string{``, `{"a":}`, `[1,]`} {
+		if _, err := PrettyJSON(json.RawMessage(bad), ""); err == nil || !strings.Contains(err.Error(), "decode JSON") {
+			t.Fatalf("PrettyJSON(%q): expected decode JSON error, got %v", bad, err)
+		}
+		if _, err := MinifyJSON(json.RawMessage(bad)); err == nil || !strings.Contains(err.Error(), "decode JSON") {
+			t.Fatalf("MinifyJSON(%q): expected decode JSON error, got %v", bad, err)
+		}
+	}
+	if _, err := MinifyJSON(json.RawMessage(`{} {}`)); err == nil || !strings.Contains(err.Error(), "trailing data") {
+		t.Fatalf("expected trailing data error, got %v", err)
+	}
+}
@@ -0,0 +1,72 @@
+package jsonutil
+
+import "fmt"
+
+// DecodeJSONC decodes JSON with comments (JSONC), as found in hand-edited config files, into T. Line (//) and block
+// (/* */) comments and trailing commas before ']' or '}' are removed first; everything else, including unknown
+// fields, trailing data and limits, is handled as in DecodeJSONRaw. Comments are replaced by spaces, so error offsets
+// still point into the original data. Empty or comment-only data yields the zero value of T.
+func DecodeJSONC[T any](data []byte) (T, error) {
+	var zero T
+	clean, err := stripJSONC(data)
+	if err != nil {
+		return zero, fmt.Errorf("decode JSON: %w", err)
+	}
+	return DecodeJSONRaw[T](clean)
+}
+
+// stripJSONC returns a copy of data with comments and trailing commas blanked out. Newlines inside block comments
+// are kept so line numbers do not shift. String literals are copied verbatim, so "//" or "/*" inside them survive.
+func stripJSONC(data []byte) ([]byte, error) {
+	out := make([]byte, len(data))
+	copy(out, data)
+
+	inString, escaped := false, false
+	lastComma := -1 // index of a comma not yet followed by a value
+	for i := 0; i < len(out); i++ {
+		c := out[i]
+		switch {
+		case escaped:
+			escaped = false
+		case inString:
+			switch c {
+			case '\\':
+				escaped = true
+			case '"':
+				inString = false
+			}
+		case c == '/' && i+1 < len(out) && out[i+1] == '/':
+			for ; i < len(out) && out[i] != '\n'; i++ {
+				out[i] = ' '
+			}
+		case c == '/' && i+1 < len(out) && out[i+1] == '*':
+			start := i
+			out[i], out[i+1] = ' ', ' '
+			for i += 2; ; i++ {
+				if i+1 >= len(out) {
+					return nil, fmt.Errorf("unterminated block comment at offset %d", start)
+				}
+				if out[i] == '*' && out[i+1] == '/' {
+					out[i], out[i+1] = ' ', ' '
+					i++
+					break
+				}
+				if out[i] != '\n' && out[i] != '\r' {
+					out[i] = ' '
+				}
+			}
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+		case c == ',':
+			lastComma = i
+		default:
+			if (c == ']' || c == '}') && lastComma >= 0 {
+				out[lastComma] = ' '
+			}
+			lastComma = -1
+			if c == '"' {
+				inString = true
+			}
+		}
+	}
+	return out, nil
+}
@@ -0,0 +1,93 @@
+package jsonutil
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestDecodeJSONC(t *testing.T) {
+	t.Parallel()
+
+	type config struct {
+		Name  string   `json:"name"`
+		URL   string   `json:"url"`
+		Tags  []string `json:"tags"`
+		Limit int      `json:"limit"`
+	}
+
+	tests := []struct {
+		name       string
+		data       string
+		want       config
+		wantErrSub string
+		wantErrIs  error
+	}{
+		{
+			name: "line comments",
+			data: "// tool config\n{\n  \"name\": \"x\", // inline\n  \"limit\": 2\n}\n// end",
+			want: config{Name: "x", Limit: 2},
+		},
+		{
+			name: "block comments",
+			data: "/* header\n   spans lines */ {\"name\": /* here */ \"x\"}",
+			want: config{Name: "x"},
+		},
+		{
+			name: "trailing commas",
+			data: `{"tags": ["a", "b",], "limit": 1,}`,
+			want: config{Tags: []string{"a", "b"}, Limit: 1},
+		},
+		{
+			name: "trailing comma before comment",
+			data: "{\"tags\": [\"a\", // last\n]}",
+			want: config{Tags: []string{"a"}},
+		},
+		{
+			name: "comment markers inside strings are kept",
+			data: `{"url": "https://example.com/a/*b*/", "name": "say \"//hi\", ok"}`,
+			want: config{URL: "https://example.com/a/*b*/", Name: `say "//hi", ok`},
+		},
+		{name: "comment only yields zero value", data: "// nothing here\n/* really */"},
+		{name: "unterminated block comment", data: `{"name": "x"} /* oops`, wantErrSub: "unterminated block comment"},
+		{name: "unknown fields still rejected", data: `{"nope": 1, // c` + "\n}", wantErrSub: "unknown field"},
+		{name: "double comma still invalid", data: `{"tags": ["a",,]}`, wantErrSub: "decode JSON"},
+		{name: "trailing data still rejected", data: `{} // c` + "\n{}", wantErrSub: "trailing data"},
+		{name: "limits still apply", data: strings.Repeat("[", DefaultMaxJSONDepth+1), wantErrIs: ErrJSONTooDeep},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := DecodeJSONC[config]([]byte(tc.data))
+			if tc.wantErrSub != "" || tc.wantErrIs != nil {
+				if err == nil {
+					t.Fatalf("expected error, got %+v", got)
+				}
+				if tc.wantErrSub != "" && !strings.Contains(err.Error(), tc.wantErrSub) {
+					t.Fatalf("error %q does not contain %q", err, tc.wantErrSub)
+				}
+				if tc.wantErrIs != nil && !errors.Is(err, tc.wantErrIs) {
+					t.Fatalf("expected errors.Is(%v), got %v", tc.wantErrIs, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Fatalf("got %+v, want %+v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestDecodeJSONC_ErrorLine(t *testing.T) {
+	t.Parallel()
+	// Comments are blanked, not removed, so the reported line matches the input.
+	_, err := DecodeJSONC[map[string]any]([]byte("/* a\nb */\n{\"x\": }"))
+	if err == nil || !strings.Contains(err.Error(), "line 3") {
+		t.Fatalf("expected error on line 3, got %v", err)
+	}
+}
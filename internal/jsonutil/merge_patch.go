@@ -0,0 +1,159 @@
+package jsonutil
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// MergePatch applies patch to target as a JSON Merge Patch (RFC 7386) and
+// returns the compact result: objects merge recursively, a null member deletes
+// the key, and any non-object patch replaces the target wholesale. Existing
+// keys keep their position and new keys are appended in patch order. A blank
+// target is treated as null.
+func MergePatch(target, patch json.RawMessage) (json.RawMessage, error) {
+	if isBlankJSON(patch) {
+		return nil, errors.New("merge patch: patch is empty")
+	}
+	if !json.Valid(patch) {
+		return nil, errors.New("merge patch: patch is not valid JSON")
+	}
+	if isBlankJSON(target) {
+		target = nil
+	} else if !json.Valid(target) {
+		return nil, errors.New("merge patch: target is not valid JSON")
+	}
+	for _, data := range [][]byte{target, patch} {
+		if err := checkJSONLimits(data, DefaultMaxJSONDepth, DefaultMaxJSONBytes); err != nil {
+			return nil, fmt.Errorf("merge patch: %w", err)
+		}
+	}
+
+	out, err := mergePatchValue(target, patch)
+	if err != nil {
+		return nil, fmt.Errorf("merge patch: %w", err)
+	}
+	return json.RawMessage(out), nil
+}
+
+// mergePatchValue implements the RFC 7386 MergePatch function on valid JSON.
+// A nil target stands for a missing or null value.
+func mergePatchValue(target, patch []byte) ([]byte, error) {
+	if !isJSONObject(patch) {
+		var buf bytes.Buffer
+		if err := json.Compact(&buf, patch); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	}
+
+	var obj orderedObject
+	if isJSONObject(target) {
+		if err := obj.decode(target); err != nil {
+			return nil, err
+		}
+	}
+	var p orderedObject
+	if err := p.decode(patch); err != nil {
+		return nil, err
+	}
+	for _, k := range p.keys {
+		v := p.vals[k]
+		if isJSONNull(v) {
+			obj.remove(k)
+			continue
+		}
+		merged, err := mergePatchValue(obj.vals[k], v)
+		if err != nil {
+			return nil, err
+		}
+		obj.set(k, merged)
+	}
+	return obj.encode()
+}
+
+// orderedObject is a JSON object that remembers key order. Duplicate keys keep
+// the first position and the last value, as encoding/json does for values.
+type orderedObject struct {
+	keys []string
+	vals map[string]json.RawMessage
+}
+
+func (o *orderedObject) decode(data []byte) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	if _, err := dec.Token(); err != nil { // '{'
+		return err
+	}
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		key, ok := tok.(string)
+		if !ok {
+			return fmt.Errorf("unexpected object key %v", tok)
+		}
+		var v json.RawMessage
+		if err := dec.Decode(&v); err != nil {
+			return err
+		}
+		o.set(key, v)
+	}
+	_, err := dec.Token() // '}'
+	return err
+}
+
+func (o *orderedObject) set(key string, v json.RawMessage) {
+	if o.vals == nil {
+		o.vals = map[string]json.RawMessage{}
+	}
+	if _, ok := o.vals[key]; !ok {
+		o.keys = append(o.keys, key)
+	}
+	o.vals[key] = v
+}
+
+func (o *orderedObject) remove(key string) {
+	if _, ok := o.vals[key]; !ok {
+		return
+	}
+	delete(o.vals, key)
+	for i, k := range o.keys {
+		if k == key {
+			o.keys = append(o.keys[:i], o.keys[i+1:]...)
+			break
+		}
+	}
+}
+
+func (o *orderedObject) encode() ([]byte, error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	buf.WriteByte('{')
+	for i, k := range o.keys {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		if err := enc.Encode(k); err != nil {
+			return nil, err
+		}
+		buf.Truncate(buf.Len() - 1) // Encode appends a newline.
+		buf.WriteByte(':')
+		if err := json.Compact(&buf, o.vals[k]); err != nil {
+			return nil, err
+		}
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+func isJSONObject(data []byte) bool {
+	data = bytes.TrimSpace(data)
+	return len(data) > 0 && data[0] == '{'
+}
+
+func isJSONNull(data []byte) bool {
+	return string(bytes.TrimSpace(data)) == "null"
+}
@@ -0,0 +1,90 @@
+package jsonutil
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestMergePatch(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		target     string
+		patch      string
+		want       string
+		wantErrSub string
+		wantErrIs  error
+	}{
+		{name: "add key", target: `{"a":"b"}`, patch: `{"b":"c"}`, want: `{"a":"b","b":"c"}`},
+		{name: "replace value", target: `{"a":"b"}`, patch: `{"a":"c"}`, want: `{"a":"c"}`},
+		{name: "delete key via null", target: `{"a":"b","b":"c"}`, patch: `{"a":null}`, want: `{"b":"c"}`},
+		{name: "delete last key", target: `{"a":"b"}`, patch: `{"a":null}`, want: `{}`},
+		{name: "delete missing key is a no-op", target: `{"a":1}`, patch: `{"z":null}`, want: `{"a":1}`},
+		{
+			name:   "nested merge",
+			target: `{"title":"Goodbye!","author":{"givenName":"John","familyName":"Doe"},"tags":["example","sample"]}`,
+			patch:  `{"title":"Hello!","phoneNumber":"+01-123-456-7890","author":{"familyName":null},"tags":["example"]}`,
+			want:   `{"title":"Hello!","author":{"givenName":"John"},"tags":["example"],"phoneNumber":"+01-123-456-7890"}`,
+		},
+		{
+			name:   "nested null removes member",
+			target: `{"a":{"b":"c"}}`,
+			patch:  `{"a":{"b":"d","c":null}}`,
+			want:   `{"a":{"b":"d"}}`,
+		},
+		{name: "nulls in new objects are dropped", target: `{}`, patch: `{"a":{"bb":{"ccc":null}}}`, want: `{"a":{"bb":{}}}`},
+		{name: "array replaces scalar", target: `{"a":"c"}`, patch: `{"a":["b"]}`, want: `{"a":["b"]}`},
+		{name: "scalar replaces array", target: `{"a":["b"]}`, patch: `{"a":"c"}`, want: `{"a":"c"}`},
+		{name: "arrays are replaced not merged", target: `{"a":[{"b":"c"}]}`, patch: `{"a":[1]}`, want: `{"a":[1]}`},
+		{name: "array patch replaces target", target: `["a","b"]`, patch: `["c","d"]`, want: `["c","d"]`},
+		{name: "array patch replaces object", target: `{"a":"b"}`, patch: `["c"]`, want: `["c"]`},
+		{name: "scalar patch replaces target", target: `{"a":"foo"}`, patch: `"bar"`, want: `"bar"`},
+		{name: "null patch replaces target", target: `{"a":"foo"}`, patch: `null`, want: `null`},
+		{name: "existing null member is kept", target: `{"e":null}`, patch: `{"a":1}`, want: `{"e":null,"a":1}`},
+		{name: "object patch on array target", target: `[1,2]`, patch: `{"a":"b","c":null}`, want: `{"a":"b"}`},
+		{name: "blank target", target: " ", patch: `{"a":{"b":null,"c":1}}`, want: `{"a":{"c":1}}`},
+		{
+			name:   "output is compact and keeps literals",
+			target: "{\n  \"n\": 1.50,\n  \"s\": \"<x>\"\n}",
+			patch:  `{"k<>":  [ 1 , 2 ]}`,
+			want:   `{"n":1.50,"s":"<x>","k<>":[1,2]}`,
+		},
+		{name: "duplicate target keys keep last value", target: `{"a":1,"b":2,"a":3}`, patch: `{}`, want: `{"a":3,"b":2}`},
+		{name: "empty patch", target: `{}`, patch: ``, wantErrSub: "patch is empty"},
+		{name: "invalid patch", target: `{}`, patch: `{"a":`, wantErrSub: "patch is not valid JSON"},
+		{name: "invalid target", target: `{"a"`, patch: `{}`, wantErrSub: "target is not valid JSON"},
+		{
+			name:      "deep patch",
+			target:    `{}`,
+			patch:     `{"a":` + strings.Repeat("[", 2000) + strings.Repeat("]", 2000) + `}`,
+			wantErrIs: ErrJSONTooDeep,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := MergePatch(json.RawMessage(tt.target), json.RawMessage(tt.patch))
+			switch {
+			case tt.wantErrIs != nil:
+				if !errors.Is(err, tt.wantErrIs) {
+					t.Fatalf("expected %v, got %v", tt.wantErrIs, err)
+				}
+				return
+			case tt.wantErrSub != "":
+				if err == nil || !strings.Contains(err.Error(), tt.wantErrSub) {
+					t.Fatalf("expected error containing %q, got %v", tt.wantErrSub, err)
+				}
+				return
+			case err != nil:
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if string(got) != tt.want {
+				t.Fatalf("unexpected result.\nwant: %s\ngot:  %s", tt.want, got)
+			}
+		})
+	}
+}
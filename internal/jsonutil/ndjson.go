@@ -0,0 +1,80 @@
+package jsonutil
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// EncodeNDJSON encodes each value as one compact JSON line (newline-delimited JSON).
+// The output ends with a trailing newline unless values is empty.
+func EncodeNDJSON[T any](values []T) ([]byte, error) {
+	var buf bytes.Buffer
+	for i, v := range values {
+		// Marshal never emits raw newlines (they are escaped inside strings),
+		// and json.RawMessage is compacted, so each value stays on one line.
+		data, err := json.Marshal(v)
+		if err != nil {
+			return nil, fmt.Errorf("encode NDJSON line %d: %w", i+1, err)
+		}
+		buf.Write(data)
+		buf.WriteByte('\n')
+	}
+	return buf.Bytes(), nil
+}
+
+// DecodeNDJSON reads newline-delimited JSON from r and calls fn with each value, in order. Each line must hold exactly
+// one value of T (unknown fields are rejected, as in DecodeJSONRaw); blank lines are skipped. A line longer than
+// DefaultMaxJSONBytes fails with ErrJSONTooLarge without being buffered in full. It stops at the first read, decode or
+// callback error, reporting the 1-based line number; callback errors are wrapped.
+func DecodeNDJSON[T any](r io.Reader, fn func(T) error) error {
+	return decodeNDJSON(r, fn, DefaultMaxJSONBytes)
+}
+
+func decodeNDJSON[T any](r io.Reader, fn func(T) error, maxBytes int64) error {
+	if fn == nil {
+		return errors.New("decode NDJSON: nil callback")
+	}
+	br := bufio.NewReader(r)
+	for lineNo := 1; ; lineNo++ {
+		line, readErr := readNDJSONLine(br, maxBytes)
+		if errors.Is(readErr, ErrJSONTooLarge) {
+			return fmt.Errorf("NDJSON line %d: %w (max %d bytes)", lineNo, readErr, maxBytes)
+		}
+		if readErr != nil && !errors.Is(readErr, io.EOF) {
+			return fmt.Errorf("read NDJSON line %d: %w", lineNo, readErr)
+		}
+		line = bytes.TrimSuffix(bytes.TrimSuffix(line, []byte{'\n'}), []byte{'\r'})
+		if !isBlankJSON(line) {
+			var v T
+			if err := decodeBytes(line, &v, true, true, DefaultMaxJSONDepth, maxBytes); err != nil {
+				return fmt.Errorf("NDJSON line %d: %w", lineNo, err)
+			}
+			if err := fn(v); err != nil {
+				return fmt.Errorf("NDJSON line %d: %w", lineNo, err)
+			}
+		}
+		if readErr != nil {
+			return nil
+		}
+	}
+}
+
+// readNDJSONLine reads up to and including the next '\n', failing with ErrJSONTooLarge once the line exceeds maxBytes
+// plus room for a CRLF terminator, so an oversized line is never buffered in full.
+func readNDJSONLine(br *bufio.Reader, maxBytes int64) ([]byte, error) {
+	var line []byte
+	for {
+		frag, err := br.ReadSlice('\n')
+		if int64(len(line)+len(frag)) > maxBytes+2 {
+			return nil, ErrJSONTooLarge
+		}
+		line = append(line, frag...)
+		if !errors.Is(err, bufio.ErrBufferFull) {
+			return line, err
+		}
+	}
+}
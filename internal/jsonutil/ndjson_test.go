@@ -0,0 +1,201 @@
+package jsonutil
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+func TestEncodeNDJSON(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		in         []any
+		want       string
+		wantErrSub string
+	}{
+		{
+			name: "empty",
+			in:   nil,
+			want: "",
+		},
+		{
+			name: "mixed values one per line",
+			in:   []any{person{Name: "a", Age: 1}, 2, "x\ny"},
+			want: "{\"name\":\"a\",\"age\":1}\n2\n\"x\\ny\"\n",
+		},
+		{
+			name: "raw messages are compacted",
+			in:   []any{json.RawMessage("{\n  \"a\": [1,\n 2]\n}")},
+			want: "{\"a\":[1,2]}\n",
+		},
+		{
+			name:       "marshal error reports line",
+			in:         []any{1, make(chan int)},
+			wantErrSub: "line 2",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := EncodeNDJSON(tt.in)
+			if tt.wantErrSub != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErrSub) {
+					t.Fatalf("expected error containing %q, got %v", tt.wantErrSub, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if string(got) != tt.want {
+				t.Fatalf("got %q want %q", string(got), tt.want)
+			}
+		})
+	}
+}
+
+func TestDecodeNDJSON(t *testing.T) {
+	t.Parallel()
+
+	errStop := errors.New("stop")
+
+	tests := []struct {
+		name       string
+		in         string
+		stopAt     int // callback returns errStop on this call (1-based); 0 = never
+		want       []person
+		wantErrSub string
+		wantErrIs  error
+	}{
+		{
+			name: "empty",
+			in:   "",
+		},
+		{
+			name: "well formed stream",
+			in:   "{\"name\":\"a\",\"age\":1}\n{\"name\":\"b\",\"age\":2}\n",
+			want: []person{{Name: "a", Age: 1}, {Name: "b", Age: 2}},
+		},
+		{
+			name: "no trailing newline and CRLF",
+			in:   "{\"name\":\"a\",\"age\":1}\r\n{\"name\":\"b\",\"age\":2}",
+			want: []person{{Name: "a", Age: 1}, {Name: "b", Age: 2}},
+		},
+		{
+			name: "blank lines are skipped",
+			in:   "\n{\"name\":\"a\",\"age\":1}\n   \n\t\n{\"name\":\"b\",\"age\":2}\n\n",
+			want: []person{{Name: "a", Age: 1}, {Name: "b", Age: 2}},
+		},
+		{
+			name:       "malformed middle record stops the stream",
+			in:         "{\"name\":\"a\",\"age\":1}\n{\"name\":\n{\"name\":\"c\",\"age\":3}\n",
+			want:       []person{{Name: "a", Age: 1}},
+			wantErrSub: "NDJSON line 2: decode JSON:",
+		},
+		{
+			name:       "two values on one line",
+			in:         "{\"name\":\"a\",\"age\":1} {\"name\":\"b\",\"age\":2}\n",
+			wantErrSub: "NDJSON line 1:",
+		},
+		{
+			name:       "unknown field",
+			in:         "{\"name\":\"a\",\"extra\":1}\n",
+			wantErrSub: "NDJSON line 1: decode JSON:",
+		},
+		{
+			name:      "callback error stops the stream",
+			in:        "{\"name\":\"a\",\"age\":1}\n\n{\"name\":\"b\",\"age\":2}\n{\"name\":\"c\",\"age\":3}\n",
+			stopAt:    2,
+			want:      []person{{Name: "a", Age: 1}, {Name: "b", Age: 2}},
+			wantErrIs: errStop,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			var got []person
+			err := DecodeNDJSON(strings.NewReader(tt.in), func(p person) error {
+				got = append(got, p)
+				if len(got) == tt.stopAt {
+					return errStop
+				}
+				return nil
+			})
+			switch {
+			case tt.wantErrIs != nil:
+				if !errors.Is(err, tt.wantErrIs) {
+					t.Fatalf("expected %v, got %v", tt.wantErrIs, err)
+				}
+			case tt.wantErrSub != "":
+				if err == nil || !strings.Contains(err.Error(), tt.wantErrSub) {
+					t.Fatalf("expected error containing %q, got %v", tt.wantErrSub, err)
+				}
+			case err != nil:
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %d values %#v, want %#v", len(got), got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Fatalf("value %d: got %#v, want %#v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestDecodeNDJSON_ReadErrorAndNilCallback(t *testing.T) {
+	t.Parallel()
+
+	r := io.MultiReader(strings.NewReader("1\n2"), iotest.ErrReader(io.ErrUnexpectedEOF))
+	var got []int
+	err := DecodeNDJSON(r, func(v int) error { got = append(got, v); return nil })
+	if !errors.Is(err, io.ErrUnexpectedEOF) || !strings.Contains(err.Error(), "line 2") {
+		t.Fatalf("expected read error on line 2, got %v", err)
+	}
+	if len(got) != 1 || got[0] != 1 {
+		t.Fatalf("got %v, want [1]", got)
+	}
+
+	if err := DecodeNDJSON[int](strings.NewReader("1\n"), nil); err == nil {
+		t.Fatalf("expected error for nil callback")
+	}
+}
+
+func TestDecodeNDJSON_LineTooLarge(t *testing.T) {
+	t.Parallel()
+
+	const maxBytes = 16
+	atLimit := `"` + strings.Repeat("a", maxBytes-2) + `"`
+
+	var got []string
+	err := decodeNDJSON(strings.NewReader(atLimit+"\r\n"+atLimit), func(v string) error {
+		got = append(got, v)
+		return nil
+	}, maxBytes)
+	if err != nil || len(got) != 2 {
+		t.Fatalf("lines at the limit: got %d values, err %v", len(got), err)
+	}
+
+	// An over-long final line without a newline must be rejected, not buffered in full.
+	got = nil
+	in := atLimit + "\n" + `"` + strings.Repeat("b", 1<<20)
+	err = decodeNDJSON(strings.NewReader(in), func(v string) error {
+		got = append(got, v)
+		return nil
+	}, maxBytes)
+	if !errors.Is(err, ErrJSONTooLarge) || !strings.Contains(err.Error(), "line 2") {
+		t.Fatalf("expected ErrJSONTooLarge on line 2, got %v", err)
+	}
+	if len(got) != 1 {
+		t.Fatalf("got %d values before the oversized line, want 1", len(got))
+	}
+}
@@ -0,0 +1,130 @@
+package jsonutil
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ErrPointerNotFound is returned (wrapped) when a JSON pointer refers to a
+// missing object key, an out-of-range array index, or a child of a scalar.
+var ErrPointerNotFound = errors.New("JSON pointer target not found")
+
+// ParsePointer splits an RFC 6901 JSON Pointer into unescaped reference tokens.
+// "" refers to the whole document and yields no tokens.
+func ParsePointer(pointer string) ([]string, error) {
+	if pointer == "" {
+		return nil, nil
+	}
+	if !strings.HasPrefix(pointer, "/") {
+		return nil, fmt.Errorf("invalid JSON pointer %q: must be empty or start with '/'", pointer)
+	}
+	parts := strings.Split(pointer[1:], "/")
+	for i, p := range parts {
+		// Order matters: "~01" must decode to "~1", not "/".
+		parts[i] = strings.ReplaceAll(strings.ReplaceAll(p, "~1", "/"), "~0", "~")
+	}
+	return parts, nil
+}
+
+// GetJSONPointer returns a copy of the raw value that pointer (RFC 6901)
+// refers to inside raw. The document is scanned token by token; only the
+// selected value is copied, and nothing is decoded into Go values.
+func GetJSONPointer(raw json.RawMessage, pointer string) (json.RawMessage, error) {
+	start, end, err := LocateValue(raw, pointer)
+	if err != nil {
+		return nil, err
+	}
+	return bytes.Clone(raw[start:end]), nil
+}
+
+// LocateValue returns the byte span [start, end) of the value that pointer
+// refers to inside the JSON document data, without re-encoding anything.
+func LocateValue(data []byte, pointer string) (start, end int, err error) {
+	tokens, err := ParsePointer(pointer)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	for depth, ref := range tokens {
+		at := "/" + strings.Join(tokens[:depth], "/")
+		tok, err := dec.Token()
+		if err != nil {
+			return 0, 0, fmt.Errorf("decode JSON: %w", err)
+		}
+		switch tok {
+		case json.Delim('{'):
+			if err := seekObjectKey(dec, ref); err != nil {
+				return 0, 0, fmt.Errorf("pointer %q: %w at %q", pointer, err, at)
+			}
+		case json.Delim('['):
+			if err := seekArrayIndex(dec, ref); err != nil {
+				return 0, 0, fmt.Errorf("pointer %q: %w at %q", pointer, err, at)
+			}
+		default:
+			return 0, 0, fmt.Errorf("pointer %q: %w: cannot descend into scalar at %q", pointer, ErrPointerNotFound, at)
+		}
+	}
+
+	start = skipValueSeparators(data, int(dec.InputOffset()))
+	var raw json.RawMessage
+	if err := dec.Decode(&raw); err != nil {
+		return 0, 0, fmt.Errorf("decode JSON: %w", err)
+	}
+	return start, int(dec.InputOffset()), nil
+}
+
+func seekObjectKey(dec *json.Decoder, key string) error {
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		if k, _ := tok.(string); k == key {
+			return nil
+		}
+		var skip json.RawMessage
+		if err := dec.Decode(&skip); err != nil {
+			return err
+		}
+	}
+	return fmt.Errorf("%w: key %q", ErrPointerNotFound, key)
+}
+
+func seekArrayIndex(dec *json.Decoder, ref string) error {
+	idx, err := strconv.Atoi(ref)
+	if err != nil || idx < 0 || (len(ref) > 1 && ref[0] == '0') {
+		return fmt.Errorf("invalid array index %q", ref)
+	}
+	for range idx {
+		if !dec.More() {
+			return fmt.Errorf("%w: array index out of range", ErrPointerNotFound)
+		}
+		var skip json.RawMessage
+		if err := dec.Decode(&skip); err != nil {
+			return err
+		}
+	}
+	if !dec.More() {
+		return fmt.Errorf("%w: array index out of range", ErrPointerNotFound)
+	}
+	return nil
+}
+
+// skipValueSeparators advances past whitespace and the ':'/',' separators that
+// precede a value; the decoder's offset sits just after the previous token.
+func skipValueSeparators(data []byte, i int) int {
+	for i < len(data) {
+		switch data[i] {
+		case ' ', '\t', '\r', '\n', ':', ',':
+			i++
+		default:
+			return i
+		}
+	}
+	return i
+}
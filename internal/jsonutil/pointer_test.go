@@ -0,0 +1,151 @@
+package jsonutil
+
+import (
+	"encoding/json"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestParsePointer(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		in      string
+		want    []string
+		wantErr bool
+	}{
+		{name: "root", in: "", want: nil},
+		{name: "simple", in: "/a/0", want: []string{"a", "0"}},
+		{name: "escapes", in: "/a~1b/m~0n/~01", want: []string{"a/b", "m~n", "~1"}},
+		{name: "empty key", in: "/", want: []string{""}},
+		{name: "missing slash", in: "a", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := ParsePointer(tt.in)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err=%v wantErr=%v", err, tt.wantErr)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Fatalf("got %q want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLocateValue(t *testing.T) {
+	t.Parallel()
+	doc := `{"a": {"b": [10, {"c": "x"}, [1,2]]}, "s": "str", "k/e~y": true}`
+	tests := []struct {
+		name      string
+		pointer   string
+		want      string
+		errSubstr string
+	}{
+		{name: "root", pointer: "", want: doc},
+		{name: "nested object", pointer: "/a", want: `{"b": [10, {"c": "x"}, [1,2]]}`},
+		{name: "array", pointer: "/a/b", want: `[10, {"c": "x"}, [1,2]]`},
+		{name: "array index", pointer: "/a/b/1", want: `{"c": "x"}`},
+		{name: "deep", pointer: "/a/b/1/c", want: `"x"`},
+		{name: "last index", pointer: "/a/b/2", want: `[1,2]`},
+		{name: "escaped key", pointer: "/k~1e~0y", want: `true`},
+		{name: "missing key", pointer: "/nope", errSubstr: "not found"},
+		{name: "index out of range", pointer: "/a/b/3", errSubstr: "out of range"},
+		{name: "bad index", pointer: "/a/b/01", errSubstr: "invalid array index"},
+		{name: "through scalar", pointer: "/s/x", errSubstr: "scalar"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			start, end, err := LocateValue([]byte(doc), tt.pointer)
+			if tt.errSubstr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.errSubstr) {
+					t.Fatalf("expected error containing %q, got %v", tt.errSubstr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got := doc[start:end]; got != tt.want {
+				t.Fatalf("got %q want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetJSONPointer(t *testing.T) {
+	t.Parallel()
+	doc := json.RawMessage(`{"a": {"b": [10, {"c": "x"}, [1,2]]}, "m~n": {"x/y": 1}, "": "empty key", "n": null}`)
+	tests := []struct {
+		name         string
+		pointer      string
+		want         string
+		wantNotFound bool
+		errSubstr    string
+	}{
+		{name: "whole document", pointer: "", want: string(doc)},
+		{name: "object traversal", pointer: "/a/b", want: `[10, {"c": "x"}, [1,2]]`},
+		{name: "array index", pointer: "/a/b/0", want: `10`},
+		{name: "array then object", pointer: "/a/b/1/c", want: `"x"`},
+		{name: "nested array", pointer: "/a/b/2/1", want: `2`},
+		{name: "escaped tokens", pointer: "/m~0n/x~1y", want: `1`},
+		{name: "empty key", pointer: "/", want: `"empty key"`},
+		{name: "null value", pointer: "/n", want: `null`},
+		{name: "missing key", pointer: "/a/nope", wantNotFound: true},
+		{name: "index out of range", pointer: "/a/b/3", wantNotFound: true},
+		{name: "end of array marker", pointer: "/a/b/-", errSubstr: "invalid array index"},
+		{name: "through scalar", pointer: "/a/b/0/x", wantNotFound: true},
+		{name: "pointer without slash", pointer: "a", errSubstr: "must be empty or start with '/'"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := GetJSONPointer(doc, tt.pointer)
+			switch {
+			case tt.wantNotFound:
+				if !errors.Is(err, ErrPointerNotFound) {
+					t.Fatalf("expected ErrPointerNotFound, got %v", err)
+				}
+				return
+			case tt.errSubstr != "":
+				if err == nil || !strings.Contains(err.Error(), tt.errSubstr) {
+					t.Fatalf("expected error containing %q, got %v", tt.errSubstr, err)
+				}
+				if errors.Is(err, ErrPointerNotFound) {
+					t.Fatalf("did not expect ErrPointerNotFound: %v", err)
+				}
+				return
+			case err != nil:
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if string(got) != tt.want {
+				t.Fatalf("got %s want %s", got, tt.want)
+			}
+		})
+	}
+
+	t.Run("result does not alias input", func(t *testing.T) {
+		t.Parallel()
+		src := json.RawMessage(`{"a":[1]}`)
+		got, err := GetJSONPointer(src, "/a")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		got[1] = '9'
+		if string(src) != `{"a":[1]}` {
+			t.Fatalf("input modified: %s", src)
+		}
+	})
+
+	t.Run("invalid document", func(t *testing.T) {
+		t.Parallel()
+		if _, err := GetJSONPointer(json.RawMessage(`{"a":`), "/a"); err == nil ||
+			!strings.Contains(err.Error(), "decode JSON") {
+			t.Fatalf("expected decode error, got %v", err)
+		}
+	})
+}
@@ -8,8 +8,21 @@ import (
 	"io"
 )
 
+const (
+	// DefaultMaxJSONDepth bounds nesting of arrays/objects accepted by the decoders.
+	DefaultMaxJSONDepth = 1000
+	// DefaultMaxJSONBytes bounds the input size accepted by the decoders.
+	DefaultMaxJSONBytes int64 = 64 * 1024 * 1024
+)
+
+var (
+	ErrJSONTooDeep  = errors.New("JSON nesting too deep")
+	ErrJSONTooLarge = errors.New("JSON input too large")
+)
+
 // EncodeToJSONRaw encodes any value to json.RawMessage.
 // No typed method here as value being of a type doesnt really affect its functionality.
+// Like json.Marshal, it escapes '<', '>' and '&' in strings (as \u003c etc.); use EncodeJSONTo to keep them verbatim.
 func EncodeToJSONRaw(value any) (json.RawMessage, error) {
 	data, err := json.Marshal(value)
 	if err != nil {
@@ -18,29 +31,120 @@ func EncodeToJSONRaw(value any) (json.RawMessage, error) {
 	return json.RawMessage(data), nil
 }
 
+// EncodeJSONTo streams the JSON encoding of value to w, followed by a newline, without building the whole encoding
+// in a separate buffer first. Unlike EncodeToJSONRaw, '<', '>' and '&' are written verbatim, so HTML and URLs in tool
+// text stay readable.
+func EncodeJSONTo(w io.Writer, value any) error {
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(value); err != nil {
+		return fmt.Errorf("encode JSON: %w", err)
+	}
+	return nil
+}
+
+// DecodeOptions relaxes the strict defaults of DecodeJSONRaw.
+type DecodeOptions struct {
+	// AllowUnknown ignores object fields that T does not declare.
+	AllowUnknown bool
+	// AllowTrailing ignores any data after the first JSON value.
+	AllowTrailing bool
+	// MaxDepth and MaxBytes bound nesting and input size; 0 uses the defaults and a negative value disables the check.
+	MaxDepth int
+	MaxBytes int64
+}
+
 // DecodeJSONRaw decodes a json.RawMessage into a typed value T, disallowing unknown fields and rejecting trailing data.
 // If raw is empty, or only whitespace, it returns the zero value of T.
 func DecodeJSONRaw[T any](raw json.RawMessage) (T, error) {
+	return DecodeJSONRawOpts[T](raw, DecodeOptions{})
+}
+
+// DecodeJSONRawOpts is DecodeJSONRaw with the strictness controlled by opts, e.g. so forward-compatible callers can
+// accept payloads carrying fields from newer schemas.
+func DecodeJSONRawOpts[T any](raw json.RawMessage, opts DecodeOptions) (T, error) {
 	var zero T
 	if isBlankJSON(raw) {
 		return zero, nil
 	}
 
 	var v T
-	if err := decodeBytes(raw, &v, true, true); err != nil {
+	maxDepth, maxBytes := opts.limits()
+	if err := decodeBytes(raw, &v, !opts.AllowUnknown, !opts.AllowTrailing, maxDepth, maxBytes); err != nil {
 		return zero, err
 	}
 	return v, nil
 }
 
+// DecodeJSONPrefix decodes the first JSON value in data into T, disallowing unknown fields, and returns the bytes
+// after it instead of rejecting them, so concatenated values such as `{"a":1}{"b":2}` can be decoded one at a time.
+// Whitespace after the value is left in rest. Limits apply as in DecodeJSONRaw. If data is empty or only whitespace,
+// it returns io.EOF, which ends a decode loop.
+func DecodeJSONPrefix[T any](data []byte) (v T, rest []byte, err error) {
+	var zero T
+	if isBlankJSON(data) {
+		return zero, nil, io.EOF
+	}
+	if err := checkJSONLimits(data, DefaultMaxJSONDepth, DefaultMaxJSONBytes); err != nil {
+		return zero, nil, fmt.Errorf("decode JSON: %w", err)
+	}
+	dec := newDecoder(bytes.NewReader(data), true, DefaultMaxJSONBytes)
+	if err := dec.Decode(&v); err != nil {
+		return zero, nil, wrapDecodeError(err, data)
+	}
+	return v, data[dec.InputOffset():], nil
+}
+
+// DecodeReader decodes a single JSON value of type T from r, reading it incrementally instead of loading it whole.
+// The options and limits behave as in DecodeJSONRawOpts, and an empty or whitespace-only reader likewise yields the
+// zero value of T. Unless AllowTrailing is set, r is read to EOF to reject trailing data.
+func DecodeReader[T any](r io.Reader, opts DecodeOptions) (T, error) {
+	var zero T
+	maxDepth, maxBytes := opts.limits()
+	if maxDepth > 0 {
+		r = &depthReader{r: r, maxDepth: maxDepth}
+	}
+	dec := newDecoder(r, !opts.AllowUnknown, maxBytes)
+
+	var v T
+	if err := dec.Decode(&v); err != nil {
+		if errors.Is(err, io.EOF) {
+			return zero, nil
+		}
+		return zero, wrapDecodeError(err, nil)
+	}
+	if !opts.AllowTrailing {
+		if err := requireNoTrailing(dec); err != nil {
+			return zero, err
+		}
+	}
+	return v, nil
+}
+
+// limits resolves MaxDepth/MaxBytes against the package defaults.
+func (o DecodeOptions) limits() (maxDepth int, maxBytes int64) {
+	maxDepth, maxBytes = DefaultMaxJSONDepth, DefaultMaxJSONBytes
+	if o.MaxDepth != 0 {
+		maxDepth = o.MaxDepth
+	}
+	if o.MaxBytes != 0 {
+		maxBytes = o.MaxBytes
+	}
+	return maxDepth, maxBytes
+}
+
 // decodeBytes decodes JSON bytes into out with options:
 // - disallowUnknown: Disallow unknown fields if true.
 // - requireEOF: Reject trailing JSON after the first value if true.
-func decodeBytes(data []byte, out any, disallowUnknown, requireEOF bool) error {
-	dec := newDecoder(bytes.NewReader(data), disallowUnknown)
-	if err := dec.Decode(out); err != nil {
+// - maxDepth, maxBytes: Fail with ErrJSONTooDeep/ErrJSONTooLarge before decoding if exceeded; <=0 disables.
+func decodeBytes(data []byte, out any, disallowUnknown, requireEOF bool, maxDepth int, maxBytes int64) error {
+	if err := checkJSONLimits(data, maxDepth, maxBytes); err != nil {
 		return fmt.Errorf("decode JSON: %w", err)
 	}
+	dec := newDecoder(bytes.NewReader(data), disallowUnknown, maxBytes)
+	if err := dec.Decode(out); err != nil {
+		return wrapDecodeError(err, data)
+	}
 	if requireEOF {
 		if err := requireNoTrailing(dec); err != nil {
 			return err
@@ -49,7 +153,43 @@ func decodeBytes(data []byte, out any, disallowUnknown, requireEOF bool) error {
 	return nil
 }
 
-func newDecoder(r io.Reader, disallowUnknown bool) *json.Decoder {
+// wrapDecodeError adds the "decode JSON:" prefix and, for syntax and type errors, where the error occurred: the byte
+// offset (plus line and column when data, the decoded input, is given) and, for type errors, the field and the
+// expected Go type.
+func wrapDecodeError(err error, data []byte) error {
+	var synErr *json.SyntaxError
+	if errors.As(err, &synErr) {
+		return fmt.Errorf("decode JSON: %s: %w", describeOffset(data, synErr.Offset), err)
+	}
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) {
+		field := typeErr.Field
+		if field == "" {
+			field = "(root)"
+		}
+		return fmt.Errorf("decode JSON: %s: field %q expects %s, got JSON %s: %w",
+			describeOffset(data, typeErr.Offset), field, typeErr.Type, typeErr.Value, err)
+	}
+	return fmt.Errorf("decode JSON: %w", err)
+}
+
+// describeOffset formats a decoder offset (bytes read before the error) as a position in data.
+func describeOffset(data []byte, offset int64) string {
+	if data == nil || offset <= 0 {
+		return fmt.Sprintf("offset %d", offset)
+	}
+	before := data[:min(offset-1, int64(len(data)))]
+	line := 1 + bytes.Count(before, []byte{'\n'})
+	col := len(before) - bytes.LastIndexByte(before, '\n')
+	return fmt.Sprintf("offset %d (line %d, column %d)", offset, line, col)
+}
+
+// newDecoder returns a decoder over r. If maxBytes > 0, reading more than maxBytes from r fails with
+// ErrJSONTooLarge.
+func newDecoder(r io.Reader, disallowUnknown bool, maxBytes int64) *json.Decoder {
+	if maxBytes > 0 {
+		r = &limitedReader{r: r, n: maxBytes}
+	}
 	dec := json.NewDecoder(r)
 	if disallowUnknown {
 		dec.DisallowUnknownFields()
@@ -68,6 +208,90 @@ func requireNoTrailing(dec *json.Decoder) error {
 	return nil
 }
 
+// checkJSONLimits rejects data longer than maxBytes or with arrays/objects nested deeper than maxDepth. It only
+// tracks brackets outside strings, so it is cheap, never recurses, and leaves syntax errors to the decoder.
+func checkJSONLimits(data []byte, maxDepth int, maxBytes int64) error {
+	if maxBytes > 0 && int64(len(data)) > maxBytes {
+		return fmt.Errorf("%w (%d bytes; max %d)", ErrJSONTooLarge, len(data), maxBytes)
+	}
+	if maxDepth <= 0 {
+		return nil
+	}
+	var ds depthScanner
+	return ds.scan(data, maxDepth)
+}
+
+// depthScanner tracks array/object nesting across successive chunks of JSON text.
+type depthScanner struct {
+	depth    int
+	inString bool
+	escaped  bool
+}
+
+func (s *depthScanner) scan(data []byte, maxDepth int) error {
+	for _, c := range data {
+		switch {
+		case s.escaped:
+			s.escaped = false
+		case s.inString:
+			switch c {
+			case '\\':
+				s.escaped = true
+			case '"':
+				s.inString = false
+			}
+		case c == '"':
+			s.inString = true
+		case c == '[' || c == '{':
+			s.depth++
+			if s.depth > maxDepth {
+				return fmt.Errorf("%w (max depth %d)", ErrJSONTooDeep, maxDepth)
+			}
+		case c == ']' || c == '}':
+			s.depth--
+		}
+	}
+	return nil
+}
+
+// depthReader fails with ErrJSONTooDeep once the JSON text read through it nests deeper than maxDepth.
+type depthReader struct {
+	r        io.Reader
+	maxDepth int
+	ds       depthScanner
+}
+
+func (d *depthReader) Read(p []byte) (int, error) {
+	n, err := d.r.Read(p)
+	if serr := d.ds.scan(p[:n], d.maxDepth); serr != nil {
+		return 0, serr
+	}
+	return n, err
+}
+
+// limitedReader is like io.LimitedReader but reports ErrJSONTooLarge instead of EOF when more data remains.
+type limitedReader struct {
+	r io.Reader
+	n int64
+}
+
+func (l *limitedReader) Read(p []byte) (int, error) {
+	if l.n <= 0 {
+		var probe [1]byte
+		n, err := l.r.Read(probe[:])
+		if n > 0 {
+			return 0, ErrJSONTooLarge
+		}
+		return 0, err
+	}
+	if int64(len(p)) > l.n {
+		p = p[:l.n]
+	}
+	n, err := l.r.Read(p)
+	l.n -= int64(n)
+	return n, err
+}
+
 func isBlankJSON(b []byte) bool {
 	return len(bytes.TrimSpace(b)) == 0
 }
@@ -3,9 +3,12 @@ package jsonutil
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
+	"io"
 	"reflect"
 	"strings"
 	"testing"
+	"testing/iotest"
 )
 
 type person struct {
@@ -68,6 +71,72 @@ func TestEncodeToJSONRaw(t *testing.T) {
 	}
 }
 
+func TestEncodeJSONTo(t *testing.T) {
+	t.Parallel()
+
+	type page struct {
+		URL  string `json:"url"`
+		HTML string `json:"html"`
+	}
+
+	tests := []struct {
+		name       string
+		in         any
+		want       string
+		wantErrSub string
+	}{
+		{
+			name: "html and url characters are not escaped",
+			in:   page{URL: "https://x.test/?a=1&b=2", HTML: "<b>bold</b>"},
+			want: `{"url":"https://x.test/?a=1&b=2","html":"<b>bold</b>"}` + "\n",
+		},
+		{name: "scalar", in: "a&b", want: `"a&b"` + "\n"},
+		{name: "null", in: nil, want: "null\n"},
+		{name: "raw message is compacted", in: json.RawMessage(`{ "a" : "&" }`), want: `{"a":"&"}` + "\n"},
+		{name: "unsupported value", in: make(chan int), wantErrSub: "encode JSON:"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			var buf bytes.Buffer
+			err := EncodeJSONTo(&buf, tt.in)
+			if tt.wantErrSub != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErrSub) {
+					t.Fatalf("expected error containing %q, got %v", tt.wantErrSub, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got := buf.String(); got != tt.want {
+				t.Fatalf("got %q want %q", got, tt.want)
+			}
+		})
+	}
+
+	t.Run("EncodeToJSONRaw still escapes", func(t *testing.T) {
+		t.Parallel()
+		raw, err := EncodeToJSONRaw("a&b")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if string(raw) != `"a\u0026b"` {
+			t.Fatalf("got %s", raw)
+		}
+	})
+
+	t.Run("writer error", func(t *testing.T) {
+		t.Parallel()
+		pr, pw := io.Pipe()
+		_ = pr.CloseWithError(errors.New("closed"))
+		if err := EncodeJSONTo(pw, "x"); err == nil || !strings.Contains(err.Error(), "closed") {
+			t.Fatalf("expected writer error, got %v", err)
+		}
+	})
+}
+
 func TestDecodeJSONRaw_BlankReturnsZero(t *testing.T) {
 	t.Parallel()
 
@@ -294,7 +363,7 @@ func TestDecodeBytes_Options(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			t.Parallel()
 			var got person
-			err := decodeBytes(tt.data, &got, tt.disallowUnknown, tt.requireEOF)
+			err := decodeBytes(tt.data, &got, tt.disallowUnknown, tt.requireEOF, 0, 0)
 			if tt.wantErrSub != "" {
 				if err == nil {
 					t.Fatalf("expected error containing %q, got nil", tt.wantErrSub)
@@ -315,6 +384,335 @@ func TestDecodeBytes_Options(t *testing.T) {
 	}
 }
 
+func TestDecodeJSONRawOpts(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		raw        json.RawMessage
+		opts       DecodeOptions
+		want       person
+		wantErrSub string
+	}{
+		{
+			name:       "default_rejects_unknown",
+			raw:        json.RawMessage(`{"name":"a","age":1,"extra":true}`),
+			wantErrSub: `decode JSON:`,
+		},
+		{
+			name: "allowUnknown_accepts_unknown",
+			raw:  json.RawMessage(`{"name":"a","age":1,"extra":true}`),
+			opts: DecodeOptions{AllowUnknown: true},
+			want: person{Name: "a", Age: 1},
+		},
+		{
+			name:       "allowUnknown_still_rejects_trailing",
+			raw:        json.RawMessage(`{"name":"a","age":1} {"name":"b","age":2}`),
+			opts:       DecodeOptions{AllowUnknown: true},
+			wantErrSub: `trailing data`,
+		},
+		{
+			name: "allowTrailing_accepts_trailing",
+			raw:  json.RawMessage(`{"name":"a","age":1} {`),
+			opts: DecodeOptions{AllowTrailing: true},
+			want: person{Name: "a", Age: 1},
+		},
+		{
+			name:       "allowTrailing_still_rejects_unknown",
+			raw:        json.RawMessage(`{"name":"a","age":1,"extra":true} {}`),
+			opts:       DecodeOptions{AllowTrailing: true},
+			wantErrSub: `decode JSON:`,
+		},
+		{
+			name: "both_relaxed",
+			raw:  json.RawMessage(`{"name":"a","age":1,"extra":{"x":1}} trailing`),
+			opts: DecodeOptions{AllowUnknown: true, AllowTrailing: true},
+			want: person{Name: "a", Age: 1},
+		},
+		{
+			name: "blank_returns_zero",
+			raw:  json.RawMessage(" \n "),
+			opts: DecodeOptions{AllowUnknown: true},
+		},
+		{
+			name:       "type_mismatch_still_errors",
+			raw:        json.RawMessage(`{"name":1}`),
+			opts:       DecodeOptions{AllowUnknown: true, AllowTrailing: true},
+			wantErrSub: `decode JSON:`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := DecodeJSONRawOpts[person](tt.raw, tt.opts)
+			if tt.wantErrSub != "" {
+				if err == nil {
+					t.Fatalf("expected error containing %q, got nil", tt.wantErrSub)
+				}
+				if !strings.Contains(err.Error(), tt.wantErrSub) {
+					t.Fatalf("expected error containing %q, got %q", tt.wantErrSub, err.Error())
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Fatalf("unexpected value.\nwant: %#v\ngot:  %#v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestDecodeLimits(t *testing.T) {
+	t.Parallel()
+
+	deep := strings.Repeat("[", 10000) + strings.Repeat("]", 10000)
+	atLimit := strings.Repeat("[", DefaultMaxJSONDepth) + strings.Repeat("]", DefaultMaxJSONDepth)
+	bracketsInString := `"` + strings.Repeat(`[{\"`, 5000) + `"`
+
+	tests := []struct {
+		name    string
+		raw     string
+		opts    DecodeOptions
+		wantErr error
+	}{
+		{name: "10k deep array", raw: deep, wantErr: ErrJSONTooDeep},
+		{name: "depth at default limit", raw: atLimit},
+		{name: "custom depth", raw: `{"a":{"b":[1]}}`, opts: DecodeOptions{MaxDepth: 2}, wantErr: ErrJSONTooDeep},
+		{name: "brackets inside strings are not nesting", raw: bracketsInString, opts: DecodeOptions{MaxDepth: 1}},
+		{name: "depth check disabled", raw: `[[[1]]]`, opts: DecodeOptions{MaxDepth: -1}},
+		{
+			name:    "oversized payload",
+			raw:     `{"name":"` + strings.Repeat("x", 64) + `"}`,
+			opts:    DecodeOptions{MaxBytes: 32},
+			wantErr: ErrJSONTooLarge,
+		},
+		{name: "payload at size limit", raw: `"abc"`, opts: DecodeOptions{MaxBytes: 5}},
+		{name: "size check disabled", raw: `"` + strings.Repeat("x", 64) + `"`, opts: DecodeOptions{MaxBytes: -1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			_, err := DecodeJSONRawOpts[any](json.RawMessage(tt.raw), tt.opts)
+			if tt.wantErr == nil {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+
+	t.Run("strict default decoder", func(t *testing.T) {
+		t.Parallel()
+		if _, err := DecodeJSONRaw[any](json.RawMessage(deep)); !errors.Is(err, ErrJSONTooDeep) {
+			t.Fatalf("expected ErrJSONTooDeep, got %v", err)
+		}
+	})
+
+	t.Run("stream decoder over the byte limit", func(t *testing.T) {
+		t.Parallel()
+		dec := newDecoder(strings.NewReader(`{"name":"`+strings.Repeat("x", 64)+`"}`), false, 16)
+		var out person
+		if err := dec.Decode(&out); !errors.Is(err, ErrJSONTooLarge) {
+			t.Fatalf("expected ErrJSONTooLarge, got %v", err)
+		}
+	})
+
+	t.Run("stream decoder within the byte limit", func(t *testing.T) {
+		t.Parallel()
+		in := `{"name":"a","age":1}`
+		dec := newDecoder(strings.NewReader(in), true, int64(len(in)))
+		var out person
+		if err := dec.Decode(&out); err != nil || out != (person{Name: "a", Age: 1}) {
+			t.Fatalf("got %#v, %v", out, err)
+		}
+	})
+}
+
+func TestDecodeReader(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		r          func() io.Reader
+		opts       DecodeOptions
+		want       person
+		wantErrSub string
+		wantErrIs  error
+	}{
+		{
+			name: "one byte at a time",
+			r:    func() io.Reader { return iotest.OneByteReader(strings.NewReader(`{"name":"a","age":1}`)) },
+			want: person{Name: "a", Age: 1},
+		},
+		{
+			name: "streamed through a pipe",
+			r: func() io.Reader {
+				pr, pw := io.Pipe()
+				go func() {
+					for _, chunk := range []string{`{"na`, `me":"b",`, ` "age"`, `:2}`, "\n"} {
+						_, _ = pw.Write([]byte(chunk))
+					}
+					_ = pw.Close()
+				}()
+				return pr
+			},
+			want: person{Name: "b", Age: 2},
+		},
+		{name: "empty reader", r: func() io.Reader { return strings.NewReader("") }},
+		{name: "whitespace reader", r: func() io.Reader { return strings.NewReader(" \n\t ") }},
+		{
+			name:       "trailing data",
+			r:          func() io.Reader { return strings.NewReader(`{"name":"a","age":1} {"name":"b"}`) },
+			wantErrSub: "trailing data",
+		},
+		{
+			name: "trailing data allowed",
+			r:    func() io.Reader { return strings.NewReader(`{"name":"a","age":1} {`) },
+			opts: DecodeOptions{AllowTrailing: true},
+			want: person{Name: "a", Age: 1},
+		},
+		{
+			name:       "unknown field",
+			r:          func() io.Reader { return strings.NewReader(`{"name":"a","extra":1}`) },
+			wantErrSub: "decode JSON:",
+		},
+		{
+			name: "unknown field allowed",
+			r:    func() io.Reader { return strings.NewReader(`{"name":"a","extra":1}`) },
+			opts: DecodeOptions{AllowUnknown: true},
+			want: person{Name: "a"},
+		},
+		{
+			name:       "truncated value",
+			r:          func() io.Reader { return strings.NewReader(`{"name":"a"`) },
+			wantErrIs:  io.ErrUnexpectedEOF,
+			wantErrSub: "decode JSON:",
+		},
+		{
+			name:      "too large",
+			r:         func() io.Reader { return strings.NewReader(`{"name":"` + strings.Repeat("x", 1024) + `"}`) },
+			opts:      DecodeOptions{MaxBytes: 64},
+			wantErrIs: ErrJSONTooLarge,
+		},
+		{
+			name: "too deep",
+			r: func() io.Reader {
+				return iotest.HalfReader(strings.NewReader(strings.Repeat("[", 10000) + strings.Repeat("]", 10000)))
+			},
+			wantErrIs: ErrJSONTooDeep,
+		},
+		{
+			name: "reader error",
+			r: func() io.Reader {
+				return io.MultiReader(strings.NewReader(`{"na`), iotest.ErrReader(errors.New("boom")))
+			},
+			wantErrSub: "boom",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := DecodeReader[person](tt.r(), tt.opts)
+			if tt.wantErrIs != nil || tt.wantErrSub != "" {
+				if err == nil {
+					t.Fatalf("expected error, got nil (value %#v)", got)
+				}
+				if tt.wantErrIs != nil && !errors.Is(err, tt.wantErrIs) {
+					t.Fatalf("expected %v, got %v", tt.wantErrIs, err)
+				}
+				if !strings.Contains(err.Error(), tt.wantErrSub) {
+					t.Fatalf("expected error containing %q, got %q", tt.wantErrSub, err.Error())
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Fatalf("unexpected value.\nwant: %#v\ngot:  %#v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestDecodeJSONPrefix(t *testing.T) {
+	t.Parallel()
+
+	data := []byte(`{"a":1}{"b":2}`)
+	first, rest, err := DecodeJSONPrefix[map[string]int](data)
+	if err != nil {
+		t.Fatalf("first decode: %v", err)
+	}
+	if !reflect.DeepEqual(first, map[string]int{"a": 1}) || string(rest) != `{"b":2}` {
+		t.Fatalf("first = %v, rest = %q", first, rest)
+	}
+	second, rest, err := DecodeJSONPrefix[map[string]int](rest)
+	if err != nil {
+		t.Fatalf("second decode: %v", err)
+	}
+	if !reflect.DeepEqual(second, map[string]int{"b": 2}) || len(rest) != 0 {
+		t.Fatalf("second = %v, rest = %q", second, rest)
+	}
+	if _, _, err := DecodeJSONPrefix[map[string]int](rest); !errors.Is(err, io.EOF) {
+		t.Fatalf("decode of empty rest: got %v, want io.EOF", err)
+	}
+
+	tests := []struct {
+		name       string
+		data       string
+		want       person
+		wantRest   string
+		wantErrSub string
+		wantErrIs  error
+	}{
+		{
+			name:     "whitespace kept in rest",
+			data:     ` {"name":"a"}` + "\n" + `{"name":"b"}`,
+			want:     person{Name: "a"},
+			wantRest: "\n" + `{"name":"b"}`,
+		},
+		{name: "scalar followed by value", data: `{"age":3} 7`, want: person{Age: 3}, wantRest: " 7"},
+		{name: "blank is EOF", data: " \n ", wantErrIs: io.EOF},
+		{name: "unknown field", data: `{"nope":1}{}`, wantErrSub: "unknown field"},
+		{name: "syntax error", data: `{"name":}`, wantErrSub: "decode JSON"},
+		{name: "too deep", data: strings.Repeat("[", DefaultMaxJSONDepth+1), wantErrIs: ErrJSONTooDeep},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			got, rest, err := DecodeJSONPrefix[person]([]byte(tc.data))
+			if tc.wantErrSub != "" || tc.wantErrIs != nil {
+				if err == nil {
+					t.Fatalf("expected error, got %+v", got)
+				}
+				if tc.wantErrSub != "" && !strings.Contains(err.Error(), tc.wantErrSub) {
+					t.Fatalf("error %q does not contain %q", err, tc.wantErrSub)
+				}
+				if tc.wantErrIs != nil && !errors.Is(err, tc.wantErrIs) {
+					t.Fatalf("expected errors.Is(%v), got %v", tc.wantErrIs, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tc.want || string(rest) != tc.wantRest {
+				t.Fatalf("got %+v rest %q, want %+v rest %q", got, rest, tc.want, tc.wantRest)
+			}
+		})
+	}
+}
+
 func TestRequireNoTrailing(t *testing.T) {
 	t.Parallel()
 
@@ -417,7 +815,7 @@ func TestNewDecoder_DisallowUnknownFieldsBehavior(t *testing.T) {
 	t.Run("disallowUnknown_true_rejects_unknown_fields", func(t *testing.T) {
 		t.Parallel()
 
-		dec := newDecoder(bytes.NewReader(input), true)
+		dec := newDecoder(bytes.NewReader(input), true, 0)
 		var out onlyA
 		err := dec.Decode(&out)
 		if err == nil {
@@ -431,7 +829,7 @@ func TestNewDecoder_DisallowUnknownFieldsBehavior(t *testing.T) {
 	t.Run("disallowUnknown_false_allows_unknown_fields", func(t *testing.T) {
 		t.Parallel()
 
-		dec := newDecoder(bytes.NewReader(input), false)
+		dec := newDecoder(bytes.NewReader(input), false, 0)
 		var out onlyA
 		err := dec.Decode(&out)
 		if err != nil {
@@ -461,7 +859,7 @@ func TestDecodeBytes_ErrorWrapping(t *testing.T) {
 	t.Parallel()
 
 	var out person
-	err := decodeBytes([]byte(`{"name":`), &out, true, true)
+	err := decodeBytes([]byte(`{"name":`), &out, true, true, 0, 0)
 	if err == nil {
 		t.Fatal("expected error, got nil")
 	}
@@ -469,3 +867,89 @@ func TestDecodeBytes_ErrorWrapping(t *testing.T) {
 		t.Fatalf("expected wrapped error containing %q, got %q", "decode JSON:", err.Error())
 	}
 }
+
+func TestDecodeBytes_ErrorLocation(t *testing.T) {
+	t.Parallel()
+
+	type nested struct {
+		A struct {
+			B int `json:"b"`
+		} `json:"a"`
+	}
+
+	tests := []struct {
+		name    string
+		data    string
+		out     func() any
+		wantAll []string
+		wantAs  func(error) bool
+	}{
+		{
+			name:    "invalid token",
+			data:    `{"name": x}`,
+			out:     func() any { return &person{} },
+			wantAll: []string{"decode JSON: offset 10 (line 1, column 10):", "invalid character 'x'"},
+			wantAs:  func(err error) bool { var e *json.SyntaxError; return errors.As(err, &e) && e.Offset == 10 },
+		},
+		{
+			name:    "invalid token on a later line",
+			data:    "{\n  \"name\": \"a\",\n  \"age\": ?\n}",
+			out:     func() any { return &person{} },
+			wantAll: []string{"offset 27 (line 3, column 10):", "invalid character '?'"},
+		},
+		{
+			name:    "wrong field type",
+			data:    `{"name":"a","age":"old"}`,
+			out:     func() any { return &person{} },
+			wantAll: []string{"decode JSON: offset 23", `field "age" expects int, got JSON string`},
+			wantAs: func(err error) bool {
+				var e *json.UnmarshalTypeError
+				return errors.As(err, &e) && e.Field == "age"
+			},
+		},
+		{
+			name:    "nested field type",
+			data:    `{"a":{"b":true}}`,
+			out:     func() any { return &nested{} },
+			wantAll: []string{`field "a.b" expects int, got JSON bool`},
+		},
+		{
+			name:    "root type",
+			data:    `[1]`,
+			out:     func() any { return &person{} },
+			wantAll: []string{`field "(root)" expects jsonutil.person, got JSON array`},
+		},
+		{
+			name:    "unexpected end keeps plain wrapping",
+			data:    `{"name":`,
+			out:     func() any { return &person{} },
+			wantAll: []string{"decode JSON: unexpected EOF"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			err := decodeBytes([]byte(tt.data), tt.out(), true, true, 0, 0)
+			if err == nil {
+				t.Fatal("expected error, got nil")
+			}
+			for _, want := range tt.wantAll {
+				if !strings.Contains(err.Error(), want) {
+					t.Fatalf("expected error containing %q, got %q", want, err.Error())
+				}
+			}
+			if tt.wantAs != nil && !tt.wantAs(err) {
+				t.Fatalf("underlying error not preserved: %v", err)
+			}
+		})
+	}
+
+	t.Run("reader reports offset only", func(t *testing.T) {
+		t.Parallel()
+		_, err := DecodeReader[person](strings.NewReader(`{"name": x}`), DecodeOptions{})
+		if err == nil || !strings.Contains(err.Error(), "decode JSON: offset 10: invalid character") {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+}
@@ -0,0 +1,650 @@
+package jsonutil
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// SchemaViolation is one validation failure, located by a JSON Pointer into the instance.
+type SchemaViolation struct {
+	Pointer string `json:"pointer"`
+	Message string `json:"message"`
+}
+
+// ValidateJSONSchema validates instance against a JSON Schema (draft-07 subset).
+//
+// Supported keywords: type, enum, const, properties, required, additionalProperties,
+// items (schema or tuple), minItems, maxItems, uniqueItems, minLength, maxLength,
+// pattern, minimum, maximum, exclusiveMinimum, exclusiveMaximum, multipleOf,
+// minProperties, maxProperties, allOf, anyOf, oneOf, not, and local $ref ("#/...").
+// Annotation keywords (title, description, default, format, ...) are ignored.
+//
+// A schema that is not valid JSON or uses a supported keyword incorrectly is an
+// error; instance problems are returned as violations, in document order.
+func ValidateJSONSchema(schema, instance []byte) ([]SchemaViolation, error) {
+	node, err := compileSchema(schema)
+	if err != nil {
+		return nil, err
+	}
+
+	var doc any
+	dec := json.NewDecoder(bytes.NewReader(instance))
+	if err := dec.Decode(&doc); err != nil {
+		return []SchemaViolation{{Pointer: "", Message: "invalid JSON: " + err.Error()}}, nil
+	}
+	if dec.More() {
+		return []SchemaViolation{{Pointer: "", Message: "invalid JSON: unexpected trailing data"}}, nil
+	}
+
+	var out []SchemaViolation
+	if err := node.validate(doc, "", &out, 0); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// CheckJSONSchema reports whether schema is a usable JSON Schema (draft-07
+// subset, see ValidateJSONSchema) without validating any instance.
+func CheckJSONSchema(schema []byte) error {
+	_, err := compileSchema(schema)
+	return err
+}
+
+func compileSchema(schema []byte) (*schemaNode, error) {
+	var root any
+	if err := json.Unmarshal(schema, &root); err != nil {
+		return nil, fmt.Errorf("invalid schema: %w", err)
+	}
+	c := &schemaCompiler{root: root, refs: map[string]*schemaNode{}, refChain: map[string]bool{}}
+	node, err := c.compile(root, "#")
+	if err != nil {
+		return nil, fmt.Errorf("invalid schema: %w", err)
+	}
+	return node, nil
+}
+
+// SchemaValidationError aggregates the violations found by ValidateAgainstSchema.
+type SchemaValidationError struct {
+	Violations []SchemaViolation
+}
+
+func (e *SchemaValidationError) Error() string {
+	msgs := make([]string, 0, len(e.Violations))
+	for _, v := range e.Violations {
+		msgs = append(msgs, fmt.Sprintf("%q: %s", v.Pointer, v.Message))
+	}
+	return "schema validation failed: " + strings.Join(msgs, "; ")
+}
+
+// ValidateAgainstSchema is ValidateJSONSchema reporting violations as an error:
+// nil if data is valid, a *SchemaValidationError listing every violation
+// otherwise, or a plain error if the schema itself is invalid. It suits
+// checking tool arguments against Tool.ArgSchema before decoding them.
+func ValidateAgainstSchema(schema, data []byte) error {
+	violations, err := ValidateJSONSchema(schema, data)
+	if err != nil {
+		return err
+	}
+	if len(violations) > 0 {
+		return &SchemaValidationError{Violations: violations}
+	}
+	return nil
+}
+
+type schemaNode struct {
+	always *bool // boolean schema (true/false)
+	ref    *schemaNode
+
+	types       []string
+	enum        []any
+	hasConst    bool
+	constVal    any
+	properties  map[string]*schemaNode
+	required    []string
+	additional  *schemaNode
+	items       *schemaNode
+	itemsTuple  []*schemaNode
+	minItems    *int
+	maxItems    *int
+	uniqueItems bool
+	minLength   *int
+	maxLength   *int
+	pattern     *regexp.Regexp
+	minimum     *float64
+	maximum     *float64
+	exclMin     *float64
+	exclMax     *float64
+	multipleOf  *float64
+	minProps    *int
+	maxProps    *int
+	allOf       []*schemaNode
+	anyOf       []*schemaNode
+	oneOf       []*schemaNode
+	not         *schemaNode
+}
+
+type schemaCompiler struct {
+	root any
+	refs map[string]*schemaNode
+	// refChain holds the references being resolved since the last schema with
+	// real keywords; meeting one again means a $ref cycle that never validates
+	// anything.
+	refChain map[string]bool
+}
+
+// maxSchemaValidationDepth bounds schema nesting followed while validating, so
+// deeply recursive schemas fail with an error instead of exhausting the stack.
+const maxSchemaValidationDepth = 1000
+
+var errSchemaTooDeep = fmt.Errorf("schema validation exceeded maximum depth %d", maxSchemaValidationDepth)
+
+var schemaTypeNames = []string{"null", "boolean", "object", "array", "number", "integer", "string"}
+
+func (c *schemaCompiler) compile(v any, at string) (*schemaNode, error) {
+	switch s := v.(type) {
+	case bool:
+		return &schemaNode{always: &s}, nil
+	case map[string]any:
+		return c.compileObject(s, at)
+	default:
+		return nil, fmt.Errorf("%s: schema must be an object or boolean", at)
+	}
+}
+
+func (c *schemaCompiler) compileObject(s map[string]any, at string) (*schemaNode, error) {
+	n := &schemaNode{}
+	if r, ok := s["$ref"]; ok {
+		ref, ok := r.(string)
+		if !ok {
+			return nil, fmt.Errorf("%s/$ref: must be a string", at)
+		}
+		target, err := c.resolve(ref)
+		if err != nil {
+			return nil, fmt.Errorf("%s/$ref: %w", at, err)
+		}
+		// Draft-07: siblings of $ref are ignored.
+		n.ref = target
+		return n, nil
+	}
+
+	var err error
+	if t, ok := s["type"]; ok {
+		if n.types, err = schemaTypes(t); err != nil {
+			return nil, fmt.Errorf("%s/type: %w", at, err)
+		}
+	}
+	if e, ok := s["enum"]; ok {
+		arr, ok := e.([]any)
+		if !ok || len(arr) == 0 {
+			return nil, fmt.Errorf("%s/enum: must be a non-empty array", at)
+		}
+		n.enum = arr
+	}
+	if cv, ok := s["const"]; ok {
+		n.hasConst, n.constVal = true, cv
+	}
+
+	if p, ok := s["properties"]; ok {
+		props, ok := p.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("%s/properties: must be an object", at)
+		}
+		n.properties = make(map[string]*schemaNode, len(props))
+		for k, ps := range props {
+			if n.properties[k], err = c.compile(ps, at+"/properties/"+escapePointerToken(k)); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if r, ok := s["required"]; ok {
+		arr, ok := r.([]any)
+		if !ok {
+			return nil, fmt.Errorf("%s/required: must be an array of strings", at)
+		}
+		for _, x := range arr {
+			name, ok := x.(string)
+			if !ok {
+				return nil, fmt.Errorf("%s/required: must be an array of strings", at)
+			}
+			n.required = append(n.required, name)
+		}
+	}
+	if a, ok := s["additionalProperties"]; ok {
+		if n.additional, err = c.compile(a, at+"/additionalProperties"); err != nil {
+			return nil, err
+		}
+	}
+
+	if it, ok := s["items"]; ok {
+		if tuple, isArr := it.([]any); isArr {
+			for i, x := range tuple {
+				sub, err := c.compile(x, at+"/items/"+strconv.Itoa(i))
+				if err != nil {
+					return nil, err
+				}
+				n.itemsTuple = append(n.itemsTuple, sub)
+			}
+		} else if n.items, err = c.compile(it, at+"/items"); err != nil {
+			return nil, err
+		}
+	}
+	if u, ok := s["uniqueItems"]; ok {
+		b, ok := u.(bool)
+		if !ok {
+			return nil, fmt.Errorf("%s/uniqueItems: must be a boolean", at)
+		}
+		n.uniqueItems = b
+	}
+
+	for kw, dst := range map[string]**int{
+		"minItems": &n.minItems, "maxItems": &n.maxItems,
+		"minLength": &n.minLength, "maxLength": &n.maxLength,
+		"minProperties": &n.minProps, "maxProperties": &n.maxProps,
+	} {
+		if x, ok := s[kw]; ok {
+			f, ok := x.(float64)
+			if !ok || f < 0 || f != math.Trunc(f) {
+				return nil, fmt.Errorf("%s/%s: must be a non-negative integer", at, kw)
+			}
+			i := int(f)
+			*dst = &i
+		}
+	}
+	for kw, dst := range map[string]**float64{
+		"minimum": &n.minimum, "maximum": &n.maximum,
+		"exclusiveMinimum": &n.exclMin, "exclusiveMaximum": &n.exclMax,
+		"multipleOf": &n.multipleOf,
+	} {
+		if x, ok := s[kw]; ok {
+			f, ok := x.(float64)
+			if !ok {
+				return nil, fmt.Errorf("%s/%s: must be a number", at, kw)
+			}
+			*dst = &f
+		}
+	}
+	if n.multipleOf != nil && *n.multipleOf <= 0 {
+		return nil, fmt.Errorf("%s/multipleOf: must be > 0", at)
+	}
+	if p, ok := s["pattern"]; ok {
+		str, ok := p.(string)
+		if !ok {
+			return nil, fmt.Errorf("%s/pattern: must be a string", at)
+		}
+		if n.pattern, err = regexp.Compile(str); err != nil {
+			return nil, fmt.Errorf("%s/pattern: %w", at, err)
+		}
+	}
+
+	for kw, dst := range map[string]*[]*schemaNode{"allOf": &n.allOf, "anyOf": &n.anyOf, "oneOf": &n.oneOf} {
+		if x, ok := s[kw]; ok {
+			arr, ok := x.([]any)
+			if !ok || len(arr) == 0 {
+				return nil, fmt.Errorf("%s/%s: must be a non-empty array", at, kw)
+			}
+			for i, sub := range arr {
+				cs, err := c.compile(sub, at+"/"+kw+"/"+strconv.Itoa(i))
+				if err != nil {
+					return nil, err
+				}
+				*dst = append(*dst, cs)
+			}
+		}
+	}
+	if x, ok := s["not"]; ok {
+		if n.not, err = c.compile(x, at+"/not"); err != nil {
+			return nil, err
+		}
+	}
+	return n, nil
+}
+
+// resolve compiles the local reference target once; the placeholder registered
+// before compiling lets recursive schemas refer to themselves.
+func (c *schemaCompiler) resolve(ref string) (*schemaNode, error) {
+	if c.refChain[ref] {
+		return nil, fmt.Errorf("reference %q is circular without any keywords to validate", ref)
+	}
+	if n, ok := c.refs[ref]; ok {
+		return n, nil
+	}
+	if ref != "#" && !strings.HasPrefix(ref, "#/") {
+		return nil, fmt.Errorf("unsupported reference %q (only local \"#/...\" references are supported)", ref)
+	}
+	tokens, err := ParsePointer(strings.TrimPrefix(ref, "#"))
+	if err != nil {
+		return nil, err
+	}
+	target := c.root
+	for _, tok := range tokens {
+		switch t := target.(type) {
+		case map[string]any:
+			v, ok := t[tok]
+			if !ok {
+				return nil, fmt.Errorf("reference %q not found", ref)
+			}
+			target = v
+		case []any:
+			i, err := strconv.Atoi(tok)
+			if err != nil || i < 0 || i >= len(t) {
+				return nil, fmt.Errorf("reference %q not found", ref)
+			}
+			target = t[i]
+		default:
+			return nil, fmt.Errorf("reference %q not found", ref)
+		}
+	}
+
+	placeholder := &schemaNode{}
+	c.refs[ref] = placeholder
+	// A target that is itself a $ref extends the chain; one with keywords
+	// starts a new one, as recursion through it consumes the instance.
+	chain := c.refChain
+	if obj, ok := target.(map[string]any); ok && obj["$ref"] != nil {
+		c.refChain[ref] = true
+		defer delete(c.refChain, ref)
+	} else {
+		c.refChain = map[string]bool{}
+		defer func() { c.refChain = chain }()
+	}
+	compiled, err := c.compile(target, ref)
+	if err != nil {
+		return nil, err
+	}
+	*placeholder = *compiled
+	return placeholder, nil
+}
+
+func schemaTypes(v any) ([]string, error) {
+	var names []string
+	switch t := v.(type) {
+	case string:
+		names = []string{t}
+	case []any:
+		for _, x := range t {
+			s, ok := x.(string)
+			if !ok {
+				return nil, errors.New("must be a string or array of strings")
+			}
+			names = append(names, s)
+		}
+	default:
+		return nil, errors.New("must be a string or array of strings")
+	}
+	for _, name := range names {
+		if !slices.Contains(schemaTypeNames, name) {
+			return nil, fmt.Errorf("unknown type %q", name)
+		}
+	}
+	return names, nil
+}
+
+// validate appends the violations of v to out. depth counts the schemas
+// entered so far; it only returns an error when maxSchemaValidationDepth is
+// exceeded.
+func (n *schemaNode) validate(v any, ptr string, out *[]SchemaViolation, depth int) error {
+	if depth > maxSchemaValidationDepth {
+		return errSchemaTooDeep
+	}
+	depth++
+	add := func(format string, args ...any) {
+		*out = append(*out, SchemaViolation{Pointer: ptr, Message: fmt.Sprintf(format, args...)})
+	}
+	if n.always != nil {
+		if !*n.always {
+			add("no value is allowed here")
+		}
+		return nil
+	}
+	if n.ref != nil {
+		return n.ref.validate(v, ptr, out, depth)
+	}
+
+	if len(n.types) > 0 && !slices.ContainsFunc(n.types, func(t string) bool { return jsonTypeMatches(t, v) }) {
+		add("expected type %s, got %s", strings.Join(n.types, " or "), jsonTypeName(v))
+		// Remaining keywords assume the right type; stop here to avoid noise.
+		return nil
+	}
+	if n.enum != nil && !slices.ContainsFunc(n.enum, func(e any) bool { return reflect.DeepEqual(e, v) }) {
+		add("value must be one of %s", compactJSON(n.enum))
+	}
+	if n.hasConst && !reflect.DeepEqual(n.constVal, v) {
+		add("value must equal %s", compactJSON(n.constVal))
+	}
+
+	switch x := v.(type) {
+	case map[string]any:
+		if err := n.validateObject(x, ptr, out, depth, add); err != nil {
+			return err
+		}
+	case []any:
+		if err := n.validateArray(x, ptr, out, depth, add); err != nil {
+			return err
+		}
+	case string:
+		l := utf8.RuneCountInString(x)
+		if n.minLength != nil && l < *n.minLength {
+			add("string length %d is less than minLength %d", l, *n.minLength)
+		}
+		if n.maxLength != nil && l > *n.maxLength {
+			add("string length %d is greater than maxLength %d", l, *n.maxLength)
+		}
+		if n.pattern != nil && !n.pattern.MatchString(x) {
+			add("string does not match pattern %q", n.pattern.String())
+		}
+	case float64:
+		if n.minimum != nil && x < *n.minimum {
+			add("value %v is less than minimum %v", x, *n.minimum)
+		}
+		if n.maximum != nil && x > *n.maximum {
+			add("value %v is greater than maximum %v", x, *n.maximum)
+		}
+		if n.exclMin != nil && x <= *n.exclMin {
+			add("value %v must be greater than %v", x, *n.exclMin)
+		}
+		if n.exclMax != nil && x >= *n.exclMax {
+			add("value %v must be less than %v", x, *n.exclMax)
+		}
+		if n.multipleOf != nil {
+			if q := x / *n.multipleOf; math.Abs(q-math.Round(q)) > 1e-9 {
+				add("value %v is not a multiple of %v", x, *n.multipleOf)
+			}
+		}
+	}
+
+	for i, sub := range n.allOf {
+		var subOut []SchemaViolation
+		if err := sub.validate(v, ptr, &subOut, depth); err != nil {
+			return err
+		}
+		if len(subOut) > 0 {
+			add("value does not match allOf[%d]: %s", i, subOut[0].Message)
+		}
+	}
+	if n.anyOf != nil {
+		m, err := countMatches(n.anyOf, v, ptr, depth)
+		if err != nil {
+			return err
+		}
+		if m == 0 {
+			add("value does not match any schema in anyOf")
+		}
+	}
+	if n.oneOf != nil {
+		m, err := countMatches(n.oneOf, v, ptr, depth)
+		if err != nil {
+			return err
+		}
+		if m != 1 {
+			add("value must match exactly one schema in oneOf (matched %d)", m)
+		}
+	}
+	if n.not != nil {
+		m, err := countMatches([]*schemaNode{n.not}, v, ptr, depth)
+		if err != nil {
+			return err
+		}
+		if m == 1 {
+			add("value must not match the schema in not")
+		}
+	}
+	return nil
+}
+
+func (n *schemaNode) validateObject(
+	x map[string]any,
+	ptr string,
+	out *[]SchemaViolation,
+	depth int,
+	add func(string, ...any),
+) error {
+	for _, r := range n.required {
+		if _, ok := x[r]; !ok {
+			add("missing required property %q", r)
+		}
+	}
+	if n.minProps != nil && len(x) < *n.minProps {
+		add("object has %d properties, fewer than minProperties %d", len(x), *n.minProps)
+	}
+	if n.maxProps != nil && len(x) > *n.maxProps {
+		add("object has %d properties, more than maxProperties %d", len(x), *n.maxProps)
+	}
+
+	keys := make([]string, 0, len(x))
+	for k := range x {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	for _, k := range keys {
+		childPtr := ptr + "/" + escapePointerToken(k)
+		if ps, ok := n.properties[k]; ok {
+			if err := ps.validate(x[k], childPtr, out, depth); err != nil {
+				return err
+			}
+			continue
+		}
+		if n.additional == nil {
+			continue
+		}
+		if n.additional.always != nil && !*n.additional.always {
+			*out = append(*out, SchemaViolation{Pointer: childPtr, Message: fmt.Sprintf("additional property %q is not allowed", k)})
+			continue
+		}
+		if err := n.additional.validate(x[k], childPtr, out, depth); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (n *schemaNode) validateArray(
+	x []any,
+	ptr string,
+	out *[]SchemaViolation,
+	depth int,
+	add func(string, ...any),
+) error {
+	if n.minItems != nil && len(x) < *n.minItems {
+		add("array has %d items, fewer than minItems %d", len(x), *n.minItems)
+	}
+	if n.maxItems != nil && len(x) > *n.maxItems {
+		add("array has %d items, more than maxItems %d", len(x), *n.maxItems)
+	}
+	if n.uniqueItems {
+	outer:
+		for i := range x {
+			for j := range i {
+				if reflect.DeepEqual(x[i], x[j]) {
+					add("array items %d and %d are equal (uniqueItems)", j, i)
+					break outer
+				}
+			}
+		}
+	}
+	for i, item := range x {
+		childPtr := ptr + "/" + strconv.Itoa(i)
+		var sub *schemaNode
+		switch {
+		case n.itemsTuple != nil:
+			if i < len(n.itemsTuple) {
+				sub = n.itemsTuple[i]
+			}
+		case n.items != nil:
+			sub = n.items
+		}
+		if sub == nil {
+			continue
+		}
+		if err := sub.validate(item, childPtr, out, depth); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func countMatches(nodes []*schemaNode, v any, ptr string, depth int) (int, error) {
+	m := 0
+	for _, sub := range nodes {
+		var subOut []SchemaViolation
+		if err := sub.validate(v, ptr, &subOut, depth); err != nil {
+			return 0, err
+		}
+		if len(subOut) == 0 {
+			m++
+		}
+	}
+	return m, nil
+}
+
+func jsonTypeMatches(t string, v any) bool {
+	switch t {
+	case "integer":
+		f, ok := v.(float64)
+		return ok && f == math.Trunc(f) && !math.IsInf(f, 0)
+	case "number":
+		_, ok := v.(float64)
+		return ok
+	default:
+		return jsonTypeName(v) == t
+	}
+}
+
+func jsonTypeName(v any) string {
+	switch v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case map[string]any:
+		return "object"
+	case []any:
+		return "array"
+	case float64:
+		return "number"
+	case string:
+		return "string"
+	default:
+		return fmt.Sprintf("%T", v)
+	}
+}
+
+func compactJSON(v any) string {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(b)
+}
+
+func escapePointerToken(s string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(s, "~", "~0"), "/", "~1")
+}
@@ -0,0 +1,283 @@
+package jsonutil
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestValidateJSONSchema(t *testing.T) {
+	t.Parallel()
+
+	const objSchema = `{
+		"type": "object",
+		"properties": {
+			"name": {"type": "string", "minLength": 2, "pattern": "^[a-z]+$"},
+			"port": {"type": "integer", "minimum": 1, "maximum": 65535},
+			"mode": {"enum": ["dev", "prod"]},
+			"tags": {"type": "array", "items": {"type": "string"}, "uniqueItems": true, "maxItems": 3},
+			"child": {"$ref": "#"}
+		},
+		"required": ["name", "port"],
+		"additionalProperties": false
+	}`
+
+	tests := []struct {
+		name      string
+		schema    string
+		instance  string
+		want      []SchemaViolation
+		errSubstr string
+	}{
+		{
+			name:     "valid object",
+			schema:   objSchema,
+			instance: `{"name": "api", "port": 8080, "mode": "dev", "tags": ["a", "b"]}`,
+		},
+		{
+			name:     "multiple violations in document order",
+			schema:   objSchema,
+			instance: `{"name": "A", "port": 0.5, "mode": "test", "extra": 1, "tags": ["a", "a", 3]}`,
+			want: []SchemaViolation{
+				{Pointer: "/extra", Message: `additional property "extra" is not allowed`},
+				{Pointer: "/mode", Message: `value must be one of ["dev","prod"]`},
+				{Pointer: "/name", Message: "string length 1 is less than minLength 2"},
+				{Pointer: "/name", Message: `string does not match pattern "^[a-z]+$"`},
+				{Pointer: "/port", Message: "expected type integer, got number"},
+				{Pointer: "/tags", Message: "array items 0 and 1 are equal (uniqueItems)"},
+				{Pointer: "/tags/2", Message: "expected type string, got number"},
+			},
+		},
+		{
+			name:     "recursive ref",
+			schema:   objSchema,
+			instance: `{"name": "ab", "port": 1, "child": {"name": "cd"}}`,
+			want:     []SchemaViolation{{Pointer: "/child", Message: `missing required property "port"`}},
+		},
+		{
+			name:     "root type mismatch",
+			schema:   `{"type": ["object", "null"]}`,
+			instance: `[1]`,
+			want:     []SchemaViolation{{Pointer: "", Message: "expected type object or null, got array"}},
+		},
+		{
+			name:     "combinators",
+			schema:   `{"oneOf": [{"type": "integer"}, {"minimum": 0}], "not": {"const": 7}}`,
+			instance: `3`,
+			want: []SchemaViolation{
+				{Pointer: "", Message: "value must match exactly one schema in oneOf (matched 2)"},
+			},
+		},
+		{
+			name:     "definitions ref and anyOf",
+			schema:   `{"definitions": {"pos": {"exclusiveMinimum": 0}}, "anyOf": [{"$ref": "#/definitions/pos"}, {"type": "string"}]}`,
+			instance: `-1`,
+			want:     []SchemaViolation{{Pointer: "", Message: "value does not match any schema in anyOf"}},
+		},
+		{
+			name:     "invalid instance JSON is a violation",
+			schema:   `{}`,
+			instance: `{"a":`,
+			want:     []SchemaViolation{{Pointer: "", Message: "invalid JSON: unexpected EOF"}},
+		},
+		{name: "schema not JSON", schema: `{`, instance: `1`, errSubstr: "invalid schema"},
+		{name: "schema bad type name", schema: `{"type": "float"}`, instance: `1`, errSubstr: `unknown type "float"`},
+		{name: "schema bad required", schema: `{"required": "a"}`, instance: `{}`, errSubstr: "/required"},
+		{name: "schema bad pattern", schema: `{"pattern": "("}`, instance: `""`, errSubstr: "/pattern"},
+		{name: "schema remote ref", schema: `{"$ref": "http://x/y.json"}`, instance: `1`, errSubstr: "unsupported reference"},
+		{name: "schema missing ref", schema: `{"$ref": "#/definitions/nope"}`, instance: `1`, errSubstr: "not found"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := ValidateJSONSchema([]byte(tt.schema), []byte(tt.instance))
+			if tt.errSubstr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.errSubstr) {
+					t.Fatalf("expected error containing %q, got %v", tt.errSubstr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("violations mismatch:\ngot:  %+v\nwant: %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckJSONSchema(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		schema  string
+		wantErr bool
+	}{
+		{name: "object schema", schema: `{"type": "object", "properties": {"a": {"type": "string"}}}`},
+		{name: "boolean schema", schema: `true`},
+		{name: "not JSON", schema: `{"type":`, wantErr: true},
+		{name: "unknown type", schema: `{"type": "obj"}`, wantErr: true},
+		{name: "bad keyword value", schema: `{"minLength": "2"}`, wantErr: true},
+		{name: "not a schema", schema: `[]`, wantErr: true},
+		{
+			name:    "self-referencing ref only",
+			schema:  `{"definitions":{"a":{"$ref":"#/definitions/a"}},"$ref":"#/definitions/a"}`,
+			wantErr: true,
+		},
+		{name: "root ref to itself", schema: `{"$ref":"#"}`, wantErr: true},
+		{
+			name:    "ref cycle through two definitions",
+			schema:  `{"definitions":{"a":{"$ref":"#/definitions/b"},"b":{"$ref":"#/definitions/a"}},"$ref":"#/definitions/a"}`,
+			wantErr: true,
+		},
+		{
+			name:   "recursive schema with keywords",
+			schema: `{"definitions":{"n":{"type":"object","properties":{"next":{"$ref":"#/definitions/n"}}}},"$ref":"#/definitions/n"}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			err := CheckJSONSchema([]byte(tt.schema))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("CheckJSONSchema(%s): err=%v, wantErr=%v", tt.schema, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateAgainstSchema(t *testing.T) {
+	t.Parallel()
+
+	const argSchema = `{
+"$schema": "http://json-schema.org/draft-07/schema#",
+"type": "object",
+"properties": {
+	"path": { "type": "string" },
+	"limit": { "type": "integer", "minimum": 1 },
+	"opts": {
+		"type": "object",
+		"properties": { "recursive": { "type": "boolean" } },
+		"additionalProperties": false
+	}
+},
+"required": ["path"],
+"additionalProperties": false
+}`
+
+	tests := []struct {
+		name          string
+		schema        string
+		data          string
+		want          []SchemaViolation
+		wantSchemaErr bool
+	}{
+		{name: "valid", schema: argSchema, data: `{"path":"/tmp","limit":2,"opts":{"recursive":true}}`},
+		{
+			name:   "missing required field",
+			schema: argSchema,
+			data:   `{"limit":2}`,
+			want:   []SchemaViolation{{Pointer: "", Message: `missing required property "path"`}},
+		},
+		{
+			name:   "wrong type",
+			schema: argSchema,
+			data:   `{"path":"/tmp","limit":"ten"}`,
+			want:   []SchemaViolation{{Pointer: "/limit", Message: "expected type integer, got string"}},
+		},
+		{
+			name:   "unexpected additional property",
+			schema: argSchema,
+			data:   `{"path":"/tmp","opts":{"recursive":true,"depth":3}}`,
+			want:   []SchemaViolation{{Pointer: "/opts/depth", Message: `additional property "depth" is not allowed`}},
+		},
+		{
+			name:   "all failures are aggregated",
+			schema: argSchema,
+			data:   `{"path":1,"limit":0,"extra":true}`,
+			want: []SchemaViolation{
+				{Pointer: "/extra", Message: `additional property "extra" is not allowed`},
+				{Pointer: "/limit", Message: "value 0 is less than minimum 1"},
+				{Pointer: "/path", Message: "expected type string, got number"},
+			},
+		},
+		{name: "invalid schema", schema: `{"type": 3}`, data: `{}`, wantSchemaErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			err := ValidateAgainstSchema([]byte(tt.schema), []byte(tt.data))
+			var verr *SchemaValidationError
+			switch {
+			case tt.wantSchemaErr:
+				if err == nil || errors.As(err, &verr) || !strings.Contains(err.Error(), "invalid schema") {
+					t.Fatalf("expected invalid schema error, got %v", err)
+				}
+				return
+			case tt.want == nil:
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if !errors.As(err, &verr) {
+				t.Fatalf("expected *SchemaValidationError, got %v", err)
+			}
+			if !reflect.DeepEqual(verr.Violations, tt.want) {
+				t.Fatalf("violations:\nwant: %#v\ngot:  %#v", tt.want, verr.Violations)
+			}
+			for _, v := range tt.want {
+				if !strings.Contains(err.Error(), v.Message) {
+					t.Fatalf("error %q does not mention %q", err.Error(), v.Message)
+				}
+			}
+		})
+	}
+}
+
+func TestValidateJSONSchema_DepthLimit(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		schema   string
+		instance string
+		wantErr  bool
+	}{
+		{
+			// allOf recursion never consumes the instance.
+			name:     "allOf self reference",
+			schema:   `{"type":"object","allOf":[{"$ref":"#"}]}`,
+			instance: `{}`,
+			wantErr:  true,
+		},
+		{
+			name:     "deep instance under recursive schema",
+			schema:   `{"type":"object","properties":{"a":{"$ref":"#"}}}`,
+			instance: strings.Repeat(`{"a":`, 2000) + `{}` + strings.Repeat(`}`, 2000),
+			wantErr:  true,
+		},
+		{
+			name:     "shallow instance under recursive schema",
+			schema:   `{"type":"object","properties":{"a":{"$ref":"#"}}}`,
+			instance: `{"a":{"a":{}}}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			_, err := ValidateJSONSchema([]byte(tt.schema), []byte(tt.instance))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err=%v, wantErr=%v", err, tt.wantErr)
+			}
+			if tt.wantErr && !errors.Is(err, errSchemaTooDeep) {
+				t.Fatalf("err=%v, want errSchemaTooDeep", err)
+			}
+		})
+	}
+}
@@ -2,15 +2,43 @@ package logutil
 
 import (
 	"context"
+	"fmt"
+	"io"
 	"log/slog"
+	"os"
+	"runtime"
+	"strconv"
 	"sync"
+	"sync/atomic"
 )
 
 var (
 	mu           sync.RWMutex
 	globalLogger *slog.Logger
+
+	// defaultLevel is the level of globalLogger if it was built with NewLogger, so SetLevel can adjust it at runtime.
+	defaultLevel *slog.LevelVar
+
+	// contextExtractor, if set, supplies attrs appended to records logged with a context.
+	contextExtractor func(context.Context) []slog.Attr
+
+	// strictArgs makes the top-level functions report malformed key/value args.
+	strictArgs atomic.Bool
 )
 
+// Handler formats accepted by NewLogger.
+const (
+	FormatText = "text"
+	FormatJSON = "json"
+)
+
+// Options configures NewLogger.
+type Options struct {
+	Level  slog.Level
+	Format string    // FormatText (default) or FormatJSON
+	Out    io.Writer // defaults to os.Stderr
+}
+
 func init() {
 	// Default to a no-op logger so the library is silent unless a logger is explicitly installed by the caller.
 	globalLogger = slog.New(slog.DiscardHandler)
@@ -23,62 +51,157 @@ func init() {
 // Debug logs at LevelDebug using the logger.
 func Debug(msg string, args ...any) {
 	Default().Debug(msg, args...)
+	checkArgs(context.Background(), msg, args)
 }
 
 // DebugContext logs at LevelDebug with context using the process-wide logger.
 func DebugContext(ctx context.Context, msg string, args ...any) {
-	Default().DebugContext(ctx, msg, args...)
+	l := Default()
+	l.DebugContext(ctx, msg, withContextArgs(ctx, l, slog.LevelDebug, args)...)
+	checkArgs(ctx, msg, args)
 }
 
 // Info logs at LevelInfo using the process-wide logger.
 func Info(msg string, args ...any) {
 	Default().Info(msg, args...)
+	checkArgs(context.Background(), msg, args)
 }
 
 // InfoContext logs at LevelInfo with context using the process-wide logger.
 func InfoContext(ctx context.Context, msg string, args ...any) {
-	Default().InfoContext(ctx, msg, args...)
+	l := Default()
+	l.InfoContext(ctx, msg, withContextArgs(ctx, l, slog.LevelInfo, args)...)
+	checkArgs(ctx, msg, args)
 }
 
 // Warn logs at LevelWarn using the process-wide logger.
 func Warn(msg string, args ...any) {
 	Default().Warn(msg, args...)
+	checkArgs(context.Background(), msg, args)
 }
 
 // WarnContext logs at LevelWarn with context using the process-wide logger.
 func WarnContext(ctx context.Context, msg string, args ...any) {
-	Default().WarnContext(ctx, msg, args...)
+	l := Default()
+	l.WarnContext(ctx, msg, withContextArgs(ctx, l, slog.LevelWarn, args)...)
+	checkArgs(ctx, msg, args)
 }
 
 // Error logs at LevelError using the process-wide logger.
 func Error(msg string, args ...any) {
 	Default().Error(msg, args...)
+	checkArgs(context.Background(), msg, args)
 }
 
 // ErrorContext logs at LevelError with context using the process-wide logger.
 func ErrorContext(ctx context.Context, msg string, args ...any) {
-	Default().ErrorContext(ctx, msg, args...)
+	l := Default()
+	l.ErrorContext(ctx, msg, withContextArgs(ctx, l, slog.LevelError, args)...)
+	checkArgs(ctx, msg, args)
 }
 
 // Log logs at the given level using the process-wide logger.
 // Signature is identical to slog.Log.
 func Log(ctx context.Context, level slog.Level, msg string, args ...any) {
-	Default().Log(ctx, level, msg, args...)
+	l := Default()
+	l.Log(ctx, level, msg, withContextArgs(ctx, l, level, args)...)
+	checkArgs(ctx, msg, args)
 }
 
 // LogAttrs logs at the given level with pre-built attributes using the
 // process-wide logger. Signature is identical to slog.LogAttrs.
 func LogAttrs(ctx context.Context, level slog.Level, msg string, attrs ...slog.Attr) {
-	Default().LogAttrs(ctx, level, msg, attrs...)
+	l := Default()
+	if extract := getContextExtractor(); extract != nil && ctx != nil && l.Enabled(ctx, level) {
+		attrs = append(attrs[:len(attrs):len(attrs)], extract(ctx)...)
+	}
+	l.LogAttrs(ctx, level, msg, attrs...)
 }
 
 // With returns a logger that includes the supplied key/value pairs as
 // attributes, rooted at the process-wide logger.
 // Signature is identical to slog.With.
 func With(args ...any) *slog.Logger {
+	checkArgs(context.Background(), "", args)
 	return Default().With(args...)
 }
 
+// SetContextExtractor installs fn to derive attrs (e.g. a request or trace id) from the context of every *Context,
+// Log and LogAttrs call; they are appended after the call's own attrs. fn is only called for enabled levels and must be
+// safe for concurrent use. A nil fn removes the extractor.
+func SetContextExtractor(fn func(context.Context) []slog.Attr) {
+	mu.Lock()
+	defer mu.Unlock()
+	contextExtractor = fn
+}
+
+func getContextExtractor() func(context.Context) []slog.Attr {
+	mu.RLock()
+	defer mu.RUnlock()
+	return contextExtractor
+}
+
+// withContextArgs appends the extracted context attrs to args, without modifying the caller's slice.
+func withContextArgs(ctx context.Context, l *slog.Logger, level slog.Level, args []any) []any {
+	extract := getContextExtractor()
+	if extract == nil || ctx == nil || !l.Enabled(ctx, level) {
+		return args
+	}
+	attrs := extract(ctx)
+	if len(attrs) == 0 {
+		return args
+	}
+	out := make([]any, 0, len(args)+len(attrs))
+	out = append(out, args...)
+	for _, a := range attrs {
+		out = append(out, a)
+	}
+	return out
+}
+
+// SetStrictArgs toggles strict argument checking. When on, a top-level call whose args are not well-formed key/value
+// pairs (a key without a value, or a key that is neither a string nor a slog.Attr) is followed by a warning record
+// naming the call site, so the misuse surfaces during development. slog still logs such args under "!BADKEY".
+// It is off by default.
+func SetStrictArgs(strict bool) {
+	strictArgs.Store(strict)
+}
+
+// checkArgs emits a warning for malformed args in strict mode. It must be called directly from the exported
+// function so the call site is two frames up.
+func checkArgs(ctx context.Context, msg string, args []any) {
+	if !strictArgs.Load() {
+		return
+	}
+	problem := argsProblem(args)
+	if problem == "" {
+		return
+	}
+	caller := "unknown"
+	if _, file, line, ok := runtime.Caller(2); ok {
+		caller = file + ":" + strconv.Itoa(line)
+	}
+	Default().LogAttrs(ctx, slog.LevelWarn, "malformed log arguments",
+		slog.String("caller", caller), slog.String("problem", problem), slog.String("logMessage", msg))
+}
+
+// argsProblem describes the first way args deviate from slog's key/value convention, or returns "".
+func argsProblem(args []any) string {
+	for i := 0; i < len(args); i++ {
+		switch k := args[i].(type) {
+		case slog.Attr:
+		case string:
+			if i+1 >= len(args) {
+				return fmt.Sprintf("key %q has no value", k)
+			}
+			i++
+		default:
+			return fmt.Sprintf("argument %d: key of type %T is not a string or slog.Attr", i, args[i])
+		}
+	}
+	return ""
+}
+
 // Default returns the current process-wide logger, analogous to slog.Default.
 func Default() *slog.Logger {
 	mu.RLock()
@@ -95,4 +218,64 @@ func SetDefault(logger *slog.Logger) {
 		logger = slog.New(slog.DiscardHandler)
 	}
 	globalLogger = logger
+	defaultLevel = nil
+	if h, ok := logger.Handler().(*leveledHandler); ok {
+		defaultLevel = h.level
+	}
+}
+
+// NewLogger returns a logger writing records at or above opts.Level to opts.Out in the given format; an unknown format
+// falls back to text. Each logger has its own level; once it is installed with SetDefault, SetLevel changes it.
+func NewLogger(opts Options) *slog.Logger {
+	out := opts.Out
+	if out == nil {
+		out = os.Stderr
+	}
+	lv := new(slog.LevelVar)
+	lv.Set(opts.Level)
+	hopts := &slog.HandlerOptions{Level: lv}
+	var h slog.Handler
+	if opts.Format == FormatJSON {
+		h = slog.NewJSONHandler(out, hopts)
+	} else {
+		h = slog.NewTextHandler(out, hopts)
+	}
+	return slog.New(&leveledHandler{Handler: h, level: lv})
+}
+
+// leveledHandler carries the level of a NewLogger logger through WithAttrs/WithGroup, so SetDefault can find it on
+// derived loggers too.
+type leveledHandler struct {
+	slog.Handler
+	level *slog.LevelVar
+}
+
+func (h *leveledHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &leveledHandler{Handler: h.Handler.WithAttrs(attrs), level: h.level}
+}
+
+func (h *leveledHandler) WithGroup(name string) slog.Handler {
+	return &leveledHandler{Handler: h.Handler.WithGroup(name), level: h.level}
+}
+
+// SetLevel changes the minimum level of the process-wide logger if it was built with NewLogger (directly or via With),
+// without replacing its handler, so loggers derived from it keep their attributes and follow the change. It is atomic
+// and safe to call while other goroutines log. Other loggers, including other NewLogger loggers and loggers built
+// elsewhere and installed with SetDefault, keep their own level.
+func SetLevel(l slog.Level) {
+	mu.RLock()
+	defer mu.RUnlock()
+	if defaultLevel != nil {
+		defaultLevel.Set(l)
+	}
+}
+
+// GetLevel reports the level of the process-wide logger if it was built with NewLogger, and slog.LevelInfo otherwise.
+func GetLevel() slog.Level {
+	mu.RLock()
+	defer mu.RUnlock()
+	if defaultLevel != nil {
+		return defaultLevel.Level()
+	}
+	return slog.LevelInfo
 }
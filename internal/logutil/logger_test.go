@@ -1,10 +1,14 @@
 package logutil
 
 import (
+	"bytes"
 	"context"
+	"fmt"
 	"log/slog"
 	"reflect"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"testing"
 )
 
@@ -35,6 +39,307 @@ func TestSetDefault_NilBecomesDiscardAndIsSilent(t *testing.T) {
 	}
 }
 
+func TestNewLogger_SetLevel(t *testing.T) {
+	defer restoreGlobal(t)()
+
+	var buf bytes.Buffer
+	SetDefault(NewLogger(Options{Level: slog.LevelInfo, Out: &buf}))
+
+	Debug("hidden-debug")
+	Info("shown-info")
+	if out := buf.String(); strings.Contains(out, "hidden-debug") || !strings.Contains(out, "msg=shown-info") {
+		t.Fatalf("unexpected output at info level: %q", out)
+	}
+
+	SetLevel(slog.LevelDebug)
+	if GetLevel() != slog.LevelDebug {
+		t.Fatalf("GetLevel()=%v want debug", GetLevel())
+	}
+	Debug("visible-debug", "k", "v")
+	if out := buf.String(); !strings.Contains(out, "level=DEBUG msg=visible-debug k=v") {
+		t.Fatalf("debug message missing after SetLevel(Debug): %q", out)
+	}
+
+	SetLevel(slog.LevelWarn)
+	buf.Reset()
+	Info("hidden-info")
+	Warn("shown-warn")
+	if out := buf.String(); strings.Contains(out, "hidden-info") || !strings.Contains(out, "shown-warn") {
+		t.Fatalf("unexpected output at warn level: %q", out)
+	}
+}
+
+func TestNewLogger_IndependentLevels(t *testing.T) {
+	defer restoreGlobal(t)()
+
+	var defBuf, errBuf bytes.Buffer
+	SetDefault(NewLogger(Options{Level: slog.LevelDebug, Out: &defBuf}))
+	errLogger := NewLogger(Options{Level: slog.LevelError, Out: &errBuf})
+
+	Debug("default-debug")
+	errLogger.Info("hidden-info")
+	errLogger.Error("shown-error")
+	if out := defBuf.String(); !strings.Contains(out, "msg=default-debug") {
+		t.Fatalf("creating another logger changed the default level: %q", out)
+	}
+	if out := errBuf.String(); strings.Contains(out, "hidden-info") || !strings.Contains(out, "msg=shown-error") {
+		t.Fatalf("unexpected output from error-level logger: %q", out)
+	}
+
+	SetLevel(slog.LevelInfo)
+	errLogger.Warn("still-hidden")
+	if GetLevel() != slog.LevelInfo || strings.Contains(errBuf.String(), "still-hidden") {
+		t.Fatalf("SetLevel affected a non-default logger: level=%v out=%q", GetLevel(), errBuf.String())
+	}
+}
+
+func TestSetLevel_ConcurrentWithLogging(t *testing.T) {
+	defer restoreGlobal(t)()
+
+	var out lockedBuffer
+	SetDefault(NewLogger(Options{Level: slog.LevelInfo, Out: &out}))
+	child := With("component", "test")
+
+	var wg sync.WaitGroup
+	stop := make(chan struct{})
+	for i := range 4 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-stop:
+					return
+				default:
+				}
+				Debug("default-debug", "g", i)
+				child.Info("child-info", "g", i)
+			}
+		}()
+	}
+	for i := range 200 {
+		if i%2 == 0 {
+			SetLevel(slog.LevelDebug)
+		} else {
+			SetLevel(slog.LevelError)
+		}
+		_ = GetLevel()
+	}
+	close(stop)
+	wg.Wait()
+
+	SetLevel(slog.LevelError)
+	out.Reset()
+	child.Info("suppressed")
+	Debug("suppressed")
+	if got := out.String(); got != "" {
+		t.Fatalf("expected no output at error level, got %q", got)
+	}
+
+	SetLevel(slog.LevelDebug)
+	child.Debug("child-debug")
+	if got := out.String(); !strings.Contains(got, "msg=child-debug component=test") {
+		t.Fatalf("With() logger lost its attrs or ignored the level: %q", got)
+	}
+}
+
+// lockedBuffer is a bytes.Buffer safe for concurrent use.
+type lockedBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *lockedBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *lockedBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+func (b *lockedBuffer) Reset() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.buf.Reset()
+}
+
+func TestNewLogger_Formats(t *testing.T) {
+
+	tests := []struct {
+		name   string
+		format string
+		want   string
+	}{
+		{name: "json", format: FormatJSON, want: `"msg":"hello","k":1}`},
+		{name: "text", format: FormatText, want: "msg=hello k=1"},
+		{name: "unknown falls back to text", format: "yaml", want: "msg=hello k=1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			NewLogger(Options{Format: tt.format, Out: &buf}).Info("hello", "k", 1)
+			if out := buf.String(); !strings.Contains(out, tt.want) {
+				t.Fatalf("output %q does not contain %q", out, tt.want)
+			}
+		})
+	}
+}
+
+func TestSetContextExtractor(t *testing.T) {
+	defer restoreGlobal(t)()
+	defer SetContextExtractor(nil)
+
+	const reqKey = ctxKey("reqID")
+	var calls atomic.Int32
+	SetContextExtractor(func(ctx context.Context) []slog.Attr {
+		calls.Add(1)
+		if id, ok := ctx.Value(reqKey).(string); ok {
+			return []slog.Attr{slog.String("reqID", id)}
+		}
+		return nil
+	})
+
+	sink := &recordSink{}
+	SetDefault(newCaptureLogger(sink))
+	ctx := context.WithValue(t.Context(), reqKey, "req-42")
+
+	args := []any{"k", "v"}
+	InfoContext(ctx, "info")
+	DebugContext(ctx, "debug", args...)
+	WarnContext(ctx, "warn")
+	ErrorContext(ctx, "error")
+	Log(ctx, slog.LevelInfo, "log", "k", "v")
+	LogAttrs(ctx, slog.LevelInfo, "attrs", slog.String("k", "v"))
+	if len(args) != 2 {
+		t.Fatalf("caller args modified: %v", args)
+	}
+
+	entries := sink.snapshot()
+	if len(entries) != 6 {
+		t.Fatalf("expected 6 records, got %d", len(entries))
+	}
+	for _, e := range entries {
+		m := attrsMap(e.rec)
+		if got := m["reqID"]; got.String() != "req-42" {
+			t.Fatalf("record %q: reqID=%v, attrs=%v", e.rec.Message, got, m)
+		}
+		if e.rec.Message == "debug" || e.rec.Message == "log" || e.rec.Message == "attrs" {
+			if m["k"].String() != "v" {
+				t.Fatalf("record %q lost its own attrs: %v", e.rec.Message, m)
+			}
+		}
+	}
+
+	t.Run("context without id adds nothing", func(t *testing.T) {
+		before := sink.len()
+		InfoContext(t.Context(), "no-id")
+		entries := sink.snapshot()
+		if len(entries) != before+1 {
+			t.Fatalf("expected one more record")
+		}
+		if _, ok := attrsMap(entries[before].rec)["reqID"]; ok {
+			t.Fatalf("unexpected reqID attr")
+		}
+	})
+
+	t.Run("non-context calls skip the extractor", func(t *testing.T) {
+		n := calls.Load()
+		Info("plain")
+		if calls.Load() != n {
+			t.Fatalf("extractor called for a non-context log call")
+		}
+	})
+
+	t.Run("disabled levels skip the extractor", func(t *testing.T) {
+		var buf bytes.Buffer
+		SetDefault(NewLogger(Options{Level: slog.LevelError, Out: &buf}))
+		n := calls.Load()
+		InfoContext(ctx, "suppressed")
+		LogAttrs(ctx, slog.LevelDebug, "suppressed")
+		if calls.Load() != n || buf.Len() != 0 {
+			t.Fatalf("extractor called or output written for disabled level: %q", buf.String())
+		}
+		ErrorContext(ctx, "shown")
+		if !strings.Contains(buf.String(), "msg=shown reqID=req-42") {
+			t.Fatalf("missing reqID in %q", buf.String())
+		}
+	})
+
+	t.Run("nil removes the extractor", func(t *testing.T) {
+		SetContextExtractor(nil)
+		sink := &recordSink{}
+		SetDefault(newCaptureLogger(sink))
+		InfoContext(ctx, "after")
+		if _, ok := attrsMap(sink.snapshot()[0].rec)["reqID"]; ok {
+			t.Fatalf("reqID attached after removing the extractor")
+		}
+	})
+}
+
+func TestSetStrictArgs(t *testing.T) {
+	defer restoreGlobal(t)()
+	defer SetStrictArgs(false)
+
+	ctx := t.Context()
+	tests := []struct {
+		name        string
+		call        func()
+		wantProblem string // "" => no warning
+	}{
+		{name: "well formed", call: func() { Info("m", "k", "v", slog.Int("n", 1)) }},
+		{name: "no args", call: func() { Warn("m") }},
+		{name: "dangling key", call: func() { Info("m", "k") }, wantProblem: `key "k" has no value`},
+		{
+			name:        "non-string key",
+			call:        func() { Error("m", 123, "v") },
+			wantProblem: "argument 0: key of type int is not a string or slog.Attr",
+		},
+		{name: "context variant", call: func() { DebugContext(ctx, "m", "a", 1, "b") }, wantProblem: `key "b" has no value`},
+		{name: "Log", call: func() { Log(ctx, slog.LevelInfo, "m", true) }, wantProblem: "key of type bool"},
+		{name: "With", call: func() { _ = With("only-key") }, wantProblem: `key "only-key" has no value`},
+	}
+
+	for _, strict := range []bool{false, true} {
+		for _, tt := range tests {
+			t.Run(fmt.Sprintf("%s/strict=%v", tt.name, strict), func(t *testing.T) {
+				SetStrictArgs(strict)
+				sink := &recordSink{}
+				SetDefault(newCaptureLogger(sink))
+
+				tt.call()
+
+				var warnings []slog.Record
+				for _, e := range sink.snapshot() {
+					if e.rec.Message == "malformed log arguments" {
+						warnings = append(warnings, e.rec)
+					}
+				}
+				if !strict || tt.wantProblem == "" {
+					if len(warnings) != 0 {
+						t.Fatalf("unexpected warning: %v", attrsMap(warnings[0]))
+					}
+					return
+				}
+				if len(warnings) != 1 {
+					t.Fatalf("expected 1 warning, got %d", len(warnings))
+				}
+				w := warnings[0]
+				m := attrsMap(w)
+				if w.Level != slog.LevelWarn || !strings.Contains(m["problem"].String(), tt.wantProblem) {
+					t.Fatalf("unexpected warning %v %v", w.Level, m)
+				}
+				if caller := m["caller"].String(); !strings.Contains(caller, "logger_test.go:") {
+					t.Fatalf("caller %q does not name the call site", caller)
+				}
+			})
+		}
+	}
+}
+
 func TestDefault_ReturnsCurrentlyInstalledLogger(t *testing.T) {
 	defer restoreGlobal(t)()
 
@@ -0,0 +1,63 @@
+package logutil
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+)
+
+// multiHandler fans records out to several handlers.
+type multiHandler struct {
+	handlers []slog.Handler
+}
+
+// NewMultiHandler returns a handler that sends each record to every handler enabled for its level, e.g. to log to
+// stdout and a file at once. Nil handlers are ignored. Errors from the handlers are joined with errors.Join.
+func NewMultiHandler(handlers ...slog.Handler) slog.Handler {
+	hs := make([]slog.Handler, 0, len(handlers))
+	for _, h := range handlers {
+		if h != nil {
+			hs = append(hs, h)
+		}
+	}
+	return &multiHandler{handlers: hs}
+}
+
+func (m *multiHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	for _, h := range m.handlers {
+		if h.Enabled(ctx, level) {
+			return true
+		}
+	}
+	return false
+}
+
+func (m *multiHandler) Handle(ctx context.Context, r slog.Record) error {
+	var errs []error
+	for _, h := range m.handlers {
+		if !h.Enabled(ctx, r.Level) {
+			continue
+		}
+		// Each handler gets its own copy, as handlers may retain or modify the record.
+		if err := h.Handle(ctx, r.Clone()); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (m *multiHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	hs := make([]slog.Handler, len(m.handlers))
+	for i, h := range m.handlers {
+		hs[i] = h.WithAttrs(attrs)
+	}
+	return &multiHandler{handlers: hs}
+}
+
+func (m *multiHandler) WithGroup(name string) slog.Handler {
+	hs := make([]slog.Handler, len(m.handlers))
+	for i, h := range m.handlers {
+		hs[i] = h.WithGroup(name)
+	}
+	return &multiHandler{handlers: hs}
+}
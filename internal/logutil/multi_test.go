@@ -0,0 +1,76 @@
+package logutil
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+)
+
+type failingHandler struct {
+	slog.Handler
+	err error
+}
+
+func (h failingHandler) Handle(context.Context, slog.Record) error { return h.err }
+
+func TestMultiHandler_FansOutWithAttrs(t *testing.T) {
+	t.Parallel()
+
+	s1, s2 := &recordSink{}, &recordSink{}
+	l := slog.New(NewMultiHandler(&captureHandler{sink: s1}, nil, &captureHandler{sink: s2}))
+	l.With("reqID", "r1").Info("first", "k", "v")
+	l.Warn("second")
+
+	for name, sink := range map[string]*recordSink{"sink1": s1, "sink2": s2} {
+		entries := sink.snapshot()
+		if len(entries) != 2 {
+			t.Fatalf("%s: got %d records, want 2", name, len(entries))
+		}
+		m := attrsMap(entries[0].rec)
+		if entries[0].rec.Message != "first" || m["reqID"].String() != "r1" || m["k"].String() != "v" {
+			t.Fatalf("%s: unexpected first record %q %v", name, entries[0].rec.Message, m)
+		}
+		if entries[1].rec.Message != "second" || entries[1].rec.Level != slog.LevelWarn {
+			t.Fatalf("%s: unexpected second record %q", name, entries[1].rec.Message)
+		}
+		if _, ok := attrsMap(entries[1].rec)["reqID"]; ok {
+			t.Fatalf("%s: With attrs leaked to the parent logger", name)
+		}
+	}
+}
+
+func TestMultiHandler_LevelsGroupsAndErrors(t *testing.T) {
+	t.Parallel()
+
+	var infoOut, errOut bytes.Buffer
+	infoH := slog.NewTextHandler(&infoOut, &slog.HandlerOptions{Level: slog.LevelInfo})
+	errH := slog.NewTextHandler(&errOut, &slog.HandlerOptions{Level: slog.LevelError})
+	l := slog.New(NewMultiHandler(infoH, errH)).WithGroup("g")
+
+	if l.Enabled(t.Context(), slog.LevelDebug) {
+		t.Fatalf("debug should be disabled for every child")
+	}
+	l.Info("note", "a", 1)
+	l.Error("boom", "b", 2)
+	if got := infoOut.String(); !strings.Contains(got, "msg=note g.a=1") || !strings.Contains(got, "msg=boom g.b=2") {
+		t.Fatalf("info sink: %q", got)
+	}
+	if got := errOut.String(); strings.Contains(got, "note") || !strings.Contains(got, "msg=boom g.b=2") {
+		t.Fatalf("error sink: %q", got)
+	}
+
+	e1, e2 := errors.New("disk full"), errors.New("pipe closed")
+	sink := &recordSink{}
+	h := NewMultiHandler(failingHandler{infoH, e1}, &captureHandler{sink: sink}, failingHandler{infoH, e2})
+	err := h.Handle(t.Context(), slog.NewRecord(time.Time{}, slog.LevelInfo, "x", 0))
+	if !errors.Is(err, e1) || !errors.Is(err, e2) {
+		t.Fatalf("expected joined errors, got %v", err)
+	}
+	if sink.len() != 1 {
+		t.Fatalf("healthy handler should still receive the record")
+	}
+}
@@ -0,0 +1,71 @@
+package logutil
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+)
+
+// RedactedValue replaces the value of redacted attributes.
+const RedactedValue = "[REDACTED]"
+
+// RedactingHandler wraps a handler and replaces the values of attributes whose key matches one of its keys
+// (case-insensitively) with RedactedValue before delegating. It applies to record attrs, attrs added via With, and
+// attrs nested in groups at any depth; a matching group is redacted as a whole.
+type RedactingHandler struct {
+	next slog.Handler
+	keys map[string]struct{}
+}
+
+// NewRedactingHandler returns a handler that redacts the given attribute keys and passes everything else to next.
+func NewRedactingHandler(next slog.Handler, keys []string) *RedactingHandler {
+	m := make(map[string]struct{}, len(keys))
+	for _, k := range keys {
+		if k = strings.ToLower(strings.TrimSpace(k)); k != "" {
+			m[k] = struct{}{}
+		}
+	}
+	return &RedactingHandler{next: next, keys: m}
+}
+
+func (h *RedactingHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+func (h *RedactingHandler) Handle(ctx context.Context, r slog.Record) error {
+	out := slog.NewRecord(r.Time, r.Level, r.Message, r.PC)
+	r.Attrs(func(a slog.Attr) bool {
+		out.AddAttrs(h.redact(a))
+		return true
+	})
+	return h.next.Handle(ctx, out)
+}
+
+func (h *RedactingHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	redacted := make([]slog.Attr, len(attrs))
+	for i, a := range attrs {
+		redacted[i] = h.redact(a)
+	}
+	return &RedactingHandler{next: h.next.WithAttrs(redacted), keys: h.keys}
+}
+
+func (h *RedactingHandler) WithGroup(name string) slog.Handler {
+	return &RedactingHandler{next: h.next.WithGroup(name), keys: h.keys}
+}
+
+func (h *RedactingHandler) redact(a slog.Attr) slog.Attr {
+	if _, ok := h.keys[strings.ToLower(a.Key)]; ok {
+		return slog.String(a.Key, RedactedValue)
+	}
+	// Resolve LogValuers so secrets they produce are inspected too.
+	v := a.Value.Resolve()
+	if v.Kind() != slog.KindGroup {
+		return slog.Attr{Key: a.Key, Value: v}
+	}
+	group := v.Group()
+	redacted := make([]slog.Attr, len(group))
+	for i, ga := range group {
+		redacted[i] = h.redact(ga)
+	}
+	return slog.Attr{Key: a.Key, Value: slog.GroupValue(redacted...)}
+}
@@ -0,0 +1,118 @@
+package logutil
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+type secretValuer struct{}
+
+func (secretValuer) LogValue() slog.Value {
+	return slog.GroupValue(slog.String("user", "bob"), slog.String("Token", "t0ps3cret"))
+}
+
+func TestRedactingHandler(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		log  func(l *slog.Logger)
+		want map[string]any
+	}{
+		{
+			name: "matching key is redacted case-insensitively",
+			log:  func(l *slog.Logger) { l.Info("m", "PassWord", "hunter2", "user", "bob") },
+			want: map[string]any{"PassWord": RedactedValue, "user": "bob"},
+		},
+		{
+			name: "nested group",
+			log: func(l *slog.Logger) {
+				l.Info("m", slog.Group("req", slog.String("path", "/x"), slog.Group("auth", slog.String("password", "p"))))
+			},
+			want: map[string]any{
+				"req": map[string]any{"path": "/x", "auth": map[string]any{"password": RedactedValue}},
+			},
+		},
+		{
+			name: "matching group is redacted whole",
+			log:  func(l *slog.Logger) { l.Info("m", slog.Group("token", slog.String("a", "b")), "n", 1) },
+			want: map[string]any{"token": RedactedValue, "n": float64(1)},
+		},
+		{
+			name: "attrs added with With",
+			log:  func(l *slog.Logger) { l.With("password", "p", "ok", true).Info("m") },
+			want: map[string]any{"password": RedactedValue, "ok": true},
+		},
+		{
+			name: "attrs inside WithGroup",
+			log:  func(l *slog.Logger) { l.WithGroup("g").Info("m", "password", "p", "x", "y") },
+			want: map[string]any{"g": map[string]any{"password": RedactedValue, "x": "y"}},
+		},
+		{
+			name: "LogValuer output is inspected",
+			log:  func(l *slog.Logger) { l.Info("m", "creds", secretValuer{}) },
+			want: map[string]any{"creds": map[string]any{"user": "bob", "Token": RedactedValue}},
+		},
+		{
+			name: "no matches pass through",
+			log:  func(l *slog.Logger) { l.Info("m", "path", "/etc/hosts", "size", 3) },
+			want: map[string]any{"path": "/etc/hosts", "size": float64(3)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			var buf bytes.Buffer
+			next := slog.NewJSONHandler(&buf, &slog.HandlerOptions{
+				ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+					if len(groups) == 0 && (a.Key == slog.TimeKey || a.Key == slog.LevelKey || a.Key == slog.MessageKey) {
+						return slog.Attr{}
+					}
+					return a
+				},
+			})
+			tt.log(slog.New(NewRedactingHandler(next, []string{"password", " TOKEN ", ""})))
+
+			var got map[string]any
+			if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+				t.Fatalf("decode %q: %v", buf.String(), err)
+			}
+			gotJSON, _ := json.Marshal(got)
+			wantJSON, _ := json.Marshal(tt.want)
+			if string(gotJSON) != string(wantJSON) {
+				t.Fatalf("got %s want %s", gotJSON, wantJSON)
+			}
+			if strings.Contains(buf.String(), "hunter2") || strings.Contains(buf.String(), "t0ps3cret") {
+				t.Fatalf("secret leaked: %s", buf.String())
+			}
+		})
+	}
+}
+
+func TestRedactingHandler_ComposesWithSetDefault(t *testing.T) {
+	defer restoreGlobal(t)()
+
+	sink := &recordSink{}
+	SetDefault(slog.New(NewRedactingHandler(&captureHandler{sink: sink}, []string{"apiKey"})))
+	InfoContext(context.WithValue(t.Context(), ctxKey("k"), "v"), "call", "apikey", "sk-123", "tool", "readfile")
+
+	entries := sink.snapshot()
+	if len(entries) != 1 {
+		t.Fatalf("expected 1 record, got %d", len(entries))
+	}
+	m := attrsMap(entries[0].rec)
+	if m["apikey"].String() != RedactedValue || m["tool"].String() != "readfile" {
+		t.Fatalf("unexpected attrs: %v", m)
+	}
+	if entries[0].ctx.Value(ctxKey("k")) != "v" {
+		t.Fatalf("context not passed through")
+	}
+	if !Default().Enabled(t.Context(), slog.LevelDebug) {
+		t.Fatalf("Enabled should delegate to the wrapped handler")
+	}
+}
@@ -0,0 +1,128 @@
+package logutil
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"sync"
+)
+
+// RotatingFileHandler is a JSON slog handler writing to a file that is rotated by size. Close it when done.
+type RotatingFileHandler struct {
+	slog.Handler
+	w *rotatingWriter
+}
+
+// NewRotatingFileHandler opens (or appends to) path and returns a handler writing JSON records to it. Before a write
+// would grow the file beyond maxBytes, the file is rotated: path.1 becomes path.2 and so on, path becomes path.1, and
+// a fresh path is started; backups beyond maxBackups are deleted ("maxBackups<=0" keeps none). "maxBytes<=0" disables
+// rotation. A record is never split across files. If rotation fails (e.g. a backup cannot be renamed), the record is
+// still appended to path, the error is returned, and rotation is retried on the next write. Writes and rotation are serialized, so the handler (and handlers
+// derived from it) may be used concurrently. Files are created with mode 0600 since logs may hold sensitive data.
+func NewRotatingFileHandler(path string, maxBytes int64, maxBackups int) (*RotatingFileHandler, error) {
+	if path == "" {
+		return nil, errors.New("log file path is required")
+	}
+	w := &rotatingWriter{path: path, maxBytes: maxBytes, maxBackups: max(maxBackups, 0)}
+	if err := w.open(); err != nil {
+		return nil, err
+	}
+	return &RotatingFileHandler{Handler: slog.NewJSONHandler(w, nil), w: w}, nil
+}
+
+// Close closes the underlying file. Records handled afterwards fail with os.ErrClosed.
+func (h *RotatingFileHandler) Close() error {
+	return h.w.Close()
+}
+
+type rotatingWriter struct {
+	mu         sync.Mutex
+	path       string
+	maxBytes   int64
+	maxBackups int
+	f          *os.File
+	size       int64
+}
+
+func (w *rotatingWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.f == nil {
+		return 0, os.ErrClosed
+	}
+	var rotateErr error
+	if w.maxBytes > 0 && w.size > 0 && w.size+int64(len(p)) > w.maxBytes {
+		if rotateErr = w.rotate(); w.f == nil {
+			return 0, rotateErr
+		}
+	}
+	n, err := w.f.Write(p)
+	w.size += int64(n)
+	if err == nil {
+		err = rotateErr
+	}
+	return n, err
+}
+
+func (w *rotatingWriter) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.f == nil {
+		return nil
+	}
+	err := w.f.Close()
+	w.f = nil
+	return err
+}
+
+func (w *rotatingWriter) open() error {
+	f, err := os.OpenFile(w.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return err
+	}
+	st, err := f.Stat()
+	if err != nil {
+		_ = f.Close()
+		return err
+	}
+	w.f, w.size = f, st.Size()
+	return nil
+}
+
+// rotate shifts the backups, moves the current file to path.1 and opens a fresh one. If shifting fails, path is
+// reopened for appending so logging continues; w.f is only left nil if that fails too. Callers hold w.mu.
+func (w *rotatingWriter) rotate() error {
+	closeErr := w.f.Close()
+	w.f = nil
+	if err := w.shiftFiles(); err != nil {
+		return errors.Join(err, w.open())
+	}
+	return errors.Join(w.open(), closeErr)
+}
+
+// shiftFiles moves path to path.1 (and each backup one slot up), or removes it if no backups are kept.
+func (w *rotatingWriter) shiftFiles() error {
+	if w.maxBackups == 0 {
+		if err := os.Remove(w.path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+		return nil
+	}
+	if err := os.Remove(w.backupPath(w.maxBackups)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	for i := w.maxBackups - 1; i >= 1; i-- {
+		if err := os.Rename(w.backupPath(i), w.backupPath(i+1)); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+	}
+	if err := os.Rename(w.path, w.backupPath(1)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+func (w *rotatingWriter) backupPath(i int) string {
+	return fmt.Sprintf("%s.%d", w.path, i)
+}
@@ -0,0 +1,199 @@
+package logutil
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestRotatingFileHandler_Rotates(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "tools.log")
+	const maxBytes = 1024
+	h, err := NewRotatingFileHandler(path, maxBytes, 2)
+	if err != nil {
+		t.Fatalf("NewRotatingFileHandler: %v", err)
+	}
+	l := slog.New(h).With("component", "test")
+
+	for i := range 100 {
+		l.Info("record", "i", i, "pad", strings.Repeat("x", 40))
+	}
+	if err := h.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	for _, p := range []string{path, path + ".1", path + ".2"} {
+		st, err := os.Stat(p)
+		if err != nil {
+			t.Fatalf("expected %s: %v", p, err)
+		}
+		if st.Size() == 0 || st.Size() > maxBytes {
+			t.Fatalf("%s size=%d, want 1..%d", p, st.Size(), maxBytes)
+		}
+		assertJSONLines(t, p)
+	}
+	if _, err := os.Stat(path + ".3"); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("expected no backup beyond maxBackups, got %v", err)
+	}
+
+	// The newest record is in the primary file, the previous ones in path.1.
+	last := lastLine(t, path)
+	if !strings.Contains(last, `"i":99`) || !strings.Contains(last, `"component":"test"`) {
+		t.Fatalf("unexpected last record %q", last)
+	}
+	if err := h.Handle(t.Context(), slog.Record{}); !errors.Is(err, os.ErrClosed) {
+		t.Fatalf("expected os.ErrClosed after Close, got %v", err)
+	}
+}
+
+func TestRotatingFileHandler_AppendsAndNoBackups(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "a.log")
+	if err := os.WriteFile(path, []byte("{\"existing\":true}\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	h, err := NewRotatingFileHandler(path, 200, 0)
+	if err != nil {
+		t.Fatalf("NewRotatingFileHandler: %v", err)
+	}
+	slog.New(h).Info("one")
+	data, _ := os.ReadFile(path)
+	if !strings.HasPrefix(string(data), "{\"existing\":true}\n") {
+		t.Fatalf("existing content not kept: %q", data)
+	}
+
+	for range 10 {
+		slog.New(h).Info("more", "pad", strings.Repeat("y", 50))
+	}
+	_ = h.Close()
+	if _, err := os.Stat(path + ".1"); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("expected no backups with maxBackups=0, got %v", err)
+	}
+	if st, _ := os.Stat(path); st.Size() > 200 {
+		t.Fatalf("primary not truncated: %d bytes", st.Size())
+	}
+
+	if _, err := NewRotatingFileHandler("", 1, 1); err == nil {
+		t.Fatalf("expected error for empty path")
+	}
+	if _, err := NewRotatingFileHandler(filepath.Join(path, "nested"), 1, 1); err == nil {
+		t.Fatalf("expected error for unopenable path")
+	}
+}
+
+func TestRotatingFileHandler_RotationFailureKeepsLogging(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "r.log")
+	// A non-empty directory in the backup slot can be neither removed nor renamed over.
+	if err := os.MkdirAll(filepath.Join(path+".1", "keep"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	h, err := NewRotatingFileHandler(path, 100, 1)
+	if err != nil {
+		t.Fatalf("NewRotatingFileHandler: %v", err)
+	}
+	t.Cleanup(func() { _ = h.Close() })
+	l := slog.New(h)
+
+	rec := func(i int) slog.Record {
+		r := slog.NewRecord(time.Now(), slog.LevelInfo, "record", 0)
+		r.AddAttrs(slog.Int("i", i), slog.String("pad", strings.Repeat("z", 60)))
+		return r
+	}
+	if err := h.Handle(t.Context(), rec(0)); err != nil {
+		t.Fatalf("first record: %v", err)
+	}
+	if err := h.Handle(t.Context(), rec(1)); err == nil {
+		t.Fatalf("expected rotation error")
+	}
+	l.Info("after-failure")
+	if n := assertJSONLines(t, path); n != 3 {
+		t.Fatalf("got %d records in %s, want 3 (logging must continue after a failed rotation)", n, path)
+	}
+
+	// Once the slot is free again, rotation resumes.
+	if err := os.RemoveAll(path + ".1"); err != nil {
+		t.Fatal(err)
+	}
+	if err := h.Handle(t.Context(), rec(2)); err != nil {
+		t.Fatalf("record after clearing the slot: %v", err)
+	}
+	if n := assertJSONLines(t, path+".1"); n != 3 {
+		t.Fatalf("got %d records in backup, want 3", n)
+	}
+	if n := assertJSONLines(t, path); n != 1 {
+		t.Fatalf("got %d records in fresh file, want 1", n)
+	}
+}
+
+func TestRotatingFileHandler_Concurrent(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "c.log")
+	h, err := NewRotatingFileHandler(path, 4096, 50)
+	if err != nil {
+		t.Fatalf("NewRotatingFileHandler: %v", err)
+	}
+	l := slog.New(h)
+	var wg sync.WaitGroup
+	for g := range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range 100 {
+				l.Info("c", "g", g, "i", i)
+			}
+		}()
+	}
+	wg.Wait()
+	_ = h.Close()
+
+	total := 0
+	files, _ := filepath.Glob(path + "*")
+	for _, p := range files {
+		total += assertJSONLines(t, p)
+	}
+	if total != 800 {
+		t.Fatalf("got %d records across %d files, want 800", total, len(files))
+	}
+}
+
+// assertJSONLines checks every line of the file is a complete JSON object and returns the line count.
+func assertJSONLines(t *testing.T, path string) int {
+	t.Helper()
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	n := 0
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		if !json.Valid(sc.Bytes()) {
+			t.Fatalf("%s: invalid line %q", path, sc.Text())
+		}
+		n++
+	}
+	return n
+}
+
+func lastLine(t *testing.T, path string) string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	return lines[len(lines)-1]
+}
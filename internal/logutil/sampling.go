@@ -0,0 +1,166 @@
+package logutil
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+const (
+	samplingWindow = time.Second
+	// samplingSweepAt is the number of tracked keys above which ended windows are swept before a full samplingWindow
+	// has passed since the last sweep.
+	samplingSweepAt = 1024
+)
+
+// SamplingHandler wraps a handler and passes at most perSecond records per (level, message) pair in each one-second
+// window, dropping the rest. Once a window with dropped records has ended, a summary record at the same level reports
+// how many were dropped ("N messages suppressed", with the original message as attr "message"). Summaries are
+// emitted, at most about once a second, ahead of the next record handled for any pair, so a burst followed by silence
+// of that pair is still reported as soon as anything else logs. Flush emits pending summaries immediately; call it on
+// shutdown, or periodically if nothing else may log. Handlers derived via WithAttrs/WithGroup share one budget.
+type SamplingHandler struct {
+	next  slog.Handler
+	state *samplingState
+}
+
+type samplingKey struct {
+	level slog.Level
+	msg   string
+}
+
+type samplingWindowState struct {
+	start      time.Time
+	count      int
+	suppressed int
+	next       slog.Handler // handler the summary is emitted through
+}
+
+type samplingState struct {
+	mu        sync.Mutex
+	perSecond int
+	now       func() time.Time
+	windows   map[samplingKey]*samplingWindowState
+	lastSweep time.Time
+}
+
+// samplingSummary is a summary record and the handler to emit it through.
+type samplingSummary struct {
+	next slog.Handler
+	rec  slog.Record
+}
+
+// NewSamplingHandler returns a handler that rate-limits records to next. "perSecond<=0" disables sampling.
+func NewSamplingHandler(next slog.Handler, perSecond int) *SamplingHandler {
+	return &SamplingHandler{
+		next: next,
+		state: &samplingState{
+			perSecond: perSecond,
+			now:       time.Now,
+			windows:   map[samplingKey]*samplingWindowState{},
+		},
+	}
+}
+
+func (h *SamplingHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+func (h *SamplingHandler) Handle(ctx context.Context, r slog.Record) error {
+	s := h.state
+	if s.perSecond <= 0 {
+		return h.next.Handle(ctx, r)
+	}
+
+	key := samplingKey{level: r.Level, msg: r.Message}
+	s.mu.Lock()
+	now := s.now()
+	var summaries []samplingSummary
+	if now.Sub(s.lastSweep) >= samplingWindow || len(s.windows) >= samplingSweepAt {
+		summaries = s.sweepLocked(now)
+	}
+	w := s.windows[key]
+	if w == nil {
+		w = &samplingWindowState{start: now}
+		s.windows[key] = w
+	} else if now.Sub(w.start) >= samplingWindow {
+		if w.suppressed > 0 {
+			summaries = append(summaries, samplingSummary{next: w.next, rec: suppressedRecord(now, key, w.suppressed)})
+		}
+		w.start, w.count, w.suppressed = now, 0, 0
+	}
+	w.count++
+	drop := w.count > s.perSecond
+	if drop {
+		w.suppressed++
+		w.next = h.next
+	}
+	s.mu.Unlock()
+
+	if err := emitSummaries(ctx, summaries); err != nil {
+		return err
+	}
+	if drop {
+		return nil
+	}
+	return h.next.Handle(ctx, r)
+}
+
+// Flush emits the summaries of all pending suppressed records and resets their windows.
+func (h *SamplingHandler) Flush(ctx context.Context) error {
+	s := h.state
+	var out []samplingSummary
+	s.mu.Lock()
+	now := s.now()
+	for key, w := range s.windows {
+		if w.suppressed > 0 {
+			out = append(out, samplingSummary{next: w.next, rec: suppressedRecord(now, key, w.suppressed)})
+		}
+		delete(s.windows, key)
+	}
+	s.mu.Unlock()
+	return emitSummaries(ctx, out)
+}
+
+// emitSummaries handles every summary and returns the first error.
+func emitSummaries(ctx context.Context, summaries []samplingSummary) error {
+	var firstErr error
+	for _, p := range summaries {
+		if err := p.next.Handle(ctx, p.rec); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+func (h *SamplingHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &SamplingHandler{next: h.next.WithAttrs(attrs), state: h.state}
+}
+
+func (h *SamplingHandler) WithGroup(name string) slog.Handler {
+	return &SamplingHandler{next: h.next.WithGroup(name), state: h.state}
+}
+
+// sweepLocked drops windows that have ended and returns the summaries of those with suppressed records.
+func (s *samplingState) sweepLocked(now time.Time) []samplingSummary {
+	s.lastSweep = now
+	var out []samplingSummary
+	for key, w := range s.windows {
+		if now.Sub(w.start) < samplingWindow {
+			continue
+		}
+		if w.suppressed > 0 {
+			out = append(out, samplingSummary{next: w.next, rec: suppressedRecord(now, key, w.suppressed)})
+		}
+		delete(s.windows, key)
+	}
+	return out
+}
+
+func suppressedRecord(now time.Time, key samplingKey, n int) slog.Record {
+	r := slog.NewRecord(now, key.level, fmt.Sprintf("%d messages suppressed", n), 0)
+	r.AddAttrs(slog.String("message", key.msg), slog.Int("suppressed", n))
+	return r
+}
@@ -0,0 +1,181 @@
+package logutil
+
+import (
+	"log/slog"
+	"sync"
+	"testing"
+	"time"
+)
+
+func newTestSamplingHandler(sink *recordSink, perSecond int) (*SamplingHandler, func(time.Duration)) {
+	h := NewSamplingHandler(&captureHandler{sink: sink}, perSecond)
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	var mu sync.Mutex
+	h.state.now = func() time.Time {
+		mu.Lock()
+		defer mu.Unlock()
+		return now
+	}
+	return h, func(d time.Duration) {
+		mu.Lock()
+		now = now.Add(d)
+		mu.Unlock()
+	}
+}
+
+func TestSamplingHandler_BurstIsSuppressedWithSummary(t *testing.T) {
+	t.Parallel()
+
+	sink := &recordSink{}
+	h, advance := newTestSamplingHandler(sink, 10)
+	l := slog.New(h)
+
+	for i := range 1000 {
+		l.Debug("tight loop", "i", i)
+	}
+	l.Info("tight loop") // different level: separate budget
+	l.Debug("other message")
+	if got := sink.len(); got != 12 {
+		t.Fatalf("downstream got %d records during burst, want 12", got)
+	}
+
+	advance(time.Second)
+	l.Debug("tight loop", "i", 1000)
+
+	entries := sink.snapshot()
+	if len(entries) != 14 {
+		t.Fatalf("downstream got %d records, want 14", len(entries))
+	}
+	summary := entries[12].rec
+	if summary.Message != "990 messages suppressed" || summary.Level != slog.LevelDebug {
+		t.Fatalf("unexpected summary: %v %q", summary.Level, summary.Message)
+	}
+	m := attrsMap(summary)
+	if m["message"].String() != "tight loop" || m["suppressed"].Int64() != 990 {
+		t.Fatalf("unexpected summary attrs: %v", m)
+	}
+	if got := attrsMap(entries[13].rec)["i"].Int64(); got != 1000 {
+		t.Fatalf("record after window: i=%d", got)
+	}
+}
+
+func TestSamplingHandler_BurstThenSilenceIsSummarized(t *testing.T) {
+	t.Parallel()
+
+	sink := &recordSink{}
+	h, advance := newTestSamplingHandler(sink, 5)
+	l := slog.New(h)
+
+	for range 50 {
+		l.Warn("burst")
+	}
+	if got := sink.len(); got != 5 {
+		t.Fatalf("downstream got %d records during burst, want 5", got)
+	}
+
+	// "burst" never logs again; the next unrelated record reports it once its window has ended.
+	advance(500 * time.Millisecond)
+	l.Info("unrelated")
+	if got := sink.len(); got != 6 {
+		t.Fatalf("summary emitted before the window ended: %d records", got)
+	}
+	advance(time.Second)
+	l.Info("unrelated")
+
+	entries := sink.snapshot()
+	if len(entries) != 8 {
+		t.Fatalf("downstream got %d records, want 8", len(entries))
+	}
+	summary := entries[6].rec
+	if summary.Message != "45 messages suppressed" || summary.Level != slog.LevelWarn ||
+		attrsMap(summary)["message"].String() != "burst" {
+		t.Fatalf("unexpected summary: %v %q %v", summary.Level, summary.Message, attrsMap(summary))
+	}
+	if entries[7].rec.Message != "unrelated" {
+		t.Fatalf("summary should precede the record that triggered it, got %q", entries[7].rec.Message)
+	}
+	if err := h.Flush(t.Context()); err != nil || sink.len() != 8 {
+		t.Fatalf("summary reported twice (err=%v, n=%d)", err, sink.len())
+	}
+}
+
+func TestSamplingHandler_FlushAndSharedBudget(t *testing.T) {
+	t.Parallel()
+
+	sink := &recordSink{}
+	h, _ := newTestSamplingHandler(sink, 2)
+	base := slog.New(h)
+	child := base.With("component", "c")
+
+	for range 3 {
+		base.Warn("w")
+		child.Warn("w")
+	}
+	if got := sink.len(); got != 2 {
+		t.Fatalf("derived handlers should share the budget; got %d records", got)
+	}
+
+	if err := h.Flush(t.Context()); err != nil {
+		t.Fatalf("Flush: %v", err)
+	}
+	entries := sink.snapshot()
+	if len(entries) != 3 || entries[2].rec.Message != "4 messages suppressed" {
+		t.Fatalf("expected flushed summary, got %d records", len(entries))
+	}
+	if err := h.Flush(t.Context()); err != nil || sink.len() != 3 {
+		t.Fatalf("second Flush should emit nothing (err=%v, n=%d)", err, sink.len())
+	}
+
+	// Flush resets the windows.
+	base.Warn("w")
+	if got := sink.len(); got != 4 {
+		t.Fatalf("expected record after Flush, got %d", got)
+	}
+}
+
+func TestSamplingHandler_Disabled(t *testing.T) {
+	t.Parallel()
+
+	sink := &recordSink{}
+	l := slog.New(NewSamplingHandler(&captureHandler{sink: sink}, 0))
+	for range 100 {
+		l.Info("m")
+	}
+	if got := sink.len(); got != 100 {
+		t.Fatalf("got %d records, want 100", got)
+	}
+}
+
+func TestSamplingHandler_Concurrent(t *testing.T) {
+	t.Parallel()
+
+	sink := &recordSink{}
+	h := NewSamplingHandler(&captureHandler{sink: sink}, 5)
+	l := slog.New(h)
+	var wg sync.WaitGroup
+	for range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range 200 {
+				l.Info("flood")
+			}
+		}()
+	}
+	wg.Wait()
+	if err := h.Flush(t.Context()); err != nil {
+		t.Fatalf("Flush: %v", err)
+	}
+
+	passed, suppressed := 0, int64(0)
+	for _, e := range sink.snapshot() {
+		if e.rec.Message == "flood" {
+			passed++
+			continue
+		}
+		suppressed += attrsMap(e.rec)["suppressed"].Int64()
+	}
+	if int64(passed)+suppressed != 1600 || passed >= 1600 {
+		t.Fatalf("passed=%d suppressed=%d, want total 1600 with sampling", passed, suppressed)
+	}
+}
@@ -1,33 +1,77 @@
 package pdfutil
 
 import (
+	"bufio"
 	"bytes"
 	"context"
 	"errors"
+	"fmt"
 	"io"
 	"strings"
+	"unicode"
+	"unicode/utf8"
 
 	"github.com/flexigpt/llmtools-go/internal/toolutil"
 	"github.com/ledongthuc/pdf"
 )
 
+// ErrEmptyPDFText is returned when a PDF parses but yields no text, e.g. a
+// scanned, image-only document. Callers can branch on it (to try OCR, say)
+// with errors.Is.
+var ErrEmptyPDFText = errors.New("empty PDF text after extraction")
+
 // ExtractPDFTextSafe extracts text from a local PDF with a byte limit and panic recovery.
 func ExtractPDFTextSafe(ctx context.Context, path string, maxBytes int) (string, error) {
-	return toolutil.WithRecoveryResp(func() (string, error) {
-		return extractPDFTextSafe(ctx, path, maxBytes)
+	text, _, err := ExtractPDFTextSafeEx(ctx, path, maxBytes)
+	return text, err
+}
+
+// ExtractPDFTextSafeEx is ExtractPDFTextSafe that also reports whether text was
+// cut off at maxBytes. Text past the limit that is only whitespace does not count
+// as truncation, since it would be trimmed anyway.
+func ExtractPDFTextSafeEx(ctx context.Context, path string, maxBytes int) (text string, truncated bool, err error) {
+	type result struct {
+		text      string
+		truncated bool
+	}
+	res, err := toolutil.WithRecoveryResp(func() (result, error) {
+		s, t, err := extractPDFTextSafe(ctx, path, maxBytes)
+		return result{s, t}, err
 	})
+	return res.text, res.truncated, err
 }
 
-func extractPDFTextSafe(ctx context.Context, path string, maxBytes int) (text string, err error) {
+func extractPDFTextSafe(ctx context.Context, path string, maxBytes int) (text string, truncated bool, err error) {
 	f, r, err := pdf.Open(path)
 	if err != nil {
-		return "", err
+		return "", false, err
 	}
 	defer f.Close()
+	return plainTextLimited(r, maxBytes)
+}
 
+// ExtractPDFTextBytes is ExtractPDFTextSafe for a PDF already in memory, e.g.
+// read from an fs.FS.
+func ExtractPDFTextBytes(ctx context.Context, data []byte, maxBytes int) (string, error) {
+	return toolutil.WithRecoveryResp(func() (string, error) {
+		if err := ctx.Err(); err != nil {
+			return "", err
+		}
+		r, err := pdf.NewReader(bytes.NewReader(data), int64(len(data)))
+		if err != nil {
+			return "", err
+		}
+		text, _, err := plainTextLimited(r, maxBytes)
+		return text, err
+	})
+}
+
+// plainTextLimited returns up to maxBytes of r's plain text, trimmed, and
+// whether non-whitespace text was cut off.
+func plainTextLimited(r *pdf.Reader, maxBytes int) (text string, truncated bool, err error) {
 	reader, err := r.GetPlainText()
 	if err != nil {
-		return "", err
+		return "", false, err
 	}
 
 	var buf bytes.Buffer
@@ -36,11 +80,174 @@ func extractPDFTextSafe(ctx context.Context, path string, maxBytes int) (text st
 		N: int64(maxBytes),
 	}
 	if _, err := io.Copy(&buf, limited); err != nil {
-		return "", err
+		return "", false, err
+	}
+	if truncated, err = hasNonSpace(reader); err != nil {
+		return "", false, err
 	}
 	text = strings.TrimSpace(buf.String())
 	if text == "" {
-		return "", errors.New("empty PDF text after extraction")
+		return "", truncated, ErrEmptyPDFText
+	}
+	return text, truncated, nil
+}
+
+// hasNonSpace reports whether r yields any non-whitespace byte before EOF.
+func hasNonSpace(r io.Reader) (bool, error) {
+	br := bufio.NewReader(r)
+	for {
+		b, err := br.ReadByte()
+		if errors.Is(err, io.EOF) {
+			return false, nil
+		}
+		if err != nil {
+			return false, err
+		}
+		if b >= utf8.RuneSelf || !unicode.IsSpace(rune(b)) {
+			return true, nil
+		}
+	}
+}
+
+// ExtractPDFTextRange extracts text from pages firstPage..lastPage (1-based, inclusive).
+// lastPage is clamped to the page count; firstPage beyond the page count or an
+// inverted range is an error. Truncation and empty-text handling match ExtractPDFTextSafe.
+func ExtractPDFTextRange(ctx context.Context, path string, firstPage, lastPage, maxBytes int) (string, error) {
+	return ExtractPDFTextRangeWithProgress(ctx, path, firstPage, lastPage, maxBytes, nil)
+}
+
+// ExtractPDFTextRangeWithProgress is ExtractPDFTextRange with a progress callback.
+// onProgress, if non-nil, is called after each page with the pages processed and
+// the pages in the (clamped) range; pages past maxBytes are not processed.
+func ExtractPDFTextRangeWithProgress(
+	ctx context.Context,
+	path string,
+	firstPage, lastPage, maxBytes int,
+	onProgress func(done, total int),
+) (string, error) {
+	return toolutil.WithRecoveryResp(func() (string, error) {
+		return extractPDFTextRange(ctx, path, firstPage, lastPage, maxBytes, onProgress)
+	})
+}
+
+func extractPDFTextRange(
+	ctx context.Context,
+	path string,
+	firstPage, lastPage, maxBytes int,
+	onProgress func(done, total int),
+) (string, error) {
+	if firstPage < 1 {
+		return "", fmt.Errorf("firstPage must be >= 1, got %d", firstPage)
+	}
+	if lastPage < firstPage {
+		return "", fmt.Errorf("invalid page range: firstPage %d > lastPage %d", firstPage, lastPage)
+	}
+
+	f, r, err := pdf.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	n := r.NumPage()
+	if firstPage > n {
+		return "", fmt.Errorf("firstPage %d out of range (document has %d pages)", firstPage, n)
+	}
+	lastPage = min(lastPage, n)
+
+	var buf bytes.Buffer
+	fonts := make(map[string]*pdf.Font)
+	for i := firstPage; i <= lastPage && buf.Len() < maxBytes; i++ {
+		if err := ctx.Err(); err != nil {
+			return "", err
+		}
+		text, err := pagePlainText(r.Page(i), fonts)
+		if err != nil {
+			return "", err
+		}
+		buf.WriteString(text)
+		if onProgress != nil {
+			onProgress(i-firstPage+1, lastPage-firstPage+1)
+		}
+	}
+
+	text := strings.TrimSpace(truncateUTF8(buf.String(), maxBytes))
+	if text == "" {
+		return "", ErrEmptyPDFText
 	}
 	return text, nil
 }
+
+// ExtractPDFTextTo streams the text of each page to w as it is extracted, so
+// memory use does not grow with document length.
+//
+// Whitespace-only pages are skipped. Leading whitespace of the output is
+// dropped; trailing whitespace is not, as it has already been written.
+//
+// It returns:
+//   - written: the bytes accepted by w, at most maxBytes. This is also set
+//     when err is non-nil, in which case w holds a partial result.
+//   - truncated: whether text was cut off to fit maxBytes. Cuts never split a
+//     UTF-8 sequence.
+//   - err: ErrEmptyPDFText if no non-whitespace text was written, otherwise
+//     any open, page, write or ctx error.
+func ExtractPDFTextTo(
+	ctx context.Context,
+	path string,
+	w io.Writer,
+	maxBytes int,
+) (written int, truncated bool, err error) {
+	type result struct {
+		written   int
+		truncated bool
+	}
+	res, err := toolutil.WithRecoveryResp(func() (result, error) {
+		n, t, err := extractPDFTextTo(ctx, path, w, maxBytes)
+		return result{n, t}, err
+	})
+	return res.written, res.truncated, err
+}
+
+func extractPDFTextTo(ctx context.Context, path string, w io.Writer, maxBytes int) (written int, truncated bool, err error) {
+	f, r, err := pdf.Open(path)
+	if err != nil {
+		return 0, false, err
+	}
+	defer f.Close()
+
+	fonts := make(map[string]*pdf.Font)
+	wroteText := false
+	for i := 1; i <= r.NumPage(); i++ {
+		if err := ctx.Err(); err != nil {
+			return written, false, err
+		}
+		text, err := pagePlainText(r.Page(i), fonts)
+		if err != nil {
+			return written, false, err
+		}
+		if strings.TrimSpace(text) == "" {
+			continue
+		}
+		if !wroteText {
+			// Like the string variants, drop the leading whitespace of the output.
+			text = strings.TrimLeftFunc(text, unicode.IsSpace)
+		}
+		if remaining := maxBytes - written; len(text) > remaining {
+			text = truncateUTF8(text, remaining)
+			truncated = true
+		}
+		wroteText = wroteText || text != ""
+		n, err := io.WriteString(w, text)
+		written += n
+		if err != nil {
+			return written, truncated, err
+		}
+		if truncated {
+			break
+		}
+	}
+	if !wroteText {
+		return written, truncated, ErrEmptyPDFText
+	}
+	return written, truncated, nil
+}
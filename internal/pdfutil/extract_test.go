@@ -1,6 +1,7 @@
 package pdfutil
 
 import (
+	"context"
 	"encoding/base64"
 	"errors"
 	"os"
@@ -24,7 +25,7 @@ func TestExtractPDFTextSafe_TableDriven(t *testing.T) {
 		maxBytes  int
 		wantText  string
 		wantErr   bool
-		errSubstr string
+		wantErrIs error
 		checkFn   func(t *testing.T, got string)
 	}{
 		{
@@ -56,21 +57,21 @@ func TestExtractPDFTextSafe_TableDriven(t *testing.T) {
 			path:      happyPath,
 			maxBytes:  0,
 			wantErr:   true,
-			errSubstr: "empty PDF text after extraction",
+			wantErrIs: ErrEmptyPDFText,
 		},
 		{
 			name:      "maxBytes negative => empty after extraction (LimitedReader.N<=0 reads nothing)",
 			path:      happyPath,
 			maxBytes:  -1,
 			wantErr:   true,
-			errSubstr: "empty PDF text after extraction",
+			wantErrIs: ErrEmptyPDFText,
 		},
 		{
 			name:      "empty text pdf => specific empty error",
 			path:      emptyTextPath,
 			maxBytes:  1 << 20,
 			wantErr:   true,
-			errSubstr: "empty PDF text after extraction",
+			wantErrIs: ErrEmptyPDFText,
 		},
 		{
 			name:     "missing file => open error",
@@ -95,8 +96,8 @@ func TestExtractPDFTextSafe_TableDriven(t *testing.T) {
 				if err == nil {
 					t.Fatalf("expected error, got nil; text=%q", got)
 				}
-				if tt.errSubstr != "" && !strings.Contains(err.Error(), tt.errSubstr) {
-					t.Fatalf("expected err to contain %q, got %q", tt.errSubstr, err.Error())
+				if tt.wantErrIs != nil && !errors.Is(err, tt.wantErrIs) {
+					t.Fatalf("expected errors.Is(%v), got %v", tt.wantErrIs, err)
 				}
 				return
 			}
@@ -114,6 +115,54 @@ func TestExtractPDFTextSafe_TableDriven(t *testing.T) {
 	}
 }
 
+func TestExtractPDFTextSafeEx_Truncated(t *testing.T) {
+	t.Parallel()
+	path := writeTempFile(t, t.TempDir(), "hello.pdf", buildMinimalPDF("Hello PDF"))
+
+	tests := []struct {
+		name          string
+		maxBytes      int
+		wantText      string
+		wantTruncated bool
+	}{
+		{name: "full extraction", maxBytes: 1 << 20, wantText: "Hello PDF"},
+		{name: "tiny limit", maxBytes: 4, wantTruncated: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, truncated, err := ExtractPDFTextSafeEx(t.Context(), path, tt.maxBytes)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if tt.wantText != "" && got != tt.wantText {
+				t.Fatalf("text mismatch: got %q want %q", got, tt.wantText)
+			}
+			if got == "" || !strings.HasPrefix("Hello PDF", got) { //nolint:gocritic // The argOrder is correct.
+				t.Fatalf("expected %q to be a non-empty prefix of %q", got, "Hello PDF")
+			}
+			if truncated != tt.wantTruncated {
+				t.Fatalf("truncated=%v want %v", truncated, tt.wantTruncated)
+			}
+		})
+	}
+}
+
+func TestExtractPDFTextBytes(t *testing.T) {
+	t.Parallel()
+	got, err := ExtractPDFTextBytes(t.Context(), buildMinimalPDF("Hello PDF"), 1<<20)
+	if err != nil || got != "Hello PDF" {
+		t.Fatalf("got %q, %v; want %q", got, err, "Hello PDF")
+	}
+	if _, err := ExtractPDFTextBytes(t.Context(), buildMinimalPDF(""), 1<<20); !errors.Is(err, ErrEmptyPDFText) {
+		t.Fatalf("expected ErrEmptyPDFText, got %v", err)
+	}
+	if _, err := ExtractPDFTextBytes(t.Context(), []byte("not a pdf"), 1<<20); err == nil {
+		t.Fatal("expected error for non-PDF data")
+	}
+}
+
 //nolint:godot // Commented test.
 // This test is optional, but useful for diagnosing fixture/library changes.
 // It asserts we can round-trip a known-good PDF payload (base64) if you prefer not to generate PDFs.
@@ -135,6 +184,72 @@ func TestExtractPDFTextSafe_TableDriven(t *testing.T) {
 // 	}
 // }
 
+func TestErrEmptyPDFTextMessage(t *testing.T) {
+	t.Parallel()
+	// The message predates the sentinel; callers may still match on it.
+	if got := ErrEmptyPDFText.Error(); got != "empty PDF text after extraction" {
+		t.Fatalf("ErrEmptyPDFText = %q", got)
+	}
+}
+
+func TestExtractPDFTextRange(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+
+	fourPages := writeTempFile(t, dir, "four.pdf", buildPDF(testPDFSpec{
+		Pages: [][]pdfTextRun{
+			{{X: 72, Y: 700, Text: "alpha"}},
+			{{X: 72, Y: 700, Text: "bravo"}},
+			{{X: 72, Y: 700, Text: "charlie"}},
+			{{X: 72, Y: 700, Text: "delta"}},
+		},
+	}))
+
+	tests := []struct {
+		name      string
+		first     int
+		last      int
+		maxBytes  int
+		want      string
+		errSubstr string
+		wantErrIs error
+	}{
+		{name: "valid subrange", first: 2, last: 3, maxBytes: 1 << 20, want: "bravo\ncharlie"},
+		{name: "single page", first: 4, last: 4, maxBytes: 1 << 20, want: "delta"},
+		{name: "lastPage clamps to page count", first: 3, last: 99, maxBytes: 1 << 20, want: "charlie\ndelta"},
+		{name: "truncated to maxBytes", first: 1, last: 4, maxBytes: 9, want: "alpha\nbr"},
+		{name: "inverted range errors", first: 3, last: 2, maxBytes: 1 << 20, errSubstr: "invalid page range"},
+		{name: "firstPage zero errors", first: 0, last: 2, maxBytes: 1 << 20, errSubstr: "firstPage must be >= 1"},
+		{name: "firstPage past end errors", first: 5, last: 9, maxBytes: 1 << 20, errSubstr: "out of range"},
+		{name: "maxBytes zero => empty", first: 1, last: 1, maxBytes: 0, wantErrIs: ErrEmptyPDFText},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := ExtractPDFTextRange(t.Context(), fourPages, tt.first, tt.last, tt.maxBytes)
+			if tt.wantErrIs != nil {
+				if !errors.Is(err, tt.wantErrIs) {
+					t.Fatalf("expected errors.Is(%v), got %v (text=%q)", tt.wantErrIs, err, got)
+				}
+				return
+			}
+			if tt.errSubstr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.errSubstr) {
+					t.Fatalf("expected error containing %q, got %v (text=%q)", tt.errSubstr, err, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Fatalf("got %q want %q", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestBuildMinimalPDF_Sanity(t *testing.T) {
 	// Sanity check our generated PDFs have a PDF header and EOF marker.
 	p := buildMinimalPDF("Hello")
@@ -241,3 +356,92 @@ func writeTempFile(t *testing.T, dir, name string, data []byte) string {
 	}
 	return path
 }
+
+// failingWriter accepts up to n bytes, then fails.
+type failingWriter struct {
+	n   int
+	buf strings.Builder
+}
+
+func (w *failingWriter) Write(p []byte) (int, error) {
+	if len(p) > w.n {
+		w.buf.Write(p[:w.n])
+		k := w.n
+		w.n = 0
+		return k, errors.New("disk full")
+	}
+	w.n -= len(p)
+	return w.buf.Write(p)
+}
+
+func TestExtractPDFTextTo(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+
+	threePages := writeTempFile(t, dir, "three.pdf", buildPDF(testPDFSpec{
+		Pages: [][]pdfTextRun{
+			{{X: 72, Y: 700, Text: "alpha"}},
+			nil,
+			{{X: 72, Y: 700, Text: "charlie"}},
+		},
+	}))
+	emptyPath := writeTempFile(t, dir, "empty.pdf", buildMinimalPDF(""))
+
+	canceled, cancel := context.WithCancel(t.Context())
+	cancel()
+
+	tests := []struct {
+		name          string
+		ctx           context.Context
+		path          string
+		w             *failingWriter
+		maxBytes      int
+		want          string
+		wantTruncated bool
+		wantErrIs     error
+		wantErr       bool
+	}{
+		{name: "all pages", path: threePages, maxBytes: 1 << 20, want: "alpha\ncharlie"},
+		{name: "exact fit is not truncated", path: threePages, maxBytes: 13, want: "alpha\ncharlie"},
+		{name: "truncated mid page", path: threePages, maxBytes: 9, want: "alpha\ncha", wantTruncated: true},
+		{name: "truncated at page boundary", path: threePages, maxBytes: 5, want: "alpha", wantTruncated: true},
+		{name: "zero budget", path: threePages, maxBytes: 0, wantTruncated: true, wantErr: true, wantErrIs: ErrEmptyPDFText},
+		{name: "no text", path: emptyPath, maxBytes: 1 << 20, wantErr: true, wantErrIs: ErrEmptyPDFText},
+		{
+			name: "write error reports partial count", path: threePages, maxBytes: 1 << 20,
+			w: &failingWriter{n: 8}, want: "alpha\nch", wantErr: true,
+		},
+		{name: "missing file", path: filepath.Join(dir, "missing.pdf"), maxBytes: 1 << 20, wantErr: true},
+		{
+			name: "canceled context", ctx: canceled, path: threePages, maxBytes: 1 << 20,
+			wantErr: true, wantErrIs: context.Canceled,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctx := tt.ctx
+			if ctx == nil {
+				ctx = t.Context()
+			}
+			w := tt.w
+			if w == nil {
+				w = &failingWriter{n: 1 << 30}
+			}
+			n, truncated, err := ExtractPDFTextTo(ctx, tt.path, w, tt.maxBytes)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err=%v wantErr=%v", err, tt.wantErr)
+			}
+			if tt.wantErrIs != nil && !errors.Is(err, tt.wantErrIs) {
+				t.Fatalf("expected errors.Is(%v), got %v", tt.wantErrIs, err)
+			}
+			if got := w.buf.String(); got != tt.want || n != len(tt.want) {
+				t.Fatalf("wrote %q (n=%d) want %q", got, n, tt.want)
+			}
+			if truncated != tt.wantTruncated {
+				t.Fatalf("truncated=%v want %v", truncated, tt.wantTruncated)
+			}
+		})
+	}
+}
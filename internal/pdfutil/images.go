@@ -0,0 +1,260 @@
+package pdfutil
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"reflect"
+
+	"github.com/flexigpt/llmtools-go/internal/logutil"
+	"github.com/flexigpt/llmtools-go/internal/toolutil"
+	"github.com/ledongthuc/pdf"
+)
+
+const (
+	// DefaultMaxPDFImages is used when ExtractPDFImages is called with maxImages <= 0.
+	DefaultMaxPDFImages = 16
+	// maxPDFImagesTotalBytes bounds the combined size of all returned image bytes.
+	maxPDFImagesTotalBytes = toolutil.MaxFileReadBytes
+	// maxPDFImagePixels bounds a single decoded (Flate) image.
+	maxPDFImagePixels = 64 * 1024 * 1024
+)
+
+// ImageData is one image XObject extracted from a PDF page.
+type ImageData struct {
+	Page       int    `json:"page"` // 1-based
+	Name       string `json:"name"` // XObject resource name, e.g. "Im1"
+	Width      int    `json:"width"`
+	Height     int    `json:"height"`
+	MIMEType   string `json:"mimeType"`
+	Base64Data string `json:"base64Data"`
+}
+
+// ExtractPDFImages returns up to maxImages image XObjects referenced directly by
+// page resources, in page order. An image used on several pages is returned once.
+//
+//   - DCTDecode (JPEG) and JPXDecode (JPEG 2000) images are returned as their raw
+//     encoded bytes.
+//   - Flate-compressed or unfiltered 8-bit DeviceGray/DeviceRGB images are decoded
+//     and re-encoded as PNG.
+//
+// Other filters/color spaces, and images that would exceed the total byte budget,
+// are skipped with a warning log rather than failing the call. Inline images and
+// images nested in form XObjects are not extracted. Encrypted PDFs only yield
+// decoded (Flate) images, since raw bytes would still be encrypted.
+func ExtractPDFImages(ctx context.Context, path string, maxImages int) ([]ImageData, error) {
+	return toolutil.WithRecoveryResp(func() ([]ImageData, error) {
+		return extractPDFImages(ctx, path, maxImages)
+	})
+}
+
+func extractPDFImages(ctx context.Context, path string, maxImages int) ([]ImageData, error) {
+	if maxImages <= 0 {
+		maxImages = DefaultMaxPDFImages
+	}
+	f, r, err := pdf.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	encrypted := !r.Trailer().Key("Encrypt").IsNull()
+
+	out := []ImageData{}
+	seen := map[any]bool{}
+	budget := int64(maxPDFImagesTotalBytes)
+	for i := 1; i <= r.NumPage() && len(out) < maxImages; i++ {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		xobjs := r.Page(i).Resources().Key("XObject")
+		for _, name := range xobjs.Keys() {
+			if len(out) >= maxImages {
+				break
+			}
+			v := xobjs.Key(name)
+			if v.Key("Subtype").Name() != "Image" {
+				continue
+			}
+			if id, ok := objectID(v); ok {
+				if seen[id] {
+					continue
+				}
+				seen[id] = true
+			}
+
+			img, err := readPDFImage(f, v, encrypted, budget)
+			if err != nil {
+				logutil.WarnContext(ctx, "skipping PDF image", "path", path, "page", i, "name", name, "error", err)
+				continue
+			}
+			budget -= int64(len(img.data))
+			out = append(out, ImageData{
+				Page:       i,
+				Name:       name,
+				Width:      img.width,
+				Height:     img.height,
+				MIMEType:   img.mimeType,
+				Base64Data: base64.StdEncoding.EncodeToString(img.data),
+			})
+		}
+	}
+	return out, nil
+}
+
+type pdfImage struct {
+	width, height int
+	mimeType      string
+	data          []byte
+}
+
+func readPDFImage(f io.ReaderAt, v pdf.Value, encrypted bool, budget int64) (*pdfImage, error) {
+	w, h := int(v.Key("Width").Int64()), int(v.Key("Height").Int64())
+	if w <= 0 || h <= 0 {
+		return nil, fmt.Errorf("invalid image dimensions %dx%d", w, h)
+	}
+	img := &pdfImage{width: w, height: h}
+
+	filter := v.Key("Filter")
+	if filter.Kind() == pdf.Array && filter.Len() == 1 {
+		filter = filter.Index(0)
+	}
+	switch filter.Kind() {
+	case pdf.Null:
+	case pdf.Name:
+		switch filter.Name() {
+		case "FlateDecode":
+		case "DCTDecode", "JPXDecode":
+			if encrypted {
+				return nil, fmt.Errorf("raw %s bytes unavailable in an encrypted PDF", filter.Name())
+			}
+			length := v.Key("Length").Int64()
+			if length <= 0 || length > budget {
+				return nil, fmt.Errorf("image stream size %d exceeds remaining budget %d", length, budget)
+			}
+			data, err := rawStreamBytes(f, v, length)
+			if err != nil {
+				return nil, err
+			}
+			img.mimeType = "image/jpeg"
+			if filter.Name() == "JPXDecode" {
+				img.mimeType = "image/jp2"
+			}
+			img.data = data
+			return img, nil
+		default:
+			return nil, fmt.Errorf("unsupported image filter %s", filter.Name())
+		}
+	default:
+		return nil, fmt.Errorf("unsupported image filter chain %v", filter)
+	}
+
+	// Decoded samples: only 8-bit gray/RGB are mapped to pixels.
+	if bpc := v.Key("BitsPerComponent").Int64(); bpc != 8 {
+		return nil, fmt.Errorf("unsupported bits per component %d", bpc)
+	}
+	var comps int
+	switch cs := v.Key("ColorSpace"); cs.Name() {
+	case "DeviceGray":
+		comps = 1
+	case "DeviceRGB":
+		comps = 3
+	default:
+		return nil, fmt.Errorf("unsupported color space %v", cs)
+	}
+	if w*h > maxPDFImagePixels {
+		return nil, fmt.Errorf("image %dx%d exceeds pixel limit", w, h)
+	}
+	n := w * h * comps
+	if int64(n) > budget {
+		return nil, fmt.Errorf("decoded image size %d exceeds remaining budget %d", n, budget)
+	}
+
+	samples := make([]byte, n)
+	if err := readStreamFull(v, samples); err != nil {
+		return nil, err
+	}
+	var decoded image.Image
+	if comps == 1 {
+		decoded = &image.Gray{Pix: samples, Stride: w, Rect: image.Rect(0, 0, w, h)}
+	} else {
+		rgba := image.NewNRGBA(image.Rect(0, 0, w, h))
+		for i := range w * h {
+			rgba.SetNRGBA(i%w, i/w, color.NRGBA{samples[3*i], samples[3*i+1], samples[3*i+2], 0xff})
+		}
+		decoded = rgba
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, decoded); err != nil {
+		return nil, err
+	}
+	if int64(buf.Len()) > budget {
+		return nil, fmt.Errorf("encoded image size %d exceeds remaining budget %d", buf.Len(), budget)
+	}
+	img.mimeType = "image/png"
+	img.data = buf.Bytes()
+	return img, nil
+}
+
+// readStreamFull fills dst from the stream's decoded data. The library panics on
+// unsupported predictors and corrupt data, so that is converted to an error.
+func readStreamFull(v pdf.Value, dst []byte) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("decode image stream: %v", r)
+		}
+	}()
+	rc := v.Reader()
+	defer rc.Close()
+	if _, err := io.ReadFull(rc, dst); err != nil {
+		return fmt.Errorf("decode image stream: %w", err)
+	}
+	return nil
+}
+
+// The pdf library only exposes decoded stream data and has no DCT/JPX decoder,
+// so raw image bytes are read from the file at the stream's offset. The offset
+// and object id are unexported; reflection reads them without modifying anything.
+
+func streamField(v pdf.Value, field string) (reflect.Value, bool) {
+	data := reflect.ValueOf(v).FieldByName("data")
+	if !data.IsValid() || data.Kind() != reflect.Interface || data.IsNil() {
+		return reflect.Value{}, false
+	}
+	s := data.Elem()
+	if s.Kind() != reflect.Struct {
+		return reflect.Value{}, false
+	}
+	fv := s.FieldByName(field)
+	return fv, fv.IsValid()
+}
+
+func rawStreamBytes(f io.ReaderAt, v pdf.Value, length int64) ([]byte, error) {
+	off, ok := streamField(v, "offset")
+	if !ok || off.Kind() != reflect.Int64 {
+		return nil, fmt.Errorf("raw stream data unavailable")
+	}
+	data := make([]byte, length)
+	if _, err := f.ReadAt(data, off.Int()); err != nil {
+		return nil, fmt.Errorf("read raw image stream: %w", err)
+	}
+	return data, nil
+}
+
+// objectID returns a comparable identity for an indirect stream object, used to
+// return an image shared by several pages only once.
+func objectID(v pdf.Value) (any, bool) {
+	ptr, ok := streamField(v, "ptr")
+	if !ok || ptr.Kind() != reflect.Struct || ptr.NumField() < 2 {
+		return nil, false
+	}
+	id, gen := ptr.Field(0), ptr.Field(1)
+	if !id.CanUint() || !gen.CanUint() {
+		return nil, false
+	}
+	return [2]uint64{id.Uint(), gen.Uint()}, true
+}
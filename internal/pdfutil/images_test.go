@@ -0,0 +1,178 @@
+package pdfutil
+
+import (
+	"bytes"
+	"compress/zlib"
+	"context"
+	"encoding/base64"
+	"errors"
+	"image/png"
+	"path/filepath"
+	"testing"
+)
+
+func TestExtractPDFImages(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+
+	// 2x2 RGB: red, green / blue, white.
+	var flate bytes.Buffer
+	zw := zlib.NewWriter(&flate)
+	_, _ = zw.Write([]byte{255, 0, 0, 0, 255, 0, 0, 0, 255, 255, 255, 255})
+	_ = zw.Close()
+	rgb := testPDFImage{
+		Name: "Im1",
+		Dict: "/Width 2 /Height 2 /ColorSpace /DeviceRGB /BitsPerComponent 8 /Filter /FlateDecode",
+		Data: flate.Bytes(),
+	}
+	jpegBytes := []byte{0xff, 0xd8, 0xff, 0xe0, 'J', 'F', 'I', 'F', 0x00, 0xff, 0xd9}
+	jpeg := testPDFImage{
+		Name: "Im2",
+		Dict: "/Width 4 /Height 3 /ColorSpace /DeviceRGB /BitsPerComponent 8 /Filter /DCTDecode",
+		Data: jpegBytes,
+	}
+	fax := testPDFImage{
+		Name: "Im3",
+		Dict: "/Width 8 /Height 8 /ColorSpace /DeviceGray /BitsPerComponent 1 /Filter /CCITTFaxDecode",
+		Data: []byte{0x00, 0x01},
+	}
+	gray := testPDFImage{
+		Name: "Gray",
+		Dict: "/Width 3 /Height 1 /ColorSpace /DeviceGray /BitsPerComponent 8",
+		Data: []byte{0, 128, 255},
+	}
+
+	page := []pdfTextRun{{X: 72, Y: 700, Text: "figure"}}
+	mixed := writeTempFile(t, dir, "mixed.pdf", buildPDF(testPDFSpec{
+		Pages:  [][]pdfTextRun{page, page, page},
+		Images: [][]testPDFImage{{rgb}, nil, {fax, jpeg, gray}},
+	}))
+	noImages := writeTempFile(t, dir, "text.pdf", buildPDF(testPDFSpec{
+		Pages: [][]pdfTextRun{page},
+	}))
+
+	canceled, cancel := context.WithCancel(t.Context())
+	cancel()
+
+	type want struct {
+		page          int
+		name          string
+		width, height int
+		mime          string
+	}
+	tests := []struct {
+		name      string
+		ctx       context.Context
+		path      string
+		maxImages int
+		want      []want
+		wantErrIs error
+		wantErr   bool
+	}{
+		{
+			name: "decodes flate, passes through jpeg, skips unsupported filter",
+			path: mixed,
+			want: []want{
+				{1, "Im1", 2, 2, "image/png"},
+				{3, "Gray", 3, 1, "image/png"},
+				{3, "Im2", 4, 3, "image/jpeg"},
+			},
+		},
+		{
+			name:      "maxImages limits results",
+			path:      mixed,
+			maxImages: 2,
+			want: []want{
+				{1, "Im1", 2, 2, "image/png"},
+				{3, "Gray", 3, 1, "image/png"},
+			},
+		},
+		{name: "pdf without images yields empty slice", path: noImages, want: []want{}},
+		{name: "missing file errors", path: filepath.Join(dir, "missing.pdf"), wantErr: true},
+		{name: "canceled context", ctx: canceled, path: mixed, wantErr: true, wantErrIs: context.Canceled},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctx := tt.ctx
+			if ctx == nil {
+				ctx = t.Context()
+			}
+			got, err := ExtractPDFImages(ctx, tt.path, tt.maxImages)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected error, got %+v", got)
+				}
+				if tt.wantErrIs != nil && !errors.Is(err, tt.wantErrIs) {
+					t.Fatalf("expected errors.Is(%v), got %v", tt.wantErrIs, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %d images want %d: %+v", len(got), len(tt.want), got)
+			}
+			for i, w := range tt.want {
+				g := got[i]
+				if g.Page != w.page || g.Name != w.name || g.Width != w.width || g.Height != w.height ||
+					g.MIMEType != w.mime {
+					t.Fatalf("image %d = %+v want %+v", i, g, w)
+				}
+				raw, err := base64.StdEncoding.DecodeString(g.Base64Data)
+				if err != nil {
+					t.Fatalf("image %d: invalid base64: %v", i, err)
+				}
+				switch g.MIMEType {
+				case "image/jpeg":
+					if !bytes.Equal(raw, jpegBytes) {
+						t.Fatalf("jpeg bytes = %x want %x", raw, jpegBytes)
+					}
+				case "image/png":
+					img, err := png.Decode(bytes.NewReader(raw))
+					if err != nil {
+						t.Fatalf("image %d: invalid png: %v", i, err)
+					}
+					if b := img.Bounds(); b.Dx() != w.width || b.Dy() != w.height {
+						t.Fatalf("png bounds %v want %dx%d", b, w.width, w.height)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestExtractPDFImagesPixels(t *testing.T) {
+	t.Parallel()
+	var flate bytes.Buffer
+	zw := zlib.NewWriter(&flate)
+	_, _ = zw.Write([]byte{255, 0, 0, 0, 255, 0, 0, 0, 255, 255, 255, 255})
+	_ = zw.Close()
+	p := writeTempFile(t, t.TempDir(), "rgb.pdf", buildPDF(testPDFSpec{
+		Pages: [][]pdfTextRun{nil},
+		Images: [][]testPDFImage{{{
+			Name: "Im1",
+			Dict: "/Width 2 /Height 2 /ColorSpace /DeviceRGB /BitsPerComponent 8 /Filter /FlateDecode",
+			Data: flate.Bytes(),
+		}}},
+	}))
+
+	got, err := ExtractPDFImages(t.Context(), p, 0)
+	if err != nil || len(got) != 1 {
+		t.Fatalf("ExtractPDFImages = %+v, %v", got, err)
+	}
+	raw, _ := base64.StdEncoding.DecodeString(got[0].Base64Data)
+	img, err := png.Decode(bytes.NewReader(raw))
+	if err != nil {
+		t.Fatalf("invalid png: %v", err)
+	}
+	wantRGB := [][3]uint32{{255, 0, 0}, {0, 255, 0}, {0, 0, 255}, {255, 255, 255}}
+	for i, w := range wantRGB {
+		r, g, b, _ := img.At(i%2, i/2).RGBA()
+		if got := [3]uint32{r >> 8, g >> 8, b >> 8}; got != w {
+			t.Fatalf("pixel %d = %v want %v", i, got, w)
+		}
+	}
+}
@@ -0,0 +1,49 @@
+package pdfutil
+
+import (
+	"context"
+
+	"github.com/flexigpt/llmtools-go/internal/toolutil"
+	"github.com/ledongthuc/pdf"
+)
+
+// DocumentInfo holds the page count and the common /Info dictionary entries.
+// Metadata fields are "" when absent. CreationDate is the raw PDF date string
+// (e.g. "D:20240131120000Z").
+type DocumentInfo struct {
+	PageCount    int    `json:"pageCount"`
+	Title        string `json:"title,omitempty"`
+	Author       string `json:"author,omitempty"`
+	Subject      string `json:"subject,omitempty"`
+	Creator      string `json:"creator,omitempty"`
+	CreationDate string `json:"creationDate,omitempty"`
+}
+
+// PDFInfo returns the page count and document metadata without extracting any text.
+func PDFInfo(ctx context.Context, path string) (DocumentInfo, error) {
+	return toolutil.WithRecoveryResp(func() (DocumentInfo, error) {
+		return pdfInfo(ctx, path)
+	})
+}
+
+func pdfInfo(ctx context.Context, path string) (DocumentInfo, error) {
+	if err := ctx.Err(); err != nil {
+		return DocumentInfo{}, err
+	}
+	f, r, err := pdf.Open(path)
+	if err != nil {
+		return DocumentInfo{}, err
+	}
+	defer f.Close()
+
+	// Missing /Info (or a missing key) yields a null Value whose Text() is "".
+	info := r.Trailer().Key("Info")
+	return DocumentInfo{
+		PageCount:    r.NumPage(),
+		Title:        info.Key("Title").Text(),
+		Author:       info.Key("Author").Text(),
+		Subject:      info.Key("Subject").Text(),
+		Creator:      info.Key("Creator").Text(),
+		CreationDate: info.Key("CreationDate").Text(),
+	}, nil
+}
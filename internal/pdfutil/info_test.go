@@ -0,0 +1,80 @@
+package pdfutil
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+)
+
+func TestPDFInfo(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+
+	page := []pdfTextRun{{X: 72, Y: 700, Text: "page"}}
+	withInfo := writeTempFile(t, dir, "info.pdf", buildPDF(testPDFSpec{
+		Pages: [][]pdfTextRun{page, page, page},
+		Info: map[string]string{
+			"Title":        "Quarterly Report",
+			"Author":       "Jane Doe",
+			"Creator":      "Writer",
+			"CreationDate": "D:20240131120000Z",
+		},
+	}))
+	noInfo := writeTempFile(t, dir, "noinfo.pdf", buildPDF(testPDFSpec{
+		Pages: [][]pdfTextRun{page},
+	}))
+
+	canceled, cancel := context.WithCancel(t.Context())
+	cancel()
+
+	tests := []struct {
+		name      string
+		ctx       context.Context
+		path      string
+		want      DocumentInfo
+		wantErr   bool
+		wantErrIs error
+	}{
+		{
+			name: "three pages with metadata",
+			path: withInfo,
+			want: DocumentInfo{
+				PageCount:    3,
+				Title:        "Quarterly Report",
+				Author:       "Jane Doe",
+				Creator:      "Writer",
+				CreationDate: "D:20240131120000Z",
+			},
+		},
+		{name: "missing info dictionary yields empty fields", path: noInfo, want: DocumentInfo{PageCount: 1}},
+		{name: "missing file errors", path: filepath.Join(dir, "missing.pdf"), wantErr: true},
+		{name: "canceled context", ctx: canceled, path: withInfo, wantErr: true, wantErrIs: context.Canceled},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctx := tt.ctx
+			if ctx == nil {
+				ctx = t.Context()
+			}
+			got, err := PDFInfo(ctx, tt.path)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected error, got %+v", got)
+				}
+				if tt.wantErrIs != nil && !errors.Is(err, tt.wantErrIs) {
+					t.Fatalf("expected errors.Is(%v), got %v", tt.wantErrIs, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Fatalf("got %+v want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,328 @@
+package pdfutil
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/flexigpt/llmtools-go/internal/toolutil"
+	"github.com/ledongthuc/pdf"
+)
+
+const (
+	// minGutterFontMultiple is the minimum width of a vertical whitespace band,
+	// in multiples of the median font size, for it to be treated as a column gutter.
+	minGutterFontMultiple = 2.0
+	// sameLineFontFraction is the maximum baseline difference, as a fraction of
+	// the font size, for two glyphs to be placed on the same line.
+	sameLineFontFraction = 0.5
+	// wordGapFontFraction is the horizontal gap, as a fraction of the font size,
+	// above which a space is inserted between adjacent glyphs.
+	wordGapFontFraction = 0.25
+	// fallbackGlyphWidthFraction approximates a glyph's width (as a fraction of
+	// the font size) when the font has no width table.
+	fallbackGlyphWidthFraction = 0.5
+)
+
+// ExtractPDFTextLayout extracts text in reading order using glyph coordinates:
+// each page is split into columns at vertical whitespace gutters, and each column
+// is emitted top-to-bottom, left-to-right. Pages are separated by a blank line.
+//
+// If no page yields positioned glyphs, it falls back to ExtractPDFTextSafe.
+// Output is truncated to maxBytes (never splitting a UTF-8 sequence).
+func ExtractPDFTextLayout(ctx context.Context, path string, maxBytes int) (string, error) {
+	return toolutil.WithRecoveryResp(func() (string, error) {
+		return extractPDFTextPositioned(ctx, path, maxBytes, "\n\n", layoutPageText)
+	})
+}
+
+// ExtractPDFTextSafeLayout extracts text preserving the page's spatial layout:
+// glyphs are placed on a fixed-width character grid derived from their x
+// coordinates, and wide vertical gaps become blank lines. Table cells and
+// side-by-side columns therefore stay aligned on the same output rows (unlike
+// ExtractPDFTextLayout, which emits whole columns one after another).
+// Each page with text after the first starts on a new line with a form feed
+// ('\f'). This is slower than the simple extraction; if no page yields
+// positioned glyphs it falls back to ExtractPDFTextSafe.
+// Output is truncated to maxBytes (never splitting a UTF-8 sequence).
+func ExtractPDFTextSafeLayout(ctx context.Context, path string, maxBytes int) (string, error) {
+	return toolutil.WithRecoveryResp(func() (string, error) {
+		return extractPDFTextPositioned(ctx, path, maxBytes, "\n\f", renderGrid)
+	})
+}
+
+// extractPDFTextPositioned renders the positioned glyphs of each page with
+// renderPage and joins the non-empty results with pageSep, stopping once the
+// output exceeds maxBytes. If no page yields positioned glyphs it falls back to
+// the simple extraction.
+func extractPDFTextPositioned(
+	ctx context.Context,
+	path string,
+	maxBytes int,
+	pageSep string,
+	renderPage func(glyphs []pdf.Text) string,
+) (string, error) {
+	f, r, err := pdf.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	var sb strings.Builder
+	positioned := false
+	for i := 1; i <= r.NumPage(); i++ {
+		if err := ctx.Err(); err != nil {
+			return "", err
+		}
+		if sb.Len() > maxBytes {
+			break
+		}
+		// Malformed content streams count as pages without positioned glyphs.
+		glyphs, err := pageGlyphs(r.Page(i))
+		if err != nil || len(glyphs) == 0 {
+			continue
+		}
+		positioned = true
+		pageText := renderPage(glyphs)
+		if pageText == "" {
+			continue
+		}
+		if sb.Len() > 0 {
+			sb.WriteString(pageSep)
+		}
+		sb.WriteString(pageText)
+	}
+
+	if !positioned {
+		// Coordinates unavailable: the simple extraction is the best we can do.
+		text, _, err := extractPDFTextSafe(ctx, path, maxBytes)
+		return text, err
+	}
+
+	text := strings.TrimSpace(truncateUTF8(sb.String(), maxBytes))
+	if text == "" {
+		return "", ErrEmptyPDFText
+	}
+	return text, nil
+}
+
+// renderGrid lays out a page on a character grid: one cell is the median glyph
+// width, so x positions map to columns and aligned text stays aligned.
+func renderGrid(glyphs []pdf.Text) string {
+	widths := make([]float64, 0, len(glyphs))
+	sizes := make([]float64, 0, len(glyphs))
+	minX := glyphs[0].X
+	for _, g := range glyphs {
+		if !isBlankGlyph(g) {
+			widths = append(widths, glyphWidth(g))
+			sizes = append(sizes, g.FontSize)
+		}
+		minX = min(minX, g.X)
+	}
+	if len(widths) == 0 {
+		return ""
+	}
+	slices.Sort(widths)
+	slices.Sort(sizes)
+	cell := max(widths[len(widths)/2], 0.1)
+	paraGap := sizes[len(sizes)/2] * minGutterFontMultiple
+
+	var sb strings.Builder
+	prevY := 0.0
+	for li, line := range groupLines(glyphs) {
+		if li > 0 {
+			sb.WriteByte('\n')
+			if prevY-line[0].Y > paraGap {
+				sb.WriteByte('\n')
+			}
+		}
+		prevY = line[0].Y
+
+		col := 0
+		var lineSB strings.Builder
+		for _, g := range line {
+			want := int((g.X-minX)/cell + 0.5)
+			if pad := want - col; pad > 0 {
+				lineSB.WriteString(strings.Repeat(" ", pad))
+				col = want
+			}
+			lineSB.WriteString(g.S)
+			col += utf8.RuneCountInString(g.S)
+		}
+		sb.WriteString(strings.TrimRight(lineSB.String(), " "))
+	}
+	return sb.String()
+}
+
+// layoutPageText returns the layout-ordered text for a page's glyphs: columns
+// in order, separated by blank lines.
+func layoutPageText(glyphs []pdf.Text) string {
+	cols := splitColumns(glyphs)
+	parts := make([]string, 0, len(cols))
+	for _, col := range cols {
+		if s := strings.TrimSpace(renderLines(col)); s != "" {
+			parts = append(parts, s)
+		}
+	}
+	return strings.Join(parts, "\n\n")
+}
+
+func pageGlyphs(p pdf.Page) (glyphs []pdf.Text, err error) {
+	defer func() {
+		// The pdf library panics on malformed content streams.
+		if r := recover(); r != nil {
+			glyphs = nil
+			err = fmt.Errorf("read page content: %v", r)
+		}
+	}()
+	return p.Content().Text, nil
+}
+
+func glyphWidth(g pdf.Text) float64 {
+	if g.W > 0 {
+		return g.W
+	}
+	return g.FontSize * fallbackGlyphWidthFraction
+}
+
+func isBlankGlyph(g pdf.Text) bool {
+	return strings.TrimSpace(g.S) == ""
+}
+
+// splitColumns partitions glyphs into columns (left to right) separated by
+// vertical bands that no visible glyph overlaps.
+func splitColumns(glyphs []pdf.Text) [][]pdf.Text {
+	type span struct{ lo, hi float64 }
+	spans := make([]span, 0, len(glyphs))
+	sizes := make([]float64, 0, len(glyphs))
+	for _, g := range glyphs {
+		if isBlankGlyph(g) {
+			continue
+		}
+		spans = append(spans, span{g.X, g.X + glyphWidth(g)})
+		sizes = append(sizes, g.FontSize)
+	}
+	if len(spans) == 0 {
+		return [][]pdf.Text{glyphs}
+	}
+	slices.SortFunc(spans, func(a, b span) int {
+		switch {
+		case a.lo < b.lo:
+			return -1
+		case a.lo > b.lo:
+			return 1
+		}
+		return 0
+	})
+	slices.Sort(sizes)
+	minGutter := sizes[len(sizes)/2] * minGutterFontMultiple
+
+	// Merge overlapping spans; gaps between merged spans wide enough are gutters.
+	var cuts []float64
+	cur := spans[0]
+	for _, s := range spans[1:] {
+		if s.lo <= cur.hi {
+			cur.hi = max(cur.hi, s.hi)
+			continue
+		}
+		if s.lo-cur.hi >= minGutter {
+			cuts = append(cuts, (cur.hi+s.lo)/2)
+		}
+		cur = s
+	}
+	if len(cuts) == 0 {
+		return [][]pdf.Text{glyphs}
+	}
+
+	cols := make([][]pdf.Text, len(cuts)+1)
+	for _, g := range glyphs {
+		i, _ := slices.BinarySearch(cuts, g.X)
+		cols[i] = append(cols[i], g)
+	}
+	return cols
+}
+
+// groupLines groups glyphs into lines by baseline (top to bottom), each line
+// sorted left to right.
+func groupLines(glyphs []pdf.Text) [][]pdf.Text {
+	gs := slices.Clone(glyphs)
+	// Stable so glyphs sharing a position keep content-stream order.
+	slices.SortStableFunc(gs, func(a, b pdf.Text) int {
+		switch {
+		case a.Y > b.Y:
+			return -1
+		case a.Y < b.Y:
+			return 1
+		}
+		return 0
+	})
+
+	var lines [][]pdf.Text
+	for _, g := range gs {
+		if n := len(lines); n > 0 {
+			ref := lines[n-1][0]
+			tol := max(ref.FontSize, g.FontSize) * sameLineFontFraction
+			if ref.Y-g.Y <= tol {
+				lines[n-1] = append(lines[n-1], g)
+				continue
+			}
+		}
+		lines = append(lines, []pdf.Text{g})
+	}
+	for _, line := range lines {
+		slices.SortStableFunc(line, func(a, b pdf.Text) int {
+			switch {
+			case a.X < b.X:
+				return -1
+			case a.X > b.X:
+				return 1
+			}
+			return 0
+		})
+	}
+	return lines
+}
+
+// renderLines joins each line left to right, inserting spaces at visible gaps.
+func renderLines(glyphs []pdf.Text) string {
+	var sb strings.Builder
+	for li, line := range groupLines(glyphs) {
+		if li > 0 {
+			sb.WriteByte('\n')
+		}
+		var lineSB strings.Builder
+		prevEnd, havePrev := 0.0, false
+		for _, g := range line {
+			if havePrev && !isBlankGlyph(g) && g.X-prevEnd > g.FontSize*wordGapFontFraction {
+				if s := lineSB.String(); s != "" && !strings.HasSuffix(s, " ") {
+					lineSB.WriteByte(' ')
+				}
+			}
+			lineSB.WriteString(g.S)
+			prevEnd, havePrev = max(prevEnd, g.X+glyphWidth(g)), true
+		}
+		sb.WriteString(strings.TrimRight(lineSB.String(), " "))
+	}
+	return sb.String()
+}
+
+// truncateUTF8 returns at most maxBytes bytes of s without splitting a rune.
+func truncateUTF8(s string, maxBytes int) string {
+	if maxBytes <= 0 {
+		return ""
+	}
+	if len(s) <= maxBytes {
+		return s
+	}
+	s = s[:maxBytes]
+	for len(s) > 0 {
+		r, size := utf8.DecodeLastRuneInString(s)
+		if r != utf8.RuneError || size > 1 {
+			break
+		}
+		s = s[:len(s)-1]
+	}
+	return s
+}
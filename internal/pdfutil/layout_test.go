@@ -0,0 +1,191 @@
+package pdfutil
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestExtractPDFTextLayout(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+
+	left := []string{"Left alpha one", "Left alpha two", "Left alpha three"}
+	right := []string{"Right beta one", "Right beta two", "Right beta three"}
+	twoCol := writeTempFile(t, dir, "twocol.pdf", buildPDF(testPDFSpec{
+		Pages: [][]pdfTextRun{twoColumnRuns(left, right)},
+	}))
+	twoColNoWidths := writeTempFile(t, dir, "twocol-nowidths.pdf", buildPDF(testPDFSpec{
+		Pages:    [][]pdfTextRun{twoColumnRuns(left, right)},
+		NoWidths: true,
+	}))
+	multiPage := writeTempFile(t, dir, "pages.pdf", buildPDF(testPDFSpec{
+		Pages: [][]pdfTextRun{
+			{{X: 72, Y: 700, Text: "Page one"}},
+			nil,
+			{{X: 72, Y: 700, Text: "Page three"}},
+		},
+	}))
+	emptyPath := writeTempFile(t, dir, "empty.pdf", buildMinimalPDF(""))
+
+	wantTwoCol := strings.Join(left, "\n") + "\n\n" + strings.Join(right, "\n")
+
+	canceled, cancel := context.WithCancel(t.Context())
+	cancel()
+
+	tests := []struct {
+		name      string
+		ctx       context.Context
+		path      string
+		maxBytes  int
+		want      string
+		wantErr   bool
+		wantErrIs error
+	}{
+		{name: "two columns are not interleaved", path: twoCol, maxBytes: 1 << 20, want: wantTwoCol},
+		{name: "two columns without font widths", path: twoColNoWidths, maxBytes: 1 << 20, want: wantTwoCol},
+		{name: "pages separated, empty page skipped", path: multiPage, maxBytes: 1 << 20, want: "Page one\n\nPage three"},
+		{name: "truncated to maxBytes", path: twoCol, maxBytes: 9, want: "Left alph"},
+		{name: "empty text errors", path: emptyPath, maxBytes: 1 << 20, wantErr: true, wantErrIs: ErrEmptyPDFText},
+		{name: "missing file errors", path: filepath.Join(dir, "missing.pdf"), maxBytes: 1 << 20, wantErr: true},
+		{
+			name: "canceled context", ctx: canceled, path: twoCol, maxBytes: 1 << 20,
+			wantErr: true, wantErrIs: context.Canceled,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctx := tt.ctx
+			if ctx == nil {
+				ctx = t.Context()
+			}
+			got, err := ExtractPDFTextLayout(ctx, tt.path, tt.maxBytes)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected error, got nil; text=%q", got)
+				}
+				if tt.wantErrIs != nil && !errors.Is(err, tt.wantErrIs) {
+					t.Fatalf("expected errors.Is(%v), got %v", tt.wantErrIs, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Fatalf("text mismatch:\ngot:\n%s\nwant:\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExtractPDFTextSafeLayout(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+
+	left := []string{"Left alpha one", "Left alpha two", "Left alpha three"}
+	right := []string{"Right beta one", "Right beta two", "Right beta three"}
+
+	for _, noWidths := range []bool{false, true} {
+		name := "with font widths"
+		if noWidths {
+			name = "without font widths"
+		}
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			p := writeTempFile(t, dir, strings.ReplaceAll(name, " ", "-")+".pdf", buildPDF(testPDFSpec{
+				Pages:    [][]pdfTextRun{twoColumnRuns(left, right)},
+				NoWidths: noWidths,
+			}))
+			got, err := ExtractPDFTextSafeLayout(t.Context(), p, 1<<20)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			lines := strings.Split(got, "\n")
+			if len(lines) != len(left) {
+				t.Fatalf("got %d lines, want %d:\n%s", len(lines), len(left), got)
+			}
+			rightCol := -1
+			for i, line := range lines {
+				// Rows read left to right, top to bottom.
+				if !strings.HasPrefix(line, left[i]) {
+					t.Fatalf("line %d %q does not start with %q", i, line, left[i])
+				}
+				col := strings.Index(line, right[i])
+				if col <= len(left[i]) {
+					t.Fatalf("line %d %q: right column text missing or not separated", i, line)
+				}
+				// Columns stay aligned across rows.
+				if rightCol == -1 {
+					rightCol = col
+				} else if col != rightCol {
+					t.Fatalf("right column misaligned: line %d at %d, line 0 at %d\n%s", i, col, rightCol, got)
+				}
+			}
+		})
+	}
+
+	t.Run("pages separated by form feed, paragraph gap kept", func(t *testing.T) {
+		t.Parallel()
+		p := writeTempFile(t, dir, "pages.pdf", buildPDF(testPDFSpec{
+			Pages: [][]pdfTextRun{
+				{{X: 72, Y: 700, Text: "Title"}, {X: 72, Y: 650, Text: "Body"}},
+				{{X: 108, Y: 700, Text: "Next"}},
+			},
+		}))
+		got, err := ExtractPDFTextSafeLayout(t.Context(), p, 1<<20)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if want := "Title\n\nBody\n\fNext"; got != want {
+			t.Fatalf("got %q want %q", got, want)
+		}
+	})
+
+	t.Run("empty text", func(t *testing.T) {
+		t.Parallel()
+		p := writeTempFile(t, dir, "empty.pdf", buildMinimalPDF(""))
+		if _, err := ExtractPDFTextSafeLayout(t.Context(), p, 1<<20); !errors.Is(err, ErrEmptyPDFText) {
+			t.Fatalf("expected ErrEmptyPDFText, got %v", err)
+		}
+	})
+
+	t.Run("canceled context", func(t *testing.T) {
+		t.Parallel()
+		p := writeTempFile(t, dir, "cancel.pdf", buildPDF(testPDFSpec{
+			Pages: [][]pdfTextRun{{{X: 72, Y: 700, Text: "x"}}},
+		}))
+		ctx, cancel := context.WithCancel(t.Context())
+		cancel()
+		if _, err := ExtractPDFTextSafeLayout(ctx, p, 1<<20); !errors.Is(err, context.Canceled) {
+			t.Fatalf("expected context.Canceled, got %v", err)
+		}
+	})
+}
+
+func TestTruncateUTF8(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name string
+		in   string
+		max  int
+		want string
+	}{
+		{name: "fits", in: "abc", max: 5, want: "abc"},
+		{name: "ascii cut", in: "abcdef", max: 3, want: "abc"},
+		{name: "does not split rune", in: "a€b", max: 3, want: "a"},
+		{name: "zero", in: "abc", max: 0, want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := truncateUTF8(tt.in, tt.max); got != tt.want {
+				t.Fatalf("got %q want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,80 @@
+package pdfutil
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"errors"
+	"image"
+	_ "image/jpeg" // register the JPEG decoder for DCTDecode page images
+	"strings"
+
+	"github.com/flexigpt/llmtools-go/internal/logutil"
+	"github.com/flexigpt/llmtools-go/internal/toolutil"
+)
+
+// maxOCRImages bounds the page images passed to an OCR function in one call.
+const maxOCRImages = 256
+
+// OCRFunc recognizes the text in a page image. Implementations wrap an OCR
+// engine of the caller's choice; none is bundled.
+type OCRFunc func(ctx context.Context, img image.Image) (string, error)
+
+// ExtractPDFTextWithOCR extracts text like ExtractPDFTextSafe. If the PDF has no
+// text layer (ErrEmptyPDFText) and ocr is non-nil, the page images (as found by
+// ExtractPDFImages) are decoded and passed to ocr in page order, and the
+// recognized texts, one per line, are returned under the same maxBytes limit.
+// JPEG 2000 images cannot be decoded and are skipped with a warning log. An OCR
+// error aborts the call; if OCR yields no text, ErrEmptyPDFText is returned.
+func ExtractPDFTextWithOCR(ctx context.Context, path string, maxBytes int, ocr OCRFunc) (string, error) {
+	return toolutil.WithRecoveryResp(func() (string, error) {
+		return extractPDFTextWithOCR(ctx, path, maxBytes, ocr)
+	})
+}
+
+func extractPDFTextWithOCR(ctx context.Context, path string, maxBytes int, ocr OCRFunc) (string, error) {
+	text, _, err := extractPDFTextSafe(ctx, path, maxBytes)
+	if ocr == nil || !errors.Is(err, ErrEmptyPDFText) {
+		return text, err
+	}
+
+	images, err := extractPDFImages(ctx, path, maxOCRImages)
+	if err != nil {
+		return "", err
+	}
+	var sb strings.Builder
+	for _, im := range images {
+		if sb.Len() >= maxBytes {
+			break
+		}
+		if err := ctx.Err(); err != nil {
+			return "", err
+		}
+		data, err := base64.StdEncoding.DecodeString(im.Base64Data)
+		if err != nil {
+			return "", err
+		}
+		img, _, err := image.Decode(bytes.NewReader(data))
+		if err != nil {
+			logutil.WarnContext(ctx, "skipping PDF image for OCR", "path", path, "page", im.Page, "name", im.Name, "error", err)
+			continue
+		}
+		pageText, err := ocr(ctx, img)
+		if err != nil {
+			return "", err
+		}
+		if pageText = strings.TrimSpace(pageText); pageText == "" {
+			continue
+		}
+		if sb.Len() > 0 {
+			sb.WriteByte('\n')
+		}
+		sb.WriteString(pageText)
+	}
+
+	text = strings.TrimSpace(truncateUTF8(sb.String(), maxBytes))
+	if text == "" {
+		return "", ErrEmptyPDFText
+	}
+	return text, nil
+}
@@ -0,0 +1,81 @@
+package pdfutil
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"image"
+	"testing"
+)
+
+func TestExtractPDFTextWithOCR(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+
+	gray := func(name string, width int) testPDFImage {
+		return testPDFImage{
+			Name: name,
+			Dict: fmt.Sprintf("/Width %d /Height 1 /ColorSpace /DeviceGray /BitsPerComponent 8", width),
+			Data: make([]byte, width),
+		}
+	}
+	scanned := writeTempFile(t, dir, "scanned.pdf", buildPDF(testPDFSpec{
+		Pages:  [][]pdfTextRun{nil, nil},
+		Images: [][]testPDFImage{{gray("Im1", 2)}, {gray("Im1", 3)}},
+	}))
+	textPDF := writeTempFile(t, dir, "text.pdf", buildMinimalPDF("Hello PDF"))
+	blank := writeTempFile(t, dir, "blank.pdf", buildMinimalPDF(""))
+
+	// The fake engine "recognizes" each image by its width.
+	fakeOCR := func(_ context.Context, img image.Image) (string, error) {
+		return fmt.Sprintf("scanned page %d", img.Bounds().Dx()), nil
+	}
+	errOCR := errors.New("ocr failed")
+
+	tests := []struct {
+		name      string
+		path      string
+		maxBytes  int
+		ocr       OCRFunc
+		want      string
+		wantErrIs error
+	}{
+		{
+			name: "image-only pdf uses ocr", path: scanned, maxBytes: 1 << 20, ocr: fakeOCR,
+			want: "scanned page 2\nscanned page 3",
+		},
+		{name: "ocr text is limited", path: scanned, maxBytes: 9, ocr: fakeOCR, want: "scanned p"},
+		{name: "text layer skips ocr", path: textPDF, maxBytes: 1 << 20, ocr: errorOCR(errOCR), want: "Hello PDF"},
+		{name: "nil ocr keeps empty error", path: scanned, maxBytes: 1 << 20, wantErrIs: ErrEmptyPDFText},
+		{name: "no images", path: blank, maxBytes: 1 << 20, ocr: fakeOCR, wantErrIs: ErrEmptyPDFText},
+		{
+			name: "empty ocr output", path: scanned, maxBytes: 1 << 20,
+			ocr:       func(context.Context, image.Image) (string, error) { return "  ", nil },
+			wantErrIs: ErrEmptyPDFText,
+		},
+		{name: "ocr error", path: scanned, maxBytes: 1 << 20, ocr: errorOCR(errOCR), wantErrIs: errOCR},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := ExtractPDFTextWithOCR(t.Context(), tt.path, tt.maxBytes, tt.ocr)
+			if tt.wantErrIs != nil {
+				if !errors.Is(err, tt.wantErrIs) {
+					t.Fatalf("expected errors.Is(%v), got %v (text=%q)", tt.wantErrIs, err, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Fatalf("text=%q want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func errorOCR(err error) OCRFunc {
+	return func(context.Context, image.Image) (string, error) { return "", err }
+}
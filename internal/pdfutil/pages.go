@@ -0,0 +1,79 @@
+package pdfutil
+
+import (
+	"context"
+	"math"
+	"strings"
+
+	"github.com/flexigpt/llmtools-go/internal/toolutil"
+	"github.com/ledongthuc/pdf"
+)
+
+// ExtractPDFPages extracts text page by page, returning one string per page in
+// document order. Each page is truncated to maxBytesPerPage and the combined
+// output is capped at the current toolutil.GetLimits().MaxFileRead; pages past
+// the cap, and pages without text, are returned as "" so indexes always match
+// page numbers minus one.
+func ExtractPDFPages(ctx context.Context, path string, maxBytesPerPage int) ([]string, error) {
+	return ExtractPDFPagesWithProgress(ctx, path, maxBytesPerPage, nil)
+}
+
+// ExtractPDFPagesWithProgress is ExtractPDFPages with a progress callback.
+// onProgress, if non-nil, is called after each page with the pages processed
+// and the page count.
+func ExtractPDFPagesWithProgress(
+	ctx context.Context,
+	path string,
+	maxBytesPerPage int,
+	onProgress func(done, total int),
+) ([]string, error) {
+	return toolutil.WithRecoveryResp(func() ([]string, error) {
+		return extractPDFPages(ctx, path, maxBytesPerPage, onProgress)
+	})
+}
+
+func extractPDFPages(
+	ctx context.Context,
+	path string,
+	maxBytesPerPage int,
+	onProgress func(done, total int),
+) ([]string, error) {
+	f, r, err := pdf.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	n := r.NumPage()
+	pages := make([]string, n)
+	fonts := make(map[string]*pdf.Font)
+	remaining := int(min(toolutil.GetLimits().MaxFileRead, math.MaxInt))
+	for i := 1; i <= n && remaining > 0; i++ {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		text, err := pagePlainText(r.Page(i), fonts)
+		if err != nil {
+			return nil, err
+		}
+		text = truncateUTF8(strings.TrimSpace(text), min(maxBytesPerPage, remaining))
+		pages[i-1] = text
+		remaining -= len(text)
+		if onProgress != nil {
+			onProgress(i, n)
+		}
+	}
+	return pages, nil
+}
+
+// pagePlainText extracts a page's text, caching parsed fonts in fonts across calls
+// (as pdf.Reader.GetPlainText does) so charmaps are not re-parsed for every page.
+func pagePlainText(p pdf.Page, fonts map[string]*pdf.Font) (string, error) {
+	for _, name := range p.Fonts() {
+		if _, ok := fonts[name]; !ok {
+			font := p.Font(name)
+			fonts[name] = &font
+		}
+	}
+	return p.GetPlainText(fonts)
+}
@@ -0,0 +1,129 @@
+package pdfutil
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/flexigpt/llmtools-go/internal/toolutil"
+)
+
+func TestExtractPDFPages(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+
+	threePages := writeTempFile(t, dir, "three.pdf", buildPDF(testPDFSpec{
+		Pages: [][]pdfTextRun{
+			{{X: 72, Y: 700, Text: "First page"}},
+			nil,
+			{{X: 72, Y: 700, Text: "Third page"}},
+		},
+	}))
+
+	canceled, cancel := context.WithCancel(t.Context())
+	cancel()
+
+	tests := []struct {
+		name            string
+		ctx             context.Context
+		path            string
+		maxBytesPerPage int
+		want            []string
+		wantErr         bool
+		wantErrIs       error
+	}{
+		{
+			name: "one entry per page, empty page kept", path: threePages, maxBytesPerPage: 1 << 20,
+			want: []string{"First page", "", "Third page"},
+		},
+		{
+			name: "each page truncated", path: threePages, maxBytesPerPage: 5,
+			want: []string{"First", "", "Third"},
+		},
+		{name: "missing file errors", path: filepath.Join(dir, "missing.pdf"), maxBytesPerPage: 1 << 20, wantErr: true},
+		{
+			name: "canceled context", ctx: canceled, path: threePages, maxBytesPerPage: 1 << 20,
+			wantErr: true, wantErrIs: context.Canceled,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctx := tt.ctx
+			if ctx == nil {
+				ctx = t.Context()
+			}
+			got, err := ExtractPDFPages(ctx, tt.path, tt.maxBytesPerPage)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected error, got nil; pages=%q", got)
+				}
+				if tt.wantErrIs != nil && !errors.Is(err, tt.wantErrIs) {
+					t.Fatalf("expected errors.Is(%v), got %v", tt.wantErrIs, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Fatalf("pages mismatch: got %q want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExtractPDFPages_TotalCapFollowsLimits(t *testing.T) {
+	toolutil.SetLimits(toolutil.Limits{MaxFileRead: 8})
+	t.Cleanup(func() { toolutil.SetLimits(toolutil.DefaultLimits()) })
+
+	path := writeTempFile(t, t.TempDir(), "three.pdf", buildPDF(testPDFSpec{
+		Pages: [][]pdfTextRun{
+			{{X: 72, Y: 700, Text: "First"}},
+			{{X: 72, Y: 700, Text: "Second"}},
+			{{X: 72, Y: 700, Text: "Third"}},
+		},
+	}))
+	got, err := ExtractPDFPages(t.Context(), path, 1<<20)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := []string{"First", "Sec", ""}; !slices.Equal(got, want) {
+		t.Fatalf("pages mismatch: got %q want %q", got, want)
+	}
+}
+
+func TestExtractPDFPages_Progress(t *testing.T) {
+	t.Parallel()
+	threePages := writeTempFile(t, t.TempDir(), "three.pdf", buildPDF(testPDFSpec{
+		Pages: [][]pdfTextRun{
+			{{X: 72, Y: 700, Text: "one"}},
+			{{X: 72, Y: 700, Text: "two"}},
+			{{X: 72, Y: 700, Text: "three"}},
+		},
+	}))
+
+	var calls [][2]int
+	if _, err := ExtractPDFPagesWithProgress(t.Context(), threePages, 1<<20, func(done, total int) {
+		calls = append(calls, [2]int{done, total})
+	}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := [][2]int{{1, 3}, {2, 3}, {3, 3}}
+	if !slices.Equal(calls, want) {
+		t.Fatalf("progress calls=%v want %v", calls, want)
+	}
+
+	var rangeCalls [][2]int
+	if _, err := ExtractPDFTextRangeWithProgress(t.Context(), threePages, 2, 10, 1<<20, func(done, total int) {
+		rangeCalls = append(rangeCalls, [2]int{done, total})
+	}); err != nil {
+		t.Fatalf("unexpected range error: %v", err)
+	}
+	if want := [][2]int{{1, 2}, {2, 2}}; !slices.Equal(rangeCalls, want) {
+		t.Fatalf("range progress calls=%v want %v", rangeCalls, want)
+	}
+}
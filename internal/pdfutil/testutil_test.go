@@ -0,0 +1,146 @@
+package pdfutil
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// pdfTextRun is one positioned text string on a page (PDF points, origin bottom-left).
+type pdfTextRun struct {
+	X, Y float64
+	Text string
+}
+
+// testPDFSpec describes a generated multi-page PDF.
+type testPDFSpec struct {
+	// Pages holds the text runs of each page; a nil/empty entry is a page with no text.
+	Pages [][]pdfTextRun
+	// Info holds optional /Info dictionary string entries (e.g. "Title").
+	Info map[string]string
+	// NoWidths omits the font width table (like many real Type1 standard fonts).
+	NoWidths bool
+	// Images holds the image XObjects of each page, indexed like Pages.
+	Images [][]testPDFImage
+}
+
+// testPDFImage is an image XObject stream. Dict holds the entries besides
+// /Type, /Subtype and /Length (e.g. "/Width 2 /Height 2 /Filter /DCTDecode").
+type testPDFImage struct {
+	Name string
+	Dict string
+	Data []byte
+}
+
+const testPDFFontSize = 12
+
+// buildPDF renders spec as a valid PDF with a correct xref table.
+// All text uses a single fixed-pitch font (600/1000 em per glyph).
+func buildPDF(spec testPDFSpec) []byte {
+	var objs []string
+	addObj := func(body string) int {
+		objs = append(objs, body)
+		return len(objs)
+	}
+
+	catalog := addObj("") // patched below once Pages id is known
+	pages := addObj("")
+	font := "<< /Type /Font /Subtype /Type1 /BaseFont /Courier"
+	if !spec.NoWidths {
+		font += " /FirstChar 32 /LastChar 126 /Widths [" + strings.TrimSpace(strings.Repeat("600 ", 95)) + "]"
+	}
+	fontID := addObj(font + " >>")
+
+	kids := make([]string, 0, len(spec.Pages))
+	for pi, runs := range spec.Pages {
+		var xobjs strings.Builder
+		if pi < len(spec.Images) {
+			for _, img := range spec.Images[pi] {
+				id := addObj(fmt.Sprintf(
+					"<< /Type /XObject /Subtype /Image %s /Length %d >>\nstream\n%s\nendstream",
+					img.Dict, len(img.Data), img.Data,
+				))
+				fmt.Fprintf(&xobjs, " /%s %d 0 R", img.Name, id)
+			}
+		}
+		resources := fmt.Sprintf("/Font << /F1 %d 0 R >>", fontID)
+		if xobjs.Len() > 0 {
+			resources += " /XObject <<" + xobjs.String() + " >>"
+		}
+
+		var content strings.Builder
+		for _, r := range runs {
+			fmt.Fprintf(&content, "BT\n/F1 %d Tf\n%g %g Td\n(%s) Tj\nET\n", testPDFFontSize, r.X, r.Y, pdfEscape(r.Text))
+		}
+		c := content.String()
+		contentsID := addObj(fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", len(c), c))
+		pageID := addObj(fmt.Sprintf(
+			"<< /Type /Page /Parent %d 0 R /MediaBox [0 0 612 792] /Contents %d 0 R /Resources << %s >> >>",
+			pages, contentsID, resources,
+		))
+		kids = append(kids, fmt.Sprintf("%d 0 R", pageID))
+	}
+	objs[catalog-1] = fmt.Sprintf("<< /Type /Catalog /Pages %d 0 R >>", pages)
+	objs[pages-1] = fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(kids))
+
+	infoID := 0
+	if len(spec.Info) > 0 {
+		keys := make([]string, 0, len(spec.Info))
+		for k := range spec.Info {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		var info strings.Builder
+		info.WriteString("<<")
+		for _, k := range keys {
+			fmt.Fprintf(&info, " /%s (%s)", k, pdfEscape(spec.Info[k]))
+		}
+		info.WriteString(" >>")
+		infoID = addObj(info.String())
+	}
+
+	var b strings.Builder
+	b.WriteString("%PDF-1.4\n")
+	offsets := make([]int, len(objs)+1)
+	for i, body := range objs {
+		offsets[i+1] = b.Len()
+		fmt.Fprintf(&b, "%d 0 obj\n%s\nendobj\n", i+1, body)
+	}
+
+	xrefStart := b.Len()
+	fmt.Fprintf(&b, "xref\n0 %d\n", len(objs)+1)
+	b.WriteString("0000000000 65535 f \n")
+	for i := 1; i <= len(objs); i++ {
+		fmt.Fprintf(&b, "%010d 00000 n \n", offsets[i])
+	}
+	fmt.Fprintf(&b, "trailer\n<< /Size %d /Root %d 0 R", len(objs)+1, catalog)
+	if infoID != 0 {
+		fmt.Fprintf(&b, " /Info %d 0 R", infoID)
+	}
+	b.WriteString(" >>\n")
+	fmt.Fprintf(&b, "startxref\n%d\n%%%%EOF\n", xrefStart)
+	return []byte(b.String())
+}
+
+func pdfEscape(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, "(", `\(`)
+	s = strings.ReplaceAll(s, ")", `\)`)
+	return s
+}
+
+// twoColumnRuns lays out left and right lines as two columns, top to bottom.
+func twoColumnRuns(left, right []string) []pdfTextRun {
+	runs := make([]pdfTextRun, 0, len(left)+len(right))
+	// Interleave in the content stream so naive extraction mixes the columns.
+	for i := range max(len(left), len(right)) {
+		y := 700 - float64(i)*16
+		if i < len(left) {
+			runs = append(runs, pdfTextRun{X: 72, Y: y, Text: left[i]})
+		}
+		if i < len(right) {
+			runs = append(runs, pdfTextRun{X: 340, Y: y, Text: right[i]})
+		}
+	}
+	return runs
+}
@@ -0,0 +1,114 @@
+package toolutil
+
+import "unicode"
+
+// ChunkSplit names the preferred boundary for ChunkText.
+type ChunkSplit string
+
+const (
+	ChunkSplitParagraph ChunkSplit = "paragraph" // After a blank line.
+	ChunkSplitSentence  ChunkSplit = "sentence"  // After ".", "!" or "?" and whitespace.
+	ChunkSplitLine      ChunkSplit = "line"      // After a newline.
+)
+
+// ChunkOptions configures ChunkText. Sizes are in characters (runes).
+type ChunkOptions struct {
+	// MaxChars caps each chunk; <=0 returns the whole text as one chunk.
+	MaxChars int
+	// Overlap is how many trailing characters of a chunk start the next one.
+	// It is clamped to [0, MaxChars-1].
+	Overlap int
+	// SplitOn is the preferred boundary; "" means paragraph.
+	SplitOn ChunkSplit
+}
+
+// TextChunk is a piece of the source text. Start and End are rune offsets into
+// the source, so []rune(src)[Start:End] == []rune(Text).
+type TextChunk struct {
+	Text  string `json:"text"`
+	Start int    `json:"start"`
+	End   int    `json:"end"`
+}
+
+// ChunkText splits text into chunks of at most opts.MaxChars characters, each
+// starting opts.Overlap characters before the previous one ended. A chunk ends
+// at the last preferred boundary that fits, falling back to finer ones
+// (paragraph, then sentence, then line, then whitespace) and finally a hard cut.
+// Empty text yields no chunks.
+func ChunkText(text string, opts ChunkOptions) []TextChunk {
+	r := []rune(text)
+	n := len(r)
+	if n == 0 {
+		return nil
+	}
+	if opts.MaxChars <= 0 || n <= opts.MaxChars {
+		return []TextChunk{{Text: text, Start: 0, End: n}}
+	}
+	overlap := min(max(opts.Overlap, 0), opts.MaxChars-1)
+	splits := chunkBoundaryOrder(opts.SplitOn)
+
+	var out []TextChunk
+	for start := 0; ; {
+		end := min(start+opts.MaxChars, n)
+		if end < n {
+			// A break at or before start+overlap would not advance the next chunk.
+			end = chunkBreak(r, start+overlap, end, splits)
+		}
+		out = append(out, TextChunk{Text: string(r[start:end]), Start: start, End: end})
+		if end == n {
+			return out
+		}
+		start = end - overlap
+	}
+}
+
+// chunkBoundaryOrder returns the boundary checks to try, preferred first.
+func chunkBoundaryOrder(split ChunkSplit) []func(r []rune, p int) bool {
+	order := []func(r []rune, p int) bool{isParagraphBreak, isSentenceBreak, isLineBreak, isSpaceBreak}
+	switch split {
+	case ChunkSplitSentence:
+		return order[1:]
+	case ChunkSplitLine:
+		return order[2:]
+	default:
+		return order
+	}
+}
+
+// chunkBreak returns the last position p in (lo, hi] accepted by the first
+// boundary check that has one, or hi if none does.
+func chunkBreak(r []rune, lo, hi int, splits []func(r []rune, p int) bool) int {
+	for _, ok := range splits {
+		for p := hi; p > lo; p-- {
+			if ok(r, p) {
+				return p
+			}
+		}
+	}
+	return hi
+}
+
+// Boundary checks report whether a chunk may end at p, i.e. just after r[p-1].
+
+func isParagraphBreak(r []rune, p int) bool {
+	return p >= 2 && r[p-1] == '\n' && r[p-2] == '\n'
+}
+
+func isSentenceBreak(r []rune, p int) bool {
+	if p < 2 || !unicode.IsSpace(r[p-1]) {
+		return false
+	}
+	switch r[p-2] {
+	case '.', '!', '?':
+		return true
+	}
+	return false
+}
+
+func isLineBreak(r []rune, p int) bool {
+	return p >= 1 && r[p-1] == '\n'
+}
+
+func isSpaceBreak(r []rune, p int) bool {
+	return p >= 1 && unicode.IsSpace(r[p-1])
+}
@@ -0,0 +1,143 @@
+package toolutil
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestChunkText(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		opts ChunkOptions
+		want []string
+	}{
+		{
+			name: "empty text",
+			text: "",
+			opts: ChunkOptions{MaxChars: 10},
+			want: nil,
+		},
+		{
+			name: "fits in one chunk",
+			text: "short text",
+			opts: ChunkOptions{MaxChars: 10},
+			want: []string{"short text"},
+		},
+		{
+			name: "no max returns whole text",
+			text: "anything at all",
+			want: []string{"anything at all"},
+		},
+		{
+			name: "paragraph boundary preferred",
+			text: "First para.\n\nSecond para here.",
+			opts: ChunkOptions{MaxChars: 20},
+			want: []string{"First para.\n\n", "Second para here."},
+		},
+		{
+			name: "sentence boundary preferred",
+			text: "One two. Three four. Five.",
+			opts: ChunkOptions{MaxChars: 12, SplitOn: ChunkSplitSentence},
+			want: []string{"One two. ", "Three four. ", "Five."},
+		},
+		{
+			name: "line split ignores sentence breaks",
+			text: "a. b. c\nd e f",
+			opts: ChunkOptions{MaxChars: 10, SplitOn: ChunkSplitLine},
+			want: []string{"a. b. c\n", "d e f"},
+		},
+		{
+			name: "falls back to whitespace",
+			text: "alpha beta gamma",
+			opts: ChunkOptions{MaxChars: 12},
+			want: []string{"alpha beta ", "gamma"},
+		},
+		{
+			name: "hard cut without boundaries",
+			text: "abcdefghij",
+			opts: ChunkOptions{MaxChars: 4},
+			want: []string{"abcd", "efgh", "ij"},
+		},
+		{
+			name: "overlap repeats trailing characters",
+			text: "abcdefghij",
+			opts: ChunkOptions{MaxChars: 4, Overlap: 2},
+			want: []string{"abcd", "cdef", "efgh", "ghij"},
+		},
+		{
+			name: "overlap clamped below max",
+			text: "abcdef",
+			opts: ChunkOptions{MaxChars: 3, Overlap: 10},
+			want: []string{"abc", "bcd", "cde", "def"},
+		},
+		{
+			name: "multibyte runes counted as characters",
+			text: "ééééé",
+			opts: ChunkOptions{MaxChars: 2},
+			want: []string{"éé", "éé", "é"},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			chunks := ChunkText(tc.text, tc.opts)
+			var got []string
+			for _, c := range chunks {
+				got = append(got, c.Text)
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Fatalf("got=%q want=%q", got, tc.want)
+			}
+			checkChunkOffsets(t, tc.text, tc.opts, chunks)
+		})
+	}
+}
+
+func TestChunkText_OffsetsReconstructSource(t *testing.T) {
+	src := strings.Repeat("Lorem ipsum dolor sit amet. Consectetur adipiscing elit!\n", 20) +
+		"\n\nNext section: ünïcödé text continues here? Yes.\n"
+	for _, split := range []ChunkSplit{ChunkSplitParagraph, ChunkSplitSentence, ChunkSplitLine} {
+		for _, overlap := range []int{0, 7, 30} {
+			opts := ChunkOptions{MaxChars: 64, Overlap: overlap, SplitOn: split}
+			chunks := ChunkText(src, opts)
+			if len(chunks) < 2 {
+				t.Fatalf("%s/%d: expected several chunks, got %d", split, overlap, len(chunks))
+			}
+			checkChunkOffsets(t, src, opts, chunks)
+
+			// Dropping each chunk's overlap prefix rebuilds the source.
+			var b strings.Builder
+			prevEnd := 0
+			for _, c := range chunks {
+				b.WriteString(string([]rune(c.Text)[prevEnd-c.Start:]))
+				prevEnd = c.End
+			}
+			if b.String() != src {
+				t.Fatalf("%s/%d: reconstruction mismatch", split, overlap)
+			}
+		}
+	}
+}
+
+func checkChunkOffsets(t *testing.T, src string, opts ChunkOptions, chunks []TextChunk) {
+	t.Helper()
+	r := []rune(src)
+	overlap := min(max(opts.Overlap, 0), max(opts.MaxChars-1, 0))
+	for i, c := range chunks {
+		if string(r[c.Start:c.End]) != c.Text {
+			t.Fatalf("chunk %d: offsets [%d,%d) do not match text %q", i, c.Start, c.End, c.Text)
+		}
+		if opts.MaxChars > 0 && c.End-c.Start > opts.MaxChars {
+			t.Fatalf("chunk %d: %d chars exceeds max %d", i, c.End-c.Start, opts.MaxChars)
+		}
+		if i > 0 && c.Start != chunks[i-1].End-overlap {
+			t.Fatalf("chunk %d: start=%d want previous end %d minus overlap %d",
+				i, c.Start, chunks[i-1].End, overlap)
+		}
+	}
+	if len(chunks) > 0 && (chunks[0].Start != 0 || chunks[len(chunks)-1].End != len(r)) {
+		t.Fatalf("chunks do not span the source: first=%d last=%d len=%d",
+			chunks[0].Start, chunks[len(chunks)-1].End, len(r))
+	}
+}
@@ -0,0 +1,47 @@
+package toolutil
+
+import (
+	"sync"
+	"time"
+)
+
+// Clock supplies the current time for time-dependent tool behavior (cache
+// expiry, session TTL eviction, "modified within" windows). Timeouts use the
+// monotonic system clock.
+type Clock interface {
+	Now() time.Time
+}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time { return time.Now() }
+
+var (
+	clockMu sync.RWMutex
+	clock   Clock = systemClock{}
+)
+
+// SetClock replaces the clock and returns a func that restores the previous one.
+// A nil c restores the system clock. Intended for tests; it affects all callers in the process.
+func SetClock(c Clock) (restore func()) {
+	if c == nil {
+		c = systemClock{}
+	}
+	clockMu.Lock()
+	prev := clock
+	clock = c
+	clockMu.Unlock()
+	return func() {
+		clockMu.Lock()
+		clock = prev
+		clockMu.Unlock()
+	}
+}
+
+// Now returns the current time of the clock set with SetClock.
+func Now() time.Time {
+	clockMu.RLock()
+	c := clock
+	clockMu.RUnlock()
+	return c.Now()
+}
@@ -0,0 +1,28 @@
+package toolutil
+
+import (
+	"testing"
+	"time"
+)
+
+type fixedClock time.Time
+
+func (c fixedClock) Now() time.Time { return time.Time(c) }
+
+func TestSetClock_RestoreAndNil(t *testing.T) {
+	fixed := time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+	restoreSystem := SetClock(fixedClock(fixed))
+	defer restoreSystem()
+	if got := Now(); !got.Equal(fixed) {
+		t.Fatalf("Now()=%v want %v", got, fixed)
+	}
+
+	restoreFixed := SetClock(nil)
+	if got := Now(); got.Equal(fixed) {
+		t.Fatalf("expected system clock after SetClock(nil)")
+	}
+	restoreFixed()
+	if got := Now(); !got.Equal(fixed) {
+		t.Fatalf("restore should reinstate previous clock, got %v", got)
+	}
+}
@@ -21,3 +21,21 @@ func CloneTool(t spec.Tool) spec.Tool {
 
 	return t
 }
+
+// CloneToolOutput returns a copy of o that shares no item with it. Item fields
+// are strings, which are immutable, so copying the items is a deep copy.
+func CloneToolOutput(o spec.ToolStoreOutputUnion) spec.ToolStoreOutputUnion {
+	if o.TextItem != nil {
+		v := *o.TextItem
+		o.TextItem = &v
+	}
+	if o.ImageItem != nil {
+		v := *o.ImageItem
+		o.ImageItem = &v
+	}
+	if o.FileItem != nil {
+		v := *o.FileItem
+		o.FileItem = &v
+	}
+	return o
+}
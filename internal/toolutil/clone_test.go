@@ -121,3 +121,73 @@ func assertStringsDeepCopied(t *testing.T, orig, cloned []string) {
 		}
 	}
 }
+
+func TestCloneToolOutput(t *testing.T) {
+	tests := []struct {
+		name string
+		in   spec.ToolStoreOutputUnion
+	}{
+		{
+			name: "zero value",
+			in:   spec.ToolStoreOutputUnion{},
+		},
+		{
+			name: "text",
+			in: spec.ToolStoreOutputUnion{
+				Kind:     spec.ToolStoreOutputKindText,
+				TextItem: &spec.ToolStoreOutputText{Text: "hello"},
+			},
+		},
+		{
+			name: "image",
+			in: spec.ToolStoreOutputUnion{
+				Kind: spec.ToolStoreOutputKindImage,
+				ImageItem: &spec.ToolStoreOutputImage{
+					Detail: spec.ImageDetailAuto, ImageName: "a.png", ImageMIME: "image/png", ImageData: "iVBO",
+				},
+			},
+		},
+		{
+			name: "file",
+			in: spec.ToolStoreOutputUnion{
+				Kind:     spec.ToolStoreOutputKindFile,
+				FileItem: &spec.ToolStoreOutputFile{FileName: "a.pdf", FileMIME: "application/pdf", FileData: "JVBE"},
+			},
+		},
+		{
+			// Items that do not match Kind are still copied, not shared.
+			name: "all items set",
+			in: spec.ToolStoreOutputUnion{
+				Kind:      spec.ToolStoreOutputKindText,
+				TextItem:  &spec.ToolStoreOutputText{Text: "t"},
+				ImageItem: &spec.ToolStoreOutputImage{ImageName: "i"},
+				FileItem:  &spec.ToolStoreOutputFile{FileName: "f"},
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			orig := tc.in
+			snap := CloneToolOutput(orig)
+			cloned := CloneToolOutput(orig)
+			if !reflect.DeepEqual(orig, cloned) {
+				t.Fatalf("clone differs from original:\norig=%#v\nclone=%#v", orig, cloned)
+			}
+
+			// Aliasing detector: mutate every item of the clone; orig must not change.
+			if cloned.TextItem != nil {
+				cloned.TextItem.Text += "-changed"
+			}
+			if cloned.ImageItem != nil {
+				cloned.ImageItem.ImageData += "-changed"
+			}
+			if cloned.FileItem != nil {
+				cloned.FileItem.FileData += "-changed"
+			}
+			if !reflect.DeepEqual(orig, snap) {
+				t.Fatalf("orig changed after mutating clone; items alias")
+			}
+		})
+	}
+}
@@ -13,11 +13,14 @@ const (
 
 const maxToolBytes = 16 * 1024 * 1024 // 16MB
 
-// MaxTextProcessingBytes caps loading/editing text files for line-based tools.
+// MaxTextProcessingBytes is the default Limits.MaxTextProcessing.
 const MaxTextProcessingBytes = maxToolBytes
 
-// MaxFileReadBytes caps raw bytes read from disk by “read file” style tools.
+// MaxFileReadBytes is the default Limits.MaxFileRead.
 const MaxFileReadBytes = maxToolBytes
 
-// MaxFileWriteBytes caps raw bytes written to disk by “write file” style tools.
+// MaxFileWriteBytes is the default Limits.MaxFileWrite.
 const MaxFileWriteBytes = maxToolBytes
+
+// MaxStdinBytes caps data piped into a command's stdin by exec style tools.
+const MaxStdinBytes = maxToolBytes
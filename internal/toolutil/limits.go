@@ -0,0 +1,48 @@
+package toolutil
+
+import "sync/atomic"
+
+// Limits are the byte caps tools apply to file and text payloads.
+type Limits struct {
+	// MaxFileRead caps raw bytes read from disk by "read file" style tools.
+	MaxFileRead int64
+	// MaxFileWrite caps raw bytes written to disk by "write file" style tools.
+	MaxFileWrite int64
+	// MaxTextProcessing caps loading/editing text files for line-based tools.
+	MaxTextProcessing int64
+}
+
+// DefaultLimits returns the limits tools use unless SetLimits is called.
+func DefaultLimits() Limits {
+	return Limits{
+		MaxFileRead:       MaxFileReadBytes,
+		MaxFileWrite:      MaxFileWriteBytes,
+		MaxTextProcessing: MaxTextProcessingBytes,
+	}
+}
+
+var limits atomic.Pointer[Limits]
+
+// SetLimits replaces the limits consulted by tools on each call. Fields <=0
+// use the default value.
+func SetLimits(l Limits) {
+	d := DefaultLimits()
+	if l.MaxFileRead <= 0 {
+		l.MaxFileRead = d.MaxFileRead
+	}
+	if l.MaxFileWrite <= 0 {
+		l.MaxFileWrite = d.MaxFileWrite
+	}
+	if l.MaxTextProcessing <= 0 {
+		l.MaxTextProcessing = d.MaxTextProcessing
+	}
+	limits.Store(&l)
+}
+
+// GetLimits returns the current limits.
+func GetLimits() Limits {
+	if l := limits.Load(); l != nil {
+		return *l
+	}
+	return DefaultLimits()
+}
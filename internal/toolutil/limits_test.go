@@ -0,0 +1,22 @@
+package toolutil
+
+import "testing"
+
+func TestSetLimits(t *testing.T) {
+	t.Cleanup(func() { SetLimits(DefaultLimits()) })
+
+	if got := GetLimits(); got != DefaultLimits() {
+		t.Fatalf("GetLimits before SetLimits: got %+v want defaults", got)
+	}
+
+	SetLimits(Limits{MaxFileRead: 10, MaxTextProcessing: -1})
+	want := Limits{MaxFileRead: 10, MaxFileWrite: MaxFileWriteBytes, MaxTextProcessing: MaxTextProcessingBytes}
+	if got := GetLimits(); got != want {
+		t.Fatalf("GetLimits: got %+v want %+v", got, want)
+	}
+
+	SetLimits(DefaultLimits())
+	if got := GetLimits(); got != DefaultLimits() {
+		t.Fatalf("GetLimits after reset: got %+v want defaults", got)
+	}
+}
@@ -0,0 +1,24 @@
+package toolutil
+
+import (
+	"context"
+	"time"
+)
+
+// WithTimeout wraps a tool function so each call runs with a child context that
+// expires after d, giving the tool a fixed time budget regardless of the
+// caller's context. The tool must honor ctx to stop early; when it does, the
+// call fails with context.DeadlineExceeded. d <= 0 returns fn unchanged.
+func WithTimeout[A, R any](
+	fn func(context.Context, A) (R, error),
+	d time.Duration,
+) func(context.Context, A) (R, error) {
+	if d <= 0 {
+		return fn
+	}
+	return func(ctx context.Context, args A) (R, error) {
+		ctx, cancel := context.WithTimeout(ctx, d)
+		defer cancel()
+		return fn(ctx, args)
+	}
+}
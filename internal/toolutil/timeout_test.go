@@ -0,0 +1,63 @@
+package toolutil
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestWithTimeout(t *testing.T) {
+	t.Parallel()
+
+	// slow waits for delay unless ctx ends first.
+	slow := func(ctx context.Context, delay time.Duration) (string, error) {
+		select {
+		case <-time.After(delay):
+			return "done", nil
+		case <-ctx.Done():
+			return "", ctx.Err()
+		}
+	}
+
+	tests := []struct {
+		name      string
+		timeout   time.Duration
+		delay     time.Duration
+		want      string
+		wantErrIs error
+	}{
+		{
+			name:      "slow tool times out",
+			timeout:   10 * time.Millisecond,
+			delay:     time.Minute,
+			wantErrIs: context.DeadlineExceeded,
+		},
+		{name: "fast tool completes", timeout: time.Minute, delay: 0, want: "done"},
+		{name: "zero timeout is a no-op", timeout: 0, delay: 0, want: "done"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := WithTimeout(slow, tc.timeout)(t.Context(), tc.delay)
+			if tc.wantErrIs != nil {
+				if !errors.Is(err, tc.wantErrIs) {
+					t.Fatalf("expected errors.Is(%v), got %v", tc.wantErrIs, err)
+				}
+				return
+			}
+			if err != nil || got != tc.want {
+				t.Fatalf("got %q, %v; want %q", got, err, tc.want)
+			}
+		})
+	}
+
+	t.Run("parent deadline still applies", func(t *testing.T) {
+		t.Parallel()
+		ctx, cancel := context.WithCancel(t.Context())
+		cancel()
+		if _, err := WithTimeout(slow, time.Minute)(ctx, time.Minute); !errors.Is(err, context.Canceled) {
+			t.Fatalf("expected context.Canceled, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,90 @@
+package toolutil
+
+import (
+	"fmt"
+	"unicode/utf8"
+
+	"github.com/flexigpt/llmtools-go/spec"
+)
+
+// TruncatedMarker is appended to text cut by TruncateOutput.
+const TruncatedMarker = "\n[truncated]"
+
+// TruncateOutput fits outs into a budget of maxBytes payload bytes (text, or
+// base64 image/file data), keeping outputs in order. The first text that does
+// not fit is cut at a UTF-8 boundary and ends with TruncatedMarker; an image or
+// file that does not fit is replaced by a short text note naming it, if the
+// note fits. Everything after the budget is exhausted is dropped. It returns
+// whether anything was cut or dropped. outs is not modified; maxBytes<=0 means
+// no limit.
+func TruncateOutput(outs []spec.ToolStoreOutputUnion, maxBytes int) ([]spec.ToolStoreOutputUnion, bool) {
+	if maxBytes <= 0 {
+		return outs, false
+	}
+	remaining := maxBytes
+	truncated := false
+	res := make([]spec.ToolStoreOutputUnion, 0, len(outs))
+	for _, o := range outs {
+		size := outputSize(o)
+		if size <= remaining {
+			res = append(res, o)
+			remaining -= size
+			continue
+		}
+		truncated = true
+		if o.Kind == spec.ToolStoreOutputKindText && o.TextItem != nil {
+			if remaining >= len(TruncatedMarker) {
+				text := truncateUTF8(o.TextItem.Text, remaining-len(TruncatedMarker)) + TruncatedMarker
+				res = append(res, textOutput(text))
+				remaining -= len(text)
+			}
+			continue
+		}
+		if note := omittedNote(o, size); note != "" && len(note) <= remaining {
+			res = append(res, textOutput(note))
+			remaining -= len(note)
+		}
+	}
+	return res, truncated
+}
+
+func outputSize(o spec.ToolStoreOutputUnion) int {
+	switch {
+	case o.TextItem != nil:
+		return len(o.TextItem.Text)
+	case o.ImageItem != nil:
+		return len(o.ImageItem.ImageData)
+	case o.FileItem != nil:
+		return len(o.FileItem.FileData)
+	}
+	return 0
+}
+
+func omittedNote(o spec.ToolStoreOutputUnion, size int) string {
+	switch {
+	case o.ImageItem != nil:
+		return fmt.Sprintf("[image %q omitted: %d bytes exceed output budget]", o.ImageItem.ImageName, size)
+	case o.FileItem != nil:
+		return fmt.Sprintf("[file %q omitted: %d bytes exceed output budget]", o.FileItem.FileName, size)
+	}
+	return ""
+}
+
+// truncateUTF8 returns the longest prefix of s of at most n bytes that does not
+// split a rune.
+func truncateUTF8(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return s[:n]
+}
+
+func textOutput(text string) spec.ToolStoreOutputUnion {
+	return spec.ToolStoreOutputUnion{
+		Kind:     spec.ToolStoreOutputKindText,
+		TextItem: &spec.ToolStoreOutputText{Text: text},
+	}
+}
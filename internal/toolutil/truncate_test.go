@@ -0,0 +1,107 @@
+package toolutil
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/flexigpt/llmtools-go/spec"
+)
+
+func TestTruncateOutput(t *testing.T) {
+	image := func(name string, size int) spec.ToolStoreOutputUnion {
+		return spec.ToolStoreOutputUnion{
+			Kind: spec.ToolStoreOutputKindImage,
+			ImageItem: &spec.ToolStoreOutputImage{
+				ImageName: name, ImageMIME: "image/png", ImageData: strings.Repeat("A", size),
+			},
+		}
+	}
+
+	tests := []struct {
+		name          string
+		in            []spec.ToolStoreOutputUnion
+		maxBytes      int
+		want          []spec.ToolStoreOutputUnion
+		wantTruncated bool
+	}{
+		{
+			name:     "within budget",
+			in:       []spec.ToolStoreOutputUnion{textOutput("hello"), image("a.png", 10)},
+			maxBytes: 15,
+			want:     []spec.ToolStoreOutputUnion{textOutput("hello"), image("a.png", 10)},
+		},
+		{
+			name:     "no limit",
+			in:       []spec.ToolStoreOutputUnion{textOutput(strings.Repeat("x", 100))},
+			maxBytes: 0,
+			want:     []spec.ToolStoreOutputUnion{textOutput(strings.Repeat("x", 100))},
+		},
+		{
+			name:     "over-budget text is cut and marked",
+			in:       []spec.ToolStoreOutputUnion{textOutput(strings.Repeat("x", 100))},
+			maxBytes: 30,
+			want: []spec.ToolStoreOutputUnion{
+				textOutput(strings.Repeat("x", 30-len(TruncatedMarker)) + TruncatedMarker),
+			},
+			wantTruncated: true,
+		},
+		{
+			name:          "text is cut on a rune boundary",
+			in:            []spec.ToolStoreOutputUnion{textOutput(strings.Repeat("é", 10))},
+			maxBytes:      len(TruncatedMarker) + 5,
+			want:          []spec.ToolStoreOutputUnion{textOutput("éé" + TruncatedMarker)},
+			wantTruncated: true,
+		},
+		{
+			name:     "image over budget becomes a note and later text is kept",
+			in:       []spec.ToolStoreOutputUnion{textOutput("caption"), image("big.png", 500), textOutput("after")},
+			maxBytes: 100,
+			want: []spec.ToolStoreOutputUnion{
+				textOutput("caption"),
+				textOutput(`[image "big.png" omitted: 500 bytes exceed output budget]`),
+				textOutput("after"),
+			},
+			wantTruncated: true,
+		},
+		{
+			name:          "everything dropped when budget is tiny",
+			in:            []spec.ToolStoreOutputUnion{image("big.png", 500), textOutput("after that")},
+			maxBytes:      5,
+			want:          []spec.ToolStoreOutputUnion{},
+			wantTruncated: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			before := cloneOutputsForTest(tc.in)
+			got, truncated := TruncateOutput(tc.in, tc.maxBytes)
+			if truncated != tc.wantTruncated {
+				t.Fatalf("truncated: got %v want %v", truncated, tc.wantTruncated)
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Fatalf("TruncateOutput:\ngot  %+v\nwant %+v", got, tc.want)
+			}
+			if !reflect.DeepEqual(tc.in, before) {
+				t.Fatalf("TruncateOutput modified its input")
+			}
+		})
+	}
+}
+
+func cloneOutputsForTest(outs []spec.ToolStoreOutputUnion) []spec.ToolStoreOutputUnion {
+	res := make([]spec.ToolStoreOutputUnion, len(outs))
+	for i, o := range outs {
+		res[i] = o
+		if o.TextItem != nil {
+			ti := *o.TextItem
+			res[i].TextItem = &ti
+		}
+		if o.ImageItem != nil {
+			ii := *o.ImageItem
+			res[i].ImageItem = &ii
+		}
+	}
+	return res
+}
@@ -0,0 +1,18 @@
+package llmtools
+
+import "github.com/flexigpt/llmtools-go/internal/toolutil"
+
+// Limits are the byte caps built-in tools apply when reading files, writing
+// files and processing text. Each defaults to 16MB.
+type Limits = toolutil.Limits
+
+// SetLimits replaces the process-wide limits consulted by built-in tools on
+// each call. Fields <=0 use the default value.
+func SetLimits(l Limits) {
+	toolutil.SetLimits(l)
+}
+
+// GetLimits returns the limits currently used by built-in tools.
+func GetLimits() Limits {
+	return toolutil.GetLimits()
+}
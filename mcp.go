@@ -0,0 +1,113 @@
+package llmtools
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"path"
+	"path/filepath"
+
+	"github.com/flexigpt/llmtools-go/internal/jsonutil"
+	"github.com/flexigpt/llmtools-go/spec"
+)
+
+// mcpCallParams are the params of an MCP tools/call request.
+type mcpCallParams struct {
+	Name      string          `json:"name"`
+	Arguments json.RawMessage `json:"arguments,omitempty"`
+}
+
+// mcpCallResult is the result of an MCP tools/call request.
+type mcpCallResult struct {
+	Content []mcpContent `json:"content"`
+	IsError bool         `json:"isError,omitempty"`
+}
+
+type mcpContent struct {
+	Type string `json:"type"` // "text" | "image" | "resource"
+
+	Text     *string      `json:"text,omitempty"` // set for all text content: MCP requires it even when empty
+	Data     string       `json:"data,omitempty"`
+	MIMEType string       `json:"mimeType,omitempty"`
+	Resource *mcpResource `json:"resource,omitempty"`
+}
+
+func mcpTextContent(text string) mcpContent {
+	return mcpContent{Type: "text", Text: &text}
+}
+
+type mcpResource struct {
+	URI      string `json:"uri"`
+	MIMEType string `json:"mimeType,omitempty"`
+	Blob     string `json:"blob"`
+}
+
+// ExportMCPTools returns the registered tools, in Tools order, as a JSON array
+// of MCP tools/list entries (see spec.Tool.ToMCPTool).
+func (r *Registry) ExportMCPTools() (json.RawMessage, error) {
+	return exportTools(r.Tools(), spec.Tool.ToMCPTool)
+}
+
+// CallMCPTool serves an MCP tools/call request: params ({"name","arguments"})
+// selects a tool by slug, which is called with arguments via CallBySlug. The
+// outputs are returned as an MCP call result ({"content","isError"}), with
+// text, image and file outputs mapped to text, image and embedded resource
+// content. Malformed params and unknown tools are returned as errors; a
+// failing tool is reported in the result with isError set, as MCP expects.
+func (r *Registry) CallMCPTool(
+	ctx context.Context,
+	params json.RawMessage,
+	callOpts ...CallOption,
+) (json.RawMessage, error) {
+	p, err := jsonutil.DecodeJSONRawOpts[mcpCallParams](params, jsonutil.DecodeOptions{AllowUnknown: true})
+	if err != nil {
+		return nil, fmt.Errorf("invalid MCP tools/call params: %w", err)
+	}
+	if p.Name == "" {
+		return nil, errors.New("invalid MCP tools/call params: missing name")
+	}
+	if _, ok := r.ToolBySlug(p.Name); !ok {
+		return nil, fmt.Errorf("unknown tool slug: %s", p.Name)
+	}
+	args := p.Arguments
+	if len(args) == 0 || string(args) == "null" {
+		args = json.RawMessage(`{}`)
+	}
+
+	res := mcpCallResult{Content: []mcpContent{}}
+	outs, err := r.CallBySlug(ctx, p.Name, args, callOpts...)
+	if err != nil {
+		res.IsError = true
+		res.Content = append(res.Content, mcpTextContent(err.Error()))
+	}
+	for _, o := range outs {
+		if c, ok := toMCPContent(o); ok {
+			res.Content = append(res.Content, c)
+		}
+	}
+	return encodeUnescaped(res)
+}
+
+// toMCPContent maps a tool output to MCP content. Outputs of unknown kind or
+// missing their item are skipped.
+func toMCPContent(o spec.ToolStoreOutputUnion) (mcpContent, bool) {
+	switch {
+	case o.Kind == spec.ToolStoreOutputKindText && o.TextItem != nil:
+		return mcpTextContent(o.TextItem.Text), true
+	case o.Kind == spec.ToolStoreOutputKindImage && o.ImageItem != nil:
+		return mcpContent{Type: "image", Data: o.ImageItem.ImageData, MIMEType: o.ImageItem.ImageMIME}, true
+	case o.Kind == spec.ToolStoreOutputKindFile && o.FileItem != nil:
+		uri := url.URL{Scheme: "file", Path: path.Join("/", filepath.ToSlash(o.FileItem.FileName))}
+		return mcpContent{
+			Type: "resource",
+			Resource: &mcpResource{
+				URI:      uri.String(),
+				MIMEType: o.FileItem.FileMIME,
+				Blob:     o.FileItem.FileData,
+			},
+		}, true
+	}
+	return mcpContent{}, false
+}
@@ -0,0 +1,140 @@
+package llmtools
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/flexigpt/llmtools-go/spec"
+)
+
+func TestRegistry_ExportMCPTools(t *testing.T) {
+	r, err := NewBuiltinRegistry()
+	if err != nil {
+		t.Fatalf("NewBuiltinRegistry error: %v", err)
+	}
+	raw, err := r.ExportMCPTools()
+	if err != nil {
+		t.Fatalf("ExportMCPTools error: %v", err)
+	}
+	var got []map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &got); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	tools := r.Tools()
+	if len(got) != len(tools) {
+		t.Fatalf("ExportMCPTools len: got %d want %d", len(got), len(tools))
+	}
+	for i, tl := range tools {
+		var name string
+		if err := json.Unmarshal(got[i]["name"], &name); err != nil || name != tl.Slug {
+			t.Fatalf("ExportMCPTools[%d] name: got %s want %q", i, got[i]["name"], tl.Slug)
+		}
+		if len(got[i]) != 3 || got[i]["description"] == nil || got[i]["inputSchema"] == nil {
+			t.Fatalf("ExportMCPTools[%d]: want exactly name, description, inputSchema; got %v", i, got[i])
+		}
+	}
+}
+
+func TestRegistry_CallMCPTool(t *testing.T) {
+	r, err := NewRegistry()
+	if err != nil {
+		t.Fatalf("NewRegistry error: %v", err)
+	}
+	type echoArgs struct {
+		Text string `json:"text"`
+	}
+	echo := func(_ context.Context, a echoArgs) ([]spec.ToolStoreOutputUnion, error) {
+		if a.Text == "fail" {
+			return nil, errors.New("echo failed")
+		}
+		if a.Text == "" {
+			return textOut(""), nil
+		}
+		return textOut("echo: " + a.Text), nil
+	}
+	if err := RegisterOutputsTool(r, mkTool("github.com/acme/tools.Echo", "echo"), echo); err != nil {
+		t.Fatalf("RegisterOutputsTool error: %v", err)
+	}
+	media := func(context.Context, json.RawMessage) ([]spec.ToolStoreOutputUnion, error) {
+		return []spec.ToolStoreOutputUnion{
+			{
+				Kind:      spec.ToolStoreOutputKindImage,
+				ImageItem: &spec.ToolStoreOutputImage{ImageName: "a.png", ImageMIME: "image/png", ImageData: "iVBO"},
+			},
+			{
+				Kind:     spec.ToolStoreOutputKindFile,
+				FileItem: &spec.ToolStoreOutputFile{FileName: "my doc.pdf", FileMIME: "application/pdf", FileData: "JVBE"},
+			},
+		}, nil
+	}
+	if err := r.RegisterTool(mkTool("github.com/acme/tools.Media", "media"), media); err != nil {
+		t.Fatalf("RegisterTool error: %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		params     string
+		want       string
+		wantErrSub string
+	}{
+		{
+			name:   "text round trip",
+			params: `{"name":"echo","arguments":{"text":"<hi>"}}`,
+			want:   `{"content":[{"type":"text","text":"echo: <hi>"}]}`,
+		},
+		{
+			name:   "empty text keeps the text field",
+			params: `{"name":"echo","arguments":{"text":""}}`,
+			want:   `{"content":[{"type":"text","text":""}]}`,
+		},
+		{
+			name:   "tool error is a result",
+			params: `{"name":"echo","arguments":{"text":"fail"}}`,
+			want:   `{"content":[{"type":"text","text":"echo failed"}],"isError":true}`,
+		},
+		{
+			name:   "invalid arguments are a result",
+			params: `{"name":"echo","arguments":{"bogus":1}}`,
+			want: `{"content":[{"type":"text",` +
+				`"text":"invalid input: decode JSON: json: unknown field \"bogus\""}],"isError":true}`,
+		},
+		{
+			name:   "image and file outputs",
+			params: `{"name":"media"}`,
+			want: `{"content":[{"type":"image","data":"iVBO","mimeType":"image/png"},` +
+				`{"type":"resource","resource":{"uri":"file:///my%20doc.pdf","mimeType":"application/pdf","blob":"JVBE"}}]}`,
+		},
+		{name: "unknown tool", params: `{"name":"nope"}`, wantErrSub: "unknown tool slug"},
+		{name: "missing name", params: `{"arguments":{}}`, wantErrSub: "missing name"},
+		{name: "malformed params", params: `[]`, wantErrSub: "invalid MCP tools/call params"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := r.CallMCPTool(t.Context(), json.RawMessage(tc.params))
+			if tc.wantErrSub != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.wantErrSub) {
+					t.Fatalf("CallMCPTool: got %v want error containing %q", err, tc.wantErrSub)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("CallMCPTool error: %v", err)
+			}
+			var gotV, wantV any
+			if err := json.Unmarshal(got, &gotV); err != nil {
+				t.Fatalf("unmarshal result: %v", err)
+			}
+			if err := json.Unmarshal([]byte(tc.want), &wantV); err != nil {
+				t.Fatalf("unmarshal want: %v", err)
+			}
+			if !reflect.DeepEqual(gotV, wantV) {
+				t.Fatalf("CallMCPTool:\ngot  %s\nwant %s", got, tc.want)
+			}
+		})
+	}
+}
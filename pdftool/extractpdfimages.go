@@ -0,0 +1,174 @@
+package pdftool
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/flexigpt/llmtools-go/internal/fileutil"
+	"github.com/flexigpt/llmtools-go/internal/jsonutil"
+	"github.com/flexigpt/llmtools-go/internal/pdfutil"
+	"github.com/flexigpt/llmtools-go/internal/toolutil"
+	"github.com/flexigpt/llmtools-go/spec"
+)
+
+const (
+	extractPDFImagesFuncID spec.FuncID = "github.com/flexigpt/llmtools-go/pdftool/extractpdfimages.ExtractPDFImages"
+
+	maxPDFImagesLimit = 64
+)
+
+var extractPDFImagesTool = spec.Tool{
+	SchemaVersion: spec.SchemaVersion,
+	ID:            "01a13d86-36d7-74ff-9ec9-304637f45098",
+	Slug:          "extractpdfimages",
+	Version:       "v1.0.0",
+	DisplayName:   "Extract PDF images",
+	Description:   "Extract embedded images from a local PDF. JPEG/JPEG 2000 images are returned as-is and simple raw images as PNG; unsupported encodings are skipped. Returns a JSON summary followed by one image output per image.",
+	Tags:          []string{"pdf", "image", "read"},
+
+	ArgSchema: spec.JSONSchema(`{
+"$schema": "http://json-schema.org/draft-07/schema#",
+"type": "object",
+"properties": {
+	"path": {
+		"type": "string",
+		"description": "Absolute or relative path of the PDF file."
+	},
+	"maxImages": {
+		"type": "integer",
+		"minimum": 1,
+		"maximum": 64,
+		"description": "Maximum number of images to return (default 16)."
+	}
+},
+"required": ["path"],
+"additionalProperties": false
+}`),
+	GoImpl: spec.GoToolImpl{FuncID: extractPDFImagesFuncID},
+
+	CreatedAt:  spec.SchemaStartTime,
+	ModifiedAt: spec.SchemaStartTime,
+}
+
+func ExtractPDFImagesTool() spec.Tool {
+	return toolutil.CloneTool(extractPDFImagesTool)
+}
+
+type ExtractPDFImagesArgs struct {
+	Path      string `json:"path"`                // required
+	MaxImages int    `json:"maxImages,omitempty"` // default 16, max 64
+}
+
+// PDFImageInfo describes one extracted image in the ExtractPDFImagesInfo header.
+type PDFImageInfo struct {
+	Page     int    `json:"page"`
+	Name     string `json:"name"`
+	Width    int    `json:"width"`
+	Height   int    `json:"height"`
+	MIMEType string `json:"mimeType"`
+}
+
+// ExtractPDFImagesInfo is the JSON header emitted as the first output item.
+type ExtractPDFImagesInfo struct {
+	Path   string         `json:"path"`
+	Images []PDFImageInfo `json:"images"`
+}
+
+// ExtractPDFImages extracts image XObjects from a local PDF.
+// It returns an ExtractPDFImagesInfo JSON text header, then one image output per
+// entry in the header, in the same order.
+func ExtractPDFImages(ctx context.Context, args ExtractPDFImagesArgs) ([]spec.ToolStoreOutputUnion, error) {
+	return toolutil.WithRecoveryResp(func() ([]spec.ToolStoreOutputUnion, error) {
+		return extractPDFImages(ctx, args)
+	})
+}
+
+func extractPDFImages(ctx context.Context, args ExtractPDFImagesArgs) ([]spec.ToolStoreOutputUnion, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	maxImages := args.MaxImages
+	if maxImages < 0 {
+		return nil, errors.New("maxImages must be >= 0")
+	}
+	if maxImages == 0 {
+		maxImages = pdfutil.DefaultMaxPDFImages
+	}
+	maxImages = min(maxImages, maxPDFImagesLimit)
+
+	path := strings.TrimSpace(args.Path)
+	if path == "" {
+		return nil, fileutil.ErrInvalidPath
+	}
+	p, err := fileutil.NormalizePath(path)
+	if err != nil {
+		return nil, err
+	}
+	st, err := fileutil.RequireExistingRegularFileNoSymlink(p)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("path does not exist: %s", p)
+		}
+		return nil, err
+	}
+	if maxRead := toolutil.GetLimits().MaxFileRead; st.Size() > maxRead {
+		return nil, fmt.Errorf(
+			"file %q is too large to read (%d bytes; max %d)",
+			p, st.Size(), maxRead,
+		)
+	}
+
+	images, err := pdfutil.ExtractPDFImages(ctx, p, maxImages)
+	if err != nil {
+		return nil, err
+	}
+
+	info := ExtractPDFImagesInfo{Path: p, Images: make([]PDFImageInfo, 0, len(images))}
+	outs := make([]spec.ToolStoreOutputUnion, 1, len(images)+1)
+	base := strings.TrimSuffix(filepath.Base(p), filepath.Ext(p))
+	for _, img := range images {
+		info.Images = append(info.Images, PDFImageInfo{
+			Page:     img.Page,
+			Name:     img.Name,
+			Width:    img.Width,
+			Height:   img.Height,
+			MIMEType: img.MIMEType,
+		})
+		outs = append(outs, spec.ToolStoreOutputUnion{
+			Kind: spec.ToolStoreOutputKindImage,
+			ImageItem: &spec.ToolStoreOutputImage{
+				Detail:    spec.ImageDetailAuto,
+				ImageName: fmt.Sprintf("%s-p%d-%s%s", base, img.Page, img.Name, imageExt(img.MIMEType)),
+				ImageMIME: img.MIMEType,
+				ImageData: img.Base64Data,
+			},
+		})
+	}
+
+	header, err := jsonutil.EncodeToJSONRaw(info)
+	if err != nil {
+		return nil, err
+	}
+	outs[0] = spec.ToolStoreOutputUnion{
+		Kind:     spec.ToolStoreOutputKindText,
+		TextItem: &spec.ToolStoreOutputText{Text: string(header)},
+	}
+	return outs, nil
+}
+
+func imageExt(mimeType string) string {
+	switch mimeType {
+	case "image/jpeg":
+		return ".jpg"
+	case "image/jp2":
+		return ".jp2"
+	case "image/png":
+		return ".png"
+	}
+	return ""
+}
@@ -0,0 +1,126 @@
+package pdftool
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/flexigpt/llmtools-go/spec"
+)
+
+func TestExtractPDFImages(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+
+	jpeg := []byte{0xff, 0xd8, 0xff, 0xe0, 0x00, 0xff, 0xd9}
+	withImages := filepath.Join(dir, "report.pdf")
+	if err := os.WriteFile(withImages, buildTestPDFWithJPEGs([][]byte{jpeg, nil, jpeg}, "a", "b", "c"), 0o600); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	noImages := writeTestPDF(t, dir, "plain.pdf", "text only")
+
+	img := func(page int) PDFImageInfo {
+		return PDFImageInfo{Page: page, Name: "Im1", Width: 1, Height: 1, MIMEType: "image/jpeg"}
+	}
+	tests := []struct {
+		name          string
+		ctx           func(t *testing.T) context.Context
+		args          ExtractPDFImagesArgs
+		wantImages    []PDFImageInfo
+		wantNames     []string
+		wantErr       bool
+		wantErrIs     error
+		wantErrSubstr string
+	}{
+		{
+			name:       "all images",
+			args:       ExtractPDFImagesArgs{Path: withImages},
+			wantImages: []PDFImageInfo{img(1), img(3)},
+			wantNames:  []string{"report-p1-Im1.jpg", "report-p3-Im1.jpg"},
+		},
+		{
+			name:       "maxImages limits",
+			args:       ExtractPDFImagesArgs{Path: withImages, MaxImages: 1},
+			wantImages: []PDFImageInfo{img(1)},
+			wantNames:  []string{"report-p1-Im1.jpg"},
+		},
+		{name: "no images", args: ExtractPDFImagesArgs{Path: noImages}, wantImages: []PDFImageInfo{}},
+		{name: "negative maxImages errors", args: ExtractPDFImagesArgs{Path: withImages, MaxImages: -1}, wantErr: true},
+		{name: "empty path errors", args: ExtractPDFImagesArgs{}, wantErr: true},
+		{
+			name:          "missing file errors",
+			args:          ExtractPDFImagesArgs{Path: filepath.Join(dir, "missing.pdf")},
+			wantErr:       true,
+			wantErrSubstr: "does not exist",
+		},
+		{
+			name: "canceled context",
+			ctx: func(t *testing.T) context.Context {
+				t.Helper()
+				ctx, cancel := context.WithCancel(t.Context())
+				cancel()
+				return ctx
+			},
+			args:      ExtractPDFImagesArgs{Path: withImages},
+			wantErr:   true,
+			wantErrIs: context.Canceled,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctx := t.Context()
+			if tt.ctx != nil {
+				ctx = tt.ctx(t)
+			}
+			outs, err := ExtractPDFImages(ctx, tt.args)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected error, got %+v", outs)
+				}
+				if tt.wantErrIs != nil && !errors.Is(err, tt.wantErrIs) {
+					t.Fatalf("expected errors.Is(%v), got %v", tt.wantErrIs, err)
+				}
+				if tt.wantErrSubstr != "" && !strings.Contains(err.Error(), tt.wantErrSubstr) {
+					t.Fatalf("error %q does not contain %q", err, tt.wantErrSubstr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(outs) != len(tt.wantImages)+1 {
+				t.Fatalf("expected %d outputs, got %d", len(tt.wantImages)+1, len(outs))
+			}
+			if outs[0].Kind != spec.ToolStoreOutputKindText || outs[0].TextItem == nil {
+				t.Fatalf("expected text header, got %+v", outs[0])
+			}
+			var info ExtractPDFImagesInfo
+			if err := json.Unmarshal([]byte(outs[0].TextItem.Text), &info); err != nil {
+				t.Fatalf("decode header: %v", err)
+			}
+			if !reflect.DeepEqual(info.Images, tt.wantImages) {
+				t.Fatalf("images=%+v want %+v", info.Images, tt.wantImages)
+			}
+			for i, o := range outs[1:] {
+				if o.Kind != spec.ToolStoreOutputKindImage || o.ImageItem == nil {
+					t.Fatalf("output %d: expected image item, got %+v", i+1, o)
+				}
+				if o.ImageItem.ImageName != tt.wantNames[i] || o.ImageItem.ImageMIME != "image/jpeg" {
+					t.Fatalf("output %d: name=%q mime=%q", i+1, o.ImageItem.ImageName, o.ImageItem.ImageMIME)
+				}
+				raw, err := base64.StdEncoding.DecodeString(o.ImageItem.ImageData)
+				if err != nil || string(raw) != string(jpeg) {
+					t.Fatalf("output %d: data=%x err=%v", i+1, raw, err)
+				}
+			}
+		})
+	}
+}
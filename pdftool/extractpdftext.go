@@ -0,0 +1,186 @@
+package pdftool
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/flexigpt/llmtools-go/internal/fileutil"
+	"github.com/flexigpt/llmtools-go/internal/jsonutil"
+	"github.com/flexigpt/llmtools-go/internal/pdfutil"
+	"github.com/flexigpt/llmtools-go/internal/toolutil"
+	"github.com/flexigpt/llmtools-go/spec"
+)
+
+// ErrEmptyPDFText is returned when a PDF has no extractable text, e.g.
+// a scanned, image-only document. Match it with errors.Is.
+var ErrEmptyPDFText = pdfutil.ErrEmptyPDFText
+
+const extractPDFTextFuncID spec.FuncID = "github.com/flexigpt/llmtools-go/pdftool/extractpdftext.ExtractPDFText"
+
+var extractPDFTextTool = spec.Tool{
+	SchemaVersion: spec.SchemaVersion,
+	ID:            "01a13d7f-42c7-7967-a0fb-1f6934152b82",
+	Slug:          "extractpdftext",
+	Version:       "v1.0.0",
+	DisplayName:   "Extract PDF text",
+	Description:   "Extract plain text from a local PDF, optionally limited to a page range. Returns the page count followed by the text.",
+	Tags:          []string{"pdf", "read"},
+
+	ArgSchema: spec.JSONSchema(`{
+"$schema": "http://json-schema.org/draft-07/schema#",
+"type": "object",
+"properties": {
+	"path": {
+		"type": "string",
+		"description": "Absolute or relative path of the PDF file."
+	},
+	"maxBytes": {
+		"type": "integer",
+		"minimum": 1,
+		"description": "Maximum bytes of extracted text to return (default and cap: 16MB)."
+	},
+	"firstPage": {
+		"type": "integer",
+		"minimum": 1,
+		"description": "First page to extract (1-based). Defaults to 1."
+	},
+	"lastPage": {
+		"type": "integer",
+		"minimum": 1,
+		"description": "Last page to extract (inclusive). Defaults to, and is clamped to, the page count."
+	},
+	"normalizeWhitespace": {
+		"type": "boolean",
+		"description": "Collapse runs of spaces, join words hyphenated across line breaks, and turn form feeds (page breaks) into blank lines.",
+		"default": false
+	}
+},
+"required": ["path"],
+"additionalProperties": false
+}`),
+	GoImpl: spec.GoToolImpl{FuncID: extractPDFTextFuncID},
+
+	CreatedAt:  spec.SchemaStartTime,
+	ModifiedAt: spec.SchemaStartTime,
+}
+
+func ExtractPDFTextTool() spec.Tool {
+	return toolutil.CloneTool(extractPDFTextTool)
+}
+
+type ExtractPDFTextArgs struct {
+	Path      string `json:"path"`                // required
+	MaxBytes  int    `json:"maxBytes,omitempty"`  // default/cap toolutil.Limits.MaxFileRead
+	FirstPage int    `json:"firstPage,omitempty"` // 1-based; 0 => 1
+	LastPage  int    `json:"lastPage,omitempty"`  // inclusive; 0 => page count
+
+	// NormalizeWhitespace tidies the text (see fileutil.NormalizeWhitespace).
+	NormalizeWhitespace bool `json:"normalizeWhitespace,omitempty"`
+
+	// OnProgress, if set, is called after each page with the pages processed and
+	// the pages in the range. Go callers only; it is not part of the tool schema.
+	OnProgress func(done, total int) `json:"-"`
+}
+
+// ExtractPDFTextInfo is the JSON header emitted as the first output item.
+type ExtractPDFTextInfo struct {
+	Path      string `json:"path"`
+	PageCount int    `json:"pageCount"`
+	FirstPage int    `json:"firstPage"`
+	LastPage  int    `json:"lastPage"`
+}
+
+// ExtractPDFText extracts text from a local PDF.
+// It returns two text outputs: an ExtractPDFTextInfo JSON header (page count and
+// the extracted range), then the extracted text. The text is returned as
+// extracted unless NormalizeWhitespace is set; maxBytes applies before
+// normalization.
+func ExtractPDFText(ctx context.Context, args ExtractPDFTextArgs) ([]spec.ToolStoreOutputUnion, error) {
+	return toolutil.WithRecoveryResp(func() ([]spec.ToolStoreOutputUnion, error) {
+		return extractPDFText(ctx, args)
+	})
+}
+
+func extractPDFText(ctx context.Context, args ExtractPDFTextArgs) ([]spec.ToolStoreOutputUnion, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	maxRead := toolutil.GetLimits().MaxFileRead
+	maxBytes := args.MaxBytes
+	if maxBytes < 0 {
+		return nil, errors.New("maxBytes must be >= 0")
+	}
+	if maxBytes == 0 || int64(maxBytes) > maxRead {
+		maxBytes = int(maxRead)
+	}
+	if args.FirstPage < 0 || args.LastPage < 0 {
+		return nil, errors.New("firstPage and lastPage must be >= 1 when set")
+	}
+
+	path := strings.TrimSpace(args.Path)
+	if path == "" {
+		return nil, fileutil.ErrInvalidPath
+	}
+	p, err := fileutil.NormalizePath(path)
+	if err != nil {
+		return nil, err
+	}
+	st, err := fileutil.RequireExistingRegularFileNoSymlink(p)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("path does not exist: %s", p)
+		}
+		return nil, err
+	}
+	if st.Size() > maxRead {
+		return nil, fmt.Errorf(
+			"file %q is too large to read (%d bytes; max %d)",
+			p, st.Size(), maxRead,
+		)
+	}
+
+	info, err := pdfutil.PDFInfo(ctx, p)
+	if err != nil {
+		return nil, err
+	}
+	first := max(args.FirstPage, 1)
+	if first > info.PageCount {
+		return nil, fmt.Errorf("firstPage %d out of range (document has %d pages)", first, info.PageCount)
+	}
+	last := info.PageCount
+	if args.LastPage > 0 {
+		last = min(args.LastPage, info.PageCount)
+	}
+
+	text, err := pdfutil.ExtractPDFTextRangeWithProgress(ctx, p, first, last, maxBytes, args.OnProgress)
+	if err != nil {
+		return nil, err
+	}
+	if args.NormalizeWhitespace {
+		text = fileutil.NormalizeWhitespace(text)
+	}
+
+	header, err := jsonutil.EncodeToJSONRaw(ExtractPDFTextInfo{
+		Path:      p,
+		PageCount: info.PageCount,
+		FirstPage: first,
+		LastPage:  last,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return []spec.ToolStoreOutputUnion{
+		{
+			Kind:     spec.ToolStoreOutputKindText,
+			TextItem: &spec.ToolStoreOutputText{Text: string(header)},
+		},
+		{
+			Kind:     spec.ToolStoreOutputKindText,
+			TextItem: &spec.ToolStoreOutputText{Text: text},
+		},
+	}, nil
+}
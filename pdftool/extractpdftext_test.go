@@ -0,0 +1,178 @@
+package pdftool
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/flexigpt/llmtools-go/spec"
+)
+
+func TestExtractPDFText(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	threePages := writeTestPDF(t, dir, "three.pdf", "alpha", "bravo", "charlie")
+	blank := writeTestPDF(t, dir, "blank.pdf", "")
+	messy := writeTestPDF(t, dir, "messy.pdf", "an exam-\nple   with  gaps", "next   page")
+	notPDF := filepath.Join(dir, "not.pdf")
+	if err := os.WriteFile(notPDF, []byte("not a pdf"), 0o600); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+
+	tests := []struct {
+		name          string
+		ctx           func(t *testing.T) context.Context
+		args          ExtractPDFTextArgs
+		wantInfo      ExtractPDFTextInfo
+		wantText      string
+		wantErr       bool
+		wantErrIs     error
+		wantErrSubstr string
+	}{
+		{
+			name:     "whole document",
+			args:     ExtractPDFTextArgs{Path: threePages},
+			wantInfo: ExtractPDFTextInfo{Path: threePages, PageCount: 3, FirstPage: 1, LastPage: 3},
+			wantText: "alpha\nbravo\ncharlie",
+		},
+		{
+			name:     "page range",
+			args:     ExtractPDFTextArgs{Path: threePages, FirstPage: 2, LastPage: 2},
+			wantInfo: ExtractPDFTextInfo{Path: threePages, PageCount: 3, FirstPage: 2, LastPage: 2},
+			wantText: "bravo",
+		},
+		{
+			name:     "lastPage clamped",
+			args:     ExtractPDFTextArgs{Path: threePages, FirstPage: 3, LastPage: 10},
+			wantInfo: ExtractPDFTextInfo{Path: threePages, PageCount: 3, FirstPage: 3, LastPage: 3},
+			wantText: "charlie",
+		},
+		{
+			name:     "maxBytes truncates",
+			args:     ExtractPDFTextArgs{Path: threePages, MaxBytes: 4},
+			wantInfo: ExtractPDFTextInfo{Path: threePages, PageCount: 3, FirstPage: 1, LastPage: 3},
+			wantText: "alp",
+		},
+		{
+			name:     "raw text by default",
+			args:     ExtractPDFTextArgs{Path: messy},
+			wantInfo: ExtractPDFTextInfo{Path: messy, PageCount: 2, FirstPage: 1, LastPage: 2},
+			wantText: "an exam-\nple   with  gaps\nnext   page",
+		},
+		{
+			name:     "normalize whitespace",
+			args:     ExtractPDFTextArgs{Path: messy, NormalizeWhitespace: true},
+			wantInfo: ExtractPDFTextInfo{Path: messy, PageCount: 2, FirstPage: 1, LastPage: 2},
+			wantText: "an example with gaps\nnext page",
+		},
+		{
+			name:          "inverted range errors",
+			args:          ExtractPDFTextArgs{Path: threePages, FirstPage: 3, LastPage: 1},
+			wantErr:       true,
+			wantErrSubstr: "invalid page range",
+		},
+		{
+			name:          "firstPage past end errors",
+			args:          ExtractPDFTextArgs{Path: threePages, FirstPage: 4},
+			wantErr:       true,
+			wantErrSubstr: "out of range",
+		},
+		{name: "negative maxBytes errors", args: ExtractPDFTextArgs{Path: threePages, MaxBytes: -1}, wantErr: true},
+		{name: "empty path errors", args: ExtractPDFTextArgs{}, wantErr: true},
+		{
+			name:          "missing file errors",
+			args:          ExtractPDFTextArgs{Path: filepath.Join(dir, "missing.pdf")},
+			wantErr:       true,
+			wantErrSubstr: "does not exist",
+		},
+		{name: "not a pdf errors", args: ExtractPDFTextArgs{Path: notPDF}, wantErr: true},
+		{
+			name:      "no text errors",
+			args:      ExtractPDFTextArgs{Path: blank},
+			wantErr:   true,
+			wantErrIs: ErrEmptyPDFText,
+		},
+		{
+			name: "canceled context",
+			ctx: func(t *testing.T) context.Context {
+				t.Helper()
+				ctx, cancel := context.WithCancel(t.Context())
+				cancel()
+				return ctx
+			},
+			args:      ExtractPDFTextArgs{Path: threePages},
+			wantErr:   true,
+			wantErrIs: context.Canceled,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctx := t.Context()
+			if tt.ctx != nil {
+				ctx = tt.ctx(t)
+			}
+			outs, err := ExtractPDFText(ctx, tt.args)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected error, got %+v", outs)
+				}
+				if tt.wantErrIs != nil && !errors.Is(err, tt.wantErrIs) {
+					t.Fatalf("expected errors.Is(%v), got %v", tt.wantErrIs, err)
+				}
+				if tt.wantErrSubstr != "" && !strings.Contains(err.Error(), tt.wantErrSubstr) {
+					t.Fatalf("error %q does not contain %q", err, tt.wantErrSubstr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(outs) != 2 {
+				t.Fatalf("expected 2 outputs, got %d", len(outs))
+			}
+			for i, o := range outs {
+				if o.Kind != spec.ToolStoreOutputKindText || o.TextItem == nil {
+					t.Fatalf("output %d: expected text item, got %+v", i, o)
+				}
+			}
+			var info ExtractPDFTextInfo
+			if err := json.Unmarshal([]byte(outs[0].TextItem.Text), &info); err != nil {
+				t.Fatalf("decode header: %v", err)
+			}
+			if info != tt.wantInfo {
+				t.Fatalf("info=%+v want %+v", info, tt.wantInfo)
+			}
+			if outs[1].TextItem.Text != tt.wantText {
+				t.Fatalf("text=%q want %q", outs[1].TextItem.Text, tt.wantText)
+			}
+		})
+	}
+}
+
+func TestExtractPDFText_OnProgress(t *testing.T) {
+	t.Parallel()
+	threePages := writeTestPDF(t, t.TempDir(), "three.pdf", "alpha", "bravo", "charlie")
+
+	calls := 0
+	_, err := ExtractPDFText(t.Context(), ExtractPDFTextArgs{
+		Path: threePages,
+		OnProgress: func(done, total int) {
+			calls++
+			if done != calls || total != 3 {
+				t.Errorf("progress(%d, %d) on call %d", done, total, calls)
+			}
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if calls != 3 {
+		t.Fatalf("progress called %d times, want 3", calls)
+	}
+}
@@ -0,0 +1,55 @@
+package pdftool
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/flexigpt/llmtools-go/internal/fileutil"
+	"github.com/flexigpt/llmtools-go/internal/pdfutil"
+	"github.com/flexigpt/llmtools-go/internal/toolutil"
+)
+
+// OCRFunc recognizes the text in a page image. No OCR engine is bundled;
+// callers adapt the engine of their choice.
+type OCRFunc = pdfutil.OCRFunc
+
+// ExtractPDFTextWithOCR returns the text of a local PDF, at most maxBytes (0
+// uses, and larger values are capped to, the file read limit). When the PDF has
+// no text layer, e.g. a scanned document, the embedded page images are decoded
+// and run through ocr in page order instead. With a nil ocr, or if OCR yields no
+// text, ErrEmptyPDFText is returned.
+func ExtractPDFTextWithOCR(ctx context.Context, path string, maxBytes int, ocr OCRFunc) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+	maxRead := toolutil.GetLimits().MaxFileRead
+	if maxBytes < 0 {
+		return "", errors.New("maxBytes must be >= 0")
+	}
+	if maxBytes == 0 || int64(maxBytes) > maxRead {
+		maxBytes = int(maxRead)
+	}
+
+	path = strings.TrimSpace(path)
+	if path == "" {
+		return "", fileutil.ErrInvalidPath
+	}
+	p, err := fileutil.NormalizePath(path)
+	if err != nil {
+		return "", err
+	}
+	st, err := fileutil.RequireExistingRegularFileNoSymlink(p)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return "", fmt.Errorf("path does not exist: %s", p)
+		}
+		return "", err
+	}
+	if st.Size() > maxRead {
+		return "", fmt.Errorf("file %q is too large to read (%d bytes; max %d)", p, st.Size(), maxRead)
+	}
+	return pdfutil.ExtractPDFTextWithOCR(ctx, p, maxBytes, ocr)
+}
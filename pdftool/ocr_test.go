@@ -0,0 +1,56 @@
+package pdftool
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"image"
+	"image/jpeg"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestExtractPDFTextWithOCR(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+
+	var jpg bytes.Buffer
+	if err := jpeg.Encode(&jpg, image.NewGray(image.Rect(0, 0, 1, 1)), nil); err != nil {
+		t.Fatalf("encode jpeg: %v", err)
+	}
+	scanned := filepath.Join(dir, "scanned.pdf")
+	if err := os.WriteFile(scanned, buildTestPDFWithJPEGs([][]byte{jpg.Bytes()}, ""), 0o600); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	textPDF := writeTestPDF(t, dir, "text.pdf", "alpha")
+
+	var calls int
+	fakeOCR := func(_ context.Context, img image.Image) (string, error) {
+		calls++
+		if img.Bounds().Dx() != 1 {
+			t.Errorf("unexpected image bounds %v", img.Bounds())
+		}
+		return "recognized text", nil
+	}
+
+	got, err := ExtractPDFTextWithOCR(t.Context(), scanned, 0, fakeOCR)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got != "recognized text" || calls != 1 {
+		t.Fatalf("text=%q calls=%d, want %q from one OCR call", got, calls, "recognized text")
+	}
+
+	got, err = ExtractPDFTextWithOCR(t.Context(), textPDF, 0, fakeOCR)
+	if err != nil || got != "alpha" || calls != 1 {
+		t.Fatalf("text layer: text=%q err=%v calls=%d", got, err, calls)
+	}
+
+	if _, err := ExtractPDFTextWithOCR(t.Context(), scanned, 0, nil); !errors.Is(err, ErrEmptyPDFText) {
+		t.Fatalf("nil ocr: expected ErrEmptyPDFText, got %v", err)
+	}
+	if _, err := ExtractPDFTextWithOCR(t.Context(), scanned, -1, fakeOCR); err == nil {
+		t.Fatalf("negative maxBytes: expected error")
+	}
+}
@@ -0,0 +1,68 @@
+package pdftool
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// buildTestPDF returns a valid PDF with one page per entry, each page showing its text.
+func buildTestPDF(pages ...string) []byte {
+	return buildTestPDFWithJPEGs(nil, pages...)
+}
+
+// buildTestPDFWithJPEGs is like buildTestPDF, additionally attaching jpegs[i]
+// (if non-nil) to page i as a 1x1 DCTDecode image XObject named Im1.
+func buildTestPDFWithJPEGs(jpegs [][]byte, pages ...string) []byte {
+	objs := []string{"", "", "<< /Type /Font /Subtype /Type1 /BaseFont /Courier >>"}
+	kids := make([]string, 0, len(pages))
+	for i, text := range pages {
+		xobjects := ""
+		if i < len(jpegs) && jpegs[i] != nil {
+			objs = append(objs, fmt.Sprintf(
+				"<< /Type /XObject /Subtype /Image /Width 1 /Height 1 /ColorSpace /DeviceRGB /BitsPerComponent 8 /Filter /DCTDecode /Length %d >>\nstream\n%s\nendstream",
+				len(jpegs[i]), jpegs[i],
+			))
+			xobjects = fmt.Sprintf(" /XObject << /Im1 %d 0 R >>", len(objs))
+		}
+		content := ""
+		if text != "" {
+			content = fmt.Sprintf("BT\n/F1 12 Tf\n72 700 Td\n(%s) Tj\nET\n", text)
+		}
+		objs = append(objs, fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", len(content), content))
+		contentsID := len(objs)
+		objs = append(objs, fmt.Sprintf(
+			"<< /Type /Page /Parent 2 0 R /MediaBox [0 0 612 792] /Contents %d 0 R /Resources << /Font << /F1 3 0 R >>%s >> >>",
+			contentsID, xobjects,
+		))
+		kids = append(kids, fmt.Sprintf("%d 0 R", len(objs)))
+	}
+	objs[0] = "<< /Type /Catalog /Pages 2 0 R >>"
+	objs[1] = fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(kids))
+
+	var b strings.Builder
+	b.WriteString("%PDF-1.4\n")
+	offsets := make([]int, len(objs))
+	for i, body := range objs {
+		offsets[i] = b.Len()
+		fmt.Fprintf(&b, "%d 0 obj\n%s\nendobj\n", i+1, body)
+	}
+	xref := b.Len()
+	fmt.Fprintf(&b, "xref\n0 %d\n0000000000 65535 f \n", len(objs)+1)
+	for _, off := range offsets {
+		fmt.Fprintf(&b, "%010d 00000 n \n", off)
+	}
+	fmt.Fprintf(&b, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(objs)+1, xref)
+	return []byte(b.String())
+}
+
+func writeTestPDF(t *testing.T, dir, name string, pages ...string) string {
+	t.Helper()
+	p := filepath.Join(dir, name)
+	if err := os.WriteFile(p, buildTestPDF(pages...), 0o600); err != nil {
+		t.Fatalf("write %s: %v", p, err)
+	}
+	return p
+}
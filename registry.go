@@ -1,6 +1,7 @@
 package llmtools
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"errors"
@@ -15,6 +16,7 @@ import (
 	"github.com/flexigpt/llmtools-go/internal/jsonutil"
 	"github.com/flexigpt/llmtools-go/internal/logutil"
 	"github.com/flexigpt/llmtools-go/internal/toolutil"
+	"github.com/flexigpt/llmtools-go/pdftool"
 	"github.com/flexigpt/llmtools-go/shelltool"
 	"github.com/flexigpt/llmtools-go/spec"
 	"github.com/flexigpt/llmtools-go/texttool"
@@ -27,8 +29,12 @@ type Registry struct {
 
 	toolMap     map[spec.FuncID]spec.ToolFunc
 	toolSpecMap map[spec.FuncID]spec.Tool
+	slugMap     map[string]spec.FuncID // slug -> funcID; slugs are unique
 
-	timeout time.Duration
+	timeout      time.Duration
+	toolTimeouts map[string]time.Duration // slug -> per-tool default timeout
+
+	idempotency *idempotencyCache
 }
 
 type RegistryOption func(*Registry) error
@@ -57,6 +63,17 @@ func WithDefaultCallTimeout(d time.Duration) RegistryOption {
 	}
 }
 
+// WithIdempotencyCache configures how long successful results of calls made
+// with WithIdempotencyKey are kept (ttl) and how many are kept at most
+// (maxEntries, LRU-evicted; <=0 means unbounded). ttl<=0 disables the cache,
+// so keys are ignored. Defaults: 10 minutes, 256 entries.
+func WithIdempotencyCache(ttl time.Duration, maxEntries int) RegistryOption {
+	return func(r *Registry) error {
+		r.idempotency = newIdempotencyCache(ttl, maxEntries)
+		return nil
+	}
+}
+
 func WithLogger(logger *slog.Logger) RegistryOption {
 	return func(ps *Registry) error {
 		ps.logger = logger
@@ -66,8 +83,11 @@ func WithLogger(logger *slog.Logger) RegistryOption {
 
 func NewRegistry(opts ...RegistryOption) (*Registry, error) {
 	r := &Registry{
-		toolMap:     make(map[spec.FuncID]spec.ToolFunc),
-		toolSpecMap: make(map[spec.FuncID]spec.Tool),
+		toolMap:      make(map[spec.FuncID]spec.ToolFunc),
+		toolSpecMap:  make(map[spec.FuncID]spec.Tool),
+		slugMap:      make(map[string]spec.FuncID),
+		toolTimeouts: make(map[string]time.Duration),
+		idempotency:  newIdempotencyCache(defaultIdempotencyTTL, defaultIdempotencyMaxEntries),
 	}
 	for _, o := range opts {
 		if err := o(r); err != nil {
@@ -90,18 +110,54 @@ func RegisterBuiltins(r *Registry) error {
 	if err := RegisterTypedAsTextTool(r, fstool.SearchFilesTool(), fstool.SearchFiles); err != nil {
 		return err
 	}
+	if err := RegisterTypedAsTextTool(r, fstool.GrepFirstTool(), fstool.GrepFirst); err != nil {
+		return err
+	}
+	if err := RegisterTypedAsTextTool(r, fstool.HeadFileTool(), fstool.HeadFile); err != nil {
+		return err
+	}
 	if err := RegisterTypedAsTextTool(r, fstool.WriteFileTool(), fstool.WriteFile); err != nil {
 		return err
 	}
+	if err := RegisterTypedAsTextTool(r, fstool.WriteNDJSONTool(), fstool.WriteNDJSON); err != nil {
+		return err
+	}
+	if err := RegisterTypedAsTextTool(r, fstool.JSONArrayUpdateTool(), fstool.JSONArrayUpdate); err != nil {
+		return err
+	}
+	if err := RegisterTypedAsTextTool(r, fstool.ValidateJSONFileTool(), fstool.ValidateJSONFile); err != nil {
+		return err
+	}
+	if err := RegisterTypedAsTextTool(r, fstool.FormatJSONFileTool(), fstool.FormatJSONFile); err != nil {
+		return err
+	}
 	if err := RegisterTypedAsTextTool(r, fstool.DeleteFileTool(), fstool.DeleteFile); err != nil {
 		return err
 	}
+	if err := RegisterTypedAsTextTool(r, fstool.RenameFilesTool(), fstool.RenameFiles); err != nil {
+		return err
+	}
 	if err := RegisterTypedAsTextTool(r, fstool.ListDirectoryTool(), fstool.ListDirectory); err != nil {
 		return err
 	}
+	if err := RegisterTypedAsTextTool(r, fstool.DirectorySummaryTool(), fstool.DirectorySummary); err != nil {
+		return err
+	}
+	if err := RegisterTypedAsTextTool(r, fstool.DirTreeTool(), fstool.DirTree); err != nil {
+		return err
+	}
+	if err := RegisterTypedAsTextTool(r, fstool.RecentlyModifiedTool(), fstool.RecentlyModified); err != nil {
+		return err
+	}
+	if err := RegisterTypedAsTextTool(r, fstool.TreeStatsTool(), fstool.TreeStats); err != nil {
+		return err
+	}
 	if err := RegisterTypedAsTextTool(r, fstool.StatPathTool(), fstool.StatPath); err != nil {
 		return err
 	}
+	if err := RegisterTypedAsTextTool(r, fstool.WatchPathTool(), fstool.WatchPath); err != nil {
+		return err
+	}
 	if err := RegisterTypedAsTextTool(r, fstool.MIMEForPathTool(), fstool.MIMEForPath); err != nil {
 		return err
 	}
@@ -112,6 +168,28 @@ func RegisterBuiltins(r *Registry) error {
 	if err := RegisterTypedAsTextTool(r, imagetool.ReadImageTool(), imagetool.ReadImage); err != nil {
 		return err
 	}
+	if err := RegisterTypedAsTextTool(r, imagetool.ConvertImageTool(), imagetool.ConvertImage); err != nil {
+		return err
+	}
+	if err := RegisterTypedAsTextTool(r, imagetool.ImageHashTool(), imagetool.ImageHash); err != nil {
+		return err
+	}
+	if err := RegisterTypedAsTextTool(r, imagetool.CropImageTool(), imagetool.CropImage); err != nil {
+		return err
+	}
+	if err := RegisterTypedAsTextTool(r, imagetool.ImagePaletteTool(), imagetool.ImagePalette); err != nil {
+		return err
+	}
+	if err := RegisterTypedAsTextTool(r, imagetool.ImageFitsBudgetTool(), imagetool.ImageFitsBudget); err != nil {
+		return err
+	}
+
+	if err := RegisterOutputsTool(r, pdftool.ExtractPDFTextTool(), pdftool.ExtractPDFText); err != nil {
+		return err
+	}
+	if err := RegisterOutputsTool(r, pdftool.ExtractPDFImagesTool(), pdftool.ExtractPDFImages); err != nil {
+		return err
+	}
 
 	sh, err := shelltool.NewShellTool(
 	// Defaults are fine for builtins; hosts should instantiate their own tool with custom policy/sessions/env/workdir
@@ -123,6 +201,23 @@ func RegisterBuiltins(r *Registry) error {
 	if err := RegisterTypedAsTextTool(r, sh.Tool(), sh.Run); err != nil {
 		return err
 	}
+	if err := RegisterTypedAsTextTool(r, sh.RunCommandTool(), sh.RunCommand); err != nil {
+		return err
+	}
+	// Background commands (startcommand/pollcommand/stopcommand) are not registered here: their processes outlive
+	// calls and are only reaped by ShellTool.Close, which the registry never calls.
+	if err := RegisterTypedAsTextTool(r, sh.SetWorkdirTool(), sh.SetWorkdir); err != nil {
+		return err
+	}
+	if err := RegisterTypedAsTextTool(r, sh.SetSessionEnvTool(), sh.SetSessionEnv); err != nil {
+		return err
+	}
+	if err := RegisterTypedAsTextTool(r, sh.UnsetSessionEnvTool(), sh.UnsetSessionEnv); err != nil {
+		return err
+	}
+	if err := RegisterTypedAsTextTool(r, sh.GetSessionEnvTool(), sh.GetSessionEnv); err != nil {
+		return err
+	}
 
 	if err := RegisterTypedAsTextTool(r, texttool.ReadTextRangeTool(), texttool.ReadTextRange); err != nil {
 		return err
@@ -139,6 +234,9 @@ func RegisterBuiltins(r *Registry) error {
 	if err := RegisterTypedAsTextTool(r, texttool.DeleteTextLinesTool(), texttool.DeleteTextLines); err != nil {
 		return err
 	}
+	if err := RegisterTypedAsTextTool(r, texttool.ReplaceInFilesTool(), texttool.ReplaceInFiles); err != nil {
+		return err
+	}
 
 	return nil
 }
@@ -164,23 +262,11 @@ func RegisterTypedAsTextTool[T, R any](
 	return r.RegisterTool(tool, typedToText(fn))
 }
 
+// RegisterTool registers fn as the implementation of tool. The tool must pass
+// spec.Tool.Validate, and neither its funcID nor its slug may be registered yet.
 func (r *Registry) RegisterTool(tool spec.Tool, fn spec.ToolFunc) error {
-	if tool.GoImpl.FuncID == "" {
-		return errors.New("invalid tool: missing funcID")
-	}
-
-	if tool.SchemaVersion == "" {
-		return errors.New("invalid tool: missing schemaVersion")
-	}
-	if tool.SchemaVersion != spec.SchemaVersion {
-		return fmt.Errorf(
-			"invalid tool: schemaVersion %q does not match library schemaVersion %q",
-			tool.SchemaVersion,
-			spec.SchemaVersion,
-		)
-	}
-	if len(tool.ArgSchema) > 0 && !json.Valid(tool.ArgSchema) {
-		return errors.New("invalid tool: argSchema is not valid JSON")
+	if err := tool.Validate(); err != nil {
+		return err
 	}
 	if fn == nil {
 		return errors.New("invalid tool: nil func")
@@ -191,14 +277,35 @@ func (r *Registry) RegisterTool(tool spec.Tool, fn spec.ToolFunc) error {
 	if _, exists := r.toolMap[tool.GoImpl.FuncID]; exists {
 		return fmt.Errorf("go-tool already registered: %s", tool.GoImpl.FuncID)
 	}
+	if other, exists := r.slugMap[tool.Slug]; exists {
+		return fmt.Errorf("tool slug %q already registered by %s", tool.Slug, other)
+	}
 	r.toolMap[tool.GoImpl.FuncID] = fn
 	r.toolSpecMap[tool.GoImpl.FuncID] = toolutil.CloneTool(tool)
+	r.slugMap[tool.Slug] = tool.GoImpl.FuncID
 
 	return nil
 }
 
+// SetDefaultTimeout sets the default call timeout for the tool with the
+// given slug, taking precedence over the registry default.
+// 0 means "no timeout" for that tool; a negative value removes the per-tool
+// setting so the registry default applies again.
+// The slug does not need to be registered yet.
+func (r *Registry) SetDefaultTimeout(slug string, d time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if d < 0 {
+		delete(r.toolTimeouts, slug)
+		return
+	}
+	r.toolTimeouts[slug] = d
+}
+
 type callOptions struct {
-	timeout *time.Duration
+	timeout        *time.Duration
+	idempotencyKey string
+	maxOutputBytes int
 }
 
 // CallOption configures per-call behavior.
@@ -213,6 +320,26 @@ func WithCallTimeout(d time.Duration) CallOption {
 	}
 }
 
+// WithIdempotencyKey makes a call safe to retry: the outputs of a successful
+// call are cached per (funcID, key), and later calls with the same key return
+// them without running the tool again. A call made while another with the same
+// key is in flight waits for its result. Failed calls are not cached.
+// An empty key disables caching for the call.
+func WithIdempotencyKey(key string) CallOption {
+	return func(o *callOptions) {
+		o.idempotencyKey = key
+	}
+}
+
+// WithMaxOutputBytes caps the payload bytes (text, or base64 image/file data)
+// of this call's outputs; see toolutil.TruncateOutput for how outputs are cut.
+// n<=0 means no cap.
+func WithMaxOutputBytes(n int) CallOption {
+	return func(o *callOptions) {
+		o.maxOutputBytes = n
+	}
+}
+
 func (r *Registry) Call(
 	ctx context.Context,
 	funcID spec.FuncID,
@@ -227,9 +354,12 @@ func (r *Registry) Call(
 			}
 		}
 
-		// Resolve timeout: call override > registry default.
+		// Resolve timeout: call override > tool default > registry default.
 		r.mu.RLock()
 		effectiveTimeout := r.timeout
+		if d, ok := r.toolTimeouts[r.toolSpecMap[funcID].Slug]; ok {
+			effectiveTimeout = d
+		}
 		if co.timeout != nil {
 			effectiveTimeout = *co.timeout
 		}
@@ -251,10 +381,69 @@ func (r *Registry) Call(
 		if !ok {
 			return nil, fmt.Errorf("unknown tool: %s", funcID)
 		}
-		return fn(fnCtx, in)
+		var outs []spec.ToolStoreOutputUnion
+		var err error
+		if co.idempotencyKey == "" || !r.idempotency.enabled() {
+			outs, err = fn(fnCtx, in)
+		} else {
+			outs, err = r.callIdempotent(fnCtx, idempotencyKey{funcID: funcID, key: co.idempotencyKey}, fn, in)
+		}
+		if err != nil {
+			return outs, err
+		}
+		outs, _ = toolutil.TruncateOutput(outs, co.maxOutputBytes)
+		return outs, nil
 	})
 }
 
+// CallBySlug is like Call but resolves the tool by slug, which is the name LLMs
+// see in tool manifests and use in tool calls.
+func (r *Registry) CallBySlug(
+	ctx context.Context,
+	slug string,
+	in json.RawMessage,
+	callOpts ...CallOption,
+) ([]spec.ToolStoreOutputUnion, error) {
+	r.mu.RLock()
+	funcID, ok := r.slugMap[slug]
+	r.mu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("unknown tool slug: %s", slug)
+	}
+	return r.Call(ctx, funcID, in, callOpts...)
+}
+
+func (r *Registry) callIdempotent(
+	ctx context.Context,
+	k idempotencyKey,
+	fn spec.ToolFunc,
+	in json.RawMessage,
+) (outputs []spec.ToolStoreOutputUnion, err error) {
+	for {
+		e, leader := r.idempotency.acquire(k)
+		if leader {
+			defer func() {
+				if p := recover(); p != nil {
+					r.idempotency.finish(e, nil, fmt.Errorf("panic: %v", p))
+					panic(p)
+				}
+				r.idempotency.finish(e, outputs, err)
+			}()
+			return fn(ctx, in)
+		}
+
+		select {
+		case <-e.ready:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+		if e.err == nil {
+			return cloneOutputs(e.outputs), nil
+		}
+		// The in-flight call failed and was not cached; run it ourselves.
+	}
+}
+
 func (r *Registry) Lookup(funcID spec.FuncID) (spec.ToolFunc, bool) {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
@@ -262,6 +451,18 @@ func (r *Registry) Lookup(funcID spec.FuncID) (spec.ToolFunc, bool) {
 	return fn, ok
 }
 
+// ToolBySlug returns a copy of the manifest registered under slug.
+func (r *Registry) ToolBySlug(slug string) (spec.Tool, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	funcID, ok := r.slugMap[slug]
+	if !ok {
+		return spec.Tool{}, false
+	}
+	return toolutil.CloneTool(r.toolSpecMap[funcID]), true
+}
+
+// Tools returns copies of all registered manifests, sorted by slug.
 func (r *Registry) Tools() []spec.Tool {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
@@ -272,14 +473,46 @@ func (r *Registry) Tools() []spec.Tool {
 	}
 	sort.Slice(out, func(i, j int) bool {
 		// Stable tool manifests matter for prompts and tests.
-		if out[i].Slug != out[j].Slug {
-			return out[i].Slug < out[j].Slug
-		}
-		return out[i].GoImpl.FuncID < out[j].GoImpl.FuncID
+		return out[i].Slug < out[j].Slug
 	})
 	return out
 }
 
+// ExportOpenAITools returns the registered tools, in Tools order, as a JSON
+// array of OpenAI function-calling tool definitions (see
+// spec.Tool.ToOpenAIFunction).
+func (r *Registry) ExportOpenAITools() (json.RawMessage, error) {
+	return exportTools(r.Tools(), spec.Tool.ToOpenAIFunction)
+}
+
+// ExportAnthropicTools returns the registered tools, in Tools order, as a JSON
+// array of Anthropic tool-use definitions (see spec.Tool.ToAnthropicTool).
+func (r *Registry) ExportAnthropicTools() (json.RawMessage, error) {
+	return exportTools(r.Tools(), spec.Tool.ToAnthropicTool)
+}
+
+func exportTools(tools []spec.Tool, render func(spec.Tool) (json.RawMessage, error)) (json.RawMessage, error) {
+	items := make([]json.RawMessage, 0, len(tools))
+	for _, t := range tools {
+		item, err := render(t)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	return encodeUnescaped(items)
+}
+
+// encodeUnescaped encodes v compactly without HTML-escaping, so descriptions and
+// texts reach the model as written.
+func encodeUnescaped(v any) (json.RawMessage, error) {
+	var buf bytes.Buffer
+	if err := jsonutil.EncodeJSONTo(&buf, v); err != nil {
+		return nil, err
+	}
+	return json.RawMessage(bytes.TrimSuffix(buf.Bytes(), []byte("\n"))), nil
+}
+
 // typedToOutputs wraps a typed function (ctx, T) -> ([]ToolStoreOutputUnion, error)
 // into a spec.ToolFunc that strictly decodes input into T.
 func typedToOutputs[T any](
@@ -309,22 +542,13 @@ func typedToText[T, R any](fn func(context.Context, T) (R, error)) spec.ToolFunc
 		if err != nil {
 			return nil, err
 		}
-		raw, err := jsonutil.EncodeToJSONRaw(out)
+		item, err := spec.StructOutput(out)
 		if err != nil {
 			return nil, fmt.Errorf("encode output: %w", err)
 		}
-
-		text := string(raw)
-		if text == "" || text == "null" {
+		if text := item.TextItem.Text; text == "" || text == "null" {
 			return nil, nil
 		}
-		return []spec.ToolStoreOutputUnion{
-			{
-				Kind: spec.ToolStoreOutputKindText,
-				TextItem: &spec.ToolStoreOutputText{
-					Text: text,
-				},
-			},
-		}, nil
+		return []spec.ToolStoreOutputUnion{item}, nil
 	}
 }
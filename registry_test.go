@@ -1,10 +1,16 @@
 package llmtools
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"errors"
+	"image"
+	"image/png"
+	"os"
+	"path/filepath"
 	"reflect"
+	"strconv"
 	"strings"
 	"testing"
 	"time"
@@ -62,6 +68,26 @@ func TestRegistry_RegisterTool_Validation(t *testing.T) {
 			fn:              okFn,
 			wantErrContains: "missing funcID",
 		},
+		{
+			name: "missing slug",
+			tool: func() spec.Tool {
+				tl := mkTool("x", "s")
+				tl.Slug = ""
+				return tl
+			}(),
+			fn:              okFn,
+			wantErrContains: "missing slug",
+		},
+		{
+			name: "unsafe slug",
+			tool: func() spec.Tool {
+				tl := mkTool("x", "s")
+				tl.Slug = "a/b"
+				return tl
+			}(),
+			fn:              okFn,
+			wantErrContains: "must match",
+		},
 		{
 			name: "missing schemaVersion",
 			tool: func() spec.Tool {
@@ -92,6 +118,16 @@ func TestRegistry_RegisterTool_Validation(t *testing.T) {
 			fn:              okFn,
 			wantErrContains: "argSchema is not valid JSON",
 		},
+		{
+			name: "argSchema invalid schema",
+			tool: func() spec.Tool {
+				tl := mkTool("x", "s")
+				tl.ArgSchema = spec.JSONSchema([]byte(`{"type": 5}`))
+				return tl
+			}(),
+			fn:              okFn,
+			wantErrContains: "invalid schema",
+		},
 		{
 			name:            "nil func",
 			tool:            mkTool("x", "s"),
@@ -148,6 +184,112 @@ func TestRegistry_RegisterTool_Duplicate(t *testing.T) {
 	}
 }
 
+func TestRegistry_RegisterTool_DuplicateSlug(t *testing.T) {
+	r, err := NewRegistry()
+	if err != nil {
+		t.Fatalf("NewRegistry error: %v", err)
+	}
+	fn := func(context.Context, json.RawMessage) ([]spec.ToolStoreOutputUnion, error) { return nil, nil }
+
+	if err := r.RegisterTool(mkTool("github.com/acme/tools.First", "same"), fn); err != nil {
+		t.Fatalf("first RegisterTool error: %v", err)
+	}
+	err = r.RegisterTool(mkTool("github.com/acme/tools.Second", "same"), fn)
+	if err == nil || !strings.Contains(err.Error(), `slug "same" already registered`) {
+		t.Fatalf("second RegisterTool: got %v want duplicate slug error", err)
+	}
+	if _, ok := r.Lookup("github.com/acme/tools.Second"); ok {
+		t.Fatalf("rejected tool must not be registered")
+	}
+}
+
+func TestRegistry_ToolBySlug(t *testing.T) {
+	r, err := NewRegistry()
+	if err != nil {
+		t.Fatalf("NewRegistry error: %v", err)
+	}
+	fn := func(context.Context, json.RawMessage) ([]spec.ToolStoreOutputUnion, error) { return nil, nil }
+	tool := mkTool("github.com/acme/tools.BySlug", "byslug")
+	if err := r.RegisterTool(tool, fn); err != nil {
+		t.Fatalf("RegisterTool error: %v", err)
+	}
+
+	got, ok := r.ToolBySlug("byslug")
+	if !ok || got.GoImpl.FuncID != tool.GoImpl.FuncID {
+		t.Fatalf("ToolBySlug: got (ok=%v, funcID=%q), want ok=true, funcID=%q", ok, got.GoImpl.FuncID, tool.GoImpl.FuncID)
+	}
+	got.Tags[0] = "mutated"
+	if again, _ := r.ToolBySlug("byslug"); again.Tags[0] == "mutated" {
+		t.Fatalf("registry state mutated via ToolBySlug return value")
+	}
+
+	if _, ok := r.ToolBySlug("unknown"); ok {
+		t.Fatalf("ToolBySlug unknown: got ok=true, want false")
+	}
+}
+
+func TestRegistry_ExportOpenAITools(t *testing.T) {
+	r, err := NewBuiltinRegistry()
+	if err != nil {
+		t.Fatalf("NewBuiltinRegistry error: %v", err)
+	}
+	raw, err := r.ExportOpenAITools()
+	if err != nil {
+		t.Fatalf("ExportOpenAITools error: %v", err)
+	}
+	var got []struct {
+		Type     string `json:"type"`
+		Function struct {
+			Name string `json:"name"`
+		} `json:"function"`
+	}
+	if err := json.Unmarshal(raw, &got); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	tools := r.Tools()
+	if len(got) != len(tools) {
+		t.Fatalf("ExportOpenAITools len: got %d want %d", len(got), len(tools))
+	}
+	for i, tl := range tools {
+		if got[i].Type != "function" || got[i].Function.Name != tl.Slug {
+			t.Fatalf("ExportOpenAITools[%d]: got %+v want function %q", i, got[i], tl.Slug)
+		}
+	}
+
+	bad := mkTool("github.com/acme/tools.Bad", "bad")
+	fn := func(context.Context, json.RawMessage) ([]spec.ToolStoreOutputUnion, error) { return nil, nil }
+	if err := r.RegisterTool(bad, fn); err != nil {
+		t.Fatalf("RegisterTool error: %v", err)
+	}
+	if _, err := r.ExportOpenAITools(); err == nil || !strings.Contains(err.Error(), "tool bad") {
+		t.Fatalf("ExportOpenAITools with non-object schema: got %v want error naming the tool", err)
+	}
+}
+
+func TestRegistry_ExportAnthropicTools(t *testing.T) {
+	r, err := NewBuiltinRegistry()
+	if err != nil {
+		t.Fatalf("NewBuiltinRegistry error: %v", err)
+	}
+	raw, err := r.ExportAnthropicTools()
+	if err != nil {
+		t.Fatalf("ExportAnthropicTools error: %v", err)
+	}
+	var got []map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &got); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	tools := r.Tools()
+	if len(got) != len(tools) {
+		t.Fatalf("ExportAnthropicTools len: got %d want %d", len(got), len(tools))
+	}
+	for i, tl := range tools {
+		if string(got[i]["name"]) != strconv.Quote(tl.Slug) || got[i]["input_schema"] == nil {
+			t.Fatalf("ExportAnthropicTools[%d]: got %v want name %q with input_schema", i, got[i], tl.Slug)
+		}
+	}
+}
+
 func TestRegistry_Lookup(t *testing.T) {
 	r, err := NewRegistry()
 	if err != nil {
@@ -181,9 +323,9 @@ func TestRegistry_Tools_SortedAndCloned(t *testing.T) {
 	dummy := func(context.Context, json.RawMessage) ([]spec.ToolStoreOutputUnion, error) { return nil, nil }
 
 	// Register in intentionally unsorted order.
-	t1 := mkTool("github.com/acme/tools.Z", "a") // slug a, func Z
+	t1 := mkTool("github.com/acme/tools.A", "c") // slug c, func A
 	t2 := mkTool("github.com/acme/tools.M", "a") // slug a, func M
-	t3 := mkTool("github.com/acme/tools.B", "b") // slug b
+	t3 := mkTool("github.com/acme/tools.Z", "b") // slug b, func Z
 
 	for _, tl := range []spec.Tool{t1, t2, t3} {
 		if err := r.RegisterTool(tl, dummy); err != nil {
@@ -196,11 +338,11 @@ func TestRegistry_Tools_SortedAndCloned(t *testing.T) {
 		t.Fatalf("Tools len: got %d want %d", len(got), 3)
 	}
 
-	// Sorted by Slug, then FuncID.
+	// Sorted by Slug, not FuncID.
 	wantOrder := []spec.FuncID{
-		t2.GoImpl.FuncID, // "a" + "M..."
-		t1.GoImpl.FuncID, // "a" + "Z..."
-		t3.GoImpl.FuncID, // "b" + "B..."
+		t2.GoImpl.FuncID, // "a"
+		t3.GoImpl.FuncID, // "b"
+		t1.GoImpl.FuncID, // "c"
 	}
 	for i := range wantOrder {
 		if got[i].GoImpl.FuncID != wantOrder[i] {
@@ -267,6 +409,123 @@ func TestRegistry_Call_UnknownTool(t *testing.T) {
 	}
 }
 
+func TestRegistry_CallBySlug_Builtins(t *testing.T) {
+	r, err := NewBuiltinRegistry()
+	if err != nil {
+		t.Fatalf("NewBuiltinRegistry error: %v", err)
+	}
+
+	dir := t.TempDir()
+	textPath := filepath.Join(dir, "note.txt")
+	if err := os.WriteFile(textPath, []byte("hello registry\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	imgPath := filepath.Join(dir, "pixel.png")
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, 3, 2))); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(imgPath, buf.Bytes(), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	rawArgs := func(v any) json.RawMessage {
+		b, err := json.Marshal(v)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return b
+	}
+
+	tests := []struct {
+		name       string
+		slug       string
+		in         json.RawMessage
+		wantKind   spec.ToolStoreOutputKind
+		wantTexts  []string
+		wantErrSub string
+	}{
+		{
+			name:      "readfile text",
+			slug:      "readfile",
+			in:        rawArgs(map[string]any{"path": textPath}),
+			wantKind:  spec.ToolStoreOutputKindText,
+			wantTexts: []string{"hello registry"},
+		},
+		{
+			name:      "readimage metadata",
+			slug:      "readimage",
+			in:        rawArgs(map[string]any{"path": imgPath}),
+			wantKind:  spec.ToolStoreOutputKindText,
+			wantTexts: []string{`"width":3`, `"height":2`, `"format":"png"`},
+		},
+		{
+			name:       "strict decoding",
+			slug:       "readfile",
+			in:         rawArgs(map[string]any{"path": textPath, "bogus": true}),
+			wantErrSub: "invalid input",
+		},
+		{
+			name:       "unknown slug",
+			slug:       "nope",
+			in:         json.RawMessage(`{}`),
+			wantErrSub: "unknown tool slug",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			out, err := r.CallBySlug(t.Context(), tc.slug, tc.in)
+			if tc.wantErrSub != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.wantErrSub) {
+					t.Fatalf("CallBySlug: got %v want error containing %q", err, tc.wantErrSub)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("CallBySlug error: %v", err)
+			}
+			if len(out) != 1 || out[0].Kind != tc.wantKind || out[0].TextItem == nil {
+				t.Fatalf("CallBySlug outputs: got %+v want one %s output", out, tc.wantKind)
+			}
+			for _, want := range tc.wantTexts {
+				if !strings.Contains(out[0].TextItem.Text, want) {
+					t.Fatalf("CallBySlug text %q does not contain %q", out[0].TextItem.Text, want)
+				}
+			}
+		})
+	}
+}
+
+func TestRegistry_Call_WithMaxOutputBytes(t *testing.T) {
+	r, err := NewRegistry()
+	if err != nil {
+		t.Fatalf("NewRegistry error: %v", err)
+	}
+	fn := func(context.Context, json.RawMessage) ([]spec.ToolStoreOutputUnion, error) {
+		return textOut(strings.Repeat("x", 100)), nil
+	}
+	tool := mkTool("github.com/acme/tools.Big", "big")
+	if err := r.RegisterTool(tool, fn); err != nil {
+		t.Fatalf("RegisterTool error: %v", err)
+	}
+
+	out, err := r.Call(t.Context(), tool.GoImpl.FuncID, json.RawMessage(`{}`), WithMaxOutputBytes(40))
+	if err != nil {
+		t.Fatalf("Call error: %v", err)
+	}
+	if len(out) != 1 || len(out[0].TextItem.Text) > 40 || !strings.HasSuffix(out[0].TextItem.Text, "[truncated]") {
+		t.Fatalf("Call with cap: got %+v want one truncated text of at most 40 bytes", out)
+	}
+
+	out, err = r.Call(t.Context(), tool.GoImpl.FuncID, json.RawMessage(`{}`))
+	if err != nil {
+		t.Fatalf("Call error: %v", err)
+	}
+	if len(out) != 1 || len(out[0].TextItem.Text) != 100 {
+		t.Fatalf("Call without cap: got %+v want the full text", out)
+	}
+}
+
 func TestRegistry_Call_TimeoutResolution(t *testing.T) {
 	const (
 		sleepDur    = 60 * time.Millisecond
@@ -277,6 +536,7 @@ func TestRegistry_Call_TimeoutResolution(t *testing.T) {
 	tests := []struct {
 		name           string
 		regTimeout     time.Duration
+		toolTimeouts   map[string]time.Duration // slug -> SetDefaultTimeout
 		callOpt        CallOption
 		wantErrIs      error
 		wantTextOutput string
@@ -303,6 +563,40 @@ func TestRegistry_Call_TimeoutResolution(t *testing.T) {
 			callOpt:    WithCallTimeout(shortTout),
 			wantErrIs:  context.DeadlineExceeded,
 		},
+		{
+			name:         "tool timeout cancels call without registry default",
+			toolTimeouts: map[string]time.Duration{"sleepy": shortTout},
+			wantErrIs:    context.DeadlineExceeded,
+		},
+		{
+			name:           "tool timeout longer than sleep completes",
+			regTimeout:     defaultTout,
+			toolTimeouts:   map[string]time.Duration{"sleepy": 10 * sleepDur},
+			wantTextOutput: "ok",
+		},
+		{
+			name:           "tool timeout 0 disables registry default",
+			regTimeout:     defaultTout,
+			toolTimeouts:   map[string]time.Duration{"sleepy": 0},
+			wantTextOutput: "ok",
+		},
+		{
+			name:         "negative tool timeout restores registry default",
+			regTimeout:   defaultTout,
+			toolTimeouts: map[string]time.Duration{"sleepy": -1},
+			wantErrIs:    context.DeadlineExceeded,
+		},
+		{
+			name:           "other tool's timeout does not apply",
+			toolTimeouts:   map[string]time.Duration{"other": shortTout},
+			wantTextOutput: "ok",
+		},
+		{
+			name:           "call timeout override beats tool timeout",
+			toolTimeouts:   map[string]time.Duration{"sleepy": shortTout},
+			callOpt:        WithCallTimeout(0),
+			wantTextOutput: "ok",
+		},
 	}
 
 	for _, tc := range tests {
@@ -324,6 +618,13 @@ func TestRegistry_Call_TimeoutResolution(t *testing.T) {
 			if err := r.RegisterTool(tool, fn); err != nil {
 				t.Fatalf("RegisterTool error: %v", err)
 			}
+			for slug, d := range tc.toolTimeouts {
+				if d < 0 {
+					// Exercise removal of an existing setting.
+					r.SetDefaultTimeout(slug, shortTout)
+				}
+				r.SetDefaultTimeout(slug, d)
+			}
 
 			opts := []CallOption(nil)
 			if tc.callOpt != nil {
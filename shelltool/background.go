@@ -0,0 +1,486 @@
+package shelltool
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/flexigpt/llmtools-go/internal/toolutil"
+	"github.com/flexigpt/llmtools-go/spec"
+)
+
+const (
+	startCommandFuncID spec.FuncID = "github.com/flexigpt/llmtools-go/shelltool/background.StartCommand"
+	pollCommandFuncID  spec.FuncID = "github.com/flexigpt/llmtools-go/shelltool/background.PollCommand"
+	stopCommandFuncID  spec.FuncID = "github.com/flexigpt/llmtools-go/shelltool/background.StopCommand"
+)
+
+// maxBackgroundProcesses bounds the handles (running or exited but not yet
+// stopped) a ShellTool keeps across all of its sessions.
+const maxBackgroundProcesses = 32
+
+// maxBackgroundRuntime bounds how long a background command may run before its
+// process group is killed.
+const maxBackgroundRuntime = time.Hour
+
+// backgroundWaitDelay bounds how long reaping waits for the output pipes to
+// close after the process exits, e.g. when a daemonized grandchild that left
+// the process group still holds them.
+const backgroundWaitDelay = 2 * time.Second
+
+var errTooManyBackgroundCommands = fmt.Errorf(
+	"too many background commands (max %d); stop some first", maxBackgroundProcesses,
+)
+
+var startCommandToolSpec = spec.Tool{
+	SchemaVersion: spec.SchemaVersion,
+	ID:            "01a13dce-417b-74e2-a67e-d9637626264d",
+	Slug:          "startcommand",
+	Version:       "v1.0.0",
+	DisplayName:   "Start background command",
+	Description:   "Start a single program (no shell) in the background, e.g. a server, and return a handle bound to a shell session. Use pollcommand to check its status and output and stopcommand to terminate it.",
+	Tags:          []string{"shell", "exec"},
+
+	ArgSchema: spec.JSONSchema(`{
+"$schema": "http://json-schema.org/draft-07/schema#",
+"type": "object",
+"properties": {
+	"command": {
+		"type": "string",
+		"description": "Program to run: a name looked up in PATH, or a path."
+	},
+	"args": {
+		"type": "array",
+		"items": { "type": "string" },
+		"description": "Arguments passed to the program as-is (no shell expansion or quoting)."
+	},
+	"sessionID": {
+		"type": "string",
+		"default": "",
+		"description": "Optional shell session whose workdir and env are used and that owns the handle. If omitted, a new session is created and returned."
+	},
+	"maxOutputBytes": {
+		"type": "integer",
+		"minimum": 0,
+		"description": "Per-stream output buffer in bytes; the last maxOutputBytes of each stream are kept. 0 uses the tool policy cap, which is also the upper bound."
+	}
+},
+"required": ["command"],
+"additionalProperties": false
+}`),
+	GoImpl: spec.GoToolImpl{FuncID: startCommandFuncID},
+
+	CreatedAt:  spec.SchemaStartTime,
+	ModifiedAt: spec.SchemaStartTime,
+}
+
+var pollCommandToolSpec = spec.Tool{
+	SchemaVersion: spec.SchemaVersion,
+	ID:            "01a13dce-41c4-722a-9299-2935c528cda9",
+	Slug:          "pollcommand",
+	Version:       "v1.0.0",
+	DisplayName:   "Poll background command",
+	Description:   "Return whether a background command started with startcommand is still running, its exit code once exited, and the output buffered so far.",
+	Tags:          []string{"shell", "exec"},
+
+	ArgSchema: spec.JSONSchema(`{
+"$schema": "http://json-schema.org/draft-07/schema#",
+"type": "object",
+"properties": {
+	"sessionID": {
+		"type": "string",
+		"description": "Session returned by startcommand alongside the handle."
+	},
+	"handleID": {
+		"type": "string",
+		"description": "Handle returned by startcommand."
+	}
+},
+"required": ["sessionID", "handleID"],
+"additionalProperties": false
+}`),
+	GoImpl: spec.GoToolImpl{FuncID: pollCommandFuncID},
+
+	CreatedAt:  spec.SchemaStartTime,
+	ModifiedAt: spec.SchemaStartTime,
+}
+
+var stopCommandToolSpec = spec.Tool{
+	SchemaVersion: spec.SchemaVersion,
+	ID:            "01a13dce-4200-712d-b587-7a0613bfcdf6",
+	Slug:          "stopcommand",
+	Version:       "v1.0.0",
+	DisplayName:   "Stop background command",
+	Description:   "Terminate a background command (and any children it spawned) if still running, return its final status and output, and release the handle.",
+	Tags:          []string{"shell", "exec"},
+
+	ArgSchema: spec.JSONSchema(`{
+"$schema": "http://json-schema.org/draft-07/schema#",
+"type": "object",
+"properties": {
+	"sessionID": {
+		"type": "string",
+		"description": "Session returned by startcommand alongside the handle."
+	},
+	"handleID": {
+		"type": "string",
+		"description": "Handle returned by startcommand."
+	}
+},
+"required": ["sessionID", "handleID"],
+"additionalProperties": false
+}`),
+	GoImpl: spec.GoToolImpl{FuncID: stopCommandFuncID},
+
+	CreatedAt:  spec.SchemaStartTime,
+	ModifiedAt: spec.SchemaStartTime,
+}
+
+type StartCommandArgs struct {
+	Command   string   `json:"command"`
+	Args      []string `json:"args,omitempty"`
+	SessionID string   `json:"sessionID,omitempty"`
+
+	// MaxOutputBytes lowers the policy's per-stream output cap for this process.
+	MaxOutputBytes int64 `json:"maxOutputBytes,omitempty"`
+}
+
+type CommandHandleArgs struct {
+	SessionID string `json:"sessionID"`
+	HandleID  string `json:"handleID"`
+}
+
+// CommandStatusOut is the state of a background command. ExitCode is set once
+// the process has exited.
+type CommandStatusOut struct {
+	HandleID  string   `json:"handleID"`
+	Command   string   `json:"command"`
+	Args      []string `json:"args,omitempty"`
+	SessionID string   `json:"sessionID"`
+	Workdir   string   `json:"workdir"`
+
+	Running    bool  `json:"running"`
+	ExitCode   *int  `json:"exitCode,omitempty"`
+	TimedOut   bool  `json:"timedOut"`
+	DurationMS int64 `json:"durationMS"`
+
+	Stdout string `json:"stdout"`
+	Stderr string `json:"stderr"`
+
+	StdoutTruncated bool `json:"stdoutTruncated"`
+	StderrTruncated bool `json:"stderrTruncated"`
+}
+
+func (st *ShellTool) StartCommandTool() spec.Tool { return toolutil.CloneTool(startCommandToolSpec) }
+
+func (st *ShellTool) PollCommandTool() spec.Tool { return toolutil.CloneTool(pollCommandToolSpec) }
+
+func (st *ShellTool) StopCommandTool() spec.Tool { return toolutil.CloneTool(stopCommandToolSpec) }
+
+// StartCommand starts Command with Args like RunCommand, but returns as soon as
+// the program is running. The process is not bound to ctx; it runs until it
+// exits, StopCommand is called, the tool is closed or it has run for an hour
+// (then it is killed and reported as timed out). Output is kept in
+// per-stream ring buffers of the last MaxOutputBytes.
+//
+// The handle belongs to SessionID (a new session if empty): it is only valid
+// together with that session, and the process is killed when the session is
+// evicted. At most 32 handles are held per tool; exited processes keep theirs
+// until stopped.
+func (st *ShellTool) StartCommand(ctx context.Context, args StartCommandArgs) (*CommandStatusOut, error) {
+	return toolutil.WithRecoveryResp(func() (*CommandStatusOut, error) {
+		return st.startCommand(ctx, args)
+	})
+}
+
+// PollCommand reports the status and buffered output of a background command.
+// Polling counts as use of the owning session for TTL eviction.
+func (st *ShellTool) PollCommand(ctx context.Context, args CommandHandleArgs) (*CommandStatusOut, error) {
+	return toolutil.WithRecoveryResp(func() (*CommandStatusOut, error) {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		sessionID, err := st.liveSessionID(args.SessionID)
+		if err != nil {
+			return nil, err
+		}
+		p, err := st.procs.get(sessionID, args.HandleID)
+		if err != nil {
+			return nil, err
+		}
+		return p.status(), nil
+	})
+}
+
+// StopCommand kills a background command's process group if it is still
+// running, waits for it to exit and releases the handle. The returned status
+// is final.
+func (st *ShellTool) StopCommand(ctx context.Context, args CommandHandleArgs) (*CommandStatusOut, error) {
+	return toolutil.WithRecoveryResp(func() (*CommandStatusOut, error) {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		sessionID, err := st.liveSessionID(args.SessionID)
+		if err != nil {
+			return nil, err
+		}
+		p, err := st.procs.remove(sessionID, args.HandleID)
+		if err != nil {
+			return nil, err
+		}
+		p.stop()
+		return p.status(), nil
+	})
+}
+
+func (st *ShellTool) startCommand(ctx context.Context, args StartCommandArgs) (*CommandStatusOut, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	pc, err := st.prepareCommand(RunCommandArgs{
+		Command:        args.Command,
+		Args:           args.Args,
+		SessionID:      args.SessionID,
+		MaxOutputBytes: args.MaxOutputBytes,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !st.procs.hasRoom() {
+		return nil, errTooManyBackgroundCommands
+	}
+	// prepareCommand validated an explicit session; without one, a fresh session
+	// has no workdir or env, so the prepared command is the same.
+	sessionID := strings.TrimSpace(args.SessionID)
+	if sessionID == "" {
+		sessionID = st.sessions.newSession().id
+	}
+
+	// The process outlives this call, so it must not be killed when ctx ends.
+	cmd := exec.CommandContext( //nolint:gosec // Exec requested command.
+		context.WithoutCancel(ctx), pc.argv[0], pc.argv[1:]...,
+	)
+	cmd.Dir = pc.workdir
+	cmd.Env = pc.env
+	cmd.WaitDelay = backgroundWaitDelay
+	configureProcessGroup(cmd)
+	p := &bgProcess{
+		command:   pc.command,
+		args:      args.Args,
+		sessionID: sessionID,
+		workdir:   pc.workdir,
+		cmd:       cmd,
+		stdout:    newCappedWriter(pc.maxOut),
+		stderr:    newCappedWriter(pc.maxOut),
+		done:      make(chan struct{}),
+	}
+	cmd.Stdout = p.stdout
+	cmd.Stderr = p.stderr
+
+	p.started = toolutil.Now()
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
+	go p.wait(maxBackgroundRuntime)
+
+	if err := st.procs.add(p); err != nil {
+		p.stop()
+		return nil, err
+	}
+	// The session may have been evicted before the handle was stored, in which
+	// case its eviction did not see (and stop) the process.
+	if _, ok := st.sessions.get(sessionID); !ok {
+		if _, err := st.procs.remove(sessionID, p.id); err == nil {
+			p.stop()
+		}
+		return nil, errors.New("session is closed")
+	}
+	return p.status(), nil
+}
+
+type bgProcess struct {
+	id        string
+	command   string
+	args      []string
+	sessionID string
+	workdir   string
+
+	cmd            *exec.Cmd
+	stdout, stderr *cappedWriter
+	started        time.Time
+
+	done     chan struct{} // closed once the process has exited
+	exitCode int           // valid after done
+	timedOut bool          // valid after done
+	ended    time.Time     // valid after done
+}
+
+// wait reaps the process, killing its process group if it is still running
+// after maxRuntime.
+func (p *bgProcess) wait(maxRuntime time.Duration) {
+	var timedOut atomic.Bool
+	timer := time.AfterFunc(maxRuntime, func() {
+		timedOut.Store(true)
+		killProcessGroup(p.cmd)
+	})
+	err := p.cmd.Wait()
+	timer.Stop()
+	p.timedOut = timedOut.Load() && err != nil
+	p.exitCode = exitCodeFromWait(err, p.timedOut)
+	p.ended = toolutil.Now()
+	close(p.done)
+}
+
+func (p *bgProcess) stop() {
+	select {
+	case <-p.done:
+		return
+	default:
+	}
+	killProcessGroup(p.cmd)
+	<-p.done
+}
+
+func (p *bgProcess) status() *CommandStatusOut {
+	out := &CommandStatusOut{
+		HandleID:  p.id,
+		Command:   p.command,
+		Args:      p.args,
+		SessionID: p.sessionID,
+		Workdir:   p.workdir,
+		Running:   true,
+
+		StdoutTruncated: p.stdout.Truncated(),
+		StderrTruncated: p.stderr.Truncated(),
+	}
+	select {
+	case <-p.done:
+		code := p.exitCode
+		out.Running = false
+		out.ExitCode = &code
+		out.TimedOut = p.timedOut
+		out.DurationMS = p.ended.Sub(p.started).Milliseconds()
+	default:
+		out.DurationMS = toolutil.Now().Sub(p.started).Milliseconds()
+	}
+	out.Stdout = safeUTF8(p.stdout.Bytes())
+	out.Stderr = safeUTF8(p.stderr.Bytes())
+	return out
+}
+
+// liveSessionID validates the sessionID of a handle lookup and marks the
+// session as used.
+func (st *ShellTool) liveSessionID(id string) (string, error) {
+	id = strings.TrimSpace(id)
+	if id == "" {
+		return "", errors.New("sessionID is required")
+	}
+	if _, ok := st.sessions.get(id); !ok {
+		return "", fmt.Errorf("unknown sessionID: %s", id)
+	}
+	return id, nil
+}
+
+// processStore holds the background processes of a ShellTool by handle id.
+// Each process belongs to a session; lookups must name the owning session.
+type processStore struct {
+	mu sync.Mutex
+	m  map[string]*bgProcess
+}
+
+func newProcessStore() *processStore {
+	return &processStore{m: map[string]*bgProcess{}}
+}
+
+func (ps *processStore) hasRoom() bool {
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+	return len(ps.m) < maxBackgroundProcesses
+}
+
+// add assigns p a fresh handle id and stores it.
+func (ps *processStore) add(p *bgProcess) error {
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+	if len(ps.m) >= maxBackgroundProcesses {
+		return errTooManyBackgroundCommands
+	}
+	for {
+		if id := newProcessHandleID(); ps.m[id] == nil {
+			p.id = id
+			ps.m[id] = p
+			return nil
+		}
+	}
+}
+
+func (ps *processStore) get(sessionID, id string) (*bgProcess, error) {
+	id = strings.TrimSpace(id)
+	if id == "" {
+		return nil, errors.New("handleID is required")
+	}
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+	p := ps.m[id]
+	if p == nil || p.sessionID != sessionID {
+		return nil, fmt.Errorf("unknown handleID: %s", id)
+	}
+	return p, nil
+}
+
+func (ps *processStore) remove(sessionID, id string) (*bgProcess, error) {
+	id = strings.TrimSpace(id)
+	if id == "" {
+		return nil, errors.New("handleID is required")
+	}
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+	p := ps.m[id]
+	if p == nil || p.sessionID != sessionID {
+		return nil, fmt.Errorf("unknown handleID: %s", id)
+	}
+	delete(ps.m, id)
+	return p, nil
+}
+
+// stopSession releases the handles of sessionID and kills their processes
+// asynchronously, so it is safe to call with the session store locked.
+func (ps *processStore) stopSession(sessionID string) {
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+	for id, p := range ps.m {
+		if p.sessionID == sessionID {
+			delete(ps.m, id)
+			go p.stop()
+		}
+	}
+}
+
+// stopAll kills every background process and releases all handles.
+func (ps *processStore) stopAll() {
+	ps.mu.Lock()
+	procs := ps.m
+	ps.m = map[string]*bgProcess{}
+	ps.mu.Unlock()
+
+	var wg sync.WaitGroup
+	for _, p := range procs {
+		wg.Go(p.stop)
+	}
+	wg.Wait()
+}
+
+func newProcessHandleID() string {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return fmt.Sprintf("proc_%d", toolutil.Now().UTC().UnixNano())
+	}
+	return "proc_" + hex.EncodeToString(b[:])
+}
@@ -0,0 +1,293 @@
+package shelltool
+
+import (
+	"errors"
+	"os/exec"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/flexigpt/llmtools-go/internal/toolutil"
+)
+
+func TestBackgroundCommand_StartPollStop(t *testing.T) {
+	if runtime.GOOS == toolutil.GOOSWindows {
+		t.Skip("unix-specific")
+	}
+	mustLookPath(t, "sleep")
+	st := newTestShellTool(t)
+	t.Cleanup(func() { _ = st.Close() })
+
+	started, err := st.StartCommand(t.Context(), StartCommandArgs{Command: "sleep", Args: []string{"30"}})
+	if err != nil {
+		t.Fatalf("StartCommand: %v", err)
+	}
+	handle := CommandHandleArgs{SessionID: started.SessionID, HandleID: started.HandleID}
+	if started.HandleID == "" || started.SessionID == "" || !started.Running || started.ExitCode != nil {
+		t.Fatalf("unexpected start status: %+v", started)
+	}
+
+	polled, err := st.PollCommand(t.Context(), handle)
+	if err != nil {
+		t.Fatalf("PollCommand: %v", err)
+	}
+	if !polled.Running || polled.ExitCode != nil {
+		t.Fatalf("want running, got %+v", polled)
+	}
+
+	begin := time.Now()
+	stopped, err := st.StopCommand(t.Context(), handle)
+	if err != nil {
+		t.Fatalf("StopCommand: %v", err)
+	}
+	if stopped.Running || stopped.ExitCode == nil || *stopped.ExitCode == 0 {
+		t.Fatalf("want killed process, got %+v", stopped)
+	}
+	if time.Since(begin) > 10*time.Second {
+		t.Fatalf("stop took too long: %v", time.Since(begin))
+	}
+
+	if _, err := st.PollCommand(t.Context(), handle); err == nil ||
+		!strings.Contains(err.Error(), "unknown handleID") {
+		t.Fatalf("poll after stop: err=%v", err)
+	}
+}
+
+func TestBackgroundCommand_OutputAndExit(t *testing.T) {
+	if runtime.GOOS == toolutil.GOOSWindows {
+		t.Skip("unix-specific")
+	}
+	mustLookPath(t, "sh")
+	st := newTestShellTool(t)
+	t.Cleanup(func() { _ = st.Close() })
+
+	started, err := st.StartCommand(t.Context(), StartCommandArgs{
+		Command: "sh",
+		Args:    []string{"-c", `printf out; printf err >&2; exit 3`},
+	})
+	if err != nil {
+		t.Fatalf("StartCommand: %v", err)
+	}
+	handle := CommandHandleArgs{SessionID: started.SessionID, HandleID: started.HandleID}
+
+	deadline := time.Now().Add(10 * time.Second)
+	var out *CommandStatusOut
+	for {
+		out, err = st.PollCommand(t.Context(), handle)
+		if err != nil {
+			t.Fatalf("PollCommand: %v", err)
+		}
+		if !out.Running || time.Now().After(deadline) {
+			break
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	if out.Running || out.ExitCode == nil || *out.ExitCode != 3 || out.Stdout != "out" || out.Stderr != "err" {
+		t.Fatalf("unexpected status: %+v", out)
+	}
+
+	// Stopping an exited command just releases its handle.
+	if _, err := st.StopCommand(t.Context(), handle); err != nil {
+		t.Fatalf("StopCommand: %v", err)
+	}
+}
+
+func TestBackgroundCommand_MaxRuntime(t *testing.T) {
+	if runtime.GOOS == toolutil.GOOSWindows {
+		t.Skip("unix-specific")
+	}
+	sleepPath := mustLookPath(t, "sleep")
+
+	cmd := exec.Command(sleepPath, "30")
+	configureProcessGroup(cmd)
+	p := &bgProcess{
+		command: "sleep",
+		cmd:     cmd,
+		stdout:  newCappedWriter(MinOutputBytes),
+		stderr:  newCappedWriter(MinOutputBytes),
+		done:    make(chan struct{}),
+	}
+	p.started = time.Now()
+	if err := cmd.Start(); err != nil {
+		t.Fatalf("start: %v", err)
+	}
+	go p.wait(50 * time.Millisecond)
+
+	select {
+	case <-p.done:
+	case <-time.After(10 * time.Second):
+		p.stop()
+		t.Fatal("process not killed after max runtime")
+	}
+	out := p.status()
+	if out.Running || !out.TimedOut || out.ExitCode == nil || *out.ExitCode != 124 {
+		t.Fatalf("want timed out process, got %+v", out)
+	}
+}
+
+func TestBackgroundCommand_StopWithEscapedGrandchild(t *testing.T) {
+	if runtime.GOOS == toolutil.GOOSWindows {
+		t.Skip("unix-specific")
+	}
+	mustLookPath(t, "sh")
+	mustLookPath(t, "setsid")
+	st := newTestShellTool(t, WithShellCommandPolicy(ShellCommandPolicy{AllowDangerous: true}))
+	t.Cleanup(func() { _ = st.Close() })
+
+	// The grandchild leaves the process group but inherits the output pipes.
+	started, err := st.StartCommand(t.Context(), StartCommandArgs{
+		Command: "sh",
+		Args:    []string{"-c", "setsid sleep 15 & echo ready; sleep 30"},
+	})
+	if err != nil {
+		t.Fatalf("StartCommand: %v", err)
+	}
+	handle := CommandHandleArgs{SessionID: started.SessionID, HandleID: started.HandleID}
+	deadline := time.Now().Add(10 * time.Second)
+	for {
+		out, err := st.PollCommand(t.Context(), handle)
+		if err != nil {
+			t.Fatalf("PollCommand: %v", err)
+		}
+		if out.Stdout != "" {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("command did not start: %+v", out)
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+
+	begin := time.Now()
+	stopped, err := st.StopCommand(t.Context(), handle)
+	if err != nil {
+		t.Fatalf("StopCommand: %v", err)
+	}
+	if stopped.Running || time.Since(begin) > 10*time.Second {
+		t.Fatalf("stop blocked on grandchild: %+v after %v", stopped, time.Since(begin))
+	}
+}
+
+func TestBackgroundCommand_SessionScope(t *testing.T) {
+	if runtime.GOOS == toolutil.GOOSWindows {
+		t.Skip("unix-specific")
+	}
+	mustLookPath(t, "sleep")
+	st := newTestShellTool(t)
+	t.Cleanup(func() { _ = st.Close() })
+
+	sess := st.sessions.newSession()
+	started, err := st.StartCommand(t.Context(), StartCommandArgs{
+		Command:   "sleep",
+		Args:      []string{"30"},
+		SessionID: sess.id,
+	})
+	if err != nil {
+		t.Fatalf("StartCommand: %v", err)
+	}
+	handle := CommandHandleArgs{SessionID: started.SessionID, HandleID: started.HandleID}
+	if started.SessionID != sess.id {
+		t.Fatalf("sessionID=%q, want %q", started.SessionID, sess.id)
+	}
+
+	other := st.sessions.newSession()
+	foreign := CommandHandleArgs{SessionID: other.id, HandleID: started.HandleID}
+	if _, err := st.PollCommand(t.Context(), foreign); err == nil ||
+		!strings.Contains(err.Error(), "unknown handleID") {
+		t.Fatalf("poll from other session: err=%v", err)
+	}
+
+	st.procs.mu.Lock()
+	p := st.procs.m[started.HandleID]
+	st.procs.mu.Unlock()
+	if p == nil {
+		t.Fatal("process not stored")
+	}
+	st.sessions.delete(sess.id)
+	select {
+	case <-p.done:
+	case <-time.After(10 * time.Second):
+		t.Fatal("process not stopped when its session was removed")
+	}
+	if _, err := st.PollCommand(t.Context(), handle); err == nil ||
+		!strings.Contains(err.Error(), "unknown sessionID") {
+		t.Fatalf("poll after session removal: err=%v", err)
+	}
+}
+
+func TestBackgroundCommand_Errors(t *testing.T) {
+	st := newTestShellTool(t)
+	t.Cleanup(func() { _ = st.Close() })
+	sess := st.sessions.newSession()
+
+	tests := []struct {
+		name          string
+		run           func() error
+		wantErrIs     error
+		wantErrSubstr string
+	}{
+		{
+			name: "missing command",
+			run: func() error {
+				_, err := st.StartCommand(t.Context(), StartCommandArgs{})
+				return err
+			},
+			wantErrSubstr: "command is required",
+		},
+		{
+			name: "blocked command",
+			run: func() error {
+				_, err := st.StartCommand(t.Context(), StartCommandArgs{Command: "shutdown"})
+				return err
+			},
+			wantErrIs: ErrCommandNotAllowed,
+		},
+		{
+			name: "empty session",
+			run: func() error {
+				_, err := st.PollCommand(t.Context(), CommandHandleArgs{HandleID: "proc_nope"})
+				return err
+			},
+			wantErrSubstr: "sessionID is required",
+		},
+		{
+			name: "unknown session",
+			run: func() error {
+				_, err := st.StopCommand(t.Context(), CommandHandleArgs{SessionID: "sess_nope", HandleID: "proc_nope"})
+				return err
+			},
+			wantErrSubstr: "unknown sessionID",
+		},
+		{
+			name: "empty handle",
+			run: func() error {
+				_, err := st.PollCommand(t.Context(), CommandHandleArgs{SessionID: sess.id})
+				return err
+			},
+			wantErrSubstr: "handleID is required",
+		},
+		{
+			name: "unknown handle",
+			run: func() error {
+				_, err := st.StopCommand(t.Context(), CommandHandleArgs{SessionID: sess.id, HandleID: "proc_nope"})
+				return err
+			},
+			wantErrSubstr: "unknown handleID",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.run()
+			if err == nil {
+				t.Fatal("expected error")
+			}
+			if tt.wantErrIs != nil && !errors.Is(err, tt.wantErrIs) {
+				t.Fatalf("err=%v, want %v", err, tt.wantErrIs)
+			}
+			if tt.wantErrSubstr != "" && !strings.Contains(err.Error(), tt.wantErrSubstr) {
+				t.Fatalf("err=%v, want substring %q", err, tt.wantErrSubstr)
+			}
+		})
+	}
+}
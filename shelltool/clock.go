@@ -0,0 +1,13 @@
+package shelltool
+
+import "github.com/flexigpt/llmtools-go/internal/toolutil"
+
+// Clock supplies the current time for time-dependent behavior in this package
+// (session TTL eviction, background command timestamps). Command timeouts use
+// the monotonic system clock.
+type Clock = toolutil.Clock
+
+// SetClock replaces the clock shared by all tool packages and returns a func
+// that restores the previous one. A nil c restores the system clock. Intended
+// for tests; it affects all callers in the process.
+func SetClock(c Clock) (restore func()) { return toolutil.SetClock(c) }
@@ -0,0 +1,57 @@
+package shelltool
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+type fakeClock struct {
+	mu sync.Mutex
+	t  time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.t
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	c.t = c.t.Add(d)
+	c.mu.Unlock()
+}
+
+func TestSetClock_SessionReapingIsDeterministic(t *testing.T) {
+	fc := &fakeClock{t: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+	t.Cleanup(SetClock(fc))
+
+	ss := newSessionStore()
+	ss.setTTL(time.Minute)
+
+	kept := ss.newSession()
+	reaped := ss.newSession()
+
+	fc.Advance(45 * time.Second)
+	if _, ok := ss.get(kept.id); !ok {
+		t.Fatalf("expected session within TTL to be present")
+	}
+
+	// kept was touched at +45s; reaped was last used at +0s.
+	fc.Advance(30 * time.Second)
+	if _, ok := ss.get(reaped.id); ok {
+		t.Fatalf("expected session idle past TTL to be reaped")
+	}
+	if _, ok := ss.get(kept.id); !ok {
+		t.Fatalf("expected recently used session to survive")
+	}
+	if got := ss.sizeForTest(); got != 1 {
+		t.Fatalf("store size=%d want 1", got)
+	}
+
+	fc.Advance(time.Minute + time.Second)
+	if _, ok := ss.get(kept.id); ok {
+		t.Fatalf("expected session to be reaped after idling past TTL")
+	}
+}
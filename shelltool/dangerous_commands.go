@@ -2,6 +2,7 @@ package shelltool
 
 import (
 	"errors"
+	"fmt"
 	"path/filepath"
 	"runtime"
 	"strings"
@@ -10,6 +11,10 @@ import (
 	"github.com/flexigpt/llmtools-go/internal/toolutil"
 )
 
+// ErrCommandNotAllowed is returned (wrapped) when RunCommand refuses to execute
+// a program because of the blocklist or the allowlist.
+var ErrCommandNotAllowed = errors.New("command not allowed")
+
 var hardBlockedCommands = func() map[string]struct{} {
 	// Non-overridable baseline. These are blocked regardless of AllowDangerous.
 	hard := []string{
@@ -114,6 +119,121 @@ func rejectDangerousCommand(
 	})
 }
 
+// rejectDangerousExec applies the command blocklist to a direct (no shell) exec of
+// command with args. Wrappers like "env" are unwrapped. If the program is itself a
+// shell, its inline script (after -c, -Command or /c) is checked like a shell command.
+// Every argument after the script flag is checked on its own as well as joined, so
+// options placed between the flag and the script (e.g. "bash -c -e 'rm x'") do not
+// hide it.
+func rejectDangerousExec(
+	command string, args []string,
+	blockedCommands map[string]struct{},
+	enableHeuristicChecks bool,
+) error {
+	name, rest := unwrapCommand(append([]string{command}, args...))
+	if name == "" {
+		return nil
+	}
+	if strings.HasPrefix(name, "mkfs.") {
+		if _, ok := blockedCommands["mkfs"]; ok {
+			return errors.New("blocked command: " + name)
+		}
+	}
+	if isBlockedName(name, blockedCommands) {
+		return errors.New("blocked command: " + name)
+	}
+
+	shellName := ShellName(strings.TrimSuffix(name, ".exe"))
+	switch shellName {
+	case ShellNameBash, ShellNameZsh, ShellNameSh, ShellNameDash, ShellNameKsh, ShellNameFish,
+		ShellNamePwsh, ShellNamePowershell, ShellNameCmd:
+	default:
+		return nil
+	}
+	for i, a := range rest {
+		if !isInlineScriptFlag(shellName, a) {
+			continue
+		}
+		scripts := append([]string{strings.Join(rest[i+1:], " ")}, rest[i+1:]...)
+		for _, script := range scripts {
+			if err := rejectDangerousCommand(script, "", shellName, blockedCommands, enableHeuristicChecks); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return nil
+}
+
+// isInlineScriptFlag reports whether arg makes shell run an inline script. For
+// sh-family shells that is any single-dash option group containing 'c' (e.g.
+// "-c", "-ec", "-lc"); PowerShell accepts any prefix of -Command.
+func isInlineScriptFlag(shell ShellName, arg string) bool {
+	switch shell {
+	case ShellNamePwsh, ShellNamePowershell:
+		a := strings.ToLower(arg)
+		return len(a) >= 2 && strings.HasPrefix("-command", a)
+	case ShellNameCmd:
+		return strings.EqualFold(arg, "/c") || strings.EqualFold(arg, "/k")
+	default:
+		if strings.EqualFold(arg, "--command") {
+			return true
+		}
+		if len(arg) < 2 || arg[0] != '-' || arg[1] == '-' {
+			return false
+		}
+		for _, r := range arg[1:] {
+			if (r < 'a' || r > 'z') && (r < 'A' || r > 'Z') {
+				return false
+			}
+		}
+		return strings.ContainsRune(arg[1:], 'c')
+	}
+}
+
+// checkResolvedExecAllowed complements rejectDangerousExec once the program
+// has been resolved to a path: the blocklist is also matched on the resolved
+// (and symlink-resolved) binary, so an alias cannot reach a blocked program,
+// and the allowlist, if non-nil, is enforced. The allowlist is matched on the program name
+// as invoked and as resolved (not on symlink targets, so allowlisting "python3"
+// does not require knowing which versioned binary it points to), and on the
+// program an env-style wrapper runs.
+func checkResolvedExecAllowed(
+	command, resolved string, args []string,
+	blockedCommands, allowedCommands map[string]struct{},
+) error {
+	invoked, resolvedName := canonicalCmd(command), canonicalCmd(resolved)
+	blockCheck := []string{resolvedName}
+	if target, err := filepath.EvalSymlinks(resolved); err == nil {
+		blockCheck = append(blockCheck, canonicalCmd(target))
+	}
+	for _, n := range blockCheck {
+		if isBlockedName(n, blockedCommands) {
+			return fmt.Errorf("%w: blocked command: %s", ErrCommandNotAllowed, n)
+		}
+	}
+
+	if allowedCommands == nil {
+		return nil
+	}
+	allowCheck := []string{invoked, resolvedName}
+	if wrapped, _ := unwrapCommand(append([]string{command}, args...)); wrapped != "" {
+		allowCheck = append(allowCheck, wrapped)
+	}
+	for _, n := range allowCheck {
+		if !isAllowedName(n, allowedCommands) {
+			return fmt.Errorf("%w: %s is not in the allowlist", ErrCommandNotAllowed, n)
+		}
+	}
+	return nil
+}
+
+// isAllowedName reports whether name is in the allowlist, with the same
+// matching rules as isBlockedName.
+func isAllowedName(name string, allowed map[string]struct{}) bool {
+	return isBlockedName(name, allowed)
+}
+
 func isBlockedName(name string, blocked map[string]struct{}) bool {
 	if blocked == nil {
 		return false
@@ -141,10 +261,10 @@ func normalizeBlockedCommand(s string) (string, error) {
 		return "", nil
 	}
 	if strings.ContainsRune(x, '\x00') {
-		return "", errors.New("blocked command contains NUL byte")
+		return "", errors.New("command name contains NUL byte")
 	}
 	if strings.IndexFunc(x, unicode.IsSpace) >= 0 {
-		return "", errors.New("blocked command must be a single command name (no whitespace)")
+		return "", errors.New("must be a single command name (no whitespace)")
 	}
 	// Allow passing "/bin/rm" etc; we only store the basename.
 	return strings.ToLower(baseAnySep(x)), nil
@@ -0,0 +1,283 @@
+package shelltool
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/flexigpt/llmtools-go/internal/toolutil"
+	"github.com/flexigpt/llmtools-go/spec"
+)
+
+const runCommandFuncID spec.FuncID = "github.com/flexigpt/llmtools-go/shelltool/runcommand.RunCommand"
+
+var runCommandToolSpec = spec.Tool{
+	SchemaVersion: spec.SchemaVersion,
+	ID:            "01a13d8c-c19d-7ad6-abdd-cfb12e9ebf4d",
+	Slug:          "runcommand",
+	Version:       "v1.0.0",
+	DisplayName:   "Run command",
+	Description:   "Run a single program directly (no shell) with an argument list. Uses the workdir/env of an existing shell session if sessionID is given. Returns exit code, stdout, stderr and whether it timed out.",
+	Tags:          []string{"shell", "exec"},
+
+	ArgSchema: spec.JSONSchema(`{
+"$schema": "http://json-schema.org/draft-07/schema#",
+"type": "object",
+"properties": {
+	"command": {
+		"type": "string",
+		"description": "Program to run: a name looked up in PATH, or a path."
+	},
+	"args": {
+		"type": "array",
+		"items": { "type": "string" },
+		"description": "Arguments passed to the program as-is (no shell expansion or quoting)."
+	},
+	"sessionID": {
+		"type": "string",
+		"default": "",
+		"description": "Optional shell session whose workdir and env are used. No session is created if omitted."
+	},
+	"timeoutMS": {
+		"type": "integer",
+		"minimum": 0,
+		"description": "Timeout in milliseconds. 0 uses the tool policy timeout; values are capped at 10 minutes."
+	},
+	"maxOutputBytes": {
+		"type": "integer",
+		"minimum": 0,
+		"description": "Per-stream output cap in bytes; the last maxOutputBytes of each stream are kept. 0 uses the tool policy cap, which is also the upper bound."
+	},
+	"stdin": {
+		"type": "string",
+		"description": "Text written to the program's stdin, which is then closed. Mutually exclusive with stdinBase64."
+	},
+	"stdinBase64": {
+		"type": "string",
+		"description": "Base64-encoded bytes written to the program's stdin, which is then closed. Mutually exclusive with stdin."
+	}
+},
+"required": ["command"],
+"additionalProperties": false
+}`),
+	GoImpl: spec.GoToolImpl{FuncID: runCommandFuncID},
+
+	CreatedAt:  spec.SchemaStartTime,
+	ModifiedAt: spec.SchemaStartTime,
+}
+
+type RunCommandArgs struct {
+	Command   string   `json:"command"`
+	Args      []string `json:"args,omitempty"`
+	SessionID string   `json:"sessionID,omitempty"`
+	TimeoutMS int      `json:"timeoutMS,omitempty"`
+
+	// MaxOutputBytes lowers the policy's per-stream output cap for this call.
+	MaxOutputBytes int64 `json:"maxOutputBytes,omitempty"`
+
+	// Optional stdin; if neither is set the program reads from the null device.
+	Stdin       string `json:"stdin,omitempty"`
+	StdinBase64 string `json:"stdinBase64,omitempty"`
+}
+
+type RunCommandOut struct {
+	Command string   `json:"command"`
+	Args    []string `json:"args,omitempty"`
+	Workdir string   `json:"workdir"`
+
+	ExitCode   int   `json:"exitCode"`
+	TimedOut   bool  `json:"timedOut"`
+	DurationMS int64 `json:"durationMS"`
+
+	Stdout string `json:"stdout"`
+	Stderr string `json:"stderr"`
+
+	StdoutTruncated bool `json:"stdoutTruncated"`
+	StderrTruncated bool `json:"stderrTruncated"`
+}
+
+// RunCommandTool returns the spec for RunCommand.
+func (st *ShellTool) RunCommandTool() spec.Tool { return toolutil.CloneTool(runCommandToolSpec) }
+
+// RunCommand executes Command with Args via os/exec, never through a shell
+// (unless Command itself is one). Workdir and env come from SessionID's session
+// if given (the session is not modified), else the current process. Each
+// output stream keeps its last MaxOutputBytes (the tool policy cap, optionally
+// lowered per call) and reports truncation. The program runs in its own process
+// group; on timeout or ctx cancellation the whole group is killed, so children
+// it spawned are not left running. A timeout still returns the output captured
+// so far, with TimedOut set. The blocklist applies to the program name and, for shells, to
+// their inline script; with WithShellAllowedCommands, only allowlisted programs
+// run. Refusals wrap ErrCommandNotAllowed.
+func (st *ShellTool) RunCommand(ctx context.Context, args RunCommandArgs) (*RunCommandOut, error) {
+	return toolutil.WithRecoveryResp(func() (*RunCommandOut, error) {
+		return st.runCommand(ctx, args)
+	})
+}
+
+func (st *ShellTool) runCommand(ctx context.Context, args RunCommandArgs) (*RunCommandOut, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	pc, err := st.prepareCommand(args)
+	if err != nil {
+		return nil, err
+	}
+	res, err := execCaptured(ctx, pc.argv, pc.workdir, pc.env, pc.stdin, pc.timeout, pc.maxOut)
+	if err != nil {
+		return nil, err
+	}
+	if res.killedByCtx && !res.timedOut {
+		// Canceled by the caller rather than by our timeout.
+		return nil, ctx.Err()
+	}
+
+	return &RunCommandOut{
+		Command: pc.command,
+		Args:    args.Args,
+		Workdir: pc.workdir,
+
+		ExitCode:   res.exitCode,
+		TimedOut:   res.timedOut,
+		DurationMS: res.duration.Milliseconds(),
+
+		Stdout: safeUTF8(res.stdout.Bytes()),
+		Stderr: safeUTF8(res.stderr.Bytes()),
+
+		StdoutTruncated: res.stdout.Truncated(),
+		StderrTruncated: res.stderr.Truncated(),
+	}, nil
+}
+
+// preparedCommand is a validated RunCommandArgs, ready to execute.
+type preparedCommand struct {
+	command string
+	argv    []string // argv[0] is the resolved program path
+	workdir string
+	env     []string
+	stdin   io.Reader
+	timeout time.Duration
+	maxOut  int64
+}
+
+// prepareCommand validates args against the tool policy, block/allow lists and
+// the session (if any), and resolves the program, workdir and env.
+func (st *ShellTool) prepareCommand(args RunCommandArgs) (*preparedCommand, error) {
+	st.mu.RLock()
+	policy := st.policy
+	roots := append([]string(nil), st.allowedWorkdirRoots...)
+	blocked := st.blockedCommands
+	allowed := st.allowedCommands
+	st.mu.RUnlock()
+
+	command := strings.TrimSpace(args.Command)
+	if command == "" {
+		return nil, errors.New("command is required")
+	}
+	argLen := len(command)
+	for _, a := range args.Args {
+		argLen += len(a) + 1
+		if strings.ContainsRune(a, '\x00') {
+			return nil, errors.New("argument contains NUL byte")
+		}
+	}
+	if strings.ContainsRune(command, '\x00') {
+		return nil, errors.New("command contains NUL byte")
+	}
+	if maxLen := effectiveMaxCommandLength(policy); argLen > maxLen {
+		return nil, fmt.Errorf("command too long (%d bytes; max %d)", argLen, maxLen)
+	}
+	if args.TimeoutMS < 0 {
+		return nil, errors.New("timeoutMS must be >= 0")
+	}
+	if args.MaxOutputBytes < 0 {
+		return nil, errors.New("maxOutputBytes must be >= 0")
+	}
+	stdin, err := runCommandStdin(args)
+	if err != nil {
+		return nil, err
+	}
+	if err := rejectDangerousExec(command, args.Args, blocked, !policy.AllowDangerous); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrCommandNotAllowed, err)
+	}
+
+	var sess *shellSession
+	if id := strings.TrimSpace(args.SessionID); id != "" {
+		var ok bool
+		if sess, ok = st.sessions.get(id); !ok {
+			return nil, fmt.Errorf("unknown sessionID: %s", id)
+		}
+	}
+	workdir, err := effectiveWorkdir("", sess, roots)
+	if err != nil {
+		return nil, err
+	}
+	env, err := effectiveEnv(sess, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	timeout := effectiveTimeout(policy)
+	if args.TimeoutMS > 0 {
+		timeout = min(time.Duration(args.TimeoutMS)*time.Millisecond, HardMaxTimeout)
+	}
+	maxOut := effectiveMaxOutputBytes(policy)
+	if args.MaxOutputBytes > 0 {
+		maxOut = min(maxOut, max(args.MaxOutputBytes, MinOutputBytes))
+	}
+
+	// A bare name is looked up in PATH; a path is used as-is (relative paths are
+	// resolved against workdir by os/exec).
+	path, resolved := command, command
+	if !strings.ContainsAny(command, `/\`) {
+		if path, err = exec.LookPath(command); err != nil {
+			return nil, err
+		}
+		resolved = path
+	} else if !filepath.IsAbs(command) {
+		resolved = filepath.Join(workdir, command)
+	}
+	if err := checkResolvedExecAllowed(command, resolved, args.Args, blocked, allowed); err != nil {
+		return nil, err
+	}
+	return &preparedCommand{
+		command: command,
+		argv:    append([]string{path}, args.Args...),
+		workdir: workdir,
+		env:     env,
+		stdin:   stdin,
+		timeout: timeout,
+		maxOut:  maxOut,
+	}, nil
+}
+
+// runCommandStdin returns the reader for the requested stdin, or nil if none.
+func runCommandStdin(args RunCommandArgs) (io.Reader, error) {
+	switch {
+	case args.Stdin != "" && args.StdinBase64 != "":
+		return nil, errors.New("stdin and stdinBase64 are mutually exclusive")
+	case args.Stdin != "":
+		if len(args.Stdin) > toolutil.MaxStdinBytes {
+			return nil, fmt.Errorf("stdin too large (%d bytes; max %d)", len(args.Stdin), toolutil.MaxStdinBytes)
+		}
+		return strings.NewReader(args.Stdin), nil
+	case args.StdinBase64 != "":
+		if base64.StdEncoding.DecodedLen(len(args.StdinBase64)) > toolutil.MaxStdinBytes {
+			return nil, fmt.Errorf("stdinBase64 too large (max %d decoded bytes)", toolutil.MaxStdinBytes)
+		}
+		data, err := base64.StdEncoding.DecodeString(args.StdinBase64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid stdinBase64: %w", err)
+		}
+		return bytes.NewReader(data), nil
+	}
+	return nil, nil
+}
@@ -0,0 +1,507 @@
+package shelltool
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/flexigpt/llmtools-go/internal/toolutil"
+)
+
+func TestRunCommand(t *testing.T) {
+	if runtime.GOOS == toolutil.GOOSWindows {
+		t.Skip("unix-specific")
+	}
+	mustLookPath(t, "sh")
+	st := newTestShellTool(t)
+
+	td := t.TempDir()
+	sess, err := st.Run(t.Context(), ShellCommandArgs{
+		Shell:    ShellNameSh,
+		Workdir:  td,
+		Env:      map[string]string{"RUNCMD_FOO": "from-session"},
+		Commands: []string{"true"},
+	})
+	if err != nil {
+		t.Fatalf("create session: %v", err)
+	}
+
+	tests := []struct {
+		name          string
+		ctx           func(t *testing.T) context.Context
+		args          RunCommandArgs
+		check         func(t *testing.T, out *RunCommandOut)
+		wantErrIs     error
+		wantErrSubstr string
+	}{
+		{
+			name: "separate streams and exit code",
+			args: RunCommandArgs{Command: "sh", Args: []string{"-c", `printf out; printf err >&2; exit 3`}},
+			check: func(t *testing.T, out *RunCommandOut) {
+				t.Helper()
+				if out.Stdout != "out" || out.Stderr != "err" || out.ExitCode != 3 || out.TimedOut {
+					t.Fatalf("unexpected result: %+v", out)
+				}
+			},
+		},
+		{
+			name: "args are not shell-expanded",
+			args: RunCommandArgs{Command: "printf", Args: []string{"%s|", "$HOME", "a b"}},
+			check: func(t *testing.T, out *RunCommandOut) {
+				t.Helper()
+				if out.Stdout != "$HOME|a b|" || out.ExitCode != 0 {
+					t.Fatalf("unexpected result: %+v", out)
+				}
+			},
+		},
+		{
+			name: "session workdir and env are used",
+			args: RunCommandArgs{
+				Command:   "sh",
+				Args:      []string{"-c", `pwd; printf '%s' "$RUNCMD_FOO"`},
+				SessionID: sess.SessionID,
+			},
+			check: func(t *testing.T, out *RunCommandOut) {
+				t.Helper()
+				lines := strings.SplitN(out.Stdout, "\n", 2)
+				if len(lines) != 2 || lines[1] != "from-session" {
+					t.Fatalf("unexpected stdout %q", out.Stdout)
+				}
+				mustSameDir(t, td, lines[0])
+				mustSameDir(t, td, out.Workdir)
+			},
+		},
+		{
+			name: "timeout sets timedOut and 124",
+			args: RunCommandArgs{Command: "sleep", Args: []string{"5"}, TimeoutMS: 100},
+			check: func(t *testing.T, out *RunCommandOut) {
+				t.Helper()
+				if !out.TimedOut || out.ExitCode != 124 {
+					t.Fatalf("expected timeout, got %+v", out)
+				}
+			},
+		},
+		{name: "missing command", args: RunCommandArgs{}, wantErrSubstr: "command is required"},
+		{
+			name:          "unknown program",
+			args:          RunCommandArgs{Command: "definitely-not-a-real-program-xyz"},
+			wantErrSubstr: "not found",
+		},
+		{
+			name:          "unknown session",
+			args:          RunCommandArgs{Command: "true", SessionID: "nope"},
+			wantErrSubstr: "unknown sessionID",
+		},
+		{name: "negative timeout", args: RunCommandArgs{Command: "true", TimeoutMS: -1}, wantErrSubstr: "timeoutMS"},
+		{name: "NUL in args", args: RunCommandArgs{Command: "echo", Args: []string{"a\x00b"}}, wantErrSubstr: "NUL"},
+		{
+			name:          "blocked program",
+			args:          RunCommandArgs{Command: "/bin/rm", Args: []string{"x"}},
+			wantErrIs:     ErrCommandNotAllowed,
+			wantErrSubstr: "blocked command: rm",
+		},
+		{
+			name:          "blocked via env wrapper",
+			args:          RunCommandArgs{Command: "env", Args: []string{"A=1", "curl", "x"}},
+			wantErrIs:     ErrCommandNotAllowed,
+			wantErrSubstr: "blocked command: curl",
+		},
+		{
+			name:          "blocked inside shell script",
+			args:          RunCommandArgs{Command: "sh", Args: []string{"-c", "echo hi && rm -rf x"}},
+			wantErrIs:     ErrCommandNotAllowed,
+			wantErrSubstr: "blocked command: rm",
+		},
+		{
+			name:          "blocked inside shell script with grouped flags",
+			args:          RunCommandArgs{Command: "bash", Args: []string{"-ec", "rm x"}},
+			wantErrIs:     ErrCommandNotAllowed,
+			wantErrSubstr: "blocked command: rm",
+		},
+		{
+			name: "canceled context",
+			ctx: func(t *testing.T) context.Context {
+				t.Helper()
+				ctx, cancel := context.WithCancel(t.Context())
+				cancel()
+				return ctx
+			},
+			args:      RunCommandArgs{Command: "true"},
+			wantErrIs: context.Canceled,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := t.Context()
+			if tt.ctx != nil {
+				ctx = tt.ctx(t)
+			}
+			out, err := st.RunCommand(ctx, tt.args)
+			if tt.wantErrIs != nil || tt.wantErrSubstr != "" {
+				if err == nil {
+					t.Fatalf("expected error, got %+v", out)
+				}
+				if tt.wantErrIs != nil && !errors.Is(err, tt.wantErrIs) {
+					t.Fatalf("expected errors.Is(%v), got %v", tt.wantErrIs, err)
+				}
+				if tt.wantErrSubstr != "" && !strings.Contains(err.Error(), tt.wantErrSubstr) {
+					t.Fatalf("error %q does not contain %q", err, tt.wantErrSubstr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			tt.check(t, out)
+		})
+	}
+}
+
+func TestRunCommand_OutputAndTimeLimits(t *testing.T) {
+	if runtime.GOOS == toolutil.GOOSWindows {
+		t.Skip("unix-specific")
+	}
+	mustLookPath(t, "sh")
+	mustLookPath(t, "yes")
+	st := newTestShellTool(t)
+
+	t.Run("output is capped per call and keeps the tail", func(t *testing.T) {
+		out, err := st.RunCommand(t.Context(), RunCommandArgs{
+			Command:        "sh",
+			Args:           []string{"-c", `head -c 100000 /dev/zero | tr '\0' x; echo END; echo errline >&2`},
+			MaxOutputBytes: 4096,
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !out.StdoutTruncated || len(out.Stdout) != 4096 || !strings.HasSuffix(out.Stdout, "xxEND\n") {
+			t.Fatalf("stdout len=%d truncated=%v", len(out.Stdout), out.StdoutTruncated)
+		}
+		if out.StderrTruncated || out.Stderr != "errline\n" {
+			t.Fatalf("stderr=%q truncated=%v", out.Stderr, out.StderrTruncated)
+		}
+	})
+
+	t.Run("cap below minimum is raised", func(t *testing.T) {
+		out, err := st.RunCommand(t.Context(), RunCommandArgs{
+			Command:        "sh",
+			Args:           []string{"-c", `head -c 5000 /dev/zero | tr '\0' x`},
+			MaxOutputBytes: 1,
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if int64(len(out.Stdout)) != MinOutputBytes || !out.StdoutTruncated {
+			t.Fatalf("stdout len=%d truncated=%v", len(out.Stdout), out.StdoutTruncated)
+		}
+	})
+
+	t.Run("flooding process is killed on timeout with partial output", func(t *testing.T) {
+		out, err := st.RunCommand(t.Context(), RunCommandArgs{
+			Command:        "yes",
+			Args:           []string{"flood"},
+			TimeoutMS:      200,
+			MaxOutputBytes: 8192,
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !out.TimedOut {
+			t.Fatalf("expected timeout, got exit=%d", out.ExitCode)
+		}
+		if !out.StdoutTruncated || len(out.Stdout) != 8192 || !strings.Contains(out.Stdout, "flood\nflood\n") {
+			t.Fatalf("stdout len=%d truncated=%v", len(out.Stdout), out.StdoutTruncated)
+		}
+	})
+
+	t.Run("sleeping process times out with partial output", func(t *testing.T) {
+		out, err := st.RunCommand(t.Context(), RunCommandArgs{
+			Command:   "sh",
+			Args:      []string{"-c", "echo partial; sleep 5; echo never"},
+			TimeoutMS: 200,
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !out.TimedOut || out.Stdout != "partial\n" || out.StdoutTruncated {
+			t.Fatalf("unexpected result: %+v", out)
+		}
+		if out.DurationMS >= 5000 {
+			t.Fatalf("process not killed promptly: %dms", out.DurationMS)
+		}
+	})
+
+	t.Run("negative cap errors", func(t *testing.T) {
+		_, err := st.RunCommand(t.Context(), RunCommandArgs{Command: "yes", MaxOutputBytes: -1})
+		if err == nil || !strings.Contains(err.Error(), "maxOutputBytes") {
+			t.Fatalf("expected maxOutputBytes error, got %v", err)
+		}
+	})
+}
+
+func TestRunCommand_Stdin(t *testing.T) {
+	if runtime.GOOS == toolutil.GOOSWindows {
+		t.Skip("unix-specific")
+	}
+	mustLookPath(t, "cat")
+	mustLookPath(t, "wc")
+	mustLookPath(t, "true")
+	st := newTestShellTool(t)
+
+	big := strings.Repeat("0123456789abcdef", 128*1024) // 2 MiB, far beyond a pipe buffer
+
+	tests := []struct {
+		name          string
+		args          RunCommandArgs
+		wantStdout    string
+		wantErrSubstr string
+	}{
+		{
+			name:       "text piped into cat",
+			args:       RunCommandArgs{Command: "cat", Stdin: "line one\nline two\n"},
+			wantStdout: "line one\nline two\n",
+		},
+		{
+			name:       "base64 piped into cat",
+			args:       RunCommandArgs{Command: "cat", StdinBase64: "aGVsbG8gYnl0ZXM="},
+			wantStdout: "hello bytes",
+		},
+		{
+			name:       "large input does not deadlock",
+			args:       RunCommandArgs{Command: "wc", Args: []string{"-c"}, Stdin: big},
+			wantStdout: "2097152",
+		},
+		{
+			name: "program ignoring stdin",
+			args: RunCommandArgs{Command: "true", Stdin: big},
+		},
+		{
+			name:       "no stdin reads empty input",
+			args:       RunCommandArgs{Command: "cat"},
+			wantStdout: "",
+		},
+		{
+			name:          "both stdin forms",
+			args:          RunCommandArgs{Command: "cat", Stdin: "a", StdinBase64: "YQ=="},
+			wantErrSubstr: "mutually exclusive",
+		},
+		{
+			name:          "invalid base64",
+			args:          RunCommandArgs{Command: "cat", StdinBase64: "not base64!"},
+			wantErrSubstr: "invalid stdinBase64",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.args.TimeoutMS = 10_000
+			out, err := st.RunCommand(t.Context(), tt.args)
+			if tt.wantErrSubstr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErrSubstr) {
+					t.Fatalf("expected error containing %q, got %v", tt.wantErrSubstr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if out.TimedOut || out.ExitCode != 0 {
+				t.Fatalf("unexpected result: %+v", out)
+			}
+			if got := strings.TrimSpace(out.Stdout); got != strings.TrimSpace(tt.wantStdout) {
+				t.Fatalf("stdout=%q want %q", got, tt.wantStdout)
+			}
+		})
+	}
+}
+
+func TestRunCommand_AllowedCommands(t *testing.T) {
+	if runtime.GOOS == toolutil.GOOSWindows {
+		t.Skip("unix-specific")
+	}
+	mustLookPath(t, "printf")
+	mustLookPath(t, "true")
+	rmPath := mustLookPath(t, "rm")
+
+	// A differently named alias of a blocked binary.
+	alias := filepath.Join(t.TempDir(), "innocent")
+	if err := os.Symlink(rmPath, alias); err != nil {
+		t.Fatalf("symlink: %v", err)
+	}
+
+	tests := []struct {
+		name          string
+		allowed       []string
+		args          RunCommandArgs
+		wantStdout    string
+		wantErrSubstr string
+	}{
+		{
+			name:       "allowlisted program runs",
+			allowed:    []string{"printf", "/usr/bin/env"},
+			args:       RunCommandArgs{Command: "printf", Args: []string{"ok"}},
+			wantStdout: "ok",
+		},
+		{
+			name:       "allowlisted program via wrapper runs",
+			allowed:    []string{"printf", "env"},
+			args:       RunCommandArgs{Command: "env", Args: []string{"A=1", "printf", "ok"}},
+			wantStdout: "ok",
+		},
+		{
+			name:          "program not in allowlist",
+			allowed:       []string{"printf"},
+			args:          RunCommandArgs{Command: "true"},
+			wantErrSubstr: "true is not in the allowlist",
+		},
+		{
+			name:          "wrapped program not in allowlist",
+			allowed:       []string{"printf", "env"},
+			args:          RunCommandArgs{Command: "env", Args: []string{"true"}},
+			wantErrSubstr: "true is not in the allowlist",
+		},
+		{
+			name:          "denylist wins over allowlist",
+			allowed:       []string{"rm"},
+			args:          RunCommandArgs{Command: "rm", Args: []string{"-f", "nothing"}},
+			wantErrSubstr: "blocked command: rm",
+		},
+		{
+			name:          "symlink alias of blocked program",
+			args:          RunCommandArgs{Command: alias, Args: []string{"-f", "nothing"}},
+			wantErrSubstr: "blocked command: rm",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var opts []ShellToolOption
+			if tt.allowed != nil {
+				opts = append(opts, WithShellAllowedCommands(tt.allowed))
+			}
+			st := newTestShellTool(t, opts...)
+			out, err := st.RunCommand(t.Context(), tt.args)
+			if tt.wantErrSubstr != "" {
+				if !errors.Is(err, ErrCommandNotAllowed) {
+					t.Fatalf("expected ErrCommandNotAllowed, got %v (out=%+v)", err, out)
+				}
+				if !strings.Contains(err.Error(), tt.wantErrSubstr) {
+					t.Fatalf("error %q does not contain %q", err, tt.wantErrSubstr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if out.Stdout != tt.wantStdout || out.ExitCode != 0 {
+				t.Fatalf("unexpected result: %+v", out)
+			}
+		})
+	}
+
+	t.Run("invalid allowlist entry", func(t *testing.T) {
+		if _, err := NewShellTool(WithShellAllowedCommands([]string{"git status"})); err == nil {
+			t.Fatal("expected error for entry with whitespace")
+		}
+	})
+}
+
+func TestRunCommand_KillsChildrenOnTimeoutAndCancel(t *testing.T) {
+	if runtime.GOOS == toolutil.GOOSWindows {
+		t.Skip("unix-specific")
+	}
+	mustLookPath(t, "sh")
+	// Backgrounding is a heuristic block; allow it to spawn a grandchild.
+	st := newTestShellTool(t, WithShellCommandPolicy(ShellCommandPolicy{AllowDangerous: true}))
+
+	for _, mode := range []string{"timeout", "cancel"} {
+		t.Run(mode, func(t *testing.T) {
+			pidFile := filepath.Join(t.TempDir(), "child.pid")
+			ctx, cancel := context.WithCancel(t.Context())
+			defer cancel()
+			args := RunCommandArgs{
+				Command: "sh",
+				Args:    []string{"-c", `sleep 30 & echo $! > "$0"; wait`, pidFile},
+			}
+			if mode == "timeout" {
+				args.TimeoutMS = 300
+			} else {
+				go func() {
+					time.Sleep(300 * time.Millisecond)
+					cancel()
+				}()
+			}
+
+			out, err := st.RunCommand(ctx, args)
+			switch mode {
+			case "timeout":
+				if err != nil || !out.TimedOut {
+					t.Fatalf("expected timed out result, got %+v, %v", out, err)
+				}
+			default:
+				if !errors.Is(err, context.Canceled) {
+					t.Fatalf("expected context.Canceled, got %+v, %v", out, err)
+				}
+			}
+
+			data, err := os.ReadFile(pidFile)
+			if err != nil {
+				t.Fatalf("read pid file: %v", err)
+			}
+			pid, err := strconv.Atoi(strings.TrimSpace(string(data)))
+			if err != nil {
+				t.Fatalf("parse pid %q: %v", data, err)
+			}
+			// The grandchild was in the killed process group; allow a moment for reaping by init.
+			deadline := time.Now().Add(2 * time.Second)
+			for syscall.Kill(pid, 0) == nil {
+				if time.Now().After(deadline) {
+					_ = syscall.Kill(pid, syscall.SIGKILL)
+					t.Fatalf("child process %d still running", pid)
+				}
+				time.Sleep(20 * time.Millisecond)
+			}
+		})
+	}
+}
+
+func TestRejectDangerousExec(t *testing.T) {
+	tests := []struct {
+		name    string
+		command string
+		args    []string
+		wantErr bool
+	}{
+		{name: "plain allowed", command: "git", args: []string{"status"}},
+		{name: "blocked by path", command: "/usr/bin/sudo", args: []string{"ls"}, wantErr: true},
+		{name: "mkfs variant", command: "mkfs.ext4", args: []string{"/dev/x"}, wantErr: true},
+		{name: "env wrapper", command: "env", args: []string{"-i", "wget", "u"}, wantErr: true},
+		{name: "shell script checked", command: "bash", args: []string{"-c", "ls | nc host 1"}, wantErr: true},
+		{name: "shell script allowed", command: "bash", args: []string{"-c", "ls -la"}},
+		{name: "shell without script", command: "sh", args: []string{"script.sh"}},
+		{name: "grouped -ec flag", command: "bash", args: []string{"-ec", "rm x"}, wantErr: true},
+		{name: "grouped -lc flag", command: "bash", args: []string{"-lc", "rm x"}, wantErr: true},
+		{name: "grouped -xc flag", command: "sh", args: []string{"-xc", "rm x"}, wantErr: true},
+		{name: "-c after other flags", command: "bash", args: []string{"-e", "-o", "pipefail", "-c", "rm x"}, wantErr: true},
+		{name: "options between -c and script", command: "bash", args: []string{"-c", "-e", "rm x"}, wantErr: true},
+		{name: "grouped flag allowed script", command: "bash", args: []string{"-ec", "ls -la"}},
+		{name: "flag group without c", command: "bash", args: []string{"-ex", "script.sh"}},
+		{name: "pwsh command prefix", command: "pwsh", args: []string{"-Comm", "Remove-Item x"}, wantErr: true},
+		{name: "blocked word as argument is fine", command: "echo", args: []string{"rm"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := rejectDangerousExec(tt.command, tt.args, hardBlockedCommands, true)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err=%v wantErr=%v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,218 @@
+package shelltool
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"strings"
+
+	"github.com/flexigpt/llmtools-go/internal/toolutil"
+	"github.com/flexigpt/llmtools-go/spec"
+)
+
+const (
+	setSessionEnvFuncID   spec.FuncID = "github.com/flexigpt/llmtools-go/shelltool/sessionenv.SetSessionEnv"
+	unsetSessionEnvFuncID spec.FuncID = "github.com/flexigpt/llmtools-go/shelltool/sessionenv.UnsetSessionEnv"
+	getSessionEnvFuncID   spec.FuncID = "github.com/flexigpt/llmtools-go/shelltool/sessionenv.GetSessionEnv"
+)
+
+var setSessionEnvToolSpec = spec.Tool{
+	SchemaVersion: spec.SchemaVersion,
+	ID:            "01a13d92-88b2-78e7-925d-7da36f7f7500",
+	Slug:          "setsessionenv",
+	Version:       "v1.0.0",
+	DisplayName:   "Set session env",
+	Description:   "Set environment variables persisted in an existing shell session. Later shell and runcommand calls in that session see them.",
+	Tags:          []string{"shell"},
+
+	ArgSchema: spec.JSONSchema(`{
+"$schema": "http://json-schema.org/draft-07/schema#",
+"type": "object",
+"properties": {
+	"sessionID": {
+		"type": "string",
+		"description": "Session returned by a previous shell call."
+	},
+	"vars": {
+		"type": "object",
+		"additionalProperties": { "type": "string" },
+		"description": "Variables to set. Names must not be empty or contain '=' or NUL."
+	}
+},
+"required": ["sessionID", "vars"],
+"additionalProperties": false
+}`),
+	GoImpl: spec.GoToolImpl{FuncID: setSessionEnvFuncID},
+
+	CreatedAt:  spec.SchemaStartTime,
+	ModifiedAt: spec.SchemaStartTime,
+}
+
+var unsetSessionEnvToolSpec = spec.Tool{
+	SchemaVersion: spec.SchemaVersion,
+	ID:            "01a13d92-88f9-73d3-8209-f4f79e63f035",
+	Slug:          "unsetsessionenv",
+	Version:       "v1.0.0",
+	DisplayName:   "Unset session env",
+	Description:   "Remove environment variables previously set in a shell session. Process-level variables are not affected.",
+	Tags:          []string{"shell"},
+
+	ArgSchema: spec.JSONSchema(`{
+"$schema": "http://json-schema.org/draft-07/schema#",
+"type": "object",
+"properties": {
+	"sessionID": {
+		"type": "string",
+		"description": "Session returned by a previous shell call."
+	},
+	"keys": {
+		"type": "array",
+		"items": { "type": "string" },
+		"description": "Variable names to remove."
+	}
+},
+"required": ["sessionID", "keys"],
+"additionalProperties": false
+}`),
+	GoImpl: spec.GoToolImpl{FuncID: unsetSessionEnvFuncID},
+
+	CreatedAt:  spec.SchemaStartTime,
+	ModifiedAt: spec.SchemaStartTime,
+}
+
+var getSessionEnvToolSpec = spec.Tool{
+	SchemaVersion: spec.SchemaVersion,
+	ID:            "01a13d92-893f-72c2-81f2-330910a74323",
+	Slug:          "getsessionenv",
+	Version:       "v1.0.0",
+	DisplayName:   "Get session env",
+	Description:   "List the environment variables set in a shell session (on top of the process environment).",
+	Tags:          []string{"shell"},
+
+	ArgSchema: spec.JSONSchema(`{
+"$schema": "http://json-schema.org/draft-07/schema#",
+"type": "object",
+"properties": {
+	"sessionID": {
+		"type": "string",
+		"description": "Session returned by a previous shell call."
+	}
+},
+"required": ["sessionID"],
+"additionalProperties": false
+}`),
+	GoImpl: spec.GoToolImpl{FuncID: getSessionEnvFuncID},
+
+	CreatedAt:  spec.SchemaStartTime,
+	ModifiedAt: spec.SchemaStartTime,
+}
+
+type SetSessionEnvArgs struct {
+	SessionID string            `json:"sessionID"`
+	Vars      map[string]string `json:"vars"`
+}
+
+type UnsetSessionEnvArgs struct {
+	SessionID string   `json:"sessionID"`
+	Keys      []string `json:"keys"`
+}
+
+type GetSessionEnvArgs struct {
+	SessionID string `json:"sessionID"`
+}
+
+// SessionEnvOut is the session env after the operation. It holds only the
+// session's own variables, not the inherited process environment.
+type SessionEnvOut struct {
+	SessionID string            `json:"sessionID"`
+	Env       map[string]string `json:"env"`
+	Removed   int               `json:"removed,omitempty"` // UnsetSessionEnv only
+}
+
+// SetSessionEnvTool returns the spec for SetSessionEnv.
+func (st *ShellTool) SetSessionEnvTool() spec.Tool { return toolutil.CloneTool(setSessionEnvToolSpec) }
+
+// UnsetSessionEnvTool returns the spec for UnsetSessionEnv.
+func (st *ShellTool) UnsetSessionEnvTool() spec.Tool {
+	return toolutil.CloneTool(unsetSessionEnvToolSpec)
+}
+
+// GetSessionEnvTool returns the spec for GetSessionEnv.
+func (st *ShellTool) GetSessionEnvTool() spec.Tool { return toolutil.CloneTool(getSessionEnvToolSpec) }
+
+// SetSessionEnv merges Vars into the session env.
+func (st *ShellTool) SetSessionEnv(ctx context.Context, args SetSessionEnvArgs) (*SessionEnvOut, error) {
+	return toolutil.WithRecoveryResp(func() (*SessionEnvOut, error) {
+		if len(args.Vars) == 0 {
+			return nil, errors.New("vars is required")
+		}
+		if err := validateEnvMap(args.Vars); err != nil {
+			return nil, err
+		}
+		return st.withSessionEnv(ctx, args.SessionID, func(s *shellSession) int {
+			s.setEnvLocked(args.Vars)
+			return 0
+		})
+	})
+}
+
+// UnsetSessionEnv removes Keys from the session env. Keys that are not set
+// are ignored.
+func (st *ShellTool) UnsetSessionEnv(ctx context.Context, args UnsetSessionEnvArgs) (*SessionEnvOut, error) {
+	return toolutil.WithRecoveryResp(func() (*SessionEnvOut, error) {
+		if len(args.Keys) == 0 {
+			return nil, errors.New("keys is required")
+		}
+		for _, k := range args.Keys {
+			if err := validateEnvKV(k, ""); err != nil {
+				return nil, fmt.Errorf("env %q: %w", k, err)
+			}
+		}
+		return st.withSessionEnv(ctx, args.SessionID, func(s *shellSession) int {
+			return s.unsetEnvLocked(args.Keys)
+		})
+	})
+}
+
+// GetSessionEnv returns the session env.
+func (st *ShellTool) GetSessionEnv(ctx context.Context, args GetSessionEnvArgs) (*SessionEnvOut, error) {
+	return toolutil.WithRecoveryResp(func() (*SessionEnvOut, error) {
+		return st.withSessionEnv(ctx, args.SessionID, nil)
+	})
+}
+
+// withSessionEnv applies mutate (if non-nil) to the session under its lock
+// and returns a snapshot of the resulting env.
+func (st *ShellTool) withSessionEnv(
+	ctx context.Context,
+	sessionID string,
+	mutate func(*shellSession) int,
+) (*SessionEnvOut, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	id := strings.TrimSpace(sessionID)
+	if id == "" {
+		return nil, errors.New("sessionID is required")
+	}
+	sess, ok := st.sessions.get(id)
+	if !ok {
+		return nil, fmt.Errorf("unknown sessionID: %s", id)
+	}
+
+	sess.mu.Lock()
+	defer sess.mu.Unlock()
+	if sess.closed {
+		return nil, errors.New("session is closed")
+	}
+	removed := 0
+	if mutate != nil {
+		removed = mutate(sess)
+	}
+	env := maps.Clone(sess.env)
+	if env == nil {
+		env = map[string]string{}
+	}
+	return &SessionEnvOut{SessionID: id, Env: env, Removed: removed}, nil
+}
@@ -0,0 +1,192 @@
+package shelltool
+
+import (
+	"context"
+	"errors"
+	"maps"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/flexigpt/llmtools-go/internal/toolutil"
+)
+
+func TestSessionEnv(t *testing.T) {
+	canceled := func(t *testing.T) context.Context {
+		t.Helper()
+		ctx, cancel := context.WithCancel(t.Context())
+		cancel()
+		return ctx
+	}
+
+	tests := []struct {
+		name          string
+		ctx           func(t *testing.T) context.Context
+		initial       map[string]string
+		op            func(ctx context.Context, st *ShellTool, id string) (*SessionEnvOut, error)
+		wantEnv       map[string]string
+		wantRemoved   int
+		wantErrIs     error
+		wantErrSubstr string
+	}{
+		{
+			name:    "set merges",
+			initial: map[string]string{"A": "1"},
+			op: func(ctx context.Context, st *ShellTool, id string) (*SessionEnvOut, error) {
+				return st.SetSessionEnv(ctx, SetSessionEnvArgs{SessionID: id, Vars: map[string]string{"B": "2", "A": "x"}})
+			},
+			wantEnv: map[string]string{"A": "x", "B": "2"},
+		},
+		{
+			name:    "unset removes present keys only",
+			initial: map[string]string{"A": "1", "B": "2"},
+			op: func(ctx context.Context, st *ShellTool, id string) (*SessionEnvOut, error) {
+				return st.UnsetSessionEnv(ctx, UnsetSessionEnvArgs{SessionID: id, Keys: []string{"A", "MISSING"}})
+			},
+			wantEnv:     map[string]string{"B": "2"},
+			wantRemoved: 1,
+		},
+		{
+			name:    "get returns session env",
+			initial: map[string]string{"A": "1"},
+			op: func(ctx context.Context, st *ShellTool, id string) (*SessionEnvOut, error) {
+				return st.GetSessionEnv(ctx, GetSessionEnvArgs{SessionID: id})
+			},
+			wantEnv: map[string]string{"A": "1"},
+		},
+		{
+			name: "get on empty session",
+			op: func(ctx context.Context, st *ShellTool, id string) (*SessionEnvOut, error) {
+				return st.GetSessionEnv(ctx, GetSessionEnvArgs{SessionID: id})
+			},
+			wantEnv: map[string]string{},
+		},
+		{
+			name: "set rejects '=' in key",
+			op: func(ctx context.Context, st *ShellTool, id string) (*SessionEnvOut, error) {
+				return st.SetSessionEnv(ctx, SetSessionEnvArgs{SessionID: id, Vars: map[string]string{"A=B": "1"}})
+			},
+			wantErrSubstr: "contains '='",
+		},
+		{
+			name: "set rejects NUL in value",
+			op: func(ctx context.Context, st *ShellTool, id string) (*SessionEnvOut, error) {
+				return st.SetSessionEnv(ctx, SetSessionEnvArgs{SessionID: id, Vars: map[string]string{"A": "x\x00"}})
+			},
+			wantErrSubstr: "NUL",
+		},
+		{
+			name: "set requires vars",
+			op: func(ctx context.Context, st *ShellTool, id string) (*SessionEnvOut, error) {
+				return st.SetSessionEnv(ctx, SetSessionEnvArgs{SessionID: id})
+			},
+			wantErrSubstr: "vars is required",
+		},
+		{
+			name: "unset rejects NUL in key",
+			op: func(ctx context.Context, st *ShellTool, id string) (*SessionEnvOut, error) {
+				return st.UnsetSessionEnv(ctx, UnsetSessionEnvArgs{SessionID: id, Keys: []string{"A\x00"}})
+			},
+			wantErrSubstr: "NUL",
+		},
+		{
+			name: "unknown session",
+			op: func(ctx context.Context, st *ShellTool, _ string) (*SessionEnvOut, error) {
+				return st.GetSessionEnv(ctx, GetSessionEnvArgs{SessionID: "nope"})
+			},
+			wantErrSubstr: "unknown sessionID",
+		},
+		{
+			name:    "canceled context",
+			ctx:     canceled,
+			initial: map[string]string{"A": "1"},
+			op: func(ctx context.Context, st *ShellTool, id string) (*SessionEnvOut, error) {
+				return st.SetSessionEnv(ctx, SetSessionEnvArgs{SessionID: id, Vars: map[string]string{"A": "2"}})
+			},
+			wantErrIs: context.Canceled,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			st := newTestShellTool(t)
+			sess := st.sessions.newSession()
+			maps.Copy(sess.env, tt.initial)
+			ctx := t.Context()
+			if tt.ctx != nil {
+				ctx = tt.ctx(t)
+			}
+
+			out, err := tt.op(ctx, st, sess.id)
+			if tt.wantErrIs != nil || tt.wantErrSubstr != "" {
+				if err == nil {
+					t.Fatalf("expected error, got %+v", out)
+				}
+				if tt.wantErrIs != nil && !errors.Is(err, tt.wantErrIs) {
+					t.Fatalf("expected errors.Is(%v), got %v", tt.wantErrIs, err)
+				}
+				if tt.wantErrSubstr != "" && !strings.Contains(err.Error(), tt.wantErrSubstr) {
+					t.Fatalf("error %q does not contain %q", err, tt.wantErrSubstr)
+				}
+				if tt.initial != nil && !maps.Equal(sess.env, tt.initial) {
+					t.Fatalf("session env changed on error: %v", sess.env)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !maps.Equal(out.Env, tt.wantEnv) || !maps.Equal(sess.env, tt.wantEnv) {
+				t.Fatalf("env out=%v session=%v want %v", out.Env, sess.env, tt.wantEnv)
+			}
+			if out.Removed != tt.wantRemoved {
+				t.Fatalf("Removed=%d want %d", out.Removed, tt.wantRemoved)
+			}
+		})
+	}
+}
+
+func TestSessionEnv_VisibleToRunCommand(t *testing.T) {
+	if runtime.GOOS == toolutil.GOOSWindows {
+		t.Skip("unix-specific")
+	}
+	mustLookPath(t, "printenv")
+	st := newTestShellTool(t)
+	sess := st.sessions.newSession()
+
+	if _, err := st.SetSessionEnv(t.Context(), SetSessionEnvArgs{
+		SessionID: sess.id,
+		Vars:      map[string]string{"LLMTOOLS_SESSION_VAR": "hello session"},
+	}); err != nil {
+		t.Fatalf("SetSessionEnv: %v", err)
+	}
+	out, err := st.RunCommand(t.Context(), RunCommandArgs{
+		Command:   "printenv",
+		Args:      []string{"LLMTOOLS_SESSION_VAR"},
+		SessionID: sess.id,
+	})
+	if err != nil {
+		t.Fatalf("RunCommand: %v", err)
+	}
+	if got := strings.TrimSpace(out.Stdout); got != "hello session" || out.ExitCode != 0 {
+		t.Fatalf("stdout=%q exit=%d", got, out.ExitCode)
+	}
+
+	if _, err := st.UnsetSessionEnv(t.Context(), UnsetSessionEnvArgs{
+		SessionID: sess.id,
+		Keys:      []string{"LLMTOOLS_SESSION_VAR"},
+	}); err != nil {
+		t.Fatalf("UnsetSessionEnv: %v", err)
+	}
+	out, err = st.RunCommand(t.Context(), RunCommandArgs{
+		Command:   "printenv",
+		Args:      []string{"LLMTOOLS_SESSION_VAR"},
+		SessionID: sess.id,
+	})
+	if err != nil {
+		t.Fatalf("RunCommand after unset: %v", err)
+	}
+	if out.Stdout != "" || out.ExitCode == 0 {
+		t.Fatalf("variable still visible after unset: stdout=%q exit=%d", out.Stdout, out.ExitCode)
+	}
+}
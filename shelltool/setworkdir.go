@@ -0,0 +1,127 @@
+package shelltool
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/flexigpt/llmtools-go/internal/fileutil"
+	"github.com/flexigpt/llmtools-go/internal/toolutil"
+	"github.com/flexigpt/llmtools-go/spec"
+)
+
+const setWorkdirFuncID spec.FuncID = "github.com/flexigpt/llmtools-go/shelltool/setworkdir.SetWorkdir"
+
+var setWorkdirToolSpec = spec.Tool{
+	SchemaVersion: spec.SchemaVersion,
+	ID:            "01a13d90-d25f-7da5-bfd4-271131f45f54",
+	Slug:          "setworkdir",
+	Version:       "v1.0.0",
+	DisplayName:   "Set session workdir",
+	Description:   "Change the working directory of an existing shell session (like cd). Later shell and runcommand calls in that session run there.",
+	Tags:          []string{"shell"},
+
+	ArgSchema: spec.JSONSchema(`{
+"$schema": "http://json-schema.org/draft-07/schema#",
+"type": "object",
+"properties": {
+	"sessionID": {
+		"type": "string",
+		"description": "Session returned by a previous shell call."
+	},
+	"path": {
+		"type": "string",
+		"description": "New working directory. Relative paths are resolved against the session's current workdir."
+	}
+},
+"required": ["sessionID", "path"],
+"additionalProperties": false
+}`),
+	GoImpl: spec.GoToolImpl{FuncID: setWorkdirFuncID},
+
+	CreatedAt:  spec.SchemaStartTime,
+	ModifiedAt: spec.SchemaStartTime,
+}
+
+type SetWorkdirArgs struct {
+	SessionID string `json:"sessionID"`
+	Path      string `json:"path"`
+}
+
+type SetWorkdirOut struct {
+	SessionID string `json:"sessionID"`
+	Workdir   string `json:"workdir"` // resolved absolute path
+}
+
+// SetWorkdirTool returns the spec for SetWorkdir.
+func (st *ShellTool) SetWorkdirTool() spec.Tool { return toolutil.CloneTool(setWorkdirToolSpec) }
+
+// SetWorkdir changes the workdir persisted in a session. Path must be an
+// existing directory whose parent components are not symlinks, and must be
+// within the allowed workdir roots if any are configured.
+func (st *ShellTool) SetWorkdir(ctx context.Context, args SetWorkdirArgs) (*SetWorkdirOut, error) {
+	return toolutil.WithRecoveryResp(func() (*SetWorkdirOut, error) {
+		return st.setWorkdir(ctx, args)
+	})
+}
+
+func (st *ShellTool) setWorkdir(ctx context.Context, args SetWorkdirArgs) (*SetWorkdirOut, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	id := strings.TrimSpace(args.SessionID)
+	if id == "" {
+		return nil, errors.New("sessionID is required")
+	}
+	path := strings.TrimSpace(args.Path)
+	if path == "" {
+		return nil, errors.New("path is required")
+	}
+	if strings.ContainsRune(path, '\x00') {
+		return nil, errors.New("path contains NUL byte")
+	}
+
+	st.mu.RLock()
+	roots := append([]string(nil), st.allowedWorkdirRoots...)
+	st.mu.RUnlock()
+
+	sess, ok := st.sessions.get(id)
+	if !ok {
+		return nil, fmt.Errorf("unknown sessionID: %s", id)
+	}
+	if !filepath.IsAbs(path) {
+		cur, err := effectiveWorkdir("", sess, roots)
+		if err != nil {
+			return nil, err
+		}
+		path = filepath.Join(cur, path)
+	}
+	path = filepath.Clean(path)
+
+	if err := fileutil.VerifyDirNoSymlink(filepath.Dir(path)); err != nil {
+		return nil, err
+	}
+	if err := ensureDirExists(path); err != nil {
+		return nil, err
+	}
+	workdir, err := canonicalWorkdir(path)
+	if err != nil {
+		return nil, err
+	}
+	if err := ensureWorkdirAllowed(workdir, roots); err != nil {
+		return nil, err
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	sess.mu.Lock()
+	defer sess.mu.Unlock()
+	if sess.closed {
+		return nil, errors.New("session is closed")
+	}
+	sess.workdir = workdir
+	return &SetWorkdirOut{SessionID: id, Workdir: workdir}, nil
+}
@@ -0,0 +1,136 @@
+package shelltool
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/flexigpt/llmtools-go/internal/toolutil"
+)
+
+func TestSetWorkdir(t *testing.T) {
+	base := t.TempDir()
+	sub := filepath.Join(base, "sub")
+	if err := os.Mkdir(sub, 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	file := filepath.Join(base, "file.txt")
+	if err := os.WriteFile(file, []byte("x"), 0o600); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+
+	tests := []struct {
+		name          string
+		ctx           func(t *testing.T) context.Context
+		startWorkdir  string
+		path          string
+		sessionID     string // overrides the created session
+		want          string
+		wantErrIs     error
+		wantErrSubstr string
+	}{
+		{name: "absolute dir", path: sub, want: sub},
+		{name: "relative to session workdir", startWorkdir: base, path: "sub", want: sub},
+		{name: "parent via dot-dot", startWorkdir: sub, path: "..", want: base},
+		{name: "file errors", path: file, wantErrSubstr: "not a directory"},
+		{name: "missing dir errors", path: filepath.Join(base, "missing"), wantErrSubstr: "no such dir"},
+		{name: "unknown session", sessionID: "nope", path: sub, wantErrSubstr: "unknown sessionID"},
+		{name: "empty path", path: "  ", wantErrSubstr: "path is required"},
+		{
+			name: "canceled context",
+			ctx: func(t *testing.T) context.Context {
+				t.Helper()
+				ctx, cancel := context.WithCancel(t.Context())
+				cancel()
+				return ctx
+			},
+			path:      sub,
+			wantErrIs: context.Canceled,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			st := newTestShellTool(t)
+			sess := st.sessions.newSession()
+			sess.workdir = tt.startWorkdir
+			id := sess.id
+			if tt.sessionID != "" {
+				id = tt.sessionID
+			}
+			ctx := t.Context()
+			if tt.ctx != nil {
+				ctx = tt.ctx(t)
+			}
+
+			out, err := st.SetWorkdir(ctx, SetWorkdirArgs{SessionID: id, Path: tt.path})
+			if tt.wantErrIs != nil || tt.wantErrSubstr != "" {
+				if err == nil {
+					t.Fatalf("expected error, got %+v", out)
+				}
+				if tt.wantErrIs != nil && !errors.Is(err, tt.wantErrIs) {
+					t.Fatalf("expected errors.Is(%v), got %v", tt.wantErrIs, err)
+				}
+				if tt.wantErrSubstr != "" && !strings.Contains(err.Error(), tt.wantErrSubstr) {
+					t.Fatalf("error %q does not contain %q", err, tt.wantErrSubstr)
+				}
+				if sess.workdir != tt.startWorkdir {
+					t.Fatalf("session workdir changed on error: %q", sess.workdir)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !filepath.IsAbs(out.Workdir) {
+				t.Fatalf("workdir not absolute: %q", out.Workdir)
+			}
+			mustSameDir(t, tt.want, out.Workdir)
+			if sess.workdir != out.Workdir {
+				t.Fatalf("session workdir=%q want %q", sess.workdir, out.Workdir)
+			}
+		})
+	}
+}
+
+func TestSetWorkdir_UnixBehavior(t *testing.T) {
+	if runtime.GOOS == toolutil.GOOSWindows {
+		t.Skip("unix-specific")
+	}
+	base := t.TempDir()
+	realDir := filepath.Join(base, "real")
+	if err := os.MkdirAll(filepath.Join(realDir, "inner"), 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	link := filepath.Join(base, "link")
+	if err := os.Symlink(realDir, link); err != nil {
+		t.Fatalf("symlink: %v", err)
+	}
+
+	t.Run("symlinked parent is refused", func(t *testing.T) {
+		st := newTestShellTool(t)
+		sess := st.sessions.newSession()
+		_, err := st.SetWorkdir(t.Context(), SetWorkdirArgs{SessionID: sess.id, Path: filepath.Join(link, "inner")})
+		if err == nil || !strings.Contains(err.Error(), "symlink") {
+			t.Fatalf("expected symlink error, got %v", err)
+		}
+	})
+
+	t.Run("run command uses new workdir", func(t *testing.T) {
+		mustLookPath(t, "pwd")
+		st := newTestShellTool(t)
+		sess := st.sessions.newSession()
+		if _, err := st.SetWorkdir(t.Context(), SetWorkdirArgs{SessionID: sess.id, Path: realDir}); err != nil {
+			t.Fatalf("SetWorkdir: %v", err)
+		}
+		out, err := st.RunCommand(t.Context(), RunCommandArgs{Command: "pwd", SessionID: sess.id})
+		if err != nil {
+			t.Fatalf("RunCommand: %v", err)
+		}
+		mustSameDir(t, realDir, strings.TrimSpace(out.Stdout))
+	})
+}
@@ -7,6 +7,7 @@ import (
 	"encoding/hex"
 	"errors"
 	"fmt"
+	"io"
 	"maps"
 	"math"
 	"os"
@@ -169,7 +170,10 @@ type ShellTool struct {
 	policy              ShellCommandPolicy
 	allowedWorkdirRoots []string            // optional; if empty, allow any
 	blockedCommands     map[string]struct{} // instance-owned blocklist (includes non-overridable hard defaults)
+	allowedCommands     map[string]struct{} // optional RunCommand allowlist; nil => any non-blocked program
 	sessions            *sessionStore
+	procs               *processStore // background commands (StartCommand)
+	reapInterval        time.Duration // optional background session reaping; 0 => reap on access only
 }
 
 type ShellToolOption func(*ShellTool) error
@@ -208,6 +212,27 @@ func WithShellBlockedCommands(cmds []string) ShellToolOption {
 	}
 }
 
+// WithShellAllowedCommands restricts RunCommand to the given programs (matched
+// on the program's base name, e.g. "git", "go"). The blocklist still applies
+// on top. It does not apply to the shell tool, whose commands are scripts;
+// hosts that need a strict allowlist should register RunCommand only.
+func WithShellAllowedCommands(cmds []string) ShellToolOption {
+	return func(st *ShellTool) error {
+		allowed := map[string]struct{}{}
+		for _, c := range cmds {
+			n, err := normalizeBlockedCommand(c)
+			if err != nil {
+				return fmt.Errorf("allowed command %q: %w", c, err)
+			}
+			if n != "" {
+				allowed[n] = struct{}{}
+			}
+		}
+		st.allowedCommands = allowed
+		return nil
+	}
+}
+
 // WithShellAllowedWorkdirRoots restricts workdir to be within one of the provided roots.
 // Roots are canonicalized (clean+abs) and must exist as directories.
 func WithShellAllowedWorkdirRoots(roots []string) ShellToolOption {
@@ -239,12 +264,35 @@ func WithShellMaxSessions(maxSessions int) ShellToolOption {
 	}
 }
 
+// WithShellSessionReapInterval starts a background goroutine that removes
+// sessions idle past the session TTL every interval, so idle sessions are
+// released even when the tool is not used. Call Close to stop it.
+// "interval<=0" disables it (expired sessions are then evicted on access).
+func WithShellSessionReapInterval(interval time.Duration) ShellToolOption {
+	return func(st *ShellTool) error {
+		st.reapInterval = max(interval, 0)
+		return nil
+	}
+}
+
+// WithShellSessionIDFunc sets the generator for new session ids (e.g. for
+// deterministic tests or a custom id scheme). Empty or in-use ids are
+// regenerated; if fn keeps failing, the default random id is used.
+// A nil fn restores the default.
+func WithShellSessionIDFunc(fn func() string) ShellToolOption {
+	return func(st *ShellTool) error {
+		st.sessions.setIDFunc(fn)
+		return nil
+	}
+}
+
 func NewShellTool(opts ...ShellToolOption) (*ShellTool, error) {
 	st := &ShellTool{
 		policy:              DefaultShellCommandPolicy,
 		allowedWorkdirRoots: nil,
 		blockedCommands:     maps.Clone(hardBlockedCommands),
 		sessions:            newSessionStore(),
+		procs:               newProcessStore(),
 	}
 	for _, opt := range opts {
 		if opt == nil {
@@ -254,9 +302,20 @@ func NewShellTool(opts ...ShellToolOption) (*ShellTool, error) {
 			return nil, err
 		}
 	}
+	st.sessions.onClose = st.procs.stopSession
+	st.sessions.startReaper(st.reapInterval)
 	return st, nil
 }
 
+// Close stops the background session reaper, if one was started, and kills
+// all background commands started with StartCommand. Sessions remain usable;
+// it is safe to call more than once.
+func (st *ShellTool) Close() error {
+	st.sessions.stopReaperLoop()
+	st.procs.stopAll()
+	return nil
+}
+
 func (st *ShellTool) Tool() spec.Tool { return toolutil.CloneTool(shellToolSpec) }
 
 // SetAllowedWorkdirRoots allows changing workdir roots at runtime (best-effort).
@@ -371,31 +430,7 @@ func (st *ShellTool) run(ctx context.Context, args ShellCommandArgs) (out *Shell
 			sess.workdir = workdir
 		}
 		if len(args.Env) != 0 {
-			if sess.env == nil {
-				sess.env = map[string]string{}
-			}
-			if runtime.GOOS == toolutil.GOOSWindows {
-				// Rebuild session env to canonical keys to avoid case-insensitive duplicates
-				// causing nondeterministic behavior.
-				canon := make(map[string]string, len(sess.env)+len(args.Env))
-				for k, v := range sess.env {
-					kk := strings.ToUpper(strings.TrimSpace(k))
-					if kk == "" {
-						continue
-					}
-					canon[kk] = v
-				}
-				for k, v := range args.Env {
-					kk := strings.ToUpper(strings.TrimSpace(k))
-					if kk == "" {
-						continue
-					}
-					canon[kk] = v
-				}
-				sess.env = canon
-			} else {
-				maps.Copy(sess.env, args.Env)
-			}
+			sess.setEnvLocked(args.Env)
 		}
 		sess.mu.Unlock()
 	}
@@ -466,6 +501,52 @@ func runOne(
 	timeout time.Duration,
 	maxOut int64,
 ) (ShellCommandExecResult, error) {
+	res, err := execCaptured(parent, deriveExecArgs(sel, command), workdir, env, nil, timeout, maxOut)
+	if err != nil {
+		return ShellCommandExecResult{}, err
+	}
+	return ShellCommandExecResult{
+		Command:   command,
+		Workdir:   workdir,
+		Shell:     sel.Name,
+		ShellPath: sel.Path,
+
+		ExitCode:   res.exitCode,
+		TimedOut:   res.timedOut,
+		DurationMS: res.duration.Milliseconds(),
+
+		Stdout: safeUTF8(res.stdout.Bytes()),
+		Stderr: safeUTF8(res.stderr.Bytes()),
+
+		StdoutTruncated: res.stdout.Truncated(),
+		StderrTruncated: res.stderr.Truncated(),
+	}, nil
+}
+
+// execResult is the outcome of a process started by execCaptured.
+type execResult struct {
+	exitCode       int
+	timedOut       bool
+	killedByCtx    bool // killed because parent ctx was canceled or the timeout fired
+	duration       time.Duration
+	stdout, stderr *cappedWriter
+}
+
+// execCaptured runs argv in its own process group, capturing each output stream
+// into a cappedWriter of maxOut bytes. A non-nil stdin is fed to the process
+// (concurrently with output capture, so large inputs cannot deadlock) and then
+// closed; a nil stdin reads from the null device. If the timeout fires or parent is canceled,
+// the whole process group is killed (so grandchildren are reaped too) before
+// returning. A non-nil error means the process could not be started.
+func execCaptured(
+	parent context.Context,
+	argv []string,
+	workdir string,
+	env []string,
+	stdin io.Reader,
+	timeout time.Duration,
+	maxOut int64,
+) (execResult, error) {
 	ctx := parent
 	var cancel context.CancelFunc
 	if timeout > 0 {
@@ -473,11 +554,11 @@ func runOne(
 		defer cancel()
 	}
 
-	args := deriveExecArgs(sel, command)
-
-	cmd := exec.CommandContext(ctx, args[0], args[1:]...) //nolint:gosec // Exec shell command.
+	cmd := exec.CommandContext(ctx, argv[0], argv[1:]...) //nolint:gosec // Exec requested command.
 	cmd.Dir = workdir
 	cmd.Env = env
+	// os/exec copies a non-file stdin from its own goroutine, and closes the pipe when done.
+	cmd.Stdin = stdin
 
 	configureProcessGroup(cmd)
 
@@ -489,7 +570,7 @@ func runOne(
 	start := time.Now()
 	runErr := cmd.Start()
 	if runErr != nil {
-		return ShellCommandExecResult{}, runErr
+		return execResult{}, runErr
 	}
 
 	// Wait in a goroutine so we can react to ctx cancellation/timeouts.
@@ -520,23 +601,13 @@ func runOne(
 	// Only mark timed out if we actually killed because ctx fired due to deadline.
 	timedOut := killedByCtx && errors.Is(ctx.Err(), context.DeadlineExceeded)
 
-	exitCode := exitCodeFromWait(waitErr, timedOut)
-
-	return ShellCommandExecResult{
-		Command:   command,
-		Workdir:   workdir,
-		Shell:     sel.Name,
-		ShellPath: sel.Path,
-
-		ExitCode:   exitCode,
-		TimedOut:   timedOut,
-		DurationMS: dur.Milliseconds(),
-
-		Stdout: safeUTF8(stdoutW.Bytes()),
-		Stderr: safeUTF8(stderrW.Bytes()),
-
-		StdoutTruncated: stdoutW.Truncated(),
-		StderrTruncated: stderrW.Truncated(),
+	return execResult{
+		exitCode:    exitCodeFromWait(waitErr, timedOut),
+		timedOut:    timedOut,
+		killedByCtx: killedByCtx,
+		duration:    dur,
+		stdout:      stdoutW,
+		stderr:      stderrW,
 	}, nil
 }
 
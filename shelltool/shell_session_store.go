@@ -2,8 +2,13 @@ package shelltool
 
 import (
 	"container/list"
+	"maps"
+	"runtime"
+	"strings"
 	"sync"
 	"time"
+
+	"github.com/flexigpt/llmtools-go/internal/toolutil"
 )
 
 type shellSession struct {
@@ -14,6 +19,52 @@ type shellSession struct {
 	closed  bool
 }
 
+// setEnvLocked merges vars into the session env. Callers hold s.mu and have
+// validated vars.
+func (s *shellSession) setEnvLocked(vars map[string]string) {
+	if s.env == nil {
+		s.env = map[string]string{}
+	}
+	if runtime.GOOS == toolutil.GOOSWindows {
+		// Rebuild session env to canonical keys to avoid case-insensitive duplicates
+		// causing nondeterministic behavior.
+		canon := make(map[string]string, len(s.env)+len(vars))
+		for k, v := range s.env {
+			kk := strings.ToUpper(strings.TrimSpace(k))
+			if kk == "" {
+				continue
+			}
+			canon[kk] = v
+		}
+		for k, v := range vars {
+			kk := strings.ToUpper(strings.TrimSpace(k))
+			if kk == "" {
+				continue
+			}
+			canon[kk] = v
+		}
+		s.env = canon
+		return
+	}
+	maps.Copy(s.env, vars)
+}
+
+// unsetEnvLocked removes keys from the session env (case-insensitively on
+// Windows) and returns how many were present. Callers hold s.mu.
+func (s *shellSession) unsetEnvLocked(keys []string) int {
+	removed := 0
+	for _, k := range keys {
+		ck := canonicalEnvKey(strings.TrimSpace(k))
+		for sk := range s.env {
+			if canonicalEnvKey(strings.TrimSpace(sk)) == ck {
+				delete(s.env, sk)
+				removed++
+			}
+		}
+	}
+	return removed
+}
+
 type sessionStore struct {
 	mu  sync.Mutex
 	ttl time.Duration
@@ -21,6 +72,12 @@ type sessionStore struct {
 
 	lru *list.List               // front=most recently used
 	m   map[string]*list.Element // id -> *list.Element(Value=*sessionItem)
+
+	stopReaper chan struct{} // non-nil while the background reaper runs
+
+	idFunc func() string // session id generator; nil => newSessionID
+
+	onClose func(id string) // called with ss.mu held when a session is removed; must not block
 }
 
 type sessionItem struct {
@@ -48,7 +105,7 @@ func (ss *sessionStore) setTTL(ttl time.Duration) {
 	}
 	ss.mu.Lock()
 	ss.ttl = ttl
-	ss.evictExpiredLocked(time.Now())
+	ss.evictExpiredLocked(toolutil.Now())
 	ss.mu.Unlock()
 }
 
@@ -63,13 +120,13 @@ func (ss *sessionStore) setMaxSessions(maxSessions int) {
 }
 
 func (ss *sessionStore) newSession() *shellSession {
-	now := time.Now()
+	now := toolutil.Now()
 	ss.mu.Lock()
 	defer ss.mu.Unlock()
 	ss.evictExpiredLocked(now)
 	ss.evictOverLimitLocked()
 
-	id := newSessionID()
+	id := ss.nextIDLocked()
 	s := &shellSession{
 		id:      id,
 		workdir: "",
@@ -83,8 +140,36 @@ func (ss *sessionStore) newSession() *shellSession {
 	return s
 }
 
+// maxSessionIDAttempts bounds retries of a custom id generator that keeps
+// returning empty or in-use ids before falling back to newSessionID.
+const maxSessionIDAttempts = 8
+
+func (ss *sessionStore) setIDFunc(fn func() string) {
+	ss.mu.Lock()
+	ss.idFunc = fn
+	ss.mu.Unlock()
+}
+
+// nextIDLocked returns an id not held by any live session, regenerating on
+// collisions. Callers hold ss.mu.
+func (ss *sessionStore) nextIDLocked() string {
+	if ss.idFunc != nil {
+		for range maxSessionIDAttempts {
+			id := strings.TrimSpace(ss.idFunc())
+			if _, taken := ss.m[id]; id != "" && !taken {
+				return id
+			}
+		}
+	}
+	for {
+		if id := newSessionID(); ss.m[id] == nil {
+			return id
+		}
+	}
+}
+
 func (ss *sessionStore) get(id string) (*shellSession, bool) {
-	now := time.Now()
+	now := toolutil.Now()
 	ss.mu.Lock()
 	ss.evictExpiredLocked(now)
 	e, ok := ss.m[id]
@@ -122,10 +207,66 @@ func (ss *sessionStore) delete(id string) {
 	ss.mu.Unlock()
 }
 
-func (ss *sessionStore) evictExpiredLocked(now time.Time) {
-	if ss.ttl <= 0 {
+// reapIdle closes and removes sessions not used for longer than maxIdle and
+// returns how many were removed. "maxIdle<=0" removes nothing.
+func (ss *sessionStore) reapIdle(maxIdle time.Duration) int {
+	now := toolutil.Now()
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+	return ss.reapIdleLocked(now, maxIdle)
+}
+
+// startReaper runs reapIdle with the store TTL every interval until
+// stopReaperLoop is called. Without it, idle sessions are only evicted when the
+// store is next accessed. Calling it again replaces the running reaper.
+func (ss *sessionStore) startReaper(interval time.Duration) {
+	if interval <= 0 {
 		return
 	}
+	stop := make(chan struct{})
+	ss.mu.Lock()
+	prev := ss.stopReaper
+	ss.stopReaper = stop
+	ss.mu.Unlock()
+	if prev != nil {
+		close(prev)
+	}
+
+	go func() {
+		t := time.NewTicker(interval)
+		defer t.Stop()
+		for {
+			select {
+			case <-stop:
+				return
+			case <-t.C:
+				ss.mu.Lock()
+				ss.reapIdleLocked(toolutil.Now(), ss.ttl)
+				ss.mu.Unlock()
+			}
+		}
+	}()
+}
+
+func (ss *sessionStore) stopReaperLoop() {
+	ss.mu.Lock()
+	stop := ss.stopReaper
+	ss.stopReaper = nil
+	ss.mu.Unlock()
+	if stop != nil {
+		close(stop)
+	}
+}
+
+func (ss *sessionStore) evictExpiredLocked(now time.Time) {
+	ss.reapIdleLocked(now, ss.ttl)
+}
+
+func (ss *sessionStore) reapIdleLocked(now time.Time, maxIdle time.Duration) int {
+	if maxIdle <= 0 {
+		return 0
+	}
+	removed := 0
 	// Oldest at back; stop once we find a non-expired entry.
 	for e := ss.lru.Back(); e != nil; {
 		prev := e.Prev()
@@ -136,12 +277,14 @@ func (ss *sessionStore) evictExpiredLocked(now time.Time) {
 			e = prev
 			continue
 		}
-		if now.Sub(it.lastUsed) <= ss.ttl {
+		if now.Sub(it.lastUsed) <= maxIdle {
 			break
 		}
 		ss.deleteElemLocked(e)
+		removed++
 		e = prev
 	}
+	return removed
 }
 
 func (ss *sessionStore) evictOverLimitLocked() {
@@ -173,6 +316,10 @@ func (ss *sessionStore) deleteElemLocked(e *list.Element) {
 	it.s.mu.Lock()
 	it.s.closed = true
 	it.s.mu.Unlock()
+
+	if ss.onClose != nil {
+		ss.onClose(it.s.id)
+	}
 }
 
 func (ss *sessionStore) sizeForTest() int {
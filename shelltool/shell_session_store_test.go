@@ -0,0 +1,142 @@
+package shelltool
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSessionStore_ReapIdle(t *testing.T) {
+	fc := &fakeClock{t: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+	t.Cleanup(SetClock(fc))
+
+	ss := newSessionStore()
+	ss.setTTL(0) // no eviction on access
+
+	idle := ss.newSession()
+	active := ss.newSession()
+
+	fc.Advance(2 * time.Minute)
+	if _, ok := ss.get(active.id); !ok {
+		t.Fatalf("expected active session to be present")
+	}
+	fc.Advance(30 * time.Second)
+
+	if got := ss.reapIdle(0); got != 0 {
+		t.Fatalf("reapIdle(0)=%d want 0", got)
+	}
+	if got := ss.reapIdle(time.Minute); got != 1 {
+		t.Fatalf("reapIdle=%d want 1", got)
+	}
+	if _, ok := ss.get(idle.id); ok {
+		t.Fatalf("expected idle session to be reaped")
+	}
+	idle.mu.RLock()
+	closed := idle.closed
+	idle.mu.RUnlock()
+	if !closed {
+		t.Fatalf("expected reaped session to be marked closed")
+	}
+	if _, ok := ss.get(active.id); !ok {
+		t.Fatalf("expected active session to survive")
+	}
+	if got := ss.sizeForTest(); got != 1 {
+		t.Fatalf("store size=%d want 1", got)
+	}
+}
+
+func TestShellTool_SessionReapInterval(t *testing.T) {
+	fc := &fakeClock{t: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+	t.Cleanup(SetClock(fc))
+
+	st := newTestShellTool(t, WithShellSessionTTL(time.Minute), WithShellSessionReapInterval(5*time.Millisecond))
+	t.Cleanup(func() { _ = st.Close() })
+
+	idle := st.sessions.newSession()
+	active := st.sessions.newSession()
+	fc.Advance(45 * time.Second)
+	if _, ok := st.sessions.get(active.id); !ok {
+		t.Fatalf("expected active session to be present")
+	}
+	fc.Advance(30 * time.Second)
+
+	// Reaping happens in the background without any further store access.
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		idle.mu.RLock()
+		closed := idle.closed
+		idle.mu.RUnlock()
+		if closed {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("idle session was not reaped in the background")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	if got := st.sessions.sizeForTest(); got != 1 {
+		t.Fatalf("store size=%d want 1", got)
+	}
+
+	if err := st.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	if err := st.Close(); err != nil {
+		t.Fatalf("second Close: %v", err)
+	}
+}
+
+func TestSessionStore_IDFunc(t *testing.T) {
+	t.Run("counter ids are stored", func(t *testing.T) {
+		n := 0
+		st := newTestShellTool(t, WithShellSessionIDFunc(func() string {
+			n++
+			return fmt.Sprintf("s%d", n)
+		}))
+		for _, want := range []string{"s1", "s2", "s3"} {
+			sess := st.sessions.newSession()
+			if sess.id != want {
+				t.Fatalf("id=%q want %q", sess.id, want)
+			}
+			if got, ok := st.sessions.get(want); !ok || got != sess {
+				t.Fatalf("session %q not retrievable by id", want)
+			}
+		}
+	})
+
+	t.Run("collisions and empty ids regenerate", func(t *testing.T) {
+		ids := []string{"a", "a", "", " b "}
+		ss := newSessionStore()
+		ss.setIDFunc(func() string {
+			id := ids[0]
+			ids = ids[1:]
+			return id
+		})
+		if got := ss.newSession().id; got != "a" {
+			t.Fatalf("first id=%q want a", got)
+		}
+		if got := ss.newSession().id; got != "b" {
+			t.Fatalf("second id=%q want b", got)
+		}
+	})
+
+	t.Run("persistent collisions fall back to random ids", func(t *testing.T) {
+		ss := newSessionStore()
+		ss.setIDFunc(func() string { return "same" })
+		first := ss.newSession().id
+		second := ss.newSession().id
+		if first != "same" || !strings.HasPrefix(second, "sess_") {
+			t.Fatalf("ids=%q,%q", first, second)
+		}
+	})
+
+	t.Run("reaped ids can be reused", func(t *testing.T) {
+		ss := newSessionStore()
+		ss.setIDFunc(func() string { return "fixed" })
+		ss.delete(ss.newSession().id)
+		if got := ss.newSession().id; got != "fixed" {
+			t.Fatalf("id=%q want fixed", got)
+		}
+	})
+}
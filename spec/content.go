@@ -0,0 +1,87 @@
+package spec
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Content block formats accepted by ToolStoreOutputUnion.ToContentBlock.
+const (
+	ContentFormatOpenAI    = "openai"
+	ContentFormatAnthropic = "anthropic"
+)
+
+const mimePDF = "application/pdf"
+
+// ToContentBlock renders o as a content block of the given provider format:
+//   - openai: {"type":"text"}, {"type":"image_url"} with a base64 data URL, and
+//     {"type":"file"} for PDFs.
+//   - anthropic: {"type":"text"}, {"type":"image"} with a base64 source, and
+//     {"type":"document"} for PDFs.
+//
+// Files the format cannot carry degrade to a text block describing them.
+func (o ToolStoreOutputUnion) ToContentBlock(format string) (json.RawMessage, error) {
+	if format != ContentFormatOpenAI && format != ContentFormatAnthropic {
+		return nil, fmt.Errorf("unknown content format: %q", format)
+	}
+	switch o.Kind {
+	case ToolStoreOutputKindText:
+		if o.TextItem == nil {
+			return nil, fmt.Errorf("%s output has no textItem", o.Kind)
+		}
+		return marshalUnescaped(map[string]any{"type": "text", "text": o.TextItem.Text})
+
+	case ToolStoreOutputKindImage:
+		img := o.ImageItem
+		if img == nil {
+			return nil, fmt.Errorf("%s output has no imageItem", o.Kind)
+		}
+		if format == ContentFormatOpenAI {
+			imageURL := map[string]any{"url": dataURL(img.ImageMIME, img.ImageData)}
+			if img.Detail != "" {
+				imageURL["detail"] = img.Detail
+			}
+			return marshalUnescaped(map[string]any{"type": "image_url", "image_url": imageURL})
+		}
+		return marshalUnescaped(map[string]any{
+			"type":   "image",
+			"source": base64Source(img.ImageMIME, img.ImageData),
+		})
+
+	case ToolStoreOutputKindFile:
+		f := o.FileItem
+		if f == nil {
+			return nil, fmt.Errorf("%s output has no fileItem", o.Kind)
+		}
+		switch {
+		case f.FileMIME != mimePDF:
+			return marshalUnescaped(map[string]any{
+				"type": "text",
+				"text": fmt.Sprintf(
+					"[file %q (%s) omitted: %s content blocks cannot carry it]",
+					f.FileName, f.FileMIME, format,
+				),
+			})
+		case format == ContentFormatOpenAI:
+			return marshalUnescaped(map[string]any{
+				"type": "file",
+				"file": map[string]any{"filename": f.FileName, "file_data": dataURL(f.FileMIME, f.FileData)},
+			})
+		default:
+			return marshalUnescaped(map[string]any{
+				"type":   "document",
+				"source": base64Source(f.FileMIME, f.FileData),
+				"title":  f.FileName,
+			})
+		}
+	}
+	return nil, fmt.Errorf("unsupported output kind: %q", o.Kind)
+}
+
+func dataURL(mime, b64 string) string {
+	return "data:" + mime + ";base64," + b64
+}
+
+func base64Source(mime, b64 string) map[string]any {
+	return map[string]any{"type": "base64", "media_type": mime, "data": b64}
+}
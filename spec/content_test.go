@@ -0,0 +1,110 @@
+package spec_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/flexigpt/llmtools-go/spec"
+)
+
+func TestToContentBlock(t *testing.T) {
+	text := spec.ToolStoreOutputUnion{
+		Kind:     spec.ToolStoreOutputKindText,
+		TextItem: &spec.ToolStoreOutputText{Text: "a <b>"},
+	}
+	image := spec.ToolStoreOutputUnion{
+		Kind: spec.ToolStoreOutputKindImage,
+		ImageItem: &spec.ToolStoreOutputImage{
+			Detail: spec.ImageDetailLow, ImageName: "a.png", ImageMIME: "image/png", ImageData: "iVBO",
+		},
+	}
+	pdf := spec.ToolStoreOutputUnion{
+		Kind:     spec.ToolStoreOutputKindFile,
+		FileItem: &spec.ToolStoreOutputFile{FileName: "doc.pdf", FileMIME: "application/pdf", FileData: "JVBE"},
+	}
+	zip := spec.ToolStoreOutputUnion{
+		Kind:     spec.ToolStoreOutputKindFile,
+		FileItem: &spec.ToolStoreOutputFile{FileName: "x.zip", FileMIME: "application/zip", FileData: "UEsD"},
+	}
+
+	tests := []struct {
+		name       string
+		out        spec.ToolStoreOutputUnion
+		format     string
+		want       string
+		wantErrSub string
+	}{
+		{name: "openai text", out: text, format: spec.ContentFormatOpenAI, want: `{"text":"a <b>","type":"text"}`},
+		{name: "anthropic text", out: text, format: spec.ContentFormatAnthropic, want: `{"text":"a <b>","type":"text"}`},
+		{
+			name:   "openai image",
+			out:    image,
+			format: spec.ContentFormatOpenAI,
+			want:   `{"image_url":{"detail":"low","url":"data:image/png;base64,iVBO"},"type":"image_url"}`,
+		},
+		{
+			name:   "anthropic image",
+			out:    image,
+			format: spec.ContentFormatAnthropic,
+			want:   `{"source":{"data":"iVBO","media_type":"image/png","type":"base64"},"type":"image"}`,
+		},
+		{
+			name:   "openai pdf",
+			out:    pdf,
+			format: spec.ContentFormatOpenAI,
+			want:   `{"file":{"file_data":"data:application/pdf;base64,JVBE","filename":"doc.pdf"},"type":"file"}`,
+		},
+		{
+			name:   "anthropic pdf",
+			out:    pdf,
+			format: spec.ContentFormatAnthropic,
+			want: `{"source":{"data":"JVBE","media_type":"application/pdf","type":"base64"},` +
+				`"title":"doc.pdf","type":"document"}`,
+		},
+		{
+			name:   "openai other file degrades to text",
+			out:    zip,
+			format: spec.ContentFormatOpenAI,
+			want: `{"text":"[file \"x.zip\" (application/zip) omitted: openai content blocks cannot carry it]",` +
+				`"type":"text"}`,
+		},
+		{
+			name:   "anthropic other file degrades to text",
+			out:    zip,
+			format: spec.ContentFormatAnthropic,
+			want: `{"text":"[file \"x.zip\" (application/zip) omitted: anthropic content blocks cannot carry it]",` +
+				`"type":"text"}`,
+		},
+		{name: "unknown format", out: text, format: "gemini", wantErrSub: "unknown content format"},
+		{
+			name:       "missing item",
+			out:        spec.ToolStoreOutputUnion{Kind: spec.ToolStoreOutputKindImage},
+			format:     spec.ContentFormatOpenAI,
+			wantErrSub: "no imageItem",
+		},
+		{
+			name:       "none kind",
+			out:        spec.ToolStoreOutputUnion{Kind: spec.ToolStoreOutputKindNone},
+			format:     spec.ContentFormatAnthropic,
+			wantErrSub: "unsupported output kind",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.out.ToContentBlock(tt.format)
+			if tt.wantErrSub != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErrSub) {
+					t.Fatalf("expected error containing %q, got %v", tt.wantErrSub, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if string(got) != tt.want {
+				t.Fatalf("ToContentBlock:\ngot  %s\nwant %s", got, tt.want)
+			}
+		})
+	}
+}
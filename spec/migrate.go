@@ -0,0 +1,108 @@
+package spec
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/flexigpt/llmtools-go/internal/jsonutil"
+)
+
+// ToolMigrationFunc upgrades the fields of a serialized tool in place, from
+// the schema version it was registered for to the next one.
+type ToolMigrationFunc func(fields map[string]json.RawMessage) error
+
+type toolMigration struct {
+	to string
+	fn ToolMigrationFunc
+}
+
+var (
+	toolMigrationsMu sync.RWMutex
+	// toolMigrations is keyed by source schemaVersion; "" is the unversioned
+	// (v0) format that predates schemaVersion.
+	toolMigrations = map[string]toolMigration{
+		"": {to: SchemaVersion, fn: migrateToolV0},
+	}
+)
+
+// RegisterToolMigration registers fn to upgrade serialized tools from schema
+// version from to version to, replacing any migration registered for from.
+// MigrateTool chains migrations until it reaches SchemaVersion.
+func RegisterToolMigration(from, to string, fn ToolMigrationFunc) {
+	toolMigrationsMu.Lock()
+	defer toolMigrationsMu.Unlock()
+	toolMigrations[from] = toolMigration{to: to, fn: fn}
+}
+
+// MigrateTool decodes a serialized tool written by an older release, applying
+// registered migrations until it matches SchemaVersion, and validates the
+// result. Tools already at SchemaVersion are only decoded and validated.
+func MigrateTool(old json.RawMessage) (Tool, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(old, &fields); err != nil || fields == nil {
+		return Tool{}, errors.New("migrate tool: not a JSON object")
+	}
+	version := ""
+	if raw, ok := fields["schemaVersion"]; ok {
+		if err := json.Unmarshal(raw, &version); err != nil {
+			return Tool{}, fmt.Errorf("migrate tool: invalid schemaVersion: %w", err)
+		}
+	}
+
+	toolMigrationsMu.RLock()
+	steps := len(toolMigrations)
+	toolMigrationsMu.RUnlock()
+	for i := 0; version != SchemaVersion; i++ {
+		toolMigrationsMu.RLock()
+		m, ok := toolMigrations[version]
+		toolMigrationsMu.RUnlock()
+		if !ok {
+			return Tool{}, fmt.Errorf("migrate tool: no migration from schemaVersion %q", version)
+		}
+		if i >= steps {
+			return Tool{}, fmt.Errorf("migrate tool: migrations loop at schemaVersion %q", version)
+		}
+		if err := m.fn(fields); err != nil {
+			return Tool{}, fmt.Errorf("migrate tool from schemaVersion %q: %w", version, err)
+		}
+		version = m.to
+		fields["schemaVersion"] = mustMarshal(version)
+	}
+
+	raw, err := json.Marshal(fields)
+	if err != nil {
+		return Tool{}, fmt.Errorf("migrate tool: %w", err)
+	}
+	t, err := jsonutil.DecodeJSONRaw[Tool](raw)
+	if err != nil {
+		return Tool{}, fmt.Errorf("migrate tool: %w", err)
+	}
+	if err := t.Validate(); err != nil {
+		return Tool{}, fmt.Errorf("migrate tool: %w", err)
+	}
+	return t, nil
+}
+
+// migrateToolV0 fills fields added with the first schemaVersion: tags and the
+// creation/modification times.
+func migrateToolV0(fields map[string]json.RawMessage) error {
+	setDefault := func(key string, v any) {
+		if raw, ok := fields[key]; !ok || string(raw) == "null" {
+			fields[key] = mustMarshal(v)
+		}
+	}
+	setDefault("tags", []string{})
+	setDefault("createdAt", SchemaStartTime)
+	setDefault("modifiedAt", SchemaStartTime)
+	return nil
+}
+
+func mustMarshal(v any) json.RawMessage {
+	raw, err := json.Marshal(v)
+	if err != nil {
+		panic(err)
+	}
+	return raw
+}
@@ -0,0 +1,116 @@
+package spec_test
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/flexigpt/llmtools-go/spec"
+)
+
+func TestMigrateTool(t *testing.T) {
+	want := spec.Tool{
+		SchemaVersion: spec.SchemaVersion,
+		ID:            "0190f3f3-6a2c-7c1a-9f59-aaaaaaaaaaaa",
+		Slug:          "weather",
+		Version:       "v1",
+		DisplayName:   "Weather",
+		Description:   "Gets weather",
+		ArgSchema:     spec.JSONSchema(`{"type":"object"}`),
+		GoImpl:        spec.GoToolImpl{FuncID: "github.com/acme/tools.Weather"},
+		CreatedAt:     spec.SchemaStartTime,
+		ModifiedAt:    spec.SchemaStartTime,
+		Tags:          []string{},
+	}
+	// payload serializes want, then edits its fields like an older release would
+	// have written them.
+	payload := func(edit func(map[string]json.RawMessage)) string {
+		raw, err := json.Marshal(want)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var fields map[string]json.RawMessage
+		if err := json.Unmarshal(raw, &fields); err != nil {
+			t.Fatal(err)
+		}
+		edit(fields)
+		raw, err = json.Marshal(fields)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(raw)
+	}
+	// A v0 payload predates schemaVersion, tags and timestamps.
+	v0 := func(fields map[string]json.RawMessage) {
+		for _, k := range []string{"schemaVersion", "tags", "createdAt", "modifiedAt"} {
+			delete(fields, k)
+		}
+	}
+
+	// A hypothetical pre-v0 format that called the slug "name".
+	spec.RegisterToolMigration("test-legacy", "", func(fields map[string]json.RawMessage) error {
+		name, ok := fields["name"]
+		if !ok {
+			return errors.New("missing name")
+		}
+		fields["slug"] = name
+		delete(fields, "name")
+		return nil
+	})
+
+	tests := []struct {
+		name       string
+		in         string
+		wantNoTags bool // tags are only filled in by migrations
+		wantErrSub string
+	}{
+		{name: "v0 gets current shape", in: payload(v0)},
+		{
+			name: "migrations are chained",
+			in: payload(func(fields map[string]json.RawMessage) {
+				v0(fields)
+				fields["schemaVersion"] = json.RawMessage(`"test-legacy"`)
+				fields["name"] = fields["slug"]
+				delete(fields, "slug")
+			}),
+		},
+		{name: "current version is unchanged", in: payload(func(map[string]json.RawMessage) {}), wantNoTags: true},
+		{
+			name:       "unknown version",
+			in:         `{"schemaVersion": "1900-01-01"}`,
+			wantErrSub: `no migration from schemaVersion "1900-01-01"`,
+		},
+		{name: "failing migration", in: `{"schemaVersion": "test-legacy"}`, wantErrSub: "missing name"},
+		{name: "invalid result", in: `{"slug": "x"}`, wantErrSub: "missing funcID"},
+		{
+			name:       "unknown field",
+			in:         payload(func(fields map[string]json.RawMessage) { v0(fields); fields["bogus"] = json.RawMessage(`1`) }),
+			wantErrSub: "unknown field",
+		},
+		{name: "not an object", in: `[]`, wantErrSub: "not a JSON object"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := spec.MigrateTool(json.RawMessage(tt.in))
+			if tt.wantErrSub != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErrSub) {
+					t.Fatalf("expected error containing %q, got %v", tt.wantErrSub, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			want := want
+			if tt.wantNoTags {
+				want.Tags = nil
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Fatalf("MigrateTool:\ngot  %+v\nwant %+v", got, want)
+			}
+		})
+	}
+}
@@ -1,5 +1,12 @@
 package spec
 
+import (
+	"fmt"
+	"strings"
+
+	"github.com/flexigpt/llmtools-go/internal/jsonutil"
+)
+
 type ToolStoreOutputKind string
 
 const (
@@ -41,3 +48,36 @@ type ToolStoreOutputUnion struct {
 	ImageItem *ToolStoreOutputImage `json:"imageItem,omitempty"`
 	FileItem  *ToolStoreOutputFile  `json:"fileItem,omitempty"`
 }
+
+// StructOutput JSON-encodes v into a single text output. This is the output
+// shape of struct-returning tools registered as text tools.
+func StructOutput(v any) (ToolStoreOutputUnion, error) {
+	return StructOutputWithSchema(v, nil)
+}
+
+// StructOutputWithSchema is StructOutput, additionally validating the encoded
+// value against outputSchema (JSON Schema, draft-07 subset). An empty schema
+// skips validation.
+func StructOutputWithSchema(v any, outputSchema JSONSchema) (ToolStoreOutputUnion, error) {
+	raw, err := jsonutil.EncodeToJSONRaw(v)
+	if err != nil {
+		return ToolStoreOutputUnion{}, err
+	}
+	if len(outputSchema) > 0 {
+		violations, err := jsonutil.ValidateJSONSchema(outputSchema, raw)
+		if err != nil {
+			return ToolStoreOutputUnion{}, fmt.Errorf("invalid output schema: %w", err)
+		}
+		if len(violations) > 0 {
+			msgs := make([]string, 0, len(violations))
+			for _, sv := range violations {
+				msgs = append(msgs, fmt.Sprintf("%q: %s", sv.Pointer, sv.Message))
+			}
+			return ToolStoreOutputUnion{}, fmt.Errorf("output does not match schema: %s", strings.Join(msgs, "; "))
+		}
+	}
+	return ToolStoreOutputUnion{
+		Kind:     ToolStoreOutputKindText,
+		TextItem: &ToolStoreOutputText{Text: string(raw)},
+	}, nil
+}
@@ -0,0 +1,117 @@
+package spec_test
+
+import (
+	"encoding/json"
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/flexigpt/llmtools-go/imagetool"
+	"github.com/flexigpt/llmtools-go/spec"
+)
+
+func TestStructOutput_ReadImageOut(t *testing.T) {
+	p := filepath.Join(t.TempDir(), "img.png")
+	f, err := os.Create(p)
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	img := image.NewRGBA(image.Rect(0, 0, 3, 2))
+	img.Set(1, 1, color.RGBA{R: 255, A: 255})
+	if err := png.Encode(f, img); err != nil {
+		t.Fatalf("encode png: %v", err)
+	}
+	if err := f.Close(); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+
+	want, err := imagetool.ReadImage(t.Context(), imagetool.ReadImageArgs{Path: p})
+	if err != nil {
+		t.Fatalf("ReadImage: %v", err)
+	}
+
+	item, err := spec.StructOutput(want)
+	if err != nil {
+		t.Fatalf("StructOutput: %v", err)
+	}
+	if item.Kind != spec.ToolStoreOutputKindText || item.TextItem == nil {
+		t.Fatalf("unexpected output %+v", item)
+	}
+	if item.ImageItem != nil || item.FileItem != nil {
+		t.Fatalf("unexpected non-text items: %+v", item)
+	}
+	if !json.Valid([]byte(item.TextItem.Text)) {
+		t.Fatalf("output is not valid JSON: %s", item.TextItem.Text)
+	}
+
+	var got imagetool.ReadImageOut
+	dec := json.NewDecoder(strings.NewReader(item.TextItem.Text))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&got); err != nil {
+		t.Fatalf("decode output: %v", err)
+	}
+	if got.ModTime == nil || want.ModTime == nil || !got.ModTime.Equal(*want.ModTime) {
+		t.Fatalf("ModTime=%v want %v", got.ModTime, want.ModTime)
+	}
+	got.ModTime, want.ModTime = nil, nil
+	if got != *want {
+		t.Fatalf("round trip mismatch:\ngot  %+v\nwant %+v", got, *want)
+	}
+	if got.Width != 3 || got.Height != 2 || got.Format != "png" {
+		t.Fatalf("unexpected image metadata: %+v", got)
+	}
+}
+
+func TestStructOutputWithSchema(t *testing.T) {
+	type out struct {
+		Name  string `json:"name"`
+		Count int    `json:"count"`
+	}
+	schema := spec.JSONSchema(`{
+	"type": "object",
+	"properties": {
+		"name": {"type": "string", "minLength": 1},
+		"count": {"type": "integer", "minimum": 0}
+	},
+	"required": ["name", "count"],
+	"additionalProperties": false
+}`)
+
+	tests := []struct {
+		name       string
+		v          any
+		schema     spec.JSONSchema
+		wantText   string
+		wantErrSub string
+	}{
+		{name: "valid", v: out{Name: "a", Count: 2}, schema: schema, wantText: `{"name":"a","count":2}`},
+		{name: "no schema skips validation", v: out{Count: -1}, wantText: `{"name":"","count":-1}`},
+		{
+			name: "violations reported", v: out{Count: -1}, schema: schema,
+			wantErrSub: `output does not match schema: "/count": value -1 is less than minimum 0; "/name"`,
+		},
+		{name: "invalid schema", v: out{}, schema: spec.JSONSchema(`{"type": 5}`), wantErrSub: "invalid output schema"},
+		{name: "unencodable value", v: make(chan int), wantErrSub: "encode JSON"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			item, err := spec.StructOutputWithSchema(tt.v, tt.schema)
+			if tt.wantErrSub != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErrSub) {
+					t.Fatalf("expected error containing %q, got %v", tt.wantErrSub, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if item.Kind != spec.ToolStoreOutputKindText || item.TextItem.Text != tt.wantText {
+				t.Fatalf("got %+v text=%q want %q", item, item.TextItem.Text, tt.wantText)
+			}
+		})
+	}
+}
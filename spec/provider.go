@@ -0,0 +1,112 @@
+package spec
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/flexigpt/llmtools-go/internal/jsonutil"
+)
+
+type openAITool struct {
+	Type     string         `json:"type"`
+	Function openAIFunction `json:"function"`
+}
+
+type openAIFunction struct {
+	Name        string          `json:"name"`
+	Description string          `json:"description"`
+	Parameters  json.RawMessage `json:"parameters"`
+}
+
+// ToOpenAIFunction renders t as an OpenAI function-calling tool definition:
+// {"type":"function","function":{"name","description","parameters"}}, with
+// Slug as name and ArgSchema as parameters. ArgSchema must be a JSON object
+// schema ("type": "object").
+func (t Tool) ToOpenAIFunction() (json.RawMessage, error) {
+	params, err := t.objectArgSchema()
+	if err != nil {
+		return nil, err
+	}
+	return marshalUnescaped(openAITool{
+		Type: "function",
+		Function: openAIFunction{
+			Name:        t.Slug,
+			Description: t.Description,
+			Parameters:  params,
+		},
+	})
+}
+
+type anthropicTool struct {
+	Name        string          `json:"name"`
+	Description string          `json:"description"`
+	InputSchema json.RawMessage `json:"input_schema"`
+}
+
+// ToAnthropicTool renders t as an Anthropic tool-use definition:
+// {"name","description","input_schema"}, with Slug as name and ArgSchema as
+// input_schema. ArgSchema must be a JSON object schema ("type": "object").
+func (t Tool) ToAnthropicTool() (json.RawMessage, error) {
+	schema, err := t.objectArgSchema()
+	if err != nil {
+		return nil, err
+	}
+	return marshalUnescaped(anthropicTool{
+		Name:        t.Slug,
+		Description: t.Description,
+		InputSchema: schema,
+	})
+}
+
+type mcpTool struct {
+	Name        string          `json:"name"`
+	Description string          `json:"description"`
+	InputSchema json.RawMessage `json:"inputSchema"`
+}
+
+// ToMCPTool renders t as a Model Context Protocol tools/list entry:
+// {"name","description","inputSchema"}, with Slug as name and ArgSchema as
+// inputSchema. ArgSchema must be a JSON object schema ("type": "object").
+func (t Tool) ToMCPTool() (json.RawMessage, error) {
+	schema, err := t.objectArgSchema()
+	if err != nil {
+		return nil, err
+	}
+	return marshalUnescaped(mcpTool{
+		Name:        t.Slug,
+		Description: t.Description,
+		InputSchema: schema,
+	})
+}
+
+// objectArgSchema returns ArgSchema after checking that it is a JSON object
+// whose "type" is "object", which is what provider tool formats accept.
+func (t Tool) objectArgSchema() (json.RawMessage, error) {
+	if t.Slug == "" {
+		return nil, errors.New("invalid tool: missing slug")
+	}
+	if len(t.ArgSchema) == 0 {
+		return nil, fmt.Errorf("tool %s: missing argSchema", t.Slug)
+	}
+	var schema map[string]json.RawMessage
+	if err := json.Unmarshal(t.ArgSchema, &schema); err != nil || schema == nil {
+		return nil, fmt.Errorf("tool %s: argSchema is not a JSON object", t.Slug)
+	}
+	var typ string
+	if err := json.Unmarshal(schema["type"], &typ); err != nil || typ != "object" {
+		return nil, fmt.Errorf(`tool %s: argSchema must have "type": "object"`, t.Slug)
+	}
+	return json.RawMessage(t.ArgSchema), nil
+}
+
+// marshalUnescaped encodes v compactly without HTML-escaping, so descriptions
+// reach the model as written.
+func marshalUnescaped(v any) (json.RawMessage, error) {
+	var buf bytes.Buffer
+	if err := jsonutil.EncodeJSONTo(&buf, v); err != nil {
+		return nil, err
+	}
+	return json.RawMessage(bytes.TrimSuffix(buf.Bytes(), []byte("\n"))), nil
+}
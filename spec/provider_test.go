@@ -0,0 +1,164 @@
+package spec_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/flexigpt/llmtools-go/fstool"
+	"github.com/flexigpt/llmtools-go/imagetool"
+	"github.com/flexigpt/llmtools-go/spec"
+)
+
+func TestToolToOpenAIFunction(t *testing.T) {
+	tests := []struct {
+		name       string
+		tool       spec.Tool
+		want       string
+		wantErrSub string
+	}{
+		{
+			name: "snapshot",
+			tool: spec.Tool{
+				Slug:        "echo",
+				Description: "Echo <text>.",
+				ArgSchema: spec.JSONSchema(`{
+	"type": "object",
+	"properties": {"text": {"type": "string"}},
+	"required": ["text"]
+}`),
+			},
+			want: `{"type":"function","function":{"name":"echo","description":"Echo <text>.",` +
+				`"parameters":{"type":"object","properties":{"text":{"type":"string"}},"required":["text"]}}}`,
+		},
+		{
+			name:       "missing slug",
+			tool:       spec.Tool{ArgSchema: spec.JSONSchema(`{"type":"object"}`)},
+			wantErrSub: "missing slug",
+		},
+		{
+			name:       "missing schema",
+			tool:       spec.Tool{Slug: "x"},
+			wantErrSub: "missing argSchema",
+		},
+		{
+			name:       "array schema",
+			tool:       spec.Tool{Slug: "x", ArgSchema: spec.JSONSchema(`[]`)},
+			wantErrSub: "not a JSON object",
+		},
+		{
+			name:       "invalid JSON",
+			tool:       spec.Tool{Slug: "x", ArgSchema: spec.JSONSchema(`{"type":`)},
+			wantErrSub: "not a JSON object",
+		},
+		{
+			name:       "non-object type",
+			tool:       spec.Tool{Slug: "x", ArgSchema: spec.JSONSchema(`{"type":"string"}`)},
+			wantErrSub: `"type": "object"`,
+		},
+		{
+			name:       "no type",
+			tool:       spec.Tool{Slug: "x", ArgSchema: spec.JSONSchema(`{"properties":{}}`)},
+			wantErrSub: `"type": "object"`,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := tc.tool.ToOpenAIFunction()
+			if tc.wantErrSub != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.wantErrSub) {
+					t.Fatalf("ToOpenAIFunction: got %v want error containing %q", err, tc.wantErrSub)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ToOpenAIFunction: %v", err)
+			}
+			if string(got) != tc.want {
+				t.Fatalf("ToOpenAIFunction:\ngot  %s\nwant %s", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestToolToOpenAIFunction_ReadImage(t *testing.T) {
+	tool := imagetool.ReadImageTool()
+	raw, err := tool.ToOpenAIFunction()
+	if err != nil {
+		t.Fatalf("ToOpenAIFunction: %v", err)
+	}
+
+	var got struct {
+		Type     string `json:"type"`
+		Function struct {
+			Name        string          `json:"name"`
+			Description string          `json:"description"`
+			Parameters  json.RawMessage `json:"parameters"`
+		} `json:"function"`
+	}
+	if err := json.Unmarshal(raw, &got); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if got.Type != "function" || got.Function.Name != "readimage" || got.Function.Description != tool.Description {
+		t.Fatalf("unexpected envelope: %s", raw)
+	}
+	var wantParams bytes.Buffer
+	if err := json.Compact(&wantParams, tool.ArgSchema); err != nil {
+		t.Fatalf("compact: %v", err)
+	}
+	if string(got.Function.Parameters) != wantParams.String() {
+		t.Fatalf("parameters:\ngot  %s\nwant %s", got.Function.Parameters, wantParams.String())
+	}
+}
+
+func TestToolToAnthropicTool(t *testing.T) {
+	echo := spec.Tool{
+		Slug:        "echo",
+		Description: "Echo <text>.",
+		ArgSchema:   spec.JSONSchema(`{"type": "object", "properties": {"text": {"type": "string"}}}`),
+	}
+	got, err := echo.ToAnthropicTool()
+	if err != nil {
+		t.Fatalf("ToAnthropicTool: %v", err)
+	}
+	want := `{"name":"echo","description":"Echo <text>.",` +
+		`"input_schema":{"type":"object","properties":{"text":{"type":"string"}}}}`
+	if string(got) != want {
+		t.Fatalf("ToAnthropicTool:\ngot  %s\nwant %s", got, want)
+	}
+
+	if _, err := (spec.Tool{Slug: "x", ArgSchema: spec.JSONSchema(`[]`)}).ToAnthropicTool(); err == nil {
+		t.Fatalf("ToAnthropicTool with array schema: expected error")
+	}
+}
+
+func TestToolToAnthropicTool_Builtins(t *testing.T) {
+	for _, tool := range []spec.Tool{fstool.ReadFileTool(), imagetool.ReadImageTool()} {
+		t.Run(tool.Slug, func(t *testing.T) {
+			raw, err := tool.ToAnthropicTool()
+			if err != nil {
+				t.Fatalf("ToAnthropicTool: %v", err)
+			}
+			var fields map[string]json.RawMessage
+			if err := json.Unmarshal(raw, &fields); err != nil {
+				t.Fatalf("unmarshal: %v", err)
+			}
+			if len(fields) != 3 || fields["name"] == nil || fields["description"] == nil || fields["input_schema"] == nil {
+				t.Fatalf("want exactly name, description, input_schema; got %s", raw)
+			}
+			var name string
+			if err := json.Unmarshal(fields["name"], &name); err != nil || name != tool.Slug {
+				t.Fatalf("name: got %s want %q", fields["name"], tool.Slug)
+			}
+			var wantSchema bytes.Buffer
+			if err := json.Compact(&wantSchema, tool.ArgSchema); err != nil {
+				t.Fatalf("compact: %v", err)
+			}
+			if string(fields["input_schema"]) != wantSchema.String() {
+				t.Fatalf("input_schema:\ngot  %s\nwant %s", fields["input_schema"], wantSchema.String())
+			}
+		})
+	}
+}
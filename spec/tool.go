@@ -3,7 +3,12 @@ package spec
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
 	"time"
+
+	"github.com/flexigpt/llmtools-go/internal/jsonutil"
 )
 
 const (
@@ -47,6 +52,44 @@ type Tool struct {
 	Tags []string `json:"tags,omitempty"`
 }
 
+// slugPattern keeps slugs usable as function names in provider tool formats.
+var slugPattern = regexp.MustCompile(`^[A-Za-z0-9_-]{1,64}$`)
+
+// Validate checks that t is well-formed: a non-empty FuncID, a slug made of
+// letters, digits, '_' and '-' (at most 64), SchemaVersion equal to the
+// library's, and an ArgSchema that is a valid JSON Schema (draft-07 subset)
+// if set.
+func (t Tool) Validate() error {
+	if t.GoImpl.FuncID == "" {
+		return errors.New("invalid tool: missing funcID")
+	}
+	if t.Slug == "" {
+		return errors.New("invalid tool: missing slug")
+	}
+	if !slugPattern.MatchString(t.Slug) {
+		return fmt.Errorf("invalid tool: slug %q must match %s", t.Slug, slugPattern)
+	}
+	if t.SchemaVersion == "" {
+		return errors.New("invalid tool: missing schemaVersion")
+	}
+	if t.SchemaVersion != SchemaVersion {
+		return fmt.Errorf(
+			"invalid tool: schemaVersion %q does not match library schemaVersion %q",
+			t.SchemaVersion,
+			SchemaVersion,
+		)
+	}
+	if len(t.ArgSchema) > 0 {
+		if !json.Valid(t.ArgSchema) {
+			return errors.New("invalid tool: argSchema is not valid JSON")
+		}
+		if err := jsonutil.CheckJSONSchema(t.ArgSchema); err != nil {
+			return fmt.Errorf("invalid tool: argSchema: %w", err)
+		}
+	}
+	return nil
+}
+
 // ToolFunc is the low-level function signature stored in the registry.
 // It receives JSON-encoded args and returns one or more tool-store outputs.
 type ToolFunc func(ctx context.Context, in json.RawMessage) ([]ToolStoreOutputUnion, error)
@@ -0,0 +1,76 @@
+package spec_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/flexigpt/llmtools-go/fstool"
+	"github.com/flexigpt/llmtools-go/spec"
+)
+
+func TestToolValidate(t *testing.T) {
+	good := func() spec.Tool {
+		return spec.Tool{
+			SchemaVersion: spec.SchemaVersion,
+			Slug:          "good_tool-1",
+			ArgSchema:     spec.JSONSchema(`{"type": "object", "properties": {"n": {"type": "integer"}}}`),
+			GoImpl:        spec.GoToolImpl{FuncID: "github.com/acme/tools.Good"},
+		}
+	}
+
+	tests := []struct {
+		name       string
+		mutate     func(*spec.Tool)
+		wantErrSub string
+	}{
+		{name: "valid", mutate: func(*spec.Tool) {}},
+		{name: "valid without argSchema", mutate: func(tl *spec.Tool) { tl.ArgSchema = nil }},
+		{name: "missing funcID", mutate: func(tl *spec.Tool) { tl.GoImpl.FuncID = "" }, wantErrSub: "missing funcID"},
+		{name: "missing slug", mutate: func(tl *spec.Tool) { tl.Slug = "" }, wantErrSub: "missing slug"},
+		{name: "unsafe slug", mutate: func(tl *spec.Tool) { tl.Slug = "read file" }, wantErrSub: "must match"},
+		{name: "slug too long", mutate: func(tl *spec.Tool) { tl.Slug = strings.Repeat("a", 65) }, wantErrSub: "must match"},
+		{
+			name:       "missing schemaVersion",
+			mutate:     func(tl *spec.Tool) { tl.SchemaVersion = "" },
+			wantErrSub: "missing schemaVersion",
+		},
+		{
+			name:       "schemaVersion mismatch",
+			mutate:     func(tl *spec.Tool) { tl.SchemaVersion = "1900-01-01" },
+			wantErrSub: "does not match",
+		},
+		{
+			name:       "argSchema invalid JSON",
+			mutate:     func(tl *spec.Tool) { tl.ArgSchema = spec.JSONSchema(`{"type":`) },
+			wantErrSub: "not valid JSON",
+		},
+		{
+			name:       "argSchema invalid schema",
+			mutate:     func(tl *spec.Tool) { tl.ArgSchema = spec.JSONSchema(`{"type": "obj"}`) },
+			wantErrSub: "invalid schema",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			tl := good()
+			tc.mutate(&tl)
+			err := tl.Validate()
+			if tc.wantErrSub == "" {
+				if err != nil {
+					t.Fatalf("Validate: unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tc.wantErrSub) {
+				t.Fatalf("Validate: got %v want error containing %q", err, tc.wantErrSub)
+			}
+		})
+	}
+}
+
+func TestToolValidate_Builtin(t *testing.T) {
+	if err := fstool.ReadFileTool().Validate(); err != nil {
+		t.Fatalf("ReadFileTool().Validate: %v", err)
+	}
+}
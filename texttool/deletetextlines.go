@@ -115,7 +115,7 @@ func deleteTextLines(ctx context.Context, args DeleteTextLinesArgs) (*DeleteText
 		expected = 1
 	}
 
-	tf, err := fileutil.ReadTextFileUTF8(path, toolutil.MaxTextProcessingBytes)
+	tf, err := fileutil.ReadTextFileUTF8(path, toolutil.GetLimits().MaxTextProcessing)
 	if err != nil {
 		return nil, err
 	}
@@ -181,7 +181,7 @@ func findText(ctx context.Context, args FindTextArgs) (*FindTextOut, error) {
 		return nil, fmt.Errorf("maxMatches too large: %d", maxMatches)
 	}
 
-	tf, err := fileutil.ReadTextFileUTF8(path, toolutil.MaxTextProcessingBytes)
+	tf, err := fileutil.ReadTextFileUTF8(path, toolutil.GetLimits().MaxTextProcessing)
 	if err != nil {
 		return nil, err
 	}
@@ -140,7 +140,7 @@ func insertTextLines(ctx context.Context, args InsertTextLinesArgs) (*InsertText
 		// Index will error out.
 	}
 
-	tf, err := fileutil.ReadTextFileUTF8(path, toolutil.MaxTextProcessingBytes)
+	tf, err := fileutil.ReadTextFileUTF8(path, toolutil.GetLimits().MaxTextProcessing)
 	if err != nil {
 		return nil, err
 	}
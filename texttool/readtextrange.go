@@ -108,7 +108,7 @@ func readTextRange(ctx context.Context, args ReadTextRangeArgs) (*ReadTextRangeO
 	startBlock := fileutil.NormalizeLineBlockInput(args.StartMatchLines)
 	endBlock := fileutil.NormalizeLineBlockInput(args.EndMatchLines)
 
-	tf, err := fileutil.ReadTextFileUTF8(path, toolutil.MaxTextProcessingBytes)
+	tf, err := fileutil.ReadTextFileUTF8(path, toolutil.GetLimits().MaxTextProcessing)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,302 @@
+package texttool
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/flexigpt/llmtools-go/internal/fileutil"
+	"github.com/flexigpt/llmtools-go/internal/toolutil"
+	"github.com/flexigpt/llmtools-go/spec"
+)
+
+const replaceInFilesFuncID spec.FuncID = "github.com/flexigpt/llmtools-go/texttool/replaceinfiles.ReplaceInFiles"
+
+var replaceInFilesTool = spec.Tool{
+	SchemaVersion: spec.SchemaVersion,
+	ID:            "01a13dcb-a280-7662-bc2f-499aad41334c",
+	Slug:          "replaceinfiles",
+	Version:       "v1.0.0",
+	DisplayName:   "Replace in files",
+	Description: "Regex-replace across the UTF-8 text files under a directory, optionally filtered by include/exclude globs.\n" +
+		"VCS metadata directories are skipped. Each file is written atomically. Use dryRun to preview per-file diffs without writing.",
+	Tags: []string{"text"},
+
+	ArgSchema: spec.JSONSchema(`{
+"$schema": "http://json-schema.org/draft-07/schema#",
+"type": "object",
+"properties": {
+	"root": {
+		"type": "string",
+		"description": "Absolute path of the directory to search recursively."
+	},
+	"pattern": {
+		"type": "string",
+		"description": "RE2 regular expression applied to the whole content of each file."
+	},
+	"replacement": {
+		"type": "string",
+		"description": "Replacement text. $1 or ${name} expand to capture groups; use $$ for a literal $."
+	},
+	"include": {
+		"type": "array",
+		"items": { "type": "string" },
+		"description": "Only files whose base name matches one of these globs (e.g. \"*.go\"). Empty means all files."
+	},
+	"exclude": {
+		"type": "array",
+		"items": { "type": "string" },
+		"description": "Skip files, and directories (with their contents), whose base name matches one of these globs. .git, .hg and .svn are always skipped."
+	},
+	"dryRun": {
+		"type": "boolean",
+		"default": false,
+		"description": "Return per-file diffs of the would-be changes without writing anything."
+	}
+},
+"required": ["root", "pattern", "replacement"],
+"additionalProperties": false
+}`),
+
+	GoImpl: spec.GoToolImpl{FuncID: replaceInFilesFuncID},
+
+	CreatedAt:  spec.SchemaStartTime,
+	ModifiedAt: spec.SchemaStartTime,
+}
+
+func ReplaceInFilesTool() spec.Tool { return toolutil.CloneTool(replaceInFilesTool) }
+
+type ReplaceInFilesArgs struct {
+	Root        string   `json:"root"`
+	Pattern     string   `json:"pattern"`     // RE2
+	Replacement string   `json:"replacement"` // regexp.Expand syntax
+	Include     []string `json:"include,omitempty"`
+	Exclude     []string `json:"exclude,omitempty"`
+	DryRun      bool     `json:"dryRun,omitempty"`
+}
+
+type ReplaceInFilesOut struct {
+	DryRun            bool                 `json:"dryRun"`
+	FilesChanged      int                  `json:"filesChanged"`
+	TotalReplacements int                  `json:"totalReplacements"`
+	Files             []ReplaceInFilesFile `json:"files"`
+	// SkippedFiles counts candidate files that were too large or not UTF-8 text.
+	SkippedFiles int `json:"skippedFiles"`
+}
+
+// ReplaceInFilesFile reports the replacements in one file. Diff (a unified diff
+// without context lines) is only set for dry runs.
+type ReplaceInFilesFile struct {
+	Path         string `json:"path"`
+	Replacements int    `json:"replacements"`
+	Diff         string `json:"diff,omitempty"`
+}
+
+// ReplaceInFiles replaces every match of Pattern with Replacement in the files
+// under Root.
+//
+// Behavior notes (entry point):
+//   - Root must be an absolute directory without symlink components.
+//   - Symlinks are neither followed nor edited; only regular files are considered.
+//   - Include/Exclude are filepath.Match globs on base names. Include applies to
+//     files only; Exclude also prunes matching directories below Root.
+//   - VCS metadata directories (.git, .hg, .svn) are never entered.
+//   - Files larger than the text processing limit, or not valid UTF‑8, are skipped.
+//   - Each file is written (atomically) as soon as it is processed, so only one
+//     file is held in memory; an error stops the walk but leaves earlier files changed.
+//   - Files without matches are not listed or rewritten.
+func ReplaceInFiles(ctx context.Context, args ReplaceInFilesArgs) (*ReplaceInFilesOut, error) {
+	return toolutil.WithRecoveryResp(func() (*ReplaceInFilesOut, error) {
+		return replaceInFiles(ctx, args)
+	})
+}
+
+// vcsMetadataDirs are directory names ReplaceInFiles never descends into.
+var vcsMetadataDirs = map[string]bool{".git": true, ".hg": true, ".svn": true}
+
+func replaceInFiles(ctx context.Context, args ReplaceInFilesArgs) (*ReplaceInFilesOut, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	root, err := fileutil.NormalizeAbsPath(args.Root)
+	if err != nil {
+		return nil, err
+	}
+	if args.Pattern == "" {
+		return nil, errors.New("pattern is required")
+	}
+	re, err := regexp.Compile(args.Pattern)
+	if err != nil {
+		return nil, err
+	}
+	for _, pat := range append(append([]string(nil), args.Include...), args.Exclude...) {
+		if _, err := filepath.Match(pat, ""); err != nil {
+			return nil, fmt.Errorf("invalid glob %q: %w", pat, err)
+		}
+	}
+	if err := fileutil.VerifyDirNoSymlink(root); err != nil {
+		return nil, err
+	}
+	st, err := os.Stat(root)
+	if err != nil {
+		return nil, err
+	}
+	if !st.IsDir() {
+		return nil, fmt.Errorf("root is not a directory: %s", root)
+	}
+
+	maxBytes := toolutil.GetLimits().MaxTextProcessing
+	out := &ReplaceInFilesOut{DryRun: args.DryRun, Files: []ReplaceInFilesFile{}}
+
+	err = filepath.WalkDir(root, func(path string, d fs.DirEntry, walkErr error) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if walkErr != nil {
+			return walkErr
+		}
+		if d.IsDir() {
+			if path != root && (vcsMetadataDirs[d.Name()] || matchesAnyGlob(args.Exclude, d.Name(), false)) {
+				return fs.SkipDir
+			}
+			return nil
+		}
+		if !d.Type().IsRegular() || !matchesAnyGlob(args.Include, d.Name(), true) ||
+			matchesAnyGlob(args.Exclude, d.Name(), false) {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		if maxBytes > 0 && info.Size() > maxBytes {
+			out.SkippedFiles++
+			return nil
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		if !utf8.Valid(data) {
+			out.SkippedFiles++
+			return nil
+		}
+
+		content := string(data)
+		locs := re.FindAllStringSubmatchIndex(content, -1)
+		if len(locs) == 0 {
+			return nil
+		}
+		replaced, diff := replaceWithDiff(re, content, locs, args.Replacement)
+		if maxBytes > 0 && int64(len(replaced)) > maxBytes {
+			return fmt.Errorf("replacement result for %s exceeds maximum allowed size (%d bytes)", path, maxBytes)
+		}
+		if replaced == content {
+			return nil
+		}
+
+		f := ReplaceInFilesFile{Path: path, Replacements: len(locs)}
+		if args.DryRun {
+			f.Diff = "--- " + path + "\n+++ " + path + "\n" + diff
+		} else if err := fileutil.WriteFileAtomicBytes(path, []byte(replaced), info.Mode().Perm(), true); err != nil {
+			return err
+		}
+		out.Files = append(out.Files, f)
+		out.TotalReplacements += len(locs)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	out.FilesChanged = len(out.Files)
+	return out, nil
+}
+
+// replaceWithDiff applies the replacement at each of locs (as returned by
+// FindAllStringSubmatchIndex) and returns the new content together with
+// zero-context unified diff hunks covering the changed lines.
+func replaceWithDiff(re *regexp.Regexp, s string, locs [][]int, replacement string) (string, string) {
+	var out, diff strings.Builder
+	lineDelta := 0 // new line number minus old line number after the hunks so far
+	copied := 0    // bytes of s already written to out
+
+	for i := 0; i < len(locs); {
+		// A hunk spans whole lines from the first match's line through the line
+		// of the last match that overlaps it.
+		hunkStart := strings.LastIndexByte(s[:locs[i][0]], '\n') + 1
+		hunkEnd := lineEndAt(s, locs[i])
+		j := i + 1
+		for j < len(locs) && locs[j][0] <= hunkEnd {
+			hunkEnd = max(hunkEnd, lineEndAt(s, locs[j]))
+			j++
+		}
+
+		var repl []byte
+		prev := hunkStart
+		for _, loc := range locs[i:j] {
+			repl = append(repl, s[prev:loc[0]]...)
+			repl = re.ExpandString(repl, replacement, s, loc)
+			prev = loc[1]
+		}
+		repl = append(repl, s[prev:hunkEnd]...)
+
+		oldText, newText := s[hunkStart:hunkEnd], string(repl)
+		out.WriteString(s[copied:hunkStart])
+		out.WriteString(newText)
+		copied = hunkEnd
+
+		if oldText != newText {
+			oldLine := strings.Count(s[:hunkStart], "\n") + 1
+			oldLines, newLines := diffLines(oldText), diffLines(newText)
+			fmt.Fprintf(&diff, "@@ -%d,%d +%d,%d @@\n", oldLine, len(oldLines), oldLine+lineDelta, len(newLines))
+			for _, l := range oldLines {
+				diff.WriteString("-" + l + "\n")
+			}
+			for _, l := range newLines {
+				diff.WriteString("+" + l + "\n")
+			}
+			lineDelta += len(newLines) - len(oldLines)
+		}
+		i = j
+	}
+	out.WriteString(s[copied:])
+	return out.String(), diff.String()
+}
+
+// lineEndAt returns the offset of the first newline at or after the end of
+// loc's match (or len(s) if there is none).
+func lineEndAt(s string, loc []int) int {
+	if k := strings.IndexByte(s[loc[1]:], '\n'); k >= 0 {
+		return loc[1] + k
+	}
+	return len(s)
+}
+
+func diffLines(s string) []string {
+	lines := strings.Split(s, "\n")
+	for i, l := range lines {
+		lines[i] = strings.TrimSuffix(l, "\r")
+	}
+	return lines
+}
+
+// matchesAnyGlob reports whether name matches one of patterns, or ifEmpty if
+// there are none. Patterns are validated up front.
+func matchesAnyGlob(patterns []string, name string, ifEmpty bool) bool {
+	if len(patterns) == 0 {
+		return ifEmpty
+	}
+	for _, pat := range patterns {
+		if ok, _ := filepath.Match(pat, name); ok {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,169 @@
+package texttool
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestReplaceInFiles(t *testing.T) {
+	tests := []struct {
+		name        string
+		files       map[string]string
+		args        ReplaceInFilesArgs
+		wantFiles   map[string]string // expected content after the call
+		wantChanged []string          // relative paths reported as changed, in walk order
+		wantTotal   int
+		wantDiffs   map[string]string
+		wantErrSub  string
+	}{
+		{
+			name: "dry_run_reports_diffs_without_writing",
+			files: map[string]string{
+				"a.go": "x := oldName()\nkeep\ny := oldName()\n",
+			},
+			args: ReplaceInFilesArgs{Pattern: `oldName\(\)`, Replacement: "newName()", DryRun: true},
+			wantFiles: map[string]string{
+				"a.go": "x := oldName()\nkeep\ny := oldName()\n",
+			},
+			wantChanged: []string{"a.go"},
+			wantTotal:   2,
+			wantDiffs: map[string]string{
+				"a.go": "@@ -1,1 +1,1 @@\n-x := oldName()\n+x := newName()\n" +
+					"@@ -3,1 +3,1 @@\n-y := oldName()\n+y := newName()\n",
+			},
+		},
+		{
+			name: "replaces_across_files_honoring_globs",
+			files: map[string]string{
+				"a.go":          "foo_1 foo_2\n",
+				"sub/b.go":      "A\r\nfoo_3\r\n",
+				"sub/b_test.go": "foo_4\n",
+				"c.txt":         "foo_5\n",
+			},
+			args: ReplaceInFilesArgs{
+				Pattern:     `foo_(\d)`,
+				Replacement: "bar$1",
+				Include:     []string{"*.go"},
+				Exclude:     []string{"*_test.go"},
+			},
+			wantFiles: map[string]string{
+				"a.go":          "bar1 bar2\n",
+				"sub/b.go":      "A\r\nbar3\r\n",
+				"sub/b_test.go": "foo_4\n",
+				"c.txt":         "foo_5\n",
+			},
+			wantChanged: []string{"a.go", "sub/b.go"},
+			wantTotal:   3,
+		},
+		{
+			name: "excluded_and_vcs_directories_are_pruned",
+			files: map[string]string{
+				"a.txt":               "main\n",
+				".git/HEAD":           "ref: refs/heads/main\n",
+				".hg/branch":          "main\n",
+				"node_modules/m.txt":  "main\n",
+				"src/node_modules.go": "main\n",
+			},
+			args: ReplaceInFilesArgs{Pattern: "main", Replacement: "trunk", Exclude: []string{"node_modules"}},
+			wantFiles: map[string]string{
+				"a.txt":               "trunk\n",
+				".git/HEAD":           "ref: refs/heads/main\n",
+				".hg/branch":          "main\n",
+				"node_modules/m.txt":  "main\n",
+				"src/node_modules.go": "trunk\n",
+			},
+			wantChanged: []string{"a.txt", "src/node_modules.go"},
+			wantTotal:   2,
+		},
+		{
+			name: "multiline_match_spanning_lines",
+			files: map[string]string{
+				"a.txt": "one\ntwo\nthree\n",
+			},
+			args:        ReplaceInFilesArgs{Pattern: `one\ntwo`, Replacement: "merged", DryRun: true},
+			wantFiles:   map[string]string{"a.txt": "one\ntwo\nthree\n"},
+			wantChanged: []string{"a.txt"},
+			wantTotal:   1,
+			wantDiffs:   map[string]string{"a.txt": "@@ -1,2 +1,1 @@\n-one\n-two\n+merged\n"},
+		},
+		{
+			name: "no_match_changes_nothing",
+			files: map[string]string{
+				"a.go": "package a\n",
+			},
+			args:        ReplaceInFilesArgs{Pattern: "absent", Replacement: "x"},
+			wantFiles:   map[string]string{"a.go": "package a\n"},
+			wantChanged: []string{},
+		},
+		{
+			name:       "invalid_regex",
+			args:       ReplaceInFilesArgs{Pattern: "(", Replacement: "x"},
+			wantErrSub: "missing closing",
+		},
+		{
+			name:       "invalid_glob",
+			args:       ReplaceInFilesArgs{Pattern: "x", Include: []string{"["}},
+			wantErrSub: "invalid glob",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := newWorkDir(t)
+			for rel, content := range tt.files {
+				p := filepath.Join(dir, filepath.FromSlash(rel))
+				if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+					t.Fatalf("mkdir: %v", err)
+				}
+				if err := os.WriteFile(p, []byte(content), 0o600); err != nil {
+					t.Fatalf("write: %v", err)
+				}
+			}
+			args := tt.args
+			args.Root = dir
+
+			out, err := ReplaceInFiles(t.Context(), args)
+			if tt.wantErrSub != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErrSub) {
+					t.Fatalf("err=%v, want substring %q", err, tt.wantErrSub)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ReplaceInFiles: %v", err)
+			}
+
+			if out.FilesChanged != len(tt.wantChanged) || len(out.Files) != len(tt.wantChanged) {
+				t.Fatalf("files=%+v, want %v", out.Files, tt.wantChanged)
+			}
+			if out.TotalReplacements != tt.wantTotal || out.DryRun != tt.args.DryRun {
+				t.Fatalf("unexpected out: %+v", out)
+			}
+			for i, rel := range tt.wantChanged {
+				f := out.Files[i]
+				if f.Path != filepath.Join(dir, filepath.FromSlash(rel)) || f.Replacements == 0 {
+					t.Fatalf("file %d = %+v, want %s", i, f, rel)
+				}
+				if !tt.args.DryRun && f.Diff != "" {
+					t.Fatalf("diff returned without dry run: %q", f.Diff)
+				}
+				if want, ok := tt.wantDiffs[rel]; ok {
+					if !strings.HasSuffix(f.Diff, "\n"+want) || !strings.HasPrefix(f.Diff, "--- "+f.Path+"\n") {
+						t.Fatalf("diff for %s:\n%s\nwant hunks:\n%s", rel, f.Diff, want)
+					}
+				}
+			}
+			for rel, want := range tt.wantFiles {
+				got, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(rel)))
+				if err != nil {
+					t.Fatalf("read %s: %v", rel, err)
+				}
+				if string(got) != want {
+					t.Fatalf("%s = %q, want %q", rel, got, want)
+				}
+			}
+		})
+	}
+}
@@ -143,7 +143,7 @@ func replaceTextLines(ctx context.Context, args ReplaceTextLinesArgs) (*ReplaceT
 		return nil, fmt.Errorf("expectedReplacements must be >= 1 (got %d)", expected)
 	}
 
-	tf, err := fileutil.ReadTextFileUTF8(path, toolutil.MaxTextProcessingBytes)
+	tf, err := fileutil.ReadTextFileUTF8(path, toolutil.GetLimits().MaxTextProcessing)
 	if err != nil {
 		return nil, err
 	}